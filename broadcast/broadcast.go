@@ -2,9 +2,13 @@
 package broadcast
 
 import (
+	"fmt"
+	"net"
 	"os"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/gopacket/pcap"
 
@@ -19,29 +23,39 @@ type Broadcaster struct {
 	iface      *types.InterfaceInfo
 	systemName string
 	stopChan   chan struct{}
+	configSet  chan struct{} // Tells run to pick up a new broadcast_max_duration
 	running    bool
 	mu         sync.Mutex
+	sendMu     sync.Mutex // Serializes transmits from the ticker and link-up events
+
+	// Callback for frame build warnings (e.g. trimmed TLVs)
+	// Each distinct warning is reported once until it stops occurring
+	OnWarning    func(string)
+	lastWarnings map[string]bool
+
+	// Callback for frames that couldn't be built (e.g. no usable source MAC)
+	// Nothing is sent for that protocol; each distinct error is reported once
+	// until it stops occurring or the broadcaster is restarted
+	OnError    func(error)
+	lastErrors map[string]bool
+
+	// Callback for when broadcast_max_duration stops the broadcaster, with how
+	// long it ran
+	OnAutoStop func(time.Duration)
+
+	// Number of varied identities sent when BroadcastVariation is enabled
+	variationSeq uint32
 }
 
 // NewBroadcaster creates a new broadcaster instance
 func NewBroadcaster(handle *pcap.Handle, cfg *config.Config, iface *types.InterfaceInfo) *Broadcaster {
-	// Determine system name
-	systemName := cfg.SystemName
-	if systemName == "" {
-		hostname, err := os.Hostname()
-		if err == nil {
-			systemName = hostname
-		} else {
-			systemName = "nbor"
-		}
-	}
-
 	return &Broadcaster{
 		handle:     handle,
 		config:     cfg,
 		iface:      iface,
-		systemName: systemName,
+		systemName: AdvertisedName(cfg),
 		stopChan:   make(chan struct{}),
+		configSet:  make(chan struct{}, 1),
 	}
 }
 
@@ -54,6 +68,7 @@ func (b *Broadcaster) Start() {
 	}
 	b.running = true
 	b.stopChan = make(chan struct{})
+	b.lastErrors = nil // Report errors again after a restart
 	b.mu.Unlock()
 
 	go b.run()
@@ -78,24 +93,101 @@ func (b *Broadcaster) IsRunning() bool {
 	return b.running
 }
 
-// UpdateConfig updates the broadcaster configuration
+// UpdateConfig updates the broadcaster configuration. A running broadcaster
+// applies a new broadcast_max_duration from when it started, so lowering it
+// below the time already spent stops it right away
 func (b *Broadcaster) UpdateConfig(cfg *config.Config) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
 	b.config = cfg
+	b.systemName = AdvertisedName(cfg)
+	b.mu.Unlock()
+
+	select {
+	case b.configSet <- struct{}{}:
+	default: // run hasn't picked up the last update yet
+	}
+}
+
+// maxAdvertisedNameLen is the longest CDP Device ID / LLDP system name sent
+// Some switches reject or cut off longer device IDs
+const maxAdvertisedNameLen = 64
+
+// AdvertisedName returns the name sent as the CDP Device ID and LLDP system name:
+// system_name, or else the hostname (its FQDN with advertise_use_fqdn), plus
+// advertise_name_suffix, with the name cut so the whole fits in
+// maxAdvertisedNameLen bytes
+func AdvertisedName(cfg *config.Config) string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = ""
+	}
+	if cfg.SystemName == "" && cfg.AdvertiseUseFQDN {
+		hostname = resolveFQDN(hostname)
+	}
+	return advertisedName(cfg, hostname)
+}
 
-	// Update system name if changed
-	if cfg.SystemName != "" {
-		b.systemName = cfg.SystemName
+// advertisedName is AdvertisedName with the hostname passed in
+func advertisedName(cfg *config.Config, hostname string) string {
+	name := cfg.SystemName
+	if name == "" {
+		name = hostname
+	}
+	if name == "" {
+		name = "nbor"
 	}
+	// Shorten the name rather than the suffix, so agents stay distinguishable
+	suffix := truncateUTF8(cfg.AdvertiseNameSuffix, maxAdvertisedNameLen)
+	return truncateUTF8(name, maxAdvertisedNameLen-len(suffix)) + suffix
 }
 
+// lookupCNAME resolves a host's canonical name, a var so tests can stub DNS
+var lookupCNAME = net.LookupCNAME
+
+// fqdnCache holds resolved FQDNs by hostname, since the self filter asks for
+// the advertised name on every received frame
+var fqdnCache sync.Map
+
+// resolveFQDN returns hostname's fully qualified name from its canonical name
+// lookup, or hostname itself when the lookup fails
+func resolveFQDN(hostname string) string {
+	if hostname == "" {
+		return ""
+	}
+	if fqdn, ok := fqdnCache.Load(hostname); ok {
+		return fqdn.(string)
+	}
+	fqdn := hostname
+	if cname, err := lookupCNAME(hostname); err == nil && strings.TrimSuffix(cname, ".") != "" {
+		fqdn = strings.TrimSuffix(cname, ".")
+	}
+	fqdnCache.Store(hostname, fqdn)
+	return fqdn
+}
+
+// truncateUTF8 cuts s to at most n bytes on a rune boundary
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// maxDurationUnit is the unit of BroadcastMaxDuration, a var so tests can shorten it
+var maxDurationUnit = time.Minute
+
 // run is the main broadcast loop
 func (b *Broadcaster) run() {
-	// Get interval from config
+	// Get interval and time limit from config
 	b.mu.Lock()
 	interval := time.Duration(b.config.AdvertiseInterval) * time.Second
+	maxDuration := time.Duration(b.config.BroadcastMaxDuration) * maxDurationUnit
+	stopChan := b.stopChan
 	b.mu.Unlock()
+	started := time.Now()
 
 	// Send immediately on start
 	b.transmit()
@@ -103,6 +195,26 @@ func (b *Broadcaster) run() {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	// Without a limit the nil channel never fires
+	var timer *time.Timer
+	var limit <-chan time.Time
+	setLimit := func() {
+		if timer != nil {
+			timer.Stop()
+		}
+		timer, limit = nil, nil
+		if maxDuration > 0 {
+			timer = time.NewTimer(max(maxDuration-time.Since(started), 0))
+			limit = timer.C
+		}
+	}
+	setLimit()
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
 	for {
 		select {
 		case <-ticker.C:
@@ -115,35 +227,172 @@ func (b *Broadcaster) run() {
 				interval = newInterval
 				ticker.Reset(interval)
 			}
-		case <-b.stopChan:
+		case <-b.configSet:
+			b.mu.Lock()
+			newMaxDuration := time.Duration(b.config.BroadcastMaxDuration) * maxDurationUnit
+			b.mu.Unlock()
+			if newMaxDuration != maxDuration {
+				maxDuration = newMaxDuration
+				setLimit()
+			}
+		case <-limit:
+			b.autoStop(stopChan, maxDuration)
+			return
+		case <-stopChan:
 			return
 		}
 	}
 }
 
+// autoStop stops the broadcaster once broadcast_max_duration is reached and
+// reports it to OnAutoStop. stopChan identifies the run that timed out, so a
+// broadcaster stopped and restarted in the meantime is left alone
+func (b *Broadcaster) autoStop(stopChan chan struct{}, after time.Duration) {
+	b.mu.Lock()
+	if !b.running || b.stopChan != stopChan {
+		b.mu.Unlock()
+		return
+	}
+	b.running = false
+	close(b.stopChan)
+	onAutoStop := b.OnAutoStop
+	b.mu.Unlock()
+
+	if onAutoStop != nil {
+		onAutoStop(after)
+	}
+}
+
+// SetHandle switches transmission to a new pcap handle, e.g. after the capture
+// is reopened when the link comes back. The caller closes the old handle
+func (b *Broadcaster) SetHandle(handle *pcap.Handle) {
+	b.sendMu.Lock()
+	defer b.sendMu.Unlock()
+	b.handle = handle
+}
+
+// LinkUp sends immediately after the interface regains link so the switch
+// relearns us without waiting for the next interval
+// Does nothing unless the broadcaster is running and BroadcastOnLinkUp is enabled
+func (b *Broadcaster) LinkUp() {
+	b.mu.Lock()
+	send := b.running && b.config.BroadcastOnLinkUp
+	b.mu.Unlock()
+
+	if send {
+		b.transmit()
+	}
+}
+
 // transmit sends CDP and/or LLDP packets based on configuration
 func (b *Broadcaster) transmit() {
+	b.sendMu.Lock()
+	defer b.sendMu.Unlock()
+
 	b.mu.Lock()
 	cfg := b.config
 	iface := b.iface
 	systemName := b.systemName
+	// Each interval advertises a new identity when variation is enabled (lab/test use)
+	if cfg.BroadcastVariationEnabled() {
+		b.variationSeq++
+		iface, systemName = varyIdentity(cfg.BroadcastVariation, b.variationSeq, iface, systemName)
+	}
 	b.mu.Unlock()
 
 	// Send CDP if enabled
+	var errs []error
 	if cfg.CDPBroadcast {
 		frame, err := BuildCDPFrame(cfg, iface, systemName)
 		if err == nil {
 			_ = b.handle.WritePacketData(frame)
+		} else {
+			errs = append(errs, fmt.Errorf("CDP not sent: %w", err))
 		}
 	}
 
 	// Send LLDP if enabled
+	var warnings []string
 	if cfg.LLDPBroadcast {
-		frame, err := BuildLLDPFrame(cfg, iface, systemName)
+		frame, lldpWarnings, err := BuildLLDPFrame(cfg, iface, systemName)
+		warnings = append(warnings, lldpWarnings...)
 		if err == nil {
 			_ = b.handle.WritePacketData(frame)
+		} else {
+			errs = append(errs, fmt.Errorf("LLDP not sent: %w", err))
+		}
+	}
+
+	b.reportWarnings(warnings)
+	b.reportErrors(errs)
+}
+
+// reportWarnings passes new warnings to OnWarning, suppressing repeats from previous transmits
+func (b *Broadcaster) reportWarnings(warnings []string) {
+	b.mu.Lock()
+	onWarning := b.OnWarning
+	current := make(map[string]bool, len(warnings))
+	var fresh []string
+	for _, w := range warnings {
+		if !b.lastWarnings[w] && !current[w] {
+			fresh = append(fresh, w)
 		}
+		current[w] = true
 	}
+	b.lastWarnings = current
+	b.mu.Unlock()
+
+	if onWarning == nil {
+		return
+	}
+	for _, w := range fresh {
+		onWarning(w)
+	}
+}
+
+// reportErrors passes new build errors to OnError, suppressing repeats from previous transmits
+func (b *Broadcaster) reportErrors(errs []error) {
+	b.mu.Lock()
+	onError := b.OnError
+	current := make(map[string]bool, len(errs))
+	var fresh []error
+	for _, err := range errs {
+		if !b.lastErrors[err.Error()] && !current[err.Error()] {
+			fresh = append(fresh, err)
+		}
+		current[err.Error()] = true
+	}
+	b.lastErrors = current
+	b.mu.Unlock()
+
+	if onError == nil {
+		return
+	}
+	for _, err := range fresh {
+		onError(err)
+	}
+}
+
+// validateSourceMAC checks that the interface has an Ethernet MAC to send from
+// Without one the frame's source address and LLDP chassis ID would be garbage
+func validateSourceMAC(iface *types.InterfaceInfo) error {
+	if len(iface.MAC) != 6 {
+		if iface.MAC == nil {
+			return fmt.Errorf("interface %s has no MAC address", iface.Name)
+		}
+		return fmt.Errorf("interface %s MAC address %s isn't 6 bytes", iface.Name, iface.MAC)
+	}
+	return nil
+}
+
+// checkFrameMTU checks that a frame's payload (everything after the 14-byte
+// Ethernet header) fits the interface MTU. An MTU of 0 means unknown
+func checkFrameMTU(proto string, frame []byte, iface *types.InterfaceInfo) error {
+	payload := len(frame) - 14
+	if iface.MTU > 0 && payload > iface.MTU {
+		return fmt.Errorf("%s frame payload is %d bytes, larger than the %d-byte MTU of %s", proto, payload, iface.MTU, iface.Name)
+	}
+	return nil
 }
 
 // SendNow sends packets immediately (for testing)
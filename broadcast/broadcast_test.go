@@ -0,0 +1,157 @@
+package broadcast
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"nbor/config"
+	"nbor/parser"
+	"nbor/types"
+)
+
+func TestAdvertisedName(t *testing.T) {
+	tests := []struct {
+		name       string
+		systemName string
+		suffix     string
+		hostname   string
+		want       string
+	}{
+		{"full hostname", "", "", "host01.corp.example.com", "host01.corp.example.com"},
+		{"suffix", "", "-lab", "host01", "host01-lab"},
+		{"system name kept whole", "probe.site1", "", "host01", "probe.site1"},
+		{"system name with suffix", "probe", "-2", "host01", "probe-2"},
+		{"no hostname", "", "", "", "nbor"},
+		{"truncated", "", "", strings.Repeat("a", 100), strings.Repeat("a", maxAdvertisedNameLen)},
+		{"truncated before suffix", "", "-lab", strings.Repeat("a", 100), strings.Repeat("a", maxAdvertisedNameLen-4) + "-lab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.SystemName = tt.systemName
+			cfg.AdvertiseNameSuffix = tt.suffix
+			if got := advertisedName(&cfg, tt.hostname); got != tt.want {
+				t.Errorf("advertisedName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveFQDN(t *testing.T) {
+	orig := lookupCNAME
+	t.Cleanup(func() { lookupCNAME = orig })
+	lookupCNAME = func(host string) (string, error) {
+		if host == "host01" {
+			return "host01.corp.example.com.", nil
+		}
+		return "", errors.New("no such host")
+	}
+
+	if got := resolveFQDN("host01"); got != "host01.corp.example.com" {
+		t.Errorf("resolveFQDN(host01) = %q, want %q", got, "host01.corp.example.com")
+	}
+	// A failed lookup falls back to the hostname as the OS reports it
+	if got := resolveFQDN("host02"); got != "host02" {
+		t.Errorf("resolveFQDN(host02) = %q, want %q", got, "host02")
+	}
+}
+
+func TestAdvertisedNameRuneBoundary(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SystemName = strings.Repeat("a", maxAdvertisedNameLen-1) + "é"
+
+	got := advertisedName(&cfg, "host01")
+	if len(got) > maxAdvertisedNameLen || !utf8.ValidString(got) {
+		t.Errorf("advertisedName() = %q (%d bytes), want valid UTF-8 of at most %d bytes", got, len(got), maxAdvertisedNameLen)
+	}
+}
+
+func TestBuildFramesWithAdvertisedName(t *testing.T) {
+	cfg := fixtureConfig()
+	cfg.AdvertiseNameSuffix = "-lab"
+	name := advertisedName(&cfg, strings.Repeat("h", 70)+".example.com")
+
+	// The TLV lengths come from the final name, so both frames decode to it
+	cdp, err := BuildCDPFrame(&cfg, testInterface(), name)
+	if err != nil {
+		t.Fatalf("BuildCDPFrame() error = %v", err)
+	}
+	n, err := parser.ParseCDP(gopacket.NewPacket(cdp, layers.LayerTypeEthernet, gopacket.Default), "eth0")
+	if err != nil {
+		t.Fatalf("ParseCDP() error = %v", err)
+	}
+	if n.Hostname != name {
+		t.Errorf("CDP Device ID = %q, want %q", n.Hostname, name)
+	}
+
+	lldp, _, err := BuildLLDPFrame(&cfg, testInterface(), name)
+	if err != nil {
+		t.Fatalf("BuildLLDPFrame() error = %v", err)
+	}
+	if info := decodeLLDP(t, lldp); info.SysName != name {
+		t.Errorf("LLDP SysName = %q, want %q", info.SysName, name)
+	}
+}
+
+func TestBroadcastMaxDuration(t *testing.T) {
+	defer func(unit time.Duration) { maxDurationUnit = unit }(maxDurationUnit)
+	maxDurationUnit = 20 * time.Millisecond
+
+	// Neither protocol is enabled, so nothing is written to the nil handle
+	cfg := config.DefaultConfig()
+	cfg.BroadcastMaxDuration = 2
+	b := NewBroadcaster(nil, &cfg, &types.InterfaceInfo{Name: "eth0"})
+	stopped := make(chan time.Duration, 1)
+	b.OnAutoStop = func(after time.Duration) { stopped <- after }
+
+	b.Start()
+	select {
+	case after := <-stopped:
+		if after != 40*time.Millisecond {
+			t.Errorf("OnAutoStop after %v, want 40ms", after)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("broadcaster didn't stop itself")
+	}
+	if b.IsRunning() {
+		t.Error("broadcaster still running after broadcast_max_duration")
+	}
+
+	// Stopping by hand first means there's nothing to report
+	b.Start()
+	b.Stop()
+	select {
+	case <-stopped:
+		t.Error("OnAutoStop called for a broadcaster that was stopped by hand")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// No limit keeps it running
+	unlimited := cfg
+	unlimited.BroadcastMaxDuration = 0
+	b.UpdateConfig(&unlimited)
+	b.Start()
+	defer b.Stop()
+	time.Sleep(100 * time.Millisecond)
+	if !b.IsRunning() {
+		t.Error("broadcaster stopped with broadcast_max_duration = 0")
+	}
+
+	// Setting a limit while running counts from when it started, so one
+	// already passed stops it right away
+	b.UpdateConfig(&cfg)
+	select {
+	case after := <-stopped:
+		if after != 40*time.Millisecond {
+			t.Errorf("OnAutoStop after %v, want 40ms", after)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("broadcaster didn't pick up the new broadcast_max_duration")
+	}
+}
@@ -9,13 +9,21 @@ import (
 	"nbor/types"
 )
 
+// maxCDPHoldtime is the largest TTL the one-byte CDP holdtime field can carry
+// Longer TTLs (LLDP allows up to 65535) are sent to CDP as this
+const maxCDPHoldtime = config.MaxCDPHoldtime
+
 // BuildCDPFrame builds a complete CDP frame ready for transmission
 func BuildCDPFrame(cfg *config.Config, iface *types.InterfaceInfo, systemName string) ([]byte, error) {
+	if err := validateSourceMAC(iface); err != nil {
+		return nil, err
+	}
+
 	// Build CDP payload (header + TLVs)
 	cdpPayload := buildCDPPayload(cfg, iface, systemName)
 
 	// Calculate checksum over CDP payload
-	checksum := calculateChecksum(cdpPayload)
+	checksum := protocol.Checksum(cdpPayload)
 	// Insert checksum into payload (bytes 2-3)
 	binary.BigEndian.PutUint16(cdpPayload[2:4], checksum)
 
@@ -52,6 +60,10 @@ func BuildCDPFrame(cfg *config.Config, iface *types.InterfaceInfo, systemName st
 	// CDP payload
 	copy(frame[offset:], cdpPayload)
 
+	if err := checkFrameMTU("CDP", frame, iface); err != nil {
+		return nil, err
+	}
+
 	return frame, nil
 }
 
@@ -62,7 +74,7 @@ func buildCDPPayload(cfg *config.Config, iface *types.InterfaceInfo, systemName
 	// CDP header (4 bytes)
 	header := make([]byte, 4)
 	header[0] = 0x02                                     // Version 2
-	header[1] = byte(cfg.TTL)                            // TTL in seconds
+	header[1] = byte(min(cfg.TTL, maxCDPHoldtime))      // TTL in seconds
 	binary.BigEndian.PutUint16(header[2:4], 0x0000)      // Checksum placeholder
 	payload = append(payload, header...)
 
@@ -79,7 +91,10 @@ func buildCDPPayload(cfg *config.Config, iface *types.InterfaceInfo, systemName
 	payload = append(payload, encodeCDPTLV(protocol.CDPTLVCapabilities, capData)...)
 
 	// TLV: Platform
-	platform := "nbor"
+	platform := cfg.PlatformString
+	if platform == "" {
+		platform = "nbor"
+	}
 	payload = append(payload, encodeCDPTLV(protocol.CDPTLVPlatform, []byte(platform))...)
 
 	// TLV: Software Version (Description)
@@ -139,26 +154,3 @@ func encodeCDPAddresses(ips []net.IP) []byte {
 
 	return data
 }
-
-// calculateChecksum calculates the CDP checksum (RFC 1071 Internet checksum)
-func calculateChecksum(data []byte) uint16 {
-	var sum uint32
-
-	// Sum all 16-bit words
-	for i := 0; i+1 < len(data); i += 2 {
-		sum += uint32(binary.BigEndian.Uint16(data[i : i+2]))
-	}
-
-	// Add odd byte if present
-	if len(data)%2 == 1 {
-		sum += uint32(data[len(data)-1]) << 8
-	}
-
-	// Fold 32-bit sum to 16 bits
-	for sum > 0xFFFF {
-		sum = (sum & 0xFFFF) + (sum >> 16)
-	}
-
-	// One's complement
-	return ^uint16(sum)
-}
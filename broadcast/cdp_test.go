@@ -0,0 +1,253 @@
+package broadcast
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"nbor/config"
+	"nbor/parser"
+	"nbor/protocol"
+	"nbor/types"
+)
+
+// fixtureConfig returns the config used by the exact-byte frame fixtures
+func fixtureConfig() config.Config {
+	cfg := config.DefaultConfig()
+	cfg.TTL = 180
+	cfg.Capabilities = []string{"router", "switch"}
+	cfg.SystemDescription = "test switch"
+	return cfg
+}
+
+// mustDecodeHex decodes a fixture hex string, ignoring whitespace
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		t.Fatalf("bad fixture hex: %v", err)
+	}
+	return b
+}
+
+// cdpFixture is the expected frame for fixtureConfig, testInterface and "host01"
+const cdpFixture = `
+	01000ccccccc 001122334455 004e
+	aaaa03 00000c2000
+	02 b4 11d2
+	0001 000a 686f73743031
+	0003 0008 65746830
+	0004 0008 00000009
+	0006 0008 6e626f72
+	0005 000f 74657374207377697463 68
+	0002 0011 00000001 0101cc 0004 c0a8010a`
+
+func TestBuildCDPFrameFixture(t *testing.T) {
+	cfg := fixtureConfig()
+
+	frame, err := BuildCDPFrame(&cfg, testInterface(), "host01")
+	if err != nil {
+		t.Fatalf("BuildCDPFrame() error = %v", err)
+	}
+
+	want := mustDecodeHex(t, cdpFixture)
+	if !bytes.Equal(frame, want) {
+		t.Errorf("BuildCDPFrame() =\n%x\nwant\n%x", frame, want)
+	}
+}
+
+func TestBuildCDPFrameLayout(t *testing.T) {
+	cfg := fixtureConfig()
+	iface := testInterface()
+
+	frame, err := BuildCDPFrame(&cfg, iface, "host01")
+	if err != nil {
+		t.Fatalf("BuildCDPFrame() error = %v", err)
+	}
+
+	// Ethernet 802.3 header
+	if !bytes.Equal(frame[0:6], protocol.CDPMulticastMAC) {
+		t.Errorf("destination MAC = %x, want %x", frame[0:6], []byte(protocol.CDPMulticastMAC))
+	}
+	if !bytes.Equal(frame[6:12], iface.MAC) {
+		t.Errorf("source MAC = %x, want %x", frame[6:12], []byte(iface.MAC))
+	}
+	if got, want := int(binary.BigEndian.Uint16(frame[12:14])), len(frame)-14; got != want {
+		t.Errorf("802.3 length = %d, want %d", got, want)
+	}
+
+	// LLC/SNAP
+	if want := []byte{0xaa, 0xaa, 0x03, 0x00, 0x00, 0x0c, 0x20, 0x00}; !bytes.Equal(frame[14:22], want) {
+		t.Errorf("LLC/SNAP = %x, want %x", frame[14:22], want)
+	}
+
+	// CDP header
+	cdp := frame[22:]
+	if cdp[0] != 2 {
+		t.Errorf("CDP version = %d, want 2", cdp[0])
+	}
+	if int(cdp[1]) != cfg.TTL {
+		t.Errorf("CDP TTL = %d, want %d", cdp[1], cfg.TTL)
+	}
+
+	// Checksum over the whole CDP payload (checksum included) must verify to zero
+	if sum := protocol.Checksum(cdp); sum != 0 {
+		t.Errorf("CDP checksum does not verify, residual = %#04x", sum)
+	}
+
+	// TLVs in order with lengths covering the rest of the payload
+	wantTypes := []uint16{
+		protocol.CDPTLVDeviceID,
+		protocol.CDPTLVPortID,
+		protocol.CDPTLVCapabilities,
+		protocol.CDPTLVPlatform,
+		protocol.CDPTLVVersion,
+		protocol.CDPTLVAddress,
+	}
+	var gotTypes []uint16
+	for offset := 4; offset < len(cdp); {
+		if offset+4 > len(cdp) {
+			t.Fatalf("truncated TLV header at offset %d", offset)
+		}
+		tlvType := binary.BigEndian.Uint16(cdp[offset : offset+2])
+		tlvLen := int(binary.BigEndian.Uint16(cdp[offset+2 : offset+4]))
+		if tlvLen < 4 || offset+tlvLen > len(cdp) {
+			t.Fatalf("TLV %#04x has bad length %d at offset %d", tlvType, tlvLen, offset)
+		}
+		if tlvType == protocol.CDPTLVCapabilities {
+			if got := binary.BigEndian.Uint32(cdp[offset+4 : offset+8]); got != protocol.CDPCapRouter|protocol.CDPCapSwitch {
+				t.Errorf("capability bits = %#08x, want %#08x", got, protocol.CDPCapRouter|protocol.CDPCapSwitch)
+			}
+		}
+		gotTypes = append(gotTypes, tlvType)
+		offset += tlvLen
+	}
+	if len(gotTypes) != len(wantTypes) {
+		t.Fatalf("TLV types = %v, want %v", gotTypes, wantTypes)
+	}
+	for i := range wantTypes {
+		if gotTypes[i] != wantTypes[i] {
+			t.Errorf("TLV %d type = %#04x, want %#04x", i, gotTypes[i], wantTypes[i])
+		}
+	}
+}
+
+func TestBuildCDPFrameLongTTL(t *testing.T) {
+	cfg := fixtureConfig()
+	iface := testInterface()
+
+	for _, tt := range []struct{ ttl, want int }{{255, 255}, {256, 255}, {300, 255}, {65535, 255}} {
+		cfg.TTL = tt.ttl
+		frame, err := BuildCDPFrame(&cfg, iface, "host01")
+		if err != nil {
+			t.Fatalf("BuildCDPFrame() error = %v", err)
+		}
+		if got := int(frame[23]); got != tt.want {
+			t.Errorf("ttl %d: CDP holdtime = %d, want %d", tt.ttl, got, tt.want)
+		}
+		if sum := protocol.Checksum(frame[22:]); sum != 0 {
+			t.Errorf("ttl %d: checksum doesn't verify (got %#04x)", tt.ttl, sum)
+		}
+	}
+}
+
+func TestBuildCDPFrameRoundTrip(t *testing.T) {
+	cfg := fixtureConfig()
+	iface := testInterface()
+
+	frame, err := BuildCDPFrame(&cfg, iface, "host01")
+	if err != nil {
+		t.Fatalf("BuildCDPFrame() error = %v", err)
+	}
+
+	packet := gopacket.NewPacket(frame, layers.LayerTypeEthernet, gopacket.Default)
+	n, err := parser.ParseCDP(packet, "eth0")
+	if err != nil {
+		t.Fatalf("ParseCDP() error = %v", err)
+	}
+
+	if n.Hostname != "host01" {
+		t.Errorf("Hostname = %q, want %q", n.Hostname, "host01")
+	}
+	if n.PortID != iface.Name {
+		t.Errorf("PortID = %q, want %q", n.PortID, iface.Name)
+	}
+	if n.Platform != "nbor" {
+		t.Errorf("Platform = %q, want %q", n.Platform, "nbor")
+	}
+	if n.Description != cfg.SystemDescription {
+		t.Errorf("Description = %q, want %q", n.Description, cfg.SystemDescription)
+	}
+	if !n.ManagementIP.Equal(net.ParseIP("192.168.1.10")) {
+		t.Errorf("ManagementIP = %v, want 192.168.1.10", n.ManagementIP)
+	}
+	if !hasCapabilities(n.Capabilities, types.CapRouter, types.CapSwitch) {
+		t.Errorf("Capabilities = %v, want Router and Switch", n.Capabilities)
+	}
+	if !bytes.Equal(n.SourceMAC, iface.MAC) {
+		t.Errorf("SourceMAC = %v, want %v", n.SourceMAC, iface.MAC)
+	}
+}
+
+func TestBuildCDPFramePlatform(t *testing.T) {
+	cfg := fixtureConfig()
+	cfg.PlatformString = "nbor/linux-amd64"
+
+	frame, err := BuildCDPFrame(&cfg, testInterface(), "host01")
+	if err != nil {
+		t.Fatalf("BuildCDPFrame() error = %v", err)
+	}
+
+	packet := gopacket.NewPacket(frame, layers.LayerTypeEthernet, gopacket.Default)
+	n, err := parser.ParseCDP(packet, "eth0")
+	if err != nil {
+		t.Fatalf("ParseCDP() error = %v", err)
+	}
+	if n.Platform != "nbor/linux-amd64" {
+		t.Errorf("Platform = %q, want %q", n.Platform, "nbor/linux-amd64")
+	}
+}
+
+func TestBuildCDPFrameNoMAC(t *testing.T) {
+	cfg := fixtureConfig()
+	iface := testInterface()
+	iface.MAC = nil
+
+	if _, err := BuildCDPFrame(&cfg, iface, "host01"); err == nil || !strings.Contains(err.Error(), "no MAC address") {
+		t.Errorf("BuildCDPFrame() error = %v, want no MAC address error", err)
+	}
+}
+
+func TestBuildCDPFrameExceedsMTU(t *testing.T) {
+	cfg := fixtureConfig()
+	cfg.SystemDescription = strings.Repeat("x", 400)
+	iface := testInterface()
+	iface.MTU = 256
+
+	if _, err := BuildCDPFrame(&cfg, iface, "host01"); err == nil || !strings.Contains(err.Error(), "MTU") {
+		t.Errorf("BuildCDPFrame() error = %v, want MTU error", err)
+	}
+}
+
+// hasCapabilities returns whether caps contains all of want
+func hasCapabilities(caps []types.Capability, want ...types.Capability) bool {
+	for _, w := range want {
+		found := false
+		for _, c := range caps {
+			if c == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
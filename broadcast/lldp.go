@@ -2,17 +2,34 @@ package broadcast
 
 import (
 	"encoding/binary"
+	"fmt"
 	"net"
+	"sort"
 
 	"nbor/config"
 	"nbor/protocol"
 	"nbor/types"
 )
 
+// Default maximum LLDPDU size in bytes when neither config nor MTU set a limit
+const defaultLLDPMaxPayload = 1500
+
+// Maximum value length of a single LLDP TLV (9-bit length field)
+const maxLLDPTLVValue = 511
+
 // BuildLLDPFrame builds a complete LLDP frame ready for transmission
-func BuildLLDPFrame(cfg *config.Config, iface *types.InterfaceInfo, systemName string) ([]byte, error) {
+// Optional TLVs are dropped if the frame would exceed the configured size limit or
+// the interface MTU; the returned warnings describe anything that was trimmed
+func BuildLLDPFrame(cfg *config.Config, iface *types.InterfaceInfo, systemName string) ([]byte, []string, error) {
+	if err := validateSourceMAC(iface); err != nil {
+		return nil, nil, err
+	}
+
 	// Build LLDP payload (TLVs)
-	lldpPayload := buildLLDPPayload(cfg, iface, systemName)
+	lldpPayload, warnings, err := buildLLDPPayload(cfg, iface, systemName)
+	if err != nil {
+		return nil, warnings, err
+	}
 
 	// Build complete frame
 	// Ethernet header (14 bytes) + LLDP payload
@@ -32,12 +49,25 @@ func BuildLLDPFrame(cfg *config.Config, iface *types.InterfaceInfo, systemName s
 	// LLDP payload
 	copy(frame[offset:], lldpPayload)
 
-	return frame, nil
+	if err := checkFrameMTU("LLDP", frame, iface); err != nil {
+		return nil, warnings, err
+	}
+
+	return frame, warnings, nil
+}
+
+// lldpOptionalTLV is an encoded optional TLV that may be trimmed to fit the frame
+type lldpOptionalTLV struct {
+	name      string
+	encoded   []byte
+	trimOrder int // Lower values are dropped first
+	dropped   bool
 }
 
-// buildLLDPPayload builds the LLDP TLVs
-func buildLLDPPayload(cfg *config.Config, iface *types.InterfaceInfo, systemName string) []byte {
+// buildLLDPPayload builds the LLDP TLVs, trimming optional TLVs if needed
+func buildLLDPPayload(cfg *config.Config, iface *types.InterfaceInfo, systemName string) ([]byte, []string, error) {
 	var payload []byte
+	var warnings []string
 
 	// Mandatory TLV: Chassis ID (using MAC address)
 	chassisIDData := make([]byte, 1+6)
@@ -56,44 +86,125 @@ func buildLLDPPayload(cfg *config.Config, iface *types.InterfaceInfo, systemName
 	binary.BigEndian.PutUint16(ttlData, uint16(cfg.TTL))
 	payload = append(payload, encodeLLDPTLV(protocol.LLDPTLVTTL, ttlData)...)
 
+	// Optional TLVs in transmission order
+	var optional []*lldpOptionalTLV
+
 	// Optional TLV: Port Description
-	payload = append(payload, encodeLLDPTLV(protocol.LLDPTLVPortDesc, []byte(iface.Name))...)
+	optional = append(optional, &lldpOptionalTLV{
+		name:      "port description",
+		encoded:   encodeLLDPTLV(protocol.LLDPTLVPortDesc, []byte(iface.Name)),
+		trimOrder: 1,
+	})
 
 	// Optional TLV: System Name
-	payload = append(payload, encodeLLDPTLV(protocol.LLDPTLVSystemName, []byte(systemName))...)
+	if len(systemName) > maxLLDPTLVValue {
+		warnings = append(warnings, fmt.Sprintf("LLDP system name truncated to %d bytes", maxLLDPTLVValue))
+	}
+	optional = append(optional, &lldpOptionalTLV{
+		name:      "system name",
+		encoded:   encodeLLDPTLV(protocol.LLDPTLVSystemName, []byte(systemName)),
+		trimOrder: 4,
+	})
 
 	// Optional TLV: System Description
 	description := cfg.SystemDescription
 	if description == "" {
 		description = "nbor network neighbor discovery tool"
 	}
-	payload = append(payload, encodeLLDPTLV(protocol.LLDPTLVSystemDesc, []byte(description))...)
+	// LLDP has no platform TLV, so a configured platform goes in the description
+	if cfg.PlatformString != "" {
+		description += " (" + cfg.PlatformString + ")"
+	}
+	if len(description) > maxLLDPTLVValue {
+		warnings = append(warnings, fmt.Sprintf("LLDP system description truncated to %d bytes", maxLLDPTLVValue))
+	}
+	optional = append(optional, &lldpOptionalTLV{
+		name:      "system description",
+		encoded:   encodeLLDPTLV(protocol.LLDPTLVSystemDesc, []byte(description)),
+		trimOrder: 0,
+	})
 
 	// Optional TLV: System Capabilities
 	capBits := protocol.BuildLLDPCapabilities(cfg.Capabilities)
 	capData := make([]byte, 4)
 	binary.BigEndian.PutUint16(capData[0:2], capBits) // System capabilities
 	binary.BigEndian.PutUint16(capData[2:4], capBits) // Enabled capabilities
-	payload = append(payload, encodeLLDPTLV(protocol.LLDPTLVSystemCap, capData)...)
+	optional = append(optional, &lldpOptionalTLV{
+		name:      "system capabilities",
+		encoded:   encodeLLDPTLV(protocol.LLDPTLVSystemCap, capData),
+		trimOrder: 3,
+	})
 
 	// Optional TLV: Management Address (if interface has IP)
-	if len(iface.IPv4Addrs) > 0 {
-		mgmtData := encodeLLDPMgmtAddress(iface.IPv4Addrs[0], iface.Name)
-		payload = append(payload, encodeLLDPTLV(protocol.LLDPTLVMgmtAddress, mgmtData)...)
+	// IPv4 is preferred; IPv6-only segments advertise the first IPv6 address
+	if mgmtIP := lldpMgmtIP(iface); mgmtIP != nil {
+		mgmtData := encodeLLDPMgmtAddress(mgmtIP, iface.Name)
+		optional = append(optional, &lldpOptionalTLV{
+			name:      "management address",
+			encoded:   encodeLLDPTLV(protocol.LLDPTLVMgmtAddress, mgmtData),
+			trimOrder: 2,
+		})
+	}
+
+	// Total size including the End TLV
+	maxSize := lldpMaxPayloadSize(cfg, iface)
+	size := len(payload) + 2
+	for _, tlv := range optional {
+		size += len(tlv.encoded)
+	}
+
+	// Drop optional TLVs in trim order until the payload fits
+	if size > maxSize {
+		byOrder := make([]*lldpOptionalTLV, len(optional))
+		copy(byOrder, optional)
+		sort.Slice(byOrder, func(i, j int) bool {
+			return byOrder[i].trimOrder < byOrder[j].trimOrder
+		})
+		for _, tlv := range byOrder {
+			if size <= maxSize {
+				break
+			}
+			tlv.dropped = true
+			size -= len(tlv.encoded)
+			warnings = append(warnings, fmt.Sprintf("LLDP %s dropped to fit %d byte limit", tlv.name, maxSize))
+		}
+	}
+
+	// Mandatory TLVs alone are too large (very long interface name or tiny limit)
+	if size > maxSize {
+		return nil, warnings, fmt.Errorf("LLDP frame of %d bytes exceeds %d byte limit", size, maxSize)
+	}
+
+	for _, tlv := range optional {
+		if !tlv.dropped {
+			payload = append(payload, tlv.encoded...)
+		}
 	}
 
 	// End TLV (type 0, length 0)
 	payload = append(payload, 0x00, 0x00)
 
-	return payload
+	return payload, warnings, nil
+}
+
+// lldpMaxPayloadSize returns the LLDPDU size limit from config, capped by the interface MTU
+func lldpMaxPayloadSize(cfg *config.Config, iface *types.InterfaceInfo) int {
+	maxSize := cfg.LLDPMaxFrameSize
+	if maxSize <= 0 {
+		maxSize = defaultLLDPMaxPayload
+	}
+	if iface.MTU > 0 && iface.MTU < maxSize {
+		maxSize = iface.MTU
+	}
+	return maxSize
 }
 
 // encodeLLDPTLV encodes an LLDP TLV
 // LLDP TLV format: Type (7 bits) + Length (9 bits) = 2 bytes header + Value
 func encodeLLDPTLV(tlvType uint8, value []byte) []byte {
 	length := len(value)
-	if length > 511 {
-		length = 511 // Max length is 9 bits
+	if length > maxLLDPTLVValue {
+		length = maxLLDPTLVValue // Max length is 9 bits
 	}
 
 	// Pack type (7 bits) and length (9 bits) into 2 bytes
@@ -105,28 +216,47 @@ func encodeLLDPTLV(tlvType uint8, value []byte) []byte {
 	return tlv
 }
 
+// lldpMgmtIP returns the address advertised in the management address TLV:
+// the first IPv4 address, else the first IPv6 address, else nil
+func lldpMgmtIP(iface *types.InterfaceInfo) net.IP {
+	if len(iface.IPv4Addrs) > 0 {
+		return iface.IPv4Addrs[0]
+	}
+	if len(iface.IPv6Addrs) > 0 {
+		return iface.IPv6Addrs[0]
+	}
+	return nil
+}
+
 // encodeLLDPMgmtAddress encodes the management address TLV data
 func encodeLLDPMgmtAddress(ip net.IP, ifaceName string) []byte {
-	ipv4 := ip.To4()
-	if ipv4 == nil {
+	// Address subtype from the IANA address family numbers: 1 = IPv4, 2 = IPv6
+	var addr []byte
+	var subtype byte
+	if ipv4 := ip.To4(); ipv4 != nil {
+		addr, subtype = ipv4, 1
+	} else if ipv6 := ip.To16(); ipv6 != nil {
+		addr, subtype = ipv6, 2
+	} else {
 		return nil
 	}
 
 	// Management address TLV format:
 	// Address string length (1 byte) = 1 + IP length
-	// Address subtype (1 byte): 1 = IPv4
-	// Address (4 bytes for IPv4)
+	// Address subtype (1 byte): 1 = IPv4, 2 = IPv6
+	// Address (4 bytes for IPv4, 16 for IPv6)
 	// Interface numbering subtype (1 byte): 2 = ifIndex
 	// Interface number (4 bytes)
 	// OID string length (1 byte): 0
 
-	data := make([]byte, 12)
-	data[0] = 5                   // Address string length (1 subtype + 4 IP bytes)
-	data[1] = 1                   // Address subtype (IPv4)
-	copy(data[2:6], ipv4)         // IP address
-	data[6] = 2                   // Interface numbering subtype (ifIndex)
-	binary.BigEndian.PutUint32(data[7:11], 1) // Interface number (use 1)
-	data[11] = 0                  // OID string length
+	n := len(addr)
+	data := make([]byte, 8+n)
+	data[0] = byte(1 + n)                        // Address string length (subtype + IP bytes)
+	data[1] = subtype                            // Address subtype
+	copy(data[2:2+n], addr)                      // IP address
+	data[2+n] = 2                                // Interface numbering subtype (ifIndex)
+	binary.BigEndian.PutUint32(data[3+n:7+n], 1) // Interface number (use 1)
+	data[7+n] = 0                                // OID string length
 
 	return data
 }
@@ -0,0 +1,258 @@
+package broadcast
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"nbor/config"
+	"nbor/parser"
+	"nbor/types"
+)
+
+func testInterface() *types.InterfaceInfo {
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	return &types.InterfaceInfo{
+		Name:      "eth0",
+		MAC:       mac,
+		MTU:       1500,
+		IPv4Addrs: []net.IP{net.ParseIP("192.168.1.10").To4()},
+	}
+}
+
+// decodeLLDP decodes a frame and returns the LLDP info layer, failing on any decode error
+func decodeLLDP(t *testing.T, frame []byte) *layers.LinkLayerDiscoveryInfo {
+	t.Helper()
+	packet := gopacket.NewPacket(frame, layers.LayerTypeEthernet, gopacket.Default)
+	if errLayer := packet.ErrorLayer(); errLayer != nil {
+		t.Fatalf("frame failed to decode: %v", errLayer.Error())
+	}
+	if packet.Layer(layers.LayerTypeLinkLayerDiscovery) == nil {
+		t.Fatal("frame has no LLDP layer")
+	}
+	infoLayer := packet.Layer(layers.LayerTypeLinkLayerDiscoveryInfo)
+	if infoLayer == nil {
+		t.Fatal("frame has no LLDP info layer")
+	}
+	return infoLayer.(*layers.LinkLayerDiscoveryInfo)
+}
+
+// lldpFixture is the expected frame for fixtureConfig, testInterface and "host01"
+const lldpFixture = `
+	0180c200000e 001122334455 88cc
+	0207 04 001122334455
+	0405 05 65746830
+	0602 00b4
+	0804 65746830
+	0a06 686f73743031
+	0c0b 74657374207377697463 68
+	0e04 0014 0014
+	100c 05 01 c0a8010a 02 00000001 00
+	0000`
+
+func TestBuildLLDPFrameFixture(t *testing.T) {
+	cfg := fixtureConfig()
+
+	frame, warnings, err := BuildLLDPFrame(&cfg, testInterface(), "host01")
+	if err != nil {
+		t.Fatalf("BuildLLDPFrame() error = %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("BuildLLDPFrame() warnings = %v, want none", warnings)
+	}
+
+	want := mustDecodeHex(t, lldpFixture)
+	if !bytes.Equal(frame, want) {
+		t.Errorf("BuildLLDPFrame() =\n%x\nwant\n%x", frame, want)
+	}
+}
+
+func TestBuildLLDPFrameRoundTrip(t *testing.T) {
+	cfg := fixtureConfig()
+	iface := testInterface()
+
+	frame, _, err := BuildLLDPFrame(&cfg, iface, "host01")
+	if err != nil {
+		t.Fatalf("BuildLLDPFrame() error = %v", err)
+	}
+
+	packet := gopacket.NewPacket(frame, layers.LayerTypeEthernet, gopacket.Default)
+	n, err := parser.ParseLLDP(packet, "eth0")
+	if err != nil {
+		t.Fatalf("ParseLLDP() error = %v", err)
+	}
+
+	if n.ID != iface.MAC.String() {
+		t.Errorf("ID = %q, want chassis MAC %q", n.ID, iface.MAC.String())
+	}
+	if n.Hostname != "host01" {
+		t.Errorf("Hostname = %q, want %q", n.Hostname, "host01")
+	}
+	if n.PortID != iface.Name {
+		t.Errorf("PortID = %q, want %q", n.PortID, iface.Name)
+	}
+	if n.Description != cfg.SystemDescription {
+		t.Errorf("Description = %q, want %q", n.Description, cfg.SystemDescription)
+	}
+	if !n.ManagementIP.Equal(net.ParseIP("192.168.1.10")) {
+		t.Errorf("ManagementIP = %v, want 192.168.1.10", n.ManagementIP)
+	}
+	if !hasCapabilities(n.Capabilities, types.CapRouter, types.CapBridge) {
+		t.Errorf("Capabilities = %v, want Router and Bridge", n.Capabilities)
+	}
+}
+
+func TestEncodeLLDPMgmtAddressIPv6(t *testing.T) {
+	ip := net.ParseIP("2001:db8::1")
+	data := encodeLLDPMgmtAddress(ip, "eth0")
+
+	if len(data) != 24 {
+		t.Fatalf("len = %d, want 24", len(data))
+	}
+	if data[0] != 17 {
+		t.Errorf("address string length = %d, want 17", data[0])
+	}
+	if data[1] != 2 {
+		t.Errorf("address subtype = %d, want 2 (IPv6)", data[1])
+	}
+	if !bytes.Equal(data[2:18], ip.To16()) {
+		t.Errorf("address = % x, want % x", data[2:18], ip.To16())
+	}
+	if data[18] != 2 {
+		t.Errorf("interface numbering subtype = %d, want 2 (ifIndex)", data[18])
+	}
+	if data[23] != 0 {
+		t.Errorf("OID string length = %d, want 0", data[23])
+	}
+}
+
+func TestBuildLLDPFrameIPv6Only(t *testing.T) {
+	cfg := fixtureConfig()
+	iface := testInterface()
+	iface.IPv4Addrs = nil
+	iface.IPv6Addrs = []net.IP{net.ParseIP("2001:db8::10")}
+
+	frame, _, err := BuildLLDPFrame(&cfg, iface, "host01")
+	if err != nil {
+		t.Fatalf("BuildLLDPFrame() error = %v", err)
+	}
+
+	packet := gopacket.NewPacket(frame, layers.LayerTypeEthernet, gopacket.Default)
+	n, err := parser.ParseLLDP(packet, "eth0")
+	if err != nil {
+		t.Fatalf("ParseLLDP() error = %v", err)
+	}
+	if !n.ManagementIP.Equal(net.ParseIP("2001:db8::10")) {
+		t.Errorf("ManagementIP = %v, want 2001:db8::10", n.ManagementIP)
+	}
+}
+
+func TestBuildLLDPFramePlatform(t *testing.T) {
+	cfg := fixtureConfig()
+	cfg.PlatformString = "nbor/linux-amd64"
+
+	frame, _, err := BuildLLDPFrame(&cfg, testInterface(), "host01")
+	if err != nil {
+		t.Fatalf("BuildLLDPFrame() error = %v", err)
+	}
+
+	info := decodeLLDP(t, frame)
+	want := cfg.SystemDescription + " (nbor/linux-amd64)"
+	if info.SysDescription != want {
+		t.Errorf("SysDescription = %q, want %q", info.SysDescription, want)
+	}
+}
+
+func TestBuildLLDPFrameDefault(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	frame, warnings, err := BuildLLDPFrame(&cfg, testInterface(), "host01")
+	if err != nil {
+		t.Fatalf("BuildLLDPFrame() error = %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("BuildLLDPFrame() warnings = %v, want none", warnings)
+	}
+
+	info := decodeLLDP(t, frame)
+	if info.SysName != "host01" {
+		t.Errorf("SysName = %q, want %q", info.SysName, "host01")
+	}
+	if len(info.MgmtAddress.Address) == 0 {
+		t.Error("management address missing")
+	}
+}
+
+func TestBuildLLDPFrameTrimsLongDescription(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SystemDescription = strings.Repeat("x", 2000)
+	cfg.LLDPMaxFrameSize = 200
+
+	frame, warnings, err := BuildLLDPFrame(&cfg, testInterface(), "host01")
+	if err != nil {
+		t.Fatalf("BuildLLDPFrame() error = %v", err)
+	}
+	if len(frame)-14 > cfg.LLDPMaxFrameSize {
+		t.Errorf("LLDPDU is %d bytes, want <= %d", len(frame)-14, cfg.LLDPMaxFrameSize)
+	}
+	if len(warnings) == 0 {
+		t.Error("expected warnings about trimmed TLVs")
+	}
+
+	// Description is dropped before the management address
+	info := decodeLLDP(t, frame)
+	if info.SysDescription != "" {
+		t.Errorf("SysDescription length = %d, want dropped", len(info.SysDescription))
+	}
+	if len(info.MgmtAddress.Address) == 0 {
+		t.Error("management address dropped, want kept")
+	}
+	if info.SysName != "host01" {
+		t.Errorf("SysName = %q, want %q", info.SysName, "host01")
+	}
+}
+
+func TestBuildLLDPFrameRespectsMTU(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SystemDescription = strings.Repeat("x", 400)
+	iface := testInterface()
+	iface.MTU = 150
+
+	frame, warnings, err := BuildLLDPFrame(&cfg, iface, "host01")
+	if err != nil {
+		t.Fatalf("BuildLLDPFrame() error = %v", err)
+	}
+	if len(frame)-14 > iface.MTU {
+		t.Errorf("LLDPDU is %d bytes, want <= MTU %d", len(frame)-14, iface.MTU)
+	}
+	if len(warnings) == 0 {
+		t.Error("expected warnings about trimmed TLVs")
+	}
+	decodeLLDP(t, frame)
+}
+
+func TestBuildLLDPFrameBadMAC(t *testing.T) {
+	cfg := fixtureConfig()
+	for _, mac := range []net.HardwareAddr{nil, {0, 1, 2, 3}} {
+		iface := testInterface()
+		iface.MAC = mac
+		if _, _, err := BuildLLDPFrame(&cfg, iface, "host01"); err == nil {
+			t.Errorf("BuildLLDPFrame() with MAC %v error = nil, want error", mac)
+		}
+	}
+}
+
+func TestBuildLLDPFrameTooSmall(t *testing.T) {
+	cfg := config.DefaultConfig()
+	iface := testInterface()
+	iface.Name = strings.Repeat("n", 300)
+	iface.MTU = 128
+
+	if _, _, err := BuildLLDPFrame(&cfg, iface, "host01"); err == nil {
+		t.Error("BuildLLDPFrame() error = nil, want error when mandatory TLVs exceed limit")
+	}
+}
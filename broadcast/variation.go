@@ -0,0 +1,86 @@
+package broadcast
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"nbor/types"
+)
+
+// varyIdentity returns a copy of the interface and system name with a distinct
+// device ID and source MAC for one broadcast interval
+// "counter" appends seq to the name and embeds it in the MAC; "random" uses random
+// values. The MAC is always locally administered unicast so it can't collide with
+// a real vendor address. Any other mode returns the inputs unchanged
+func varyIdentity(mode string, seq uint32, iface *types.InterfaceInfo, systemName string) (*types.InterfaceInfo, string) {
+	// Without a real Ethernet MAC to derive from there is nothing to vary
+	if len(iface.MAC) != 6 {
+		return iface, systemName
+	}
+	mac := make(net.HardwareAddr, 6)
+	copy(mac, iface.MAC)
+
+	switch mode {
+	case "counter":
+		mac[3] = byte(seq >> 16)
+		mac[4] = byte(seq >> 8)
+		mac[5] = byte(seq)
+		systemName = fmt.Sprintf("%s-%d", systemName, seq)
+	case "random":
+		var suffix [3]byte
+		_, _ = rand.Read(suffix[:])
+		copy(mac[3:], suffix[:])
+		systemName = fmt.Sprintf("%s-%x", systemName, suffix)
+	default:
+		return iface, systemName
+	}
+
+	// Locally administered, unicast
+	mac[0] = (mac[0] | 0x02) &^ 0x01
+
+	varied := *iface
+	varied.MAC = mac
+	return &varied, systemName
+}
+
+// IsOwnMAC reports whether mac is base or, with varied set (broadcast_variation
+// on), a MAC varyIdentity derived from it: base's second and third bytes under
+// its locally administered first byte. Without variation only base matches, so
+// a virtual lab sharing a locally administered prefix with us stays visible
+func IsOwnMAC(base, mac net.HardwareAddr, varied bool) bool {
+	if len(base) != 6 || len(mac) != 6 {
+		return false
+	}
+	if bytes.Equal(base, mac) {
+		return true
+	}
+	return varied && mac[0] == (base[0]|0x02)&^0x01 && mac[1] == base[1] && mac[2] == base[2]
+}
+
+// IsOwnName reports whether name is systemName or, with varied set, a name
+// varyIdentity derived from it: systemName followed by "-" and a counter or six
+// hex digits
+func IsOwnName(systemName, name string, varied bool) bool {
+	if name == "" {
+		return false
+	}
+	if name == systemName {
+		return true
+	}
+	if !varied {
+		return false
+	}
+	suffix, ok := strings.CutPrefix(name, systemName+"-")
+	if !ok || suffix == "" {
+		return false
+	}
+	if _, err := strconv.ParseUint(suffix, 10, 32); err == nil {
+		return true
+	}
+	_, err := strconv.ParseUint(suffix, 16, 32)
+	return len(suffix) == 6 && err == nil
+}
@@ -0,0 +1,96 @@
+package broadcast
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+func TestVaryIdentity(t *testing.T) {
+	iface := testInterface()
+
+	t.Run("off leaves identity unchanged", func(t *testing.T) {
+		got, name := varyIdentity("off", 1, iface, "host01")
+		if got != iface || name != "host01" {
+			t.Errorf("varyIdentity(off) = %v, %q, want original interface and name", got.MAC, name)
+		}
+	})
+
+	t.Run("no MAC leaves identity unchanged", func(t *testing.T) {
+		noMAC := testInterface()
+		noMAC.MAC = nil
+		got, name := varyIdentity("counter", 1, noMAC, "host01")
+		if got != noMAC || name != "host01" {
+			t.Errorf("varyIdentity(counter) without MAC = %v, %q, want original interface and name", got.MAC, name)
+		}
+	})
+
+	t.Run("counter embeds sequence", func(t *testing.T) {
+		got, name := varyIdentity("counter", 0x010203, iface, "host01")
+		if name != "host01-66051" {
+			t.Errorf("name = %q, want %q", name, "host01-66051")
+		}
+		want := []byte{0x02, 0x11, 0x22, 0x01, 0x02, 0x03}
+		if !bytes.Equal(got.MAC, want) {
+			t.Errorf("MAC = %v, want %x", got.MAC, want)
+		}
+		if !bytes.Equal(iface.MAC, []byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}) {
+			t.Errorf("original MAC modified: %v", iface.MAC)
+		}
+	})
+
+	t.Run("random is locally administered unicast", func(t *testing.T) {
+		a, nameA := varyIdentity("random", 1, iface, "host01")
+		b, nameB := varyIdentity("random", 2, iface, "host01")
+		for _, mac := range [][]byte{a.MAC, b.MAC} {
+			if mac[0]&0x02 == 0 || mac[0]&0x01 != 0 {
+				t.Errorf("MAC %x is not locally administered unicast", mac)
+			}
+		}
+		if bytes.Equal(a.MAC, b.MAC) && nameA == nameB {
+			t.Errorf("random identities repeated: %v %q", a.MAC, nameA)
+		}
+	})
+}
+
+func TestIsOwnIdentity(t *testing.T) {
+	iface := testInterface()
+	for _, mode := range []string{"counter", "random"} {
+		for seq := uint32(1); seq <= 3; seq++ {
+			varied, name := varyIdentity(mode, seq, iface, "host01")
+			if !IsOwnMAC(iface.MAC, varied.MAC, true) {
+				t.Errorf("%s: IsOwnMAC(%v) = false for a varied MAC", mode, varied.MAC)
+			}
+			if !IsOwnName("host01", name, true) {
+				t.Errorf("%s: IsOwnName(%q) = false for a varied name", mode, name)
+			}
+		}
+	}
+
+	if !IsOwnMAC(iface.MAC, iface.MAC, false) || !IsOwnName("host01", "host01", false) {
+		t.Error("the unvaried identity isn't recognized")
+	}
+	other, _ := net.ParseMAC("00:11:23:33:44:55")
+	if IsOwnMAC(iface.MAC, other, true) {
+		t.Errorf("IsOwnMAC(%v) = true for another device", other)
+	}
+	for _, name := range []string{"", "host02", "host01-core", "host01-", "host01.example.net"} {
+		if IsOwnName("host01", name, true) {
+			t.Errorf("IsOwnName(%q) = true, want false", name)
+		}
+	}
+
+	// Without broadcast_variation only the exact identity is ours
+	for _, name := range []string{"host01-2", "host01-abc123"} {
+		if IsOwnName("host01", name, false) {
+			t.Errorf("IsOwnName(%q) = true with variation off, want false", name)
+		}
+	}
+	// A locally administered base MAC (here QEMU's 52:54:00) shares its first
+	// bytes with every other VM in the lab
+	base, _ := net.ParseMAC("52:54:00:12:34:56")
+	vm, _ := net.ParseMAC("52:54:00:ab:cd:ef")
+	if IsOwnMAC(base, vm, false) {
+		t.Errorf("IsOwnMAC(%v, %v) = true with variation off, want false", base, vm)
+	}
+}
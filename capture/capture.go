@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/google/gopacket"
@@ -16,10 +17,106 @@ import (
 var (
 	// CDP multicast address
 	CDPMulticast = net.HardwareAddr{0x01, 0x00, 0x0c, 0xcc, 0xcc, 0xcc}
-	// LLDP multicast address
+	// LLDP multicast address (nearest bridge)
 	LLDPMulticast = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x0e}
+	// LLDP nearest non-TPMR bridge and nearest customer bridge addresses, which
+	// some switches send to instead; STP also uses the customer bridge address
+	LLDPNonTPMRMulticast        = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x03}
+	LLDPCustomerBridgeMulticast = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x00}
 )
 
+// errorBackoff is how long the capture loop waits after a read error, so an
+// interface that has gone down doesn't spin the CPU until it comes back
+const errorBackoff = 200 * time.Millisecond
+
+// CDPFilter is the BPF filter that captures only CDP frames
+const CDPFilter = "ether dst 01:00:0c:cc:cc:cc"
+
+// LLDPFilter is the BPF filter that captures only LLDP frames
+// LLDP is matched on all three of its group addresses; on the two it shares with
+// other protocols only the LLDP EtherType is let through
+const LLDPFilter = "ether dst 01:80:c2:00:00:0e or " +
+	"((ether dst 01:80:c2:00:00:03 or ether dst 01:80:c2:00:00:00) and ether proto 0x88cc)"
+
+// DefaultFilter is the BPF filter that captures only CDP and LLDP frames
+const DefaultFilter = CDPFilter + " or " + LLDPFilter
+
+// NoFramesFilter matches no Ethernet frame (every one is longer than 1 byte),
+// for when neither protocol is listened for
+const NoFramesFilter = "less 1"
+
+// DefaultSnapLen captures whole frames, including jumbo LLDPDUs
+const DefaultSnapLen = 65535
+
+// ListenFilter returns the built-in filter for the protocols being listened for,
+// so frames of a protocol that's turned off never leave the kernel
+func ListenFilter(cdp, lldp bool) string {
+	switch {
+	case cdp && lldp:
+		return DefaultFilter
+	case cdp:
+		return CDPFilter
+	case lldp:
+		return LLDPFilter
+	}
+	return NoFramesFilter
+}
+
+// Filter returns the BPF filter to capture with: custom if set, otherwise the
+// ListenFilter for the cdp and lldp listen settings. A non-empty extra expression
+// is ANDed onto it, so it can only narrow the capture
+func Filter(custom, extra string, cdp, lldp bool) string {
+	filter := ListenFilter(cdp, lldp)
+	if strings.TrimSpace(custom) != "" {
+		filter = custom
+	}
+	if strings.TrimSpace(extra) != "" {
+		filter = "(" + filter + ") and (" + extra + ")"
+	}
+	return filter
+}
+
+// OpenHandle opens device for promiscuous capture with the given snapshot length
+// (0 = DefaultSnapLen) and kernel buffer size in MB (0 = the OS default)
+// A larger buffer keeps frames from being dropped on busy links
+func OpenHandle(device string, snapLen, bufferMB int) (*pcap.Handle, error) {
+	inactive, err := pcap.NewInactiveHandle(device)
+	if err != nil {
+		return nil, err
+	}
+	defer inactive.CleanUp()
+
+	if snapLen <= 0 {
+		snapLen = DefaultSnapLen
+	}
+	if err := inactive.SetSnapLen(snapLen); err != nil {
+		return nil, fmt.Errorf("failed to set snapshot length: %w", err)
+	}
+	if err := inactive.SetPromisc(true); err != nil {
+		return nil, fmt.Errorf("failed to set promiscuous mode: %w", err)
+	}
+	// Use 100ms timeout instead of BlockForever to allow clean shutdown on Linux
+	if err := inactive.SetTimeout(100 * time.Millisecond); err != nil {
+		return nil, fmt.Errorf("failed to set read timeout: %w", err)
+	}
+	if bufferMB > 0 {
+		if err := inactive.SetBufferSize(bufferMB * 1024 * 1024); err != nil {
+			return nil, fmt.Errorf("failed to set capture buffer size: %w", err)
+		}
+	}
+
+	return inactive.Activate()
+}
+
+// ValidateFilter checks that a BPF expression compiles for Ethernet capture
+// Used to report a bad custom filter before any interface is opened
+func ValidateFilter(expr string) error {
+	if _, err := pcap.CompileBPFFilter(layers.LinkTypeEthernet, 65535, expr); err != nil {
+		return fmt.Errorf("invalid BPF filter %q: %w", expr, err)
+	}
+	return nil
+}
+
 // ErrInterfaceNotFound is returned when the specified interface doesn't exist
 var ErrInterfaceNotFound = errors.New("interface not found")
 
@@ -51,18 +148,14 @@ func NewCapturer(ifaceName string) (*Capturer, error) {
 		}
 	}
 
-	// Open pcap handle
-	// Snapshot length of 65535 to capture full packets
-	// Promiscuous mode to see all packets
-	// Use 100ms timeout instead of BlockForever to allow clean shutdown on Linux
-	handle, err := pcap.OpenLive(ifaceName, 65535, true, 100*time.Millisecond)
+	// Open pcap handle with full-frame snapshots in promiscuous mode
+	handle, err := OpenHandle(ifaceName, DefaultSnapLen, 0)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open interface %s: %w", ifaceName, err)
 	}
 
 	// Set BPF filter to only capture CDP and LLDP packets
-	filter := "ether dst 01:00:0c:cc:cc:cc or ether dst 01:80:c2:00:00:0e"
-	if err := handle.SetBPFFilter(filter); err != nil {
+	if err := handle.SetBPFFilter(DefaultFilter); err != nil {
 		handle.Close()
 		return nil, fmt.Errorf("failed to set BPF filter: %w", err)
 	}
@@ -104,11 +197,17 @@ func (c *Capturer) Start() <-chan gopacket.Packet {
 			default:
 				packet, err := packetSource.NextPacket()
 				if err != nil {
+					// Timeouts are normal; other errors (e.g. the interface went down)
+					// tend to repeat, so back off before retrying
+					wait := time.Duration(0)
+					if err != pcap.NextErrorTimeoutExpired {
+						wait = errorBackoff
+					}
 					// Check if we're stopping
 					select {
 					case <-c.stop:
 						return
-					default:
+					case <-time.After(wait):
 						continue
 					}
 				}
@@ -155,14 +254,21 @@ func IsCDPPacket(packet gopacket.Packet) bool {
 	return eth.DstMAC.String() == CDPMulticast.String()
 }
 
-// IsLLDPPacket checks if a packet is destined for the LLDP multicast address
+// IsLLDPPacket checks if a packet is destined for the LLDP multicast address, or
+// is an LLDP frame sent to the non-TPMR or customer bridge address
 func IsLLDPPacket(packet gopacket.Packet) bool {
 	ethLayer := packet.Layer(layers.LayerTypeEthernet)
 	if ethLayer == nil {
 		return false
 	}
 	eth := ethLayer.(*layers.Ethernet)
-	return eth.DstMAC.String() == LLDPMulticast.String()
+	switch eth.DstMAC.String() {
+	case LLDPMulticast.String():
+		return true
+	case LLDPNonTPMRMulticast.String(), LLDPCustomerBridgeMulticast.String():
+		return eth.EthernetType == layers.EthernetTypeLinkLayerDiscovery
+	}
+	return false
 }
 
 // GetSourceMAC extracts the source MAC address from a packet
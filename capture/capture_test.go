@@ -0,0 +1,104 @@
+package capture
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		custom string
+		extra  string
+		want   string
+	}{
+		{"built-in", "", "", DefaultFilter},
+		{"custom replaces built-in", "ether proto 0x88cc", "", "ether proto 0x88cc"},
+		{"extra narrows built-in", "", "ether src 00:11:22:33:44:55", "(" + DefaultFilter + ") and (ether src 00:11:22:33:44:55)"},
+		{"extra narrows custom", "ether proto 0x88cc", "vlan", "(ether proto 0x88cc) and (vlan)"},
+		{"blank extra ignored", "", "  ", DefaultFilter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Filter(tt.custom, tt.extra, true, true); got != tt.want {
+				t.Errorf("Filter(%q, %q) = %q, want %q", tt.custom, tt.extra, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListenFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		cdp, lldp bool
+		want      string
+		excludes  string // A destination MAC the filter must not match
+	}{
+		{"both", true, true, DefaultFilter, ""},
+		{"CDP only", true, false, CDPFilter, LLDPMulticast.String()},
+		{"LLDP only", false, true, LLDPFilter, CDPMulticast.String()},
+		{"neither", false, false, NoFramesFilter, CDPMulticast.String()},
+	}
+	for _, tt := range tests {
+		got := ListenFilter(tt.cdp, tt.lldp)
+		if got != tt.want {
+			t.Errorf("%s: ListenFilter = %q, want %q", tt.name, got, tt.want)
+		}
+		if tt.excludes != "" && strings.Contains(got, tt.excludes) {
+			t.Errorf("%s: ListenFilter %q still captures %s", tt.name, got, tt.excludes)
+		}
+	}
+
+	// A custom filter is used as written, whatever is listened for
+	if got := Filter("ether proto 0x88cc", "", true, false); got != "ether proto 0x88cc" {
+		t.Errorf("Filter with a custom filter and CDP only = %q, want the custom filter", got)
+	}
+	if got := Filter("", "vlan", false, true); got != "("+LLDPFilter+") and (vlan)" {
+		t.Errorf("Filter with LLDP only and extra = %q", got)
+	}
+}
+
+func TestDefaultFilterGroupAddresses(t *testing.T) {
+	for _, mac := range []net.HardwareAddr{CDPMulticast, LLDPMulticast, LLDPNonTPMRMulticast, LLDPCustomerBridgeMulticast} {
+		if !strings.Contains(DefaultFilter, mac.String()) {
+			t.Errorf("DefaultFilter %q doesn't capture %s", DefaultFilter, mac)
+		}
+	}
+}
+
+func TestIsLLDPPacket(t *testing.T) {
+	lldp := []byte{0x02, 0x07, 0x04, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x04, 0x02, 0x07, 0x31, 0x06, 0x02, 0x00, 0x78, 0x00, 0x00}
+	tests := []struct {
+		name      string
+		dst       net.HardwareAddr
+		etherType layers.EthernetType
+		want      bool
+	}{
+		{"nearest bridge", LLDPMulticast, layers.EthernetTypeLinkLayerDiscovery, true},
+		{"nearest non-TPMR bridge", LLDPNonTPMRMulticast, layers.EthernetTypeLinkLayerDiscovery, true},
+		{"nearest customer bridge", LLDPCustomerBridgeMulticast, layers.EthernetTypeLinkLayerDiscovery, true},
+		{"other protocol on customer bridge address", LLDPCustomerBridgeMulticast, layers.EthernetTypeIPv4, false},
+		{"unicast", net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x66}, layers.EthernetTypeLinkLayerDiscovery, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eth := &layers.Ethernet{
+				SrcMAC:       net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+				DstMAC:       tt.dst,
+				EthernetType: tt.etherType,
+			}
+			buf := gopacket.NewSerializeBuffer()
+			if err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{}, eth, gopacket.Payload(lldp)); err != nil {
+				t.Fatal(err)
+			}
+			packet := gopacket.NewPacket(buf.Bytes(), layers.LayerTypeEthernet, gopacket.Default)
+			if got := IsLLDPPacket(packet); got != tt.want {
+				t.Errorf("IsLLDPPacket() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,6 +8,14 @@ import (
 
 // ApplyOverrides applies CLI flag overrides to the config
 func ApplyOverrides(cfg *config.Config, opts Options) {
+	// Display overrides
+	if opts.ColorProfile != "" {
+		cfg.ColorProfile = opts.ColorProfile
+	}
+	if opts.Quiet {
+		cfg.BellOnNew = false
+	}
+
 	// Identity overrides
 	if opts.SystemName != "" {
 		cfg.SystemName = opts.SystemName
@@ -15,6 +23,9 @@ func ApplyOverrides(cfg *config.Config, opts Options) {
 	if opts.SystemDescription != "" {
 		cfg.SystemDescription = opts.SystemDescription
 	}
+	if opts.Platform != "" {
+		cfg.PlatformString = opts.Platform
+	}
 
 	// Listening overrides
 	if opts.CDPListen != nil {
@@ -23,6 +34,9 @@ func ApplyOverrides(cfg *config.Config, opts Options) {
 	if opts.LLDPListen != nil {
 		cfg.LLDPListen = *opts.LLDPListen
 	}
+	if opts.CaptureBPF != "" {
+		cfg.CaptureBPF = opts.CaptureBPF
+	}
 
 	// Broadcasting overrides
 	if opts.BroadcastAll {
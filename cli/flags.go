@@ -6,23 +6,32 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Options holds parsed command-line arguments
 type Options struct {
 	ThemeName         string
+	ColorProfile      string // auto, truecolor, 256, 16 or ascii
+	NoColor           bool   // Plain text for the listing commands and --tail
+	Quiet             bool   // No terminal bell on new neighbors
 	InterfaceName     string
 	ListThemes        bool
+	Profile           string // Config profile to apply ("" = none)
+	ListProfiles      bool
 	ListInterfaces    bool
 	ListAllInterfaces bool
+	WhyInterface      string // Explain filtering for this interface
 	ShowHelp          bool
 	ShowVersion       bool
 
 	// CDP/LLDP options
 	SystemName        string
 	SystemDescription string
+	Platform          string
 	CDPListen         *bool // nil = use config, true/false = override
 	LLDPListen        *bool
+	CaptureBPF        string // Custom capture filter ("" = use config)
 	CDPBroadcast      *bool
 	LLDPBroadcast     *bool
 	BroadcastAll      bool // --broadcast enables both
@@ -31,7 +40,33 @@ type Options struct {
 	Capabilities      string
 
 	// Interface selection
-	NoAutoSelect *bool // nil = use config, true/false = override
+	NoAutoSelect  *bool // nil = use config, true/false = override
+	AllInterfaces bool  // Capture on every usable interface at once
+
+	// Output modes
+	ShowFrame bool          // Print the CDP and LLDP frames that would be sent, then exit
+	Tail      bool          // Print one line per neighbor event instead of the TUI
+	JSON      bool          // Print one JSON object per neighbor instead of the TUI
+	Watch     bool          // With JSON, print a snapshot of all current neighbors every interval
+	Timeout   int           // Seconds before exiting a non-interactive mode (0 = run until Ctrl+C)
+	Duration  time.Duration // Like Timeout, as a duration such as 30s (0 = not set)
+
+	// Monitoring
+	MetricsAddr string // Serve Prometheus metrics on this address ("" = off)
+}
+
+// ColorDisabled reports whether plain output was asked for, with --no-color or
+// a non-empty NO_COLOR environment variable
+func (o Options) ColorDisabled() bool {
+	return o.NoColor || os.Getenv("NO_COLOR") != ""
+}
+
+// RunsTUI reports whether the options start the TUI, rather than a command
+// that prints something and exits
+func (o Options) RunsTUI() bool {
+	return !o.ShowHelp && !o.ShowVersion && !o.ListThemes && !o.ListProfiles &&
+		!o.ListInterfaces && !o.ListAllInterfaces && o.WhyInterface == "" &&
+		!o.ShowFrame && !o.Tail && !o.JSON
 }
 
 // ParseArgs parses command-line arguments
@@ -53,10 +88,49 @@ func ParseArgs() Options {
 			opts.ShowVersion = true
 		case arg == "--list-themes":
 			opts.ListThemes = true
+		case arg == "--profile":
+			if i+1 < len(args) {
+				i++
+				opts.Profile = args[i]
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a profile name\n", arg)
+				os.Exit(1)
+			}
+		case strings.HasPrefix(arg, "--profile="):
+			opts.Profile = strings.TrimPrefix(arg, "--profile=")
+		case arg == "--list-profiles":
+			opts.ListProfiles = true
 		case arg == "-l" || arg == "--list-interfaces":
 			opts.ListInterfaces = true
 		case arg == "--list-all-interfaces":
 			opts.ListAllInterfaces = true
+		case arg == "--color-profile":
+			if i+1 < len(args) {
+				i++
+				opts.ColorProfile = args[i]
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a profile (auto, truecolor, 256, 16, ascii)\n", arg)
+				os.Exit(1)
+			}
+		case strings.HasPrefix(arg, "--color-profile="):
+			opts.ColorProfile = strings.TrimPrefix(arg, "--color-profile=")
+		case arg == "--no-color":
+			opts.NoColor = true
+		case arg == "--quiet":
+			opts.Quiet = true
+		case arg == "--mono":
+			// Monochrome is the ascii profile, which also switches styles to text attributes
+			opts.ColorProfile = "ascii"
+		case arg == "--why":
+			if i+1 < len(args) {
+				i++
+				opts.WhyInterface = args[i]
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: %s requires an interface name\n", arg)
+				os.Exit(1)
+			}
+		case strings.HasPrefix(arg, "--why="):
+			opts.WhyInterface = strings.TrimPrefix(arg, "--why=")
 		case arg == "-t" || arg == "--theme":
 			if i+1 < len(args) {
 				i++
@@ -93,6 +167,17 @@ func ParseArgs() Options {
 		case strings.HasPrefix(arg, "--description="):
 			opts.SystemDescription = strings.TrimPrefix(arg, "--description=")
 
+		case arg == "--platform":
+			if i+1 < len(args) {
+				i++
+				opts.Platform = args[i]
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a platform string\n", arg)
+				os.Exit(1)
+			}
+		case strings.HasPrefix(arg, "--platform="):
+			opts.Platform = strings.TrimPrefix(arg, "--platform=")
+
 		case arg == "--cdp-listen":
 			opts.CDPListen = &boolTrue
 		case arg == "--no-cdp-listen":
@@ -102,6 +187,17 @@ func ParseArgs() Options {
 		case arg == "--no-lldp-listen":
 			opts.LLDPListen = &boolFalse
 
+		case arg == "--bpf":
+			if i+1 < len(args) {
+				i++
+				opts.CaptureBPF = args[i]
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a BPF filter expression\n", arg)
+				os.Exit(1)
+			}
+		case strings.HasPrefix(arg, "--bpf="):
+			opts.CaptureBPF = strings.TrimPrefix(arg, "--bpf=")
+
 		case arg == "--cdp-broadcast":
 			opts.CDPBroadcast = &boolTrue
 		case arg == "--no-cdp-broadcast":
@@ -170,6 +266,60 @@ func ParseArgs() Options {
 			opts.NoAutoSelect = &boolFalse // auto-select enabled (noAutoSelect = false)
 		case arg == "--no-auto-select":
 			opts.NoAutoSelect = &boolTrue // auto-select disabled (noAutoSelect = true)
+		case arg == "--all-interfaces":
+			opts.AllInterfaces = true
+
+		case arg == "--show-frame":
+			opts.ShowFrame = true
+		case arg == "--tail":
+			opts.Tail = true
+		case arg == "--timeout":
+			if i+1 < len(args) {
+				i++
+				val, err := strconv.Atoi(args[i])
+				if err != nil || val <= 0 {
+					fmt.Fprintf(os.Stderr, "Error: %s requires a positive integer\n", arg)
+					os.Exit(1)
+				}
+				opts.Timeout = val
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a duration in seconds\n", arg)
+				os.Exit(1)
+			}
+		case strings.HasPrefix(arg, "--timeout="):
+			val, err := strconv.Atoi(strings.TrimPrefix(arg, "--timeout="))
+			if err != nil || val <= 0 {
+				fmt.Fprintf(os.Stderr, "Error: --timeout requires a positive integer\n")
+				os.Exit(1)
+			}
+			opts.Timeout = val
+
+		case arg == "--json":
+			opts.JSON = true
+		case arg == "--watch":
+			opts.JSON = true
+			opts.Watch = true
+		case arg == "--duration":
+			if i+1 < len(args) {
+				i++
+				opts.Duration = parseDuration(arg, args[i])
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: %s requires a duration (e.g. 30s or 2m)\n", arg)
+				os.Exit(1)
+			}
+		case strings.HasPrefix(arg, "--duration="):
+			opts.Duration = parseDuration("--duration", strings.TrimPrefix(arg, "--duration="))
+
+		case arg == "--metrics-addr":
+			if i+1 < len(args) {
+				i++
+				opts.MetricsAddr = args[i]
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: %s requires an address (e.g. :9105)\n", arg)
+				os.Exit(1)
+			}
+		case strings.HasPrefix(arg, "--metrics-addr="):
+			opts.MetricsAddr = strings.TrimPrefix(arg, "--metrics-addr=")
 
 		case strings.HasPrefix(arg, "-"):
 			fmt.Fprintf(os.Stderr, "Error: unknown option %s\n", arg)
@@ -188,3 +338,17 @@ func ParseArgs() Options {
 
 	return opts
 }
+
+// parseDuration parses a positive duration such as 30s or 2m for flag
+// A bare number is taken as seconds
+func parseDuration(flag, value string) time.Duration {
+	if _, err := strconv.Atoi(value); err == nil {
+		value += "s"
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: %s requires a positive duration (e.g. 30s or 2m)\n", flag)
+		os.Exit(1)
+	}
+	return d
+}
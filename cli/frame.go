@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"nbor/broadcast"
+	"nbor/config"
+	"nbor/protocol"
+	"nbor/types"
+)
+
+// cdpTLVNames names the CDP TLVs nbor sends or commonly sees
+var cdpTLVNames = map[uint16]string{
+	protocol.CDPTLVDeviceID:     "Device ID",
+	protocol.CDPTLVAddress:      "Addresses",
+	protocol.CDPTLVPortID:       "Port ID",
+	protocol.CDPTLVCapabilities: "Capabilities",
+	protocol.CDPTLVVersion:      "Software Version",
+	protocol.CDPTLVPlatform:     "Platform",
+	protocol.CDPTLVNativeVLAN:   "Native VLAN",
+	protocol.CDPTLVDuplex:       "Duplex",
+	protocol.CDPTLVMgmtAddress:  "Mgmt Address",
+}
+
+// lldpTLVNames names the basic LLDP TLVs
+var lldpTLVNames = map[uint8]string{
+	protocol.LLDPTLVEnd:         "End",
+	protocol.LLDPTLVChassisID:   "Chassis ID",
+	protocol.LLDPTLVPortID:      "Port ID",
+	protocol.LLDPTLVTTL:         "TTL",
+	protocol.LLDPTLVPortDesc:    "Port Description",
+	protocol.LLDPTLVSystemName:  "System Name",
+	protocol.LLDPTLVSystemDesc:  "System Description",
+	protocol.LLDPTLVSystemCap:   "Capabilities",
+	protocol.LLDPTLVMgmtAddress: "Mgmt Address",
+	127:                         "Org Specific",
+}
+
+// lldpCapabilityNames names the LLDP capability bits, lowest first
+var lldpCapabilityNames = []struct {
+	bit  uint16
+	name string
+}{
+	{protocol.LLDPCapOther, "Other"},
+	{protocol.LLDPCapRepeater, "Repeater"},
+	{protocol.LLDPCapBridge, "Bridge"},
+	{protocol.LLDPCapWLANAP, "AP"},
+	{protocol.LLDPCapRouter, "Router"},
+	{protocol.LLDPCapPhone, "Phone"},
+	{protocol.LLDPCapDocsis, "DOCSIS"},
+	{protocol.LLDPCapStation, "Station"},
+}
+
+// PrintFramePreview builds the CDP and LLDP frames nbor would advertise on iface
+// with cfg and writes each as a hex dump followed by its decoded TLVs. Nothing is
+// sent. Frames for protocols that aren't set to broadcast are shown too, marked off
+func PrintFramePreview(w io.Writer, cfg *config.Config, iface *types.InterfaceInfo) {
+	systemName := broadcast.AdvertisedName(cfg)
+
+	fmt.Fprintf(w, "Frames nbor would send on %s (nothing is transmitted)\n", iface.Name)
+	if cfg.BroadcastVariationEnabled() {
+		fmt.Fprintf(w, "Note: broadcast_variation = %q changes the identity every interval; the first frames are shown without it\n", cfg.BroadcastVariation)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "CDP (broadcast %s)\n", onOff(cfg.CDPBroadcast))
+	if frame, err := broadcast.BuildCDPFrame(cfg, iface, systemName); err != nil {
+		fmt.Fprintf(w, "  not sent: %v\n", err)
+	} else {
+		writeHexDump(w, frame)
+		writeCDPFields(w, frame)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "LLDP (broadcast %s)\n", onOff(cfg.LLDPBroadcast))
+	frame, warnings, err := broadcast.BuildLLDPFrame(cfg, iface, systemName)
+	for _, warning := range warnings {
+		fmt.Fprintf(w, "  warning: %s\n", warning)
+	}
+	if err != nil {
+		fmt.Fprintf(w, "  not sent: %v\n", err)
+		return
+	}
+	writeHexDump(w, frame)
+	writeLLDPFields(w, frame)
+}
+
+// onOff formats a setting as on or off
+func onOff(v bool) string {
+	if v {
+		return "on"
+	}
+	return "off"
+}
+
+// writeHexDump writes frame as offset, hex and ASCII columns, with its size
+func writeHexDump(w io.Writer, frame []byte) {
+	fmt.Fprintf(w, "  %d bytes\n", len(frame))
+	for _, line := range strings.Split(strings.TrimSuffix(hex.Dump(frame), "\n"), "\n") {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+}
+
+// writeField writes one annotated line: the field's offset in the frame, its name and value
+func writeField(w io.Writer, offset int, name, value string) {
+	line := fmt.Sprintf("  %04x  %-18s %s", offset, name, value)
+	fmt.Fprintln(w, strings.TrimRight(line, " "))
+}
+
+// writeCDPFields annotates an 802.3 LLC/SNAP CDP frame as built by BuildCDPFrame
+func writeCDPFields(w io.Writer, frame []byte) {
+	if len(frame) < 26 {
+		return
+	}
+	fmt.Fprintln(w)
+	writeField(w, 0, "Ethernet", fmt.Sprintf("dst %s  src %s  length %d",
+		net.HardwareAddr(frame[0:6]), net.HardwareAddr(frame[6:12]), binary.BigEndian.Uint16(frame[12:14])))
+	writeField(w, 14, "LLC/SNAP", fmt.Sprintf("% x  OUI % x  protocol %#04x",
+		frame[14:17], frame[17:20], binary.BigEndian.Uint16(frame[20:22])))
+	writeField(w, 22, "CDP header", fmt.Sprintf("version %d  TTL %ds  checksum %#04x",
+		frame[22], frame[23], binary.BigEndian.Uint16(frame[24:26])))
+
+	for offset := 26; offset+4 <= len(frame); {
+		tlvType := binary.BigEndian.Uint16(frame[offset:])
+		length := int(binary.BigEndian.Uint16(frame[offset+2:]))
+		if length < 4 || offset+length > len(frame) {
+			writeField(w, offset, "Malformed TLV", fmt.Sprintf("type %#04x length %d", tlvType, length))
+			return
+		}
+		value := frame[offset+4 : offset+length]
+		name, ok := cdpTLVNames[tlvType]
+		if !ok {
+			name = fmt.Sprintf("TLV %#04x", tlvType)
+		}
+		writeField(w, offset, name, cdpValue(tlvType, value))
+		offset += length
+	}
+}
+
+// cdpValue formats a CDP TLV value for the preview
+func cdpValue(tlvType uint16, value []byte) string {
+	switch tlvType {
+	case protocol.CDPTLVCapabilities:
+		if len(value) == 4 {
+			return fmt.Sprintf("%#08x %v", binary.BigEndian.Uint32(value), protocol.ParseCDPCapabilities(value))
+		}
+	case protocol.CDPTLVAddress:
+		return cdpAddresses(value)
+	}
+	return textOrHex(value)
+}
+
+// cdpAddresses formats the IPv4 addresses in a CDP Addresses TLV, falling back
+// to hex for anything else
+func cdpAddresses(value []byte) string {
+	if len(value) < 4 {
+		return textOrHex(value)
+	}
+	count := int(binary.BigEndian.Uint32(value))
+	var addrs []string
+	rest := value[4:]
+	for i := 0; i < count; i++ {
+		// Protocol type (1), protocol length (1), protocol, address length (2), address
+		if len(rest) < 2 || len(rest) < 2+int(rest[1])+2 {
+			return textOrHex(value)
+		}
+		protoLen := int(rest[1])
+		addrLen := int(binary.BigEndian.Uint16(rest[2+protoLen:]))
+		start := 2 + protoLen + 2
+		if len(rest) < start+addrLen {
+			return textOrHex(value)
+		}
+		if addrLen == 4 || addrLen == 16 {
+			addrs = append(addrs, net.IP(rest[start:start+addrLen]).String())
+		} else {
+			addrs = append(addrs, fmt.Sprintf("% x", rest[start:start+addrLen]))
+		}
+		rest = rest[start+addrLen:]
+	}
+	return strings.Join(addrs, ", ")
+}
+
+// writeLLDPFields annotates an LLDP frame as built by BuildLLDPFrame
+func writeLLDPFields(w io.Writer, frame []byte) {
+	if len(frame) < 14 {
+		return
+	}
+	fmt.Fprintln(w)
+	writeField(w, 0, "Ethernet", fmt.Sprintf("dst %s  src %s  type %#04x",
+		net.HardwareAddr(frame[0:6]), net.HardwareAddr(frame[6:12]), binary.BigEndian.Uint16(frame[12:14])))
+
+	for offset := 14; offset+2 <= len(frame); {
+		header := binary.BigEndian.Uint16(frame[offset:])
+		tlvType := uint8(header >> 9)
+		length := int(header & 0x01ff)
+		if offset+2+length > len(frame) {
+			writeField(w, offset, "Malformed TLV", fmt.Sprintf("type %d length %d", tlvType, length))
+			return
+		}
+		value := frame[offset+2 : offset+2+length]
+		name, ok := lldpTLVNames[tlvType]
+		if !ok {
+			name = fmt.Sprintf("TLV %d", tlvType)
+		}
+		writeField(w, offset, name, lldpValue(tlvType, value))
+		if tlvType == protocol.LLDPTLVEnd {
+			return
+		}
+		offset += 2 + length
+	}
+}
+
+// lldpValue formats an LLDP TLV value for the preview
+func lldpValue(tlvType uint8, value []byte) string {
+	switch tlvType {
+	case protocol.LLDPTLVEnd:
+		return ""
+	case protocol.LLDPTLVChassisID, protocol.LLDPTLVPortID:
+		if len(value) < 1 {
+			break
+		}
+		// The MAC address subtype is 4 for the Chassis ID but 3 for the Port ID
+		macSubtype := protocol.LLDPChassisIDSubtypeMAC
+		if tlvType == protocol.LLDPTLVPortID {
+			macSubtype = protocol.LLDPPortIDSubtypeMACAddr
+		}
+		if value[0] == macSubtype && len(value) == 7 {
+			return fmt.Sprintf("subtype %d (MAC) %s", value[0], net.HardwareAddr(value[1:]))
+		}
+		return fmt.Sprintf("subtype %d %s", value[0], textOrHex(value[1:]))
+	case protocol.LLDPTLVTTL:
+		if len(value) == 2 {
+			return fmt.Sprintf("%ds", binary.BigEndian.Uint16(value))
+		}
+	case protocol.LLDPTLVSystemCap:
+		if len(value) == 4 {
+			return fmt.Sprintf("supported %s  enabled %s",
+				lldpCapabilities(binary.BigEndian.Uint16(value)), lldpCapabilities(binary.BigEndian.Uint16(value[2:])))
+		}
+	case protocol.LLDPTLVMgmtAddress:
+		// Address string length (subtype + address), subtype, address, ...
+		if len(value) >= 2 {
+			n := int(value[0]) - 1
+			if (n == 4 || n == 16) && len(value) >= 2+n {
+				return net.IP(value[2 : 2+n]).String()
+			}
+		}
+	}
+	return textOrHex(value)
+}
+
+// lldpCapabilities formats LLDP capability bits as hex and names
+func lldpCapabilities(bits uint16) string {
+	var names []string
+	for _, c := range lldpCapabilityNames {
+		if bits&c.bit != 0 {
+			names = append(names, c.name)
+		}
+	}
+	return fmt.Sprintf("%#04x %v", bits, names)
+}
+
+// textOrHex quotes printable ASCII values and shows anything else as hex
+func textOrHex(value []byte) string {
+	for _, b := range value {
+		if b < 0x20 || b > 0x7e {
+			return fmt.Sprintf("% x", value)
+		}
+	}
+	return fmt.Sprintf("%q", value)
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"nbor/config"
+	"nbor/protocol"
+	"nbor/types"
+)
+
+func TestPrintFramePreview(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SystemName = "sw-preview"
+	cfg.Capabilities = []string{"router"}
+	cfg.CDPBroadcast = true
+	iface := &types.InterfaceInfo{
+		Name:      "eth0",
+		MAC:       net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+		MTU:       1500,
+		IPv4Addrs: []net.IP{net.ParseIP("192.0.2.10")},
+	}
+
+	var b strings.Builder
+	PrintFramePreview(&b, &cfg, iface)
+	out := b.String()
+
+	for _, want := range []string{
+		"CDP (broadcast on)",
+		"LLDP (broadcast off)",
+		"dst 01:00:0c:cc:cc:cc  src 00:11:22:33:44:55",
+		`Device ID          "sw-preview"`,
+		"Capabilities       0x00000001 [Router]",
+		"Addresses          192.0.2.10",
+		"Chassis ID         subtype 4 (MAC) 00:11:22:33:44:55",
+		"TTL                20s",
+		"supported 0x0010 [Router]  enabled 0x0010 [Router]",
+		"Mgmt Address       192.0.2.10",
+		"End",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("preview is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestLLDPValueMACSubtypes(t *testing.T) {
+	mac := []byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}
+	tests := []struct {
+		name    string
+		tlvType uint8
+		subtype uint8
+		want    string
+	}{
+		{"chassis ID MAC", protocol.LLDPTLVChassisID, 4, "subtype 4 (MAC) 00:11:22:33:44:55"},
+		{"port ID MAC", protocol.LLDPTLVPortID, 3, "subtype 3 (MAC) 00:11:22:33:44:55"},
+		{"port ID network address", protocol.LLDPTLVPortID, 4, "subtype 4 00 11 22 33 44 55"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lldpValue(tt.tlvType, append([]byte{tt.subtype}, mac...)); got != tt.want {
+				t.Errorf("lldpValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintFramePreviewError(t *testing.T) {
+	cfg := config.DefaultConfig()
+	var b strings.Builder
+	PrintFramePreview(&b, &cfg, &types.InterfaceInfo{Name: "eth0", MTU: 1500})
+	if got := strings.Count(b.String(), "not sent:"); got != 2 {
+		t.Errorf("preview without a MAC shows %d \"not sent\" lines, want 2:\n%s", got, b.String())
+	}
+}
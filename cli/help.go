@@ -2,7 +2,11 @@ package cli
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
+	"nbor/config"
 	"nbor/tui"
 )
 
@@ -15,21 +19,31 @@ Usage:
 
 Options:
   -t, --theme <name>      Use specified theme (session only)
+  --color-profile <name>  Color output: auto, truecolor, 256, 16, ascii
+  --mono                  No color; show state with bold, faint and reverse text
+  --no-color              Plain text for the list commands and --tail (also set by NO_COLOR)
+  --quiet                 Don't ring the terminal bell on new neighbors
   --list-themes           List available themes
+  --profile <name>        Apply a [profiles.<name>] table from the config file
+  --list-profiles         List the config file's profiles and their settings
   -l, --list-interfaces   List available network interfaces
   --list-all-interfaces   List all interfaces (including filtered)
+  --why <interface>       Explain why an interface is shown or filtered
   -v, --version           Show version
   -h, --help              Show this help
 
 Identity Options:
   --name <string>         System name to advertise (default: hostname)
   --description <string>  System description to advertise
+  --platform <string>     Platform to advertise in CDP and the LLDP description (default: nbor)
 
 Listening Options:
   --cdp-listen            Enable CDP listening (default)
   --no-cdp-listen         Disable CDP listening
   --lldp-listen           Enable LLDP listening (default)
   --no-lldp-listen        Disable LLDP listening
+  --bpf <expr>            Capture with this BPF filter instead of the CDP/LLDP one
+                          (only CDP and LLDP frames are still decoded)
 
 Broadcasting Options:
   --broadcast             Enable both CDP and LLDP broadcasting
@@ -45,6 +59,23 @@ Broadcasting Options:
 Interface Options:
   --auto-select           Auto-select if only one interface (default)
   --no-auto-select        Always show interface picker
+  --all-interfaces        Capture on every usable wired interface at once
+
+Output Options:
+  --show-frame            Print the CDP and LLDP frames nbor would advertise on the
+                          interface, as hex with decoded TLVs, without sending them
+  --tail                  Print one line per new/updated neighbor instead of the TUI
+  --json                  Print one JSON object per discovered neighbor instead of the TUI
+                          (with -l or --list-all-interfaces, list interfaces as JSON)
+  --watch                 Like --json, but print a JSON array of all current neighbors
+                          every --interval seconds (default: 5) until Ctrl+C
+  --duration <time>       How long --json, --watch or --tail captures, e.g. 30s or 2m
+                          (default for --json: 60s)
+  --timeout <seconds>     Exit --tail or --json mode after this many seconds
+
+Monitoring Options:
+  --metrics-addr <addr>   Serve Prometheus metrics at http://<addr>/metrics
+                          (e.g. :9105)
 
 Examples:
   nbor                              # Interactive main menu
@@ -53,23 +84,79 @@ Examples:
   nbor --broadcast --interval 10    # Broadcast every 10 seconds
   nbor --name "my-host" --broadcast # Custom system name
   nbor --capabilities router,bridge # Advertise as router and bridge
+  nbor --tail eth0 | grep Gi1/0/1   # Watch neighbor events as text
+  nbor --json --duration 30s eth0   # Inventory neighbors as JSON lines
+  nbor --watch --interval 10 eth0   # JSON snapshot of neighbors every 10s
+  nbor --profile voip-survey eth0   # Use the voip-survey profile's settings
+  nbor --show-frame eth0            # Preview advertised frames, send nothing
 
 Configuration:
   Config file: ~/.config/nbor/config.toml (Linux/macOS)
                %%APPDATA%%\nbor\config.toml (Windows)
 
-  CLI flags override config file settings.
+  Profiles override the rest of the config file; CLI flags override both.
 `
 	fmt.Print(help)
 }
 
+// PrintProfiles prints the config file's profiles with the settings each one changes
+func PrintProfiles(cfg *config.Config) {
+	names := cfg.ProfileNames()
+	if len(names) == 0 {
+		fmt.Println("No profiles defined.")
+		fmt.Println()
+		fmt.Println("Add [profiles.<name>] tables to the config file, e.g.")
+		fmt.Println("  [profiles.listen-only]")
+		fmt.Println("  cdp_broadcast = false")
+		fmt.Println("  lldp_broadcast = false")
+		return
+	}
+
+	fmt.Println("Available profiles:")
+	fmt.Println()
+	for _, name := range names {
+		fmt.Printf("  %s\n", name)
+		settings := cfg.Profiles[name]
+		keys := make([]string, 0, len(settings))
+		for k := range settings {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Printf("    %s = %s\n", k, formatProfileValue(settings[k]))
+		}
+	}
+	fmt.Println()
+	fmt.Println("Usage: nbor --profile <name>")
+}
+
+// formatProfileValue formats a profile setting the way it's written in TOML
+func formatProfileValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = formatProfileValue(item)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 // PrintThemes prints available themes
 func PrintThemes() {
 	fmt.Println("Available themes:")
 	fmt.Println()
 	themes := tui.ListThemes()
 	for _, t := range themes {
-		fmt.Printf("  %-20s  %s\n", t[0], t[1])
+		name := t[1]
+		if tui.IsCustomTheme(t[0]) {
+			name += " (custom)"
+		}
+		fmt.Printf("  %-20s  %s\n", t[0], name)
 	}
 	fmt.Println()
 	fmt.Println("Usage: nbor --theme <slug>")
@@ -2,7 +2,9 @@ package cli
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -13,6 +15,23 @@ import (
 	"nbor/types"
 )
 
+// noColor is set by --no-color or NO_COLOR; the listing helpers and --tail then
+// print plain text, so piped output has no escape codes
+var noColor bool
+
+// SetNoColor turns styling of the listing helpers and --tail off or on
+func SetNoColor(v bool) {
+	noColor = v
+}
+
+// render applies style to s unless color is off
+func render(style lipgloss.Style, s string) string {
+	if noColor {
+		return s
+	}
+	return style.Render(s)
+}
+
 // FindInterface searches for an interface by name (case-insensitive)
 func FindInterface(interfaces []types.InterfaceInfo, name string) *types.InterfaceInfo {
 	nameLower := strings.ToLower(name)
@@ -31,18 +50,18 @@ func PrintInterfaceError(name string, interfaces []types.InterfaceInfo) {
 	hintStyle := lipgloss.NewStyle().Foreground(theme.Base03)
 	nameStyle := lipgloss.NewStyle().Foreground(theme.Base0A)
 
-	fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error: Interface '%s' not found", name)))
+	fmt.Fprintln(os.Stderr, render(errorStyle, fmt.Sprintf("Error: Interface '%s' not found", name)))
 	fmt.Fprintln(os.Stderr)
-	fmt.Fprintln(os.Stderr, hintStyle.Render("Available interfaces:"))
+	fmt.Fprintln(os.Stderr, render(hintStyle, "Available interfaces:"))
 	for _, iface := range interfaces {
 		status := "down"
 		if iface.IsUp {
 			status = "up"
 		}
-		fmt.Fprintf(os.Stderr, "  %s (%s)\n", nameStyle.Render(iface.Name), status)
+		fmt.Fprintf(os.Stderr, "  %s (%s)\n", render(nameStyle, iface.Name), status)
 	}
 	fmt.Fprintln(os.Stderr)
-	fmt.Fprintln(os.Stderr, hintStyle.Render("Falling back to interface picker..."))
+	fmt.Fprintln(os.Stderr, render(hintStyle, "Falling back to interface picker..."))
 	fmt.Fprintln(os.Stderr)
 }
 
@@ -56,7 +75,7 @@ func PrintInterfaces(interfaces []types.InterfaceInfo) {
 	upStyle := lipgloss.NewStyle().Foreground(theme.Base0B)
 	downStyle := lipgloss.NewStyle().Foreground(theme.Base08)
 
-	fmt.Println(headerStyle.Render("Available interfaces:"))
+	fmt.Println(render(headerStyle, "Available interfaces:"))
 	fmt.Println()
 
 	if len(interfaces) == 0 {
@@ -65,25 +84,25 @@ func PrintInterfaces(interfaces []types.InterfaceInfo) {
 	}
 
 	for _, iface := range interfaces {
-		fmt.Printf("  %s\n", nameStyle.Render(iface.Name))
+		fmt.Printf("  %s\n", render(nameStyle, iface.Name))
 
 		if len(iface.MAC) > 0 {
-			fmt.Printf("    %s %s\n", labelStyle.Render("MAC:"), valueStyle.Render(iface.MAC.String()))
+			fmt.Printf("    %s %s\n", render(labelStyle, "MAC:"), render(valueStyle, iface.MAC.String()))
 		}
 
 		for _, ip := range iface.IPv4Addrs {
-			fmt.Printf("    %s %s\n", labelStyle.Render("IPv4:"), valueStyle.Render(ip.String()))
+			fmt.Printf("    %s %s\n", render(labelStyle, "IPv4:"), render(valueStyle, ip.String()))
 		}
 
 		for _, ip := range iface.IPv6Addrs {
-			fmt.Printf("    %s %s\n", labelStyle.Render("IPv6:"), valueStyle.Render(ip.String()))
+			fmt.Printf("    %s %s\n", render(labelStyle, "IPv6:"), render(valueStyle, ip.String()))
 		}
 
-		status := downStyle.Render("down")
+		status := render(downStyle, "down")
 		if iface.IsUp {
-			status = upStyle.Render("up")
+			status = render(upStyle, "up")
 		}
-		fmt.Printf("    %s %s\n", labelStyle.Render("Status:"), status)
+		fmt.Printf("    %s %s\n", render(labelStyle, "Status:"), status)
 		fmt.Println()
 	}
 }
@@ -107,28 +126,28 @@ func PrintAllInterfaces(usable, all []types.InterfaceInfo) {
 	}
 
 	// Print usable interfaces
-	fmt.Println(headerStyle.Render("Available interfaces:"))
+	fmt.Println(render(headerStyle, "Available interfaces:"))
 	fmt.Println()
 
 	if len(usable) == 0 {
 		fmt.Println("  No suitable Ethernet interfaces found.")
 	} else {
 		for _, iface := range usable {
-			fmt.Printf("  %s\n", nameStyle.Render(iface.Name))
+			fmt.Printf("  %s\n", render(nameStyle, iface.Name))
 
 			if len(iface.MAC) > 0 {
-				fmt.Printf("    %s %s\n", labelStyle.Render("MAC:"), valueStyle.Render(iface.MAC.String()))
+				fmt.Printf("    %s %s\n", render(labelStyle, "MAC:"), render(valueStyle, iface.MAC.String()))
 			}
 
 			for _, ip := range iface.IPv4Addrs {
-				fmt.Printf("    %s %s\n", labelStyle.Render("IPv4:"), valueStyle.Render(ip.String()))
+				fmt.Printf("    %s %s\n", render(labelStyle, "IPv4:"), render(valueStyle, ip.String()))
 			}
 
-			status := downStyle.Render("down")
+			status := render(downStyle, "down")
 			if iface.IsUp {
-				status = upStyle.Render("up")
+				status = render(upStyle, "up")
 			}
-			fmt.Printf("    %s %s\n", labelStyle.Render("Status:"), status)
+			fmt.Printf("    %s %s\n", render(labelStyle, "Status:"), status)
 			fmt.Println()
 		}
 	}
@@ -142,7 +161,7 @@ func PrintAllInterfaces(usable, all []types.InterfaceInfo) {
 	}
 
 	if len(filtered) > 0 {
-		fmt.Println(filteredHeaderStyle.Render("Filtered interfaces:"))
+		fmt.Println(render(filteredHeaderStyle, "Filtered interfaces:"))
 		fmt.Println()
 
 		for _, iface := range filtered {
@@ -150,26 +169,95 @@ func PrintAllInterfaces(usable, all []types.InterfaceInfo) {
 			if reason == "" {
 				reason = "unknown"
 			}
-			fmt.Printf("  %s (%s)\n", nameStyle.Render(iface.Name), reasonStyle.Render(reason))
+			fmt.Printf("  %s (%s)\n", render(nameStyle, iface.Name), render(reasonStyle, reason))
 
 			if len(iface.MAC) > 0 {
-				fmt.Printf("    %s %s\n", labelStyle.Render("MAC:"), valueStyle.Render(iface.MAC.String()))
+				fmt.Printf("    %s %s\n", render(labelStyle, "MAC:"), render(valueStyle, iface.MAC.String()))
 			}
 
 			for _, ip := range iface.IPv4Addrs {
-				fmt.Printf("    %s %s\n", labelStyle.Render("IPv4:"), valueStyle.Render(ip.String()))
+				fmt.Printf("    %s %s\n", render(labelStyle, "IPv4:"), render(valueStyle, ip.String()))
 			}
 
-			status := downStyle.Render("down")
+			status := render(downStyle, "down")
 			if iface.IsUp {
-				status = upStyle.Render("up")
+				status = render(upStyle, "up")
 			}
-			fmt.Printf("    %s %s\n", labelStyle.Render("Status:"), status)
+			fmt.Printf("    %s %s\n", render(labelStyle, "Status:"), status)
 			fmt.Println()
 		}
 	}
 }
 
+// interfaceJSON is the stable schema of one interface in --list-interfaces --json
+type interfaceJSON struct {
+	Name         string   `json:"name"`
+	MAC          string   `json:"mac"`
+	Up           bool     `json:"up"`
+	Speed        string   `json:"speed"`
+	MTU          int      `json:"mtu"`
+	IPv4         []string `json:"ipv4"`
+	IPv6         []string `json:"ipv6"`
+	Filtered     bool     `json:"filtered"`
+	FilterReason string   `json:"filter_reason"`
+}
+
+// PrintInterfacesJSON writes the usable interfaces as a JSON array, for scripts
+// that pick an interface before running nbor --json. With all set (for
+// --list-all-interfaces), the interfaces missing from usable follow, marked
+// filtered with the reason from platform.GetFilterReason
+func PrintInterfacesJSON(w io.Writer, usable, all []types.InterfaceInfo) error {
+	return writeInterfacesJSON(w, usable, all, platform.GetFilterReason)
+}
+
+// writeInterfacesJSON is PrintInterfacesJSON with the filter reason lookup passed in
+func writeInterfacesJSON(w io.Writer, usable, all []types.InterfaceInfo, filterReason func(string) string) error {
+	usableMap := make(map[string]bool)
+	result := make([]interfaceJSON, 0, len(all))
+	for _, iface := range usable {
+		usableMap[iface.Name] = true
+		result = append(result, newInterfaceJSON(iface))
+	}
+	for _, iface := range all {
+		if usableMap[iface.Name] {
+			continue
+		}
+		entry := newInterfaceJSON(iface)
+		entry.Filtered = true
+		entry.FilterReason = filterReason(iface.Name)
+		if entry.FilterReason == "" {
+			entry.FilterReason = "unknown"
+		}
+		result = append(result, entry)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+// newInterfaceJSON converts an interface, using empty lists rather than null
+func newInterfaceJSON(iface types.InterfaceInfo) interfaceJSON {
+	entry := interfaceJSON{
+		Name:  iface.Name,
+		Up:    iface.IsUp,
+		Speed: iface.Speed,
+		MTU:   iface.MTU,
+		IPv4:  []string{},
+		IPv6:  []string{},
+	}
+	if len(iface.MAC) > 0 {
+		entry.MAC = iface.MAC.String()
+	}
+	for _, ip := range iface.IPv4Addrs {
+		entry.IPv4 = append(entry.IPv4, ip.String())
+	}
+	for _, ip := range iface.IPv6Addrs {
+		entry.IPv6 = append(entry.IPv6, ip.String())
+	}
+	return entry
+}
+
 // PrintFilterWarning prints a warning when using a filtered interface
 func PrintFilterWarning(name, reason string) {
 	theme := tui.DefaultTheme
@@ -178,15 +266,112 @@ func PrintFilterWarning(name, reason string) {
 	hintStyle := lipgloss.NewStyle().Foreground(theme.Base03)
 	promptStyle := lipgloss.NewStyle().Foreground(theme.Base0C)
 
-	fmt.Fprintln(os.Stderr, warnStyle.Render(fmt.Sprintf("Warning: '%s' appears to be a %s", name, reason)))
-	fmt.Fprintln(os.Stderr, textStyle.Render("CDP/LLDP protocols are typically only used on wired networks."))
+	fmt.Fprintln(os.Stderr, render(warnStyle, fmt.Sprintf("Warning: '%s' appears to be a %s", name, reason)))
+	fmt.Fprintln(os.Stderr, render(textStyle, "CDP/LLDP protocols are typically only used on wired networks."))
 	fmt.Fprintln(os.Stderr)
-	fmt.Fprint(os.Stderr, promptStyle.Render("Press Enter to continue (or Ctrl+C to cancel)... "))
+	fmt.Fprint(os.Stderr, render(promptStyle, "Press Enter to continue (or Ctrl+C to cancel)... "))
 
 	// Wait for user to press Enter
 	reader := bufio.NewReader(os.Stdin)
 	_, _ = reader.ReadString('\n')
 
-	fmt.Fprintln(os.Stderr, hintStyle.Render("Continuing..."))
+	fmt.Fprintln(os.Stderr, render(hintStyle, "Continuing..."))
 	fmt.Fprintln(os.Stderr)
 }
+
+// PrintExplanation prints why an interface is or isn't shown, for --why
+func PrintExplanation(e platform.InterfaceExplanation) {
+	theme := tui.DefaultTheme
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Base0B)
+	nameStyle := lipgloss.NewStyle().Foreground(theme.Base0A).Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Base03)
+	valueStyle := lipgloss.NewStyle().Foreground(theme.Base05)
+	passStyle := lipgloss.NewStyle().Foreground(theme.Base0B)
+	failStyle := lipgloss.NewStyle().Foreground(theme.Base08)
+	reasonStyle := lipgloss.NewStyle().Foreground(theme.Base0E)
+
+	printValue := func(label, value string) {
+		fmt.Printf("    %s %s\n", render(labelStyle, fmt.Sprintf("%-12s", label)), render(valueStyle, value))
+	}
+	yesNo := func(ok bool) string {
+		if ok {
+			return render(passStyle, "yes")
+		}
+		return render(failStyle, "no")
+	}
+
+	fmt.Printf("%s %s\n", render(headerStyle, "Interface"), render(nameStyle, e.Name))
+	fmt.Println()
+
+	if !e.Found && !e.PcapListed {
+		fmt.Println(render(failStyle, "  Not found by the OS or pcap."))
+		fmt.Println(render(labelStyle, "  Run 'nbor --list-all-interfaces' to see interface names."))
+		return
+	}
+
+	// Raw properties
+	fmt.Println(render(headerStyle, "  Properties:"))
+	if len(e.MAC) > 0 {
+		printValue("MAC:", e.MAC.String())
+	} else {
+		printValue("MAC:", "none")
+	}
+	if e.MTU > 0 {
+		printValue("MTU:", fmt.Sprintf("%d", e.MTU))
+	}
+	if e.Found {
+		printValue("Flags:", e.Flags.String())
+	}
+	for _, ip := range e.Addrs {
+		printValue("Address:", ip.String())
+	}
+	if e.PcapListed {
+		printValue("pcap name:", e.PcapName)
+		if e.PcapDescription != "" {
+			printValue("pcap desc:", e.PcapDescription)
+		}
+		printValue("pcap flags:", fmt.Sprintf("0x%08X", e.PcapFlags))
+	}
+	fmt.Println()
+
+	// Filtering rules in order
+	fmt.Println(render(headerStyle, "  Filtering rules:"))
+	for _, c := range e.Checks {
+		mark := render(passStyle, "✓")
+		if !c.Passed {
+			mark = render(failStyle, "✗")
+		}
+		line := fmt.Sprintf("    %s %s", mark, render(valueStyle, c.Rule))
+		if c.Detail != "" {
+			line += " " + render(reasonStyle, "("+c.Detail+")")
+		}
+		fmt.Println(line)
+	}
+	if e.PcapListed {
+		if e.OpenError != nil {
+			fmt.Printf("    %s %s %s\n", render(failStyle, "✗"), render(valueStyle, "pcap can open it"),
+				render(reasonStyle, "("+e.OpenError.Error()+")"))
+		} else {
+			fmt.Printf("    %s %s\n", render(passStyle, "✓"), render(valueStyle, "pcap can open it"))
+		}
+	}
+	fmt.Println()
+
+	// Verdict
+	fmt.Println(render(headerStyle, "  Result:"))
+	fmt.Printf("    %s %s\n", render(labelStyle, "Shown in interface list:"), yesNo(e.Usable))
+	canCapture := e.PcapListed && e.OpenError == nil
+	usableExplicitly := (e.Usable || e.Selectable) && canCapture
+	fmt.Printf("    %s %s\n", render(labelStyle, fmt.Sprintf("Usable as 'nbor %s':", e.Name)), yesNo(usableExplicitly))
+
+	switch {
+	case e.Usable && canCapture:
+		// Nothing to explain
+	case usableExplicitly:
+		fmt.Println(render(labelStyle, "    Filtered interfaces can still be used by naming them; nbor shows a warning first."))
+	case !e.Selectable && !e.Usable:
+		fmt.Println(render(labelStyle, "    nbor only captures on Ethernet-type interfaces with a MAC or IP address."))
+	case !canCapture:
+		fmt.Println(render(labelStyle, "    pcap can't capture on it; check permissions and that the interface exists."))
+	}
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"nbor/tui"
+	"nbor/types"
+)
+
+func TestWriteInterfacesJSON(t *testing.T) {
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	eth0 := types.InterfaceInfo{
+		Name:      "eth0",
+		MAC:       mac,
+		IsUp:      true,
+		Speed:     "1 Gbps",
+		MTU:       1500,
+		IPv4Addrs: []net.IP{net.ParseIP("192.168.1.10").To4()},
+		IPv6Addrs: []net.IP{net.ParseIP("2001:db8::10")},
+	}
+	wlan0 := types.InterfaceInfo{Name: "wlan0", MTU: 1500}
+	reason := func(name string) string { return map[string]string{"wlan0": "wireless"}[name] }
+
+	var buf bytes.Buffer
+	if err := writeInterfacesJSON(&buf, []types.InterfaceInfo{eth0}, []types.InterfaceInfo{eth0, wlan0}, reason); err != nil {
+		t.Fatalf("writeInterfacesJSON() error = %v", err)
+	}
+
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output isn't a JSON array: %v\n%s", err, buf.String())
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d interfaces, want 2", len(got))
+	}
+
+	// Every key is always present, so scripts can rely on the schema
+	keys := []string{"name", "mac", "up", "speed", "mtu", "ipv4", "ipv6", "filtered", "filter_reason"}
+	for _, entry := range got {
+		if len(entry) != len(keys) {
+			t.Errorf("%v has %d keys, want %d", entry["name"], len(entry), len(keys))
+		}
+		for _, k := range keys {
+			if _, ok := entry[k]; !ok {
+				t.Errorf("%v is missing %q", entry["name"], k)
+			}
+		}
+	}
+
+	if got[0]["name"] != "eth0" || got[0]["mac"] != "00:11:22:33:44:55" || got[0]["filtered"] != false {
+		t.Errorf("eth0 = %v", got[0])
+	}
+	if ips := got[0]["ipv6"].([]any); len(ips) != 1 || ips[0] != "2001:db8::10" {
+		t.Errorf("eth0 ipv6 = %v, want [2001:db8::10]", ips)
+	}
+	if got[1]["name"] != "wlan0" || got[1]["filtered"] != true || got[1]["filter_reason"] != "wireless" {
+		t.Errorf("wlan0 = %v, want filtered as wireless", got[1])
+	}
+	if ips, ok := got[1]["ipv4"].([]any); !ok || len(ips) != 0 {
+		t.Errorf("wlan0 ipv4 = %v, want an empty list", got[1]["ipv4"])
+	}
+}
+
+func TestPrintInterfacesNoColor(t *testing.T) {
+	if err := tui.SetColorProfile("truecolor"); err != nil {
+		t.Fatal(err)
+	}
+	defer SetNoColor(false)
+
+	interfaces := []types.InterfaceInfo{{Name: "eth0", IsUp: true, IPv4Addrs: []net.IP{net.ParseIP("192.168.1.10").To4()}}}
+
+	tests := []struct {
+		name      string
+		noColor   bool   // --no-color
+		env       string // NO_COLOR
+		wantColor bool
+	}{
+		{"color", false, "", true},
+		{"--no-color", true, "", false},
+		{"NO_COLOR", false, "1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.env)
+			SetNoColor(Options{NoColor: tt.noColor}.ColorDisabled())
+
+			out := captureStdout(t, func() { PrintInterfaces(interfaces) })
+			if got := strings.Contains(out, "\x1b["); got != tt.wantColor {
+				t.Errorf("escape sequences in output = %v, want %v:\n%q", got, tt.wantColor, out)
+			}
+			if !tt.wantColor && !strings.Contains(out, "Status: up") {
+				t.Errorf("plain output missing the status line:\n%s", out)
+			}
+		})
+	}
+}
+
+// captureStdout returns what f prints to stdout
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	f()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
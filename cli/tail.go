@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"nbor/tui"
+	"nbor/types"
+)
+
+// Tail event names
+const (
+	TailEventNew    = "new"
+	TailEventUpdate = "update"
+)
+
+// TailPrinter writes one line per neighbor event, for --tail
+type TailPrinter struct {
+	mu sync.Mutex
+	w  io.Writer
+
+	timeStyle   lipgloss.Style
+	ifaceStyle  lipgloss.Style
+	newStyle    lipgloss.Style
+	updateStyle lipgloss.Style
+	hostStyle   lipgloss.Style
+	valueStyle  lipgloss.Style
+	dimStyle    lipgloss.Style
+}
+
+// NewTailPrinter creates a printer writing to w using the current theme
+// Lines are plain text when color is off (--no-color or NO_COLOR)
+func NewTailPrinter(w io.Writer) *TailPrinter {
+	theme := tui.DefaultTheme
+	return &TailPrinter{
+		w:           w,
+		timeStyle:   lipgloss.NewStyle().Foreground(theme.Base03),
+		ifaceStyle:  lipgloss.NewStyle().Foreground(theme.Base0C),
+		newStyle:    lipgloss.NewStyle().Foreground(theme.Base0B).Bold(true),
+		updateStyle: lipgloss.NewStyle().Foreground(theme.Base0D),
+		hostStyle:   lipgloss.NewStyle().Foreground(theme.Base0A).Bold(true),
+		valueStyle:  lipgloss.NewStyle().Foreground(theme.Base05),
+		dimStyle:    lipgloss.NewStyle().Foreground(theme.Base03),
+	}
+}
+
+// Print writes a line for a neighbor event
+// Safe to call from store callbacks; each line is written with a single Write
+func (t *TailPrinter) Print(event string, n *types.Neighbor) {
+	eventStyle := t.updateStyle
+	if event == TailEventNew {
+		eventStyle = t.newStyle
+	}
+
+	hostname := n.Hostname
+	if hostname == "" {
+		hostname = n.ID
+	}
+
+	fields := []string{
+		render(t.timeStyle, n.LastSeen.Format("2006-01-02 15:04:05")),
+		render(t.ifaceStyle, tailValue(n.Interface)),
+		render(eventStyle, fmt.Sprintf("%-6s", event)),
+		render(t.hostStyle, tailValue(hostname)),
+		render(t.valueStyle, tailValue(n.PortID)),
+		render(t.valueStyle, tailIP(n)),
+		render(t.dimStyle, string(n.Protocol)),
+	}
+	line := strings.Join(fields, "  ") + "\n"
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = io.WriteString(t.w, line)
+}
+
+// tailValue returns s with whitespace collapsed so each event stays on one line
+func tailValue(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// tailIP returns the neighbor's management IP or "-"
+func tailIP(n *types.Neighbor) string {
+	if n.ManagementIP == nil {
+		return "-"
+	}
+	return n.ManagementIP.String()
+}
+
+// PrintTailHeader prints a note describing the tail session to w (normally stderr)
+func PrintTailHeader(w io.Writer, iface string, timeout time.Duration) {
+	hintStyle := lipgloss.NewStyle().Foreground(tui.DefaultTheme.Base03)
+	msg := fmt.Sprintf("Listening on %s (Ctrl+C to stop)", iface)
+	if timeout > 0 {
+		msg = fmt.Sprintf("Listening on %s for %s (Ctrl+C to stop)", iface, timeout)
+	}
+	fmt.Fprintln(w, render(hintStyle, msg))
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"nbor/tui"
+	"nbor/types"
+)
+
+func TestTailPrinterColor(t *testing.T) {
+	if err := tui.SetColorProfile("truecolor"); err != nil {
+		t.Fatal(err)
+	}
+	defer SetNoColor(false)
+
+	n := &types.Neighbor{
+		Interface:    "eth0",
+		Hostname:     "core-sw1",
+		PortID:       "Gi0/1",
+		ManagementIP: net.ParseIP("10.0.0.1"),
+		Protocol:     types.ProtocolCDP,
+		LastSeen:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	want := "2026-01-02 03:04:05  eth0  new     core-sw1  Gi0/1  10.0.0.1  CDP\n"
+
+	tests := []struct {
+		name      string
+		noColor   bool   // --no-color
+		env       string // NO_COLOR
+		wantColor bool
+	}{
+		{"color", false, "", true},
+		{"--no-color", true, "", false},
+		{"NO_COLOR", false, "1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.env)
+			SetNoColor(Options{NoColor: tt.noColor}.ColorDisabled())
+			var buf bytes.Buffer
+			NewTailPrinter(&buf).Print(TailEventNew, n)
+			PrintTailHeader(&buf, "eth0", 0)
+
+			out := buf.String()
+			if got := strings.Contains(out, "\x1b["); got != tt.wantColor {
+				t.Errorf("escape sequences in output = %v, want %v:\n%q", got, tt.wantColor, out)
+			}
+			if !tt.wantColor && !strings.HasPrefix(out, want) {
+				t.Errorf("plain line = %q, want %q", out, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"nbor/types"
+)
+
+// WriteSnapshot writes the neighbors that aren't stale as a single-line JSON
+// array, sorted by interface and device, for --watch. An empty capture is "[]"
+func WriteSnapshot(w io.Writer, neighbors []*types.Neighbor) error {
+	current := make([]*types.Neighbor, 0, len(neighbors))
+	for _, n := range neighbors {
+		if !n.IsStale {
+			current = append(current, n)
+		}
+	}
+	sort.Slice(current, func(i, j int) bool {
+		return current[i].NeighborKey() < current[j].NeighborKey()
+	})
+
+	data, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"nbor/types"
+)
+
+func TestWriteSnapshot(t *testing.T) {
+	var b strings.Builder
+	if err := WriteSnapshot(&b, nil); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "[]\n" {
+		t.Errorf("empty snapshot = %q, want %q", b.String(), "[]\n")
+	}
+
+	neighbors := []*types.Neighbor{
+		{ID: "sw-b", Hostname: "sw-b", Interface: "eth0", SourceMAC: net.HardwareAddr{0, 0, 0, 0, 0, 2}},
+		{ID: "gone", Hostname: "gone", Interface: "eth0", SourceMAC: net.HardwareAddr{0, 0, 0, 0, 0, 3}, IsStale: true},
+		{ID: "sw-a", Hostname: "sw-a", Interface: "eth0", SourceMAC: net.HardwareAddr{0, 0, 0, 0, 0, 1}},
+	}
+	b.Reset()
+	if err := WriteSnapshot(&b, neighbors); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if strings.Count(out, "\n") != 1 || !strings.HasSuffix(out, "\n") {
+		t.Fatalf("snapshot isn't a single line: %q", out)
+	}
+
+	var got []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("snapshot isn't a JSON array: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "sw-a" || got[1].ID != "sw-b" {
+		t.Errorf("snapshot = %+v, want sw-a then sw-b without the stale neighbor", got)
+	}
+}
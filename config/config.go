@@ -3,6 +3,7 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -16,21 +17,68 @@ type Config struct {
 	// Theme is the slug name of the theme to use (e.g., "tokyo-night", "catppuccin-mocha")
 	Theme string `toml:"theme"`
 
+	// ThemeFilter limits the config menu theme list: "all", "dark" or "light"
+	ThemeFilter string `toml:"theme_filter"`
+
+	// AutoSaveTheme writes the theme to the config file as soon as one is picked
+	// in the theme menu, without waiting for Save & Exit
+	AutoSaveTheme bool `toml:"auto_save_theme"`
+
+	// ColorProfile selects terminal color output: "auto" (detect), "truecolor", "256",
+	// "16" or "ascii". Defaults to truecolor since some terminals that support it
+	// (e.g. Windows Terminal) don't advertise it
+	ColorProfile string `toml:"color_profile"`
+
+	// CursorColor and FlashColor pick the Base16 slot ("base00"-"base0f") used for the
+	// table selection cursor and for new/flashing rows, to tune contrast per theme
+	CursorColor string `toml:"cursor_color"`
+	FlashColor  string `toml:"flash_color"`
+
 	// SystemName is the name advertised in CDP/LLDP broadcasts (defaults to hostname)
 	SystemName string `toml:"system_name"`
 
+	// AdvertiseUseFQDN advertises the hostname's fully qualified name, looked up
+	// in DNS, when SystemName is empty; otherwise the hostname is sent as is
+	AdvertiseUseFQDN bool `toml:"advertise_use_fqdn"`
+
+	// AdvertiseNameSuffix is appended to the advertised name, e.g. "-lab"
+	AdvertiseNameSuffix string `toml:"advertise_name_suffix"`
+
 	// SystemDescription is the description advertised in CDP/LLDP broadcasts
 	SystemDescription string `toml:"system_description"`
 
+	// PlatformString is the platform advertised in CDP broadcasts and appended to the
+	// LLDP system description, to tell nbor agents apart (empty = "nbor")
+	PlatformString string `toml:"platform_string"`
+
 	// CDPListen enables listening for CDP packets
 	CDPListen bool `toml:"cdp_listen"`
 
+	// CDPStrictChecksum drops CDP frames whose checksum doesn't match instead of
+	// parsing them. Mismatches are counted in the metrics either way
+	CDPStrictChecksum bool `toml:"cdp_strict_checksum"`
+
 	// CDPBroadcast enables broadcasting CDP packets
 	CDPBroadcast bool `toml:"cdp_broadcast"`
 
 	// LLDPListen enables listening for LLDP packets
 	LLDPListen bool `toml:"lldp_listen"`
 
+	// CaptureBPF replaces the built-in CDP/LLDP capture filter with a custom BPF
+	// expression. Only CDP and LLDP frames are decoded either way. Empty means built-in
+	CaptureBPF string `toml:"capture_bpf"`
+
+	// CaptureBPFExtra is ANDed onto the capture filter to narrow it further, e.g.
+	// to one neighbor's MAC while debugging. The CDP/LLDP filter stays. Empty means none
+	CaptureBPFExtra string `toml:"capture_bpf_extra"`
+
+	// CaptureSnapLen is the pcap snapshot length in bytes. 0 means use the default
+	CaptureSnapLen int `toml:"capture_snaplen"`
+
+	// CaptureBufferMB is the kernel capture buffer size; raise it if busy links drop
+	// frames. 0 means the OS default
+	CaptureBufferMB int `toml:"capture_buffer_mb"`
+
 	// LLDPBroadcast enables broadcasting LLDP packets
 	LLDPBroadcast bool `toml:"lldp_broadcast"`
 
@@ -38,12 +86,38 @@ type Config struct {
 	// If false, broadcasting must be manually enabled with the 'b' key
 	BroadcastOnStartup bool `toml:"broadcast_on_startup"`
 
+	// ConfirmBroadcast asks before the 'b' key starts broadcasting, naming the
+	// interface's routable addresses, since sent frames show up in switch neighbor
+	// tables and monitoring
+	ConfirmBroadcast bool `toml:"confirm_broadcast"`
+
+	// BroadcastOnLinkUp sends an extra broadcast as soon as the interface regains link
+	// Off by default since a flapping link causes a send on every up transition
+	BroadcastOnLinkUp bool `toml:"broadcast_on_link_up"`
+
+	// BroadcastMaxDuration stops broadcasting this many minutes after it starts, so
+	// an agent isn't left advertising on a production switch (0 = no limit)
+	BroadcastMaxDuration int `toml:"broadcast_max_duration"`
+
 	// AdvertiseInterval is the interval between broadcast packets in seconds
 	AdvertiseInterval int `toml:"advertise_interval"`
 
 	// TTL is the time-to-live for advertised information in seconds
 	TTL int `toml:"ttl"`
 
+	// FixShortTTL raises TTL to RecommendedTTLRatio times the advertise interval when
+	// it doesn't outlast the interval. When false the mismatch is only warned about
+	FixShortTTL bool `toml:"fix_short_ttl"`
+
+	// LLDPMaxFrameSize is the largest LLDPDU (frame payload, in bytes) that will be broadcast
+	// Optional TLVs are trimmed to fit; the interface MTU also caps the size. 0 means use the default
+	LLDPMaxFrameSize int `toml:"lldp_max_frame_size"`
+
+	// BroadcastVariation varies the advertised device ID and source MAC on every
+	// interval so switches see many neighbors: "off", "counter" or "random"
+	// Lab/test use only - this floods MAC and neighbor tables
+	BroadcastVariation string `toml:"broadcast_variation"`
+
 	// Capabilities is the list of capabilities to advertise (router, bridge, station, etc.)
 	Capabilities []string `toml:"capabilities"`
 
@@ -52,42 +126,200 @@ type Config struct {
 	FilterCapabilities []string `toml:"filter_capabilities"`
 
 	// StalenessTimeout is the number of seconds before a neighbor is marked as stale (grayed out)
+	// 0 means never mark neighbors stale, by their TTL included
 	StalenessTimeout int `toml:"staleness_timeout"`
 
+	// StalenessFromTTL marks a neighbor stale once its advertised TTL (hold time) has
+	// passed, plus a short grace. StalenessTimeout is used for neighbors without a TTL
+	StalenessFromTTL bool `toml:"staleness_from_ttl"`
+
 	// StaleRemovalTime is the number of seconds before a stale neighbor is removed from display
 	// 0 means never remove stale neighbors
 	StaleRemovalTime int `toml:"stale_removal_time"`
 
+	// ClearOnLinkDown removes all neighbors when the interface loses link
+	ClearOnLinkDown bool `toml:"clear_on_link_down"`
+
+	// MergeAcrossInterfaces keys neighbors by device ID (or MAC) alone, so a device
+	// heard on several local interfaces is one neighbor listing all of them
+	MergeAcrossInterfaces bool `toml:"merge_across_interfaces"`
+
+	// FlapWindow is the number of seconds of neighbor changes considered for link instability
+	FlapWindow int `toml:"flap_window"`
+
+	// FlapThreshold is the number of neighbor changes within FlapWindow that flags
+	// an interface as possibly unstable. 0 disables detection
+	FlapThreshold int `toml:"flap_threshold"`
+
 	// LoggingEnabled controls whether neighbor events are logged to files
 	LoggingEnabled bool `toml:"logging_enabled"`
 
 	// LogDirectory is the directory where log files are stored
 	LogDirectory string `toml:"log_directory"`
 
+	// LogFormat is the format of the new-neighbor log: "csv" or "jsonl"
+	// (one JSON object per line, keeping multi-valued fields like capabilities)
+	LogFormat string `toml:"log_format"`
+
+	// LogMaxSizeMB starts a new timestamped log file once the current one reaches
+	// this many megabytes, so a long-running probe can't fill the disk. 0 = no limit
+	LogMaxSizeMB int `toml:"log_max_size_mb"`
+
+	// MapFormat is the format of the interface-to-neighbor map written with the m key:
+	// "markdown", "csv" or "text". Files go in LogDirectory
+	MapFormat string `toml:"map_format"`
+
+	// SQLitePath is the SQLite database that neighbor sightings are exported to
+	// Empty disables SQLite export
+	SQLitePath string `toml:"sqlite_path"`
+
+	// SyslogServer receives an RFC 5424 message for each new neighbor, as host:port
+	// (UDP), udp://host:port or tcp://host:port. Empty disables syslog
+	SyslogServer string `toml:"syslog_server"`
+
+	// WebhookURL receives a JSON POST for each new neighbor. Empty disables the webhook
+	WebhookURL string `toml:"webhook_url"`
+
+	// NewNeighborCommand is run when a new neighbor appears, with {hostname}, {ip},
+	// {port} and other placeholders substituted. Empty disables the hook
+	NewNeighborCommand string `toml:"new_neighbor_command"`
+
 	// AutoSelectInterface automatically selects the interface if only one wired interface is available
 	AutoSelectInterface bool `toml:"auto_select_interface"`
+
+	// PickerSummary shows what was last seen on each interface, by capability, in the
+	// interface picker. Needs sqlite_path, since the counts come from the recorded history
+	PickerSummary bool `toml:"picker_summary"`
+
+	// FlashDurationMS is how long new or updated rows stay highlighted, in milliseconds
+	// Highlights are cleared on the once-per-second tick. 0 means use the default
+	FlashDurationMS int `toml:"flash_duration_ms"`
+
+	// EventStripLines shows the latest new, stale and removed neighbors on this many
+	// lines above the footer (0-2). 0 turns the strip off
+	EventStripLines int `toml:"event_strip_lines"`
+
+	// PingCount is how many echo requests the detail view's ping action sends
+	PingCount int `toml:"ping_count"`
+
+	// ResolvePTR looks up each neighbor's management IP in reverse DNS and shows
+	// the name in the detail view. Off by default so nbor makes no DNS queries
+	ResolvePTR bool `toml:"resolve_ptr"`
+
+	// DetailWrapFields lists detail popup fields that wrap across lines instead of
+	// being truncated: "platform", "description", "location", "ports" and/or "note"
+	DetailWrapFields []string `toml:"detail_wrap_fields"`
+
+	// RefreshKey sets what the r key does: "redraw" repaints the screen keeping the
+	// selection and scroll position, "reset" also clears highlights and scrolls to the top
+	// R always does the full reset
+	RefreshKey string `toml:"refresh_key"`
+
+	// CtrlCBack makes Ctrl+C close popups and sub-menus like ESC, quitting only
+	// from the neighbor table or interface picker
+	CtrlCBack bool `toml:"ctrl_c_back"`
+
+	// CollapseStacked shows neighbors with the same chassis ID, hostname and platform
+	// (e.g. stacked or MLAG switches seen on several ports) as a single row
+	CollapseStacked bool `toml:"collapse_stacked"`
+
+	// BellOnNew rings the terminal bell when a new neighbor appears (at most once
+	// every couple of seconds, so a burst of neighbors rings once)
+	BellOnNew bool `toml:"bell_on_new"`
+
+	// ListeningAnimation animates the ellipsis on the "Listening..." message
+	ListeningAnimation bool `toml:"listening_animation"`
+
+	// QuietWarningSeconds is how long nbor waits without a CDP or LLDP packet before
+	// the empty table suggests checking the cable, switch settings and privileges
+	// 0 turns the warning off
+	QuietWarningSeconds int `toml:"quiet_warning_seconds"`
+
+	// ProtocolColors tints the table's Proto cell by protocol (CDP, LLDP or both)
+	// using the protocol badge colors, instead of the row's state color
+	ProtocolColors bool `toml:"protocol_colors"`
+
+	// StaleFade fades a row from its active color to gray over a few seconds when
+	// the neighbor goes stale, instead of switching at once
+	StaleFade bool `toml:"stale_fade"`
+
+	// ShowDiscoveredColumn adds a Discovered column with how long ago each
+	// neighbor was first seen, to spot devices that appeared late in a capture
+	ShowDiscoveredColumn bool `toml:"show_discovered_column"`
+
+	// ShowCaptureStats shows the packets captured and dropped by pcap in the
+	// neighbor table footer, to tell a capture problem from a parsing one
+	ShowCaptureStats bool `toml:"show_capture_stats"`
+
+	// ShowLocalAddresses lists the capture interface's own MAC and IP addresses in
+	// Broadcast Options, marking which are sent in the CDP/LLDP address TLVs
+	ShowLocalAddresses bool `toml:"show_local_addresses"`
+
+	// Profiles are named sets of settings, each a [profiles.<name>] table, that
+	// --profile applies over the rest of the file
+	Profiles map[string]map[string]any `toml:"profiles"`
 }
 
 // DefaultConfig returns the default configuration
 func DefaultConfig() Config {
 	return Config{
-		Theme:              "solarized-dark",
-		SystemName:         "", // Empty means use hostname
-		SystemDescription:  "", // Empty means use default "nbor vX.Y.Z"
-		CDPListen:          true,
-		CDPBroadcast:       false,
-		LLDPListen:         true,
-		LLDPBroadcast:      false,
-		BroadcastOnStartup: false,
-		AdvertiseInterval:  5,
-		TTL:                20,
-		Capabilities:       []string{"station"},
-		FilterCapabilities: []string{}, // Empty means show all
-		StalenessTimeout:   180,         // 3 minutes
-		StaleRemovalTime:   0,           // Never remove
-		LoggingEnabled:      true,
-		LogDirectory:        "", // Empty means use default location
-		AutoSelectInterface: true,
+		Theme:                "solarized-dark",
+		ThemeFilter:          "all",
+		ColorProfile:         "truecolor",
+		CursorColor:          "base0d", // Blue
+		FlashColor:           "base0b", // Green
+		SystemName:           "",       // Empty means use hostname
+		AdvertiseUseFQDN:     false,
+		AdvertiseNameSuffix:  "",
+		SystemDescription:    "", // Empty means use default "nbor vX.Y.Z"
+		PlatformString:       "", // Empty means "nbor"
+		CDPListen:            true,
+		CDPStrictChecksum:    false,
+		CDPBroadcast:         false,
+		LLDPListen:           true,
+		LLDPBroadcast:        false,
+		CaptureSnapLen:       65535,
+		CaptureBufferMB:      0, // OS default
+		BroadcastOnStartup:   false,
+		ConfirmBroadcast:     true,
+		BroadcastOnLinkUp:    false,
+		BroadcastMaxDuration: 0, // No limit
+		AdvertiseInterval:    5,
+		TTL:                  20,
+		FixShortTTL:          false,
+		LLDPMaxFrameSize:     1500, // Standard LLDPDU limit
+		BroadcastVariation:   "off",
+		Capabilities:         []string{"station"},
+		FilterCapabilities:   []string{}, // Empty means show all
+		StalenessTimeout:     180,        // 3 minutes
+		StalenessFromTTL:     true,
+		StaleRemovalTime:     0, // Never remove
+		ClearOnLinkDown:      false,
+		FlapWindow:           600, // 10 minutes
+		FlapThreshold:        3,
+		LoggingEnabled:       true,
+		LogDirectory:         "", // Empty means use default location
+		LogFormat:            "csv",
+		LogMaxSizeMB:         0, // No limit
+		MapFormat:            "markdown",
+		AutoSelectInterface:  true,
+		PickerSummary:        true,
+		FlashDurationMS:      2000,
+		PingCount:            4,
+		ResolvePTR:           false,
+		EventStripLines:      0,
+		DetailWrapFields:     []string{"description"},
+		RefreshKey:           "redraw",
+		CtrlCBack:            false,
+		CollapseStacked:      false,
+		BellOnNew:            true,
+		ListeningAnimation:   true,
+		QuietWarningSeconds:  90, // 2x the typical advertise interval
+		ProtocolColors:       false,
+		StaleFade:            true,
+		ShowDiscoveredColumn: false,
+		ShowCaptureStats:     false,
+		ShowLocalAddresses:   true,
 	}
 }
 
@@ -135,6 +367,15 @@ func GetConfigPath() (string, error) {
 	return filepath.Join(dir, "config.toml"), nil
 }
 
+// GetThemesDir returns the directory user theme files are loaded from
+func GetThemesDir() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "themes"), nil
+}
+
 // Load reads the configuration from the config file
 // Returns default config if file doesn't exist
 func Load() (Config, error) {
@@ -160,11 +401,15 @@ func Load() (Config, error) {
 	if cfg.Theme == "" {
 		cfg.Theme = defaults.Theme
 	}
-	// Note: SystemName and SystemDescription empty is valid (means use defaults at runtime)
+	// Note: SystemName, SystemDescription and PlatformString empty is valid (means use defaults at runtime)
+	// AdvertiseNameSuffix empty is valid too (means no suffix)
 
 	// For bool fields, use metadata to check if they were actually defined
 	// This allows us to distinguish between "not set" and "explicitly set to false"
 	// Check ALL boolean fields for consistency and future-proofing
+	if !meta.IsDefined("advertise_use_fqdn") {
+		cfg.AdvertiseUseFQDN = defaults.AdvertiseUseFQDN
+	}
 	if !meta.IsDefined("cdp_listen") {
 		cfg.CDPListen = defaults.CDPListen
 	}
@@ -180,12 +425,79 @@ func Load() (Config, error) {
 	if !meta.IsDefined("broadcast_on_startup") {
 		cfg.BroadcastOnStartup = defaults.BroadcastOnStartup
 	}
+	if !meta.IsDefined("confirm_broadcast") {
+		cfg.ConfirmBroadcast = defaults.ConfirmBroadcast
+	}
+	if !meta.IsDefined("broadcast_on_link_up") {
+		cfg.BroadcastOnLinkUp = defaults.BroadcastOnLinkUp
+	}
+	if !meta.IsDefined("clear_on_link_down") {
+		cfg.ClearOnLinkDown = defaults.ClearOnLinkDown
+	}
+	if !meta.IsDefined("resolve_ptr") {
+		cfg.ResolvePTR = defaults.ResolvePTR
+	}
 	if !meta.IsDefined("logging_enabled") {
 		cfg.LoggingEnabled = defaults.LoggingEnabled
 	}
 	if !meta.IsDefined("auto_select_interface") {
 		cfg.AutoSelectInterface = defaults.AutoSelectInterface
 	}
+	if !meta.IsDefined("picker_summary") {
+		cfg.PickerSummary = defaults.PickerSummary
+	}
+	if !meta.IsDefined("bell_on_new") {
+		cfg.BellOnNew = defaults.BellOnNew
+	}
+	if !meta.IsDefined("listening_animation") {
+		cfg.ListeningAnimation = defaults.ListeningAnimation
+	}
+	if !meta.IsDefined("protocol_colors") {
+		cfg.ProtocolColors = defaults.ProtocolColors
+	}
+	if !meta.IsDefined("stale_fade") {
+		cfg.StaleFade = defaults.StaleFade
+	}
+	if !meta.IsDefined("show_discovered_column") {
+		cfg.ShowDiscoveredColumn = defaults.ShowDiscoveredColumn
+	}
+	if !meta.IsDefined("show_capture_stats") {
+		cfg.ShowCaptureStats = defaults.ShowCaptureStats
+	}
+	if !meta.IsDefined("show_local_addresses") {
+		cfg.ShowLocalAddresses = defaults.ShowLocalAddresses
+	}
+	if !meta.IsDefined("cdp_strict_checksum") {
+		cfg.CDPStrictChecksum = defaults.CDPStrictChecksum
+	}
+	if !meta.IsDefined("staleness_from_ttl") {
+		cfg.StalenessFromTTL = defaults.StalenessFromTTL
+	}
+
+	if cfg.ThemeFilter == "" {
+		cfg.ThemeFilter = defaults.ThemeFilter
+	}
+	if cfg.ColorProfile == "" {
+		cfg.ColorProfile = defaults.ColorProfile
+	}
+	if cfg.CursorColor == "" {
+		cfg.CursorColor = defaults.CursorColor
+	}
+	if cfg.FlashColor == "" {
+		cfg.FlashColor = defaults.FlashColor
+	}
+	if cfg.BroadcastVariation == "" {
+		cfg.BroadcastVariation = defaults.BroadcastVariation
+	}
+	if cfg.RefreshKey == "" {
+		cfg.RefreshKey = defaults.RefreshKey
+	}
+	if cfg.LogFormat == "" {
+		cfg.LogFormat = defaults.LogFormat
+	}
+	if cfg.MapFormat == "" {
+		cfg.MapFormat = defaults.MapFormat
+	}
 
 	// Fill in missing numeric defaults (0 means not set for these)
 	if cfg.AdvertiseInterval <= 0 {
@@ -194,17 +506,48 @@ func Load() (Config, error) {
 	if cfg.TTL <= 0 {
 		cfg.TTL = defaults.TTL
 	}
+	if cfg.LLDPMaxFrameSize <= 0 {
+		cfg.LLDPMaxFrameSize = defaults.LLDPMaxFrameSize
+	}
+	if cfg.CaptureSnapLen <= 0 {
+		cfg.CaptureSnapLen = defaults.CaptureSnapLen
+	}
+	// CaptureBufferMB: 0 is valid (means OS default), so don't fill default
 	if len(cfg.Capabilities) == 0 {
 		cfg.Capabilities = defaults.Capabilities
 	}
+	// DetailWrapFields: empty is valid (means truncate everything), so only fill when missing
+	if !meta.IsDefined("detail_wrap_fields") {
+		cfg.DetailWrapFields = defaults.DetailWrapFields
+	}
 
 	// Fill in new field defaults
 	// FilterCapabilities: empty is valid (means show all), so don't fill default
-	if cfg.StalenessTimeout <= 0 {
+	// QuietWarningSeconds: 0 is valid (means no warning), so only fill when missing
+	if !meta.IsDefined("quiet_warning_seconds") {
+		cfg.QuietWarningSeconds = defaults.QuietWarningSeconds
+	}
+	// StalenessTimeout: 0 is valid (means never stale), so only fill when missing
+	if !meta.IsDefined("staleness_timeout") {
 		cfg.StalenessTimeout = defaults.StalenessTimeout
 	}
 	// StaleRemovalTime: 0 is valid (means never remove), so don't fill default
+	// BroadcastMaxDuration: 0 is valid (means no limit), so don't fill default
+	if cfg.FlapWindow <= 0 {
+		cfg.FlapWindow = defaults.FlapWindow
+	}
+	// FlapThreshold: 0 is valid (means disabled), so only fill when missing
+	if !meta.IsDefined("flap_threshold") {
+		cfg.FlapThreshold = defaults.FlapThreshold
+	}
 	// LogDirectory: empty is valid (means use default location)
+	// LogMaxSizeMB: 0 is valid (means no limit), so don't fill default
+	if cfg.FlashDurationMS <= 0 {
+		cfg.FlashDurationMS = defaults.FlashDurationMS
+	}
+	if cfg.PingCount <= 0 {
+		cfg.PingCount = defaults.PingCount
+	}
 
 	// Validate and fix any out-of-range values
 	cfg.ValidateAndFix()
@@ -240,27 +583,66 @@ func Save(cfg Config) error {
 		"",
 		"# Visual theme (use slug format with hyphens, e.g., tokyo-night, catppuccin-mocha)",
 		fmt.Sprintf("theme = %q", cfg.Theme),
+		"# theme_filter limits the theme menu to \"all\", \"dark\" or \"light\" themes",
+		fmt.Sprintf("theme_filter = %q", cfg.ThemeFilter),
+		"# auto_save_theme saves a theme picked in the theme menu right away",
+		fmt.Sprintf("auto_save_theme = %t", cfg.AutoSaveTheme),
+		"# color_profile is \"auto\" (detect), \"truecolor\", \"256\", \"16\" or \"ascii\"",
+		fmt.Sprintf("color_profile = %q", cfg.ColorProfile),
+		"# Base16 slots (base00-base0f) for the selection cursor and new/flashing rows",
+		fmt.Sprintf("cursor_color = %q", cfg.CursorColor),
+		fmt.Sprintf("flash_color = %q", cfg.FlashColor),
 		"",
 		"# System Identity",
 		"# system_name defaults to hostname if empty",
 		fmt.Sprintf("system_name = %q", cfg.SystemName),
+		"# advertise_use_fqdn advertises the hostname's fully qualified name from DNS",
+		fmt.Sprintf("advertise_use_fqdn = %t", cfg.AdvertiseUseFQDN),
+		"# advertise_name_suffix is appended to the advertised name, e.g. \"-lab\"",
+		fmt.Sprintf("advertise_name_suffix = %q", cfg.AdvertiseNameSuffix),
 		fmt.Sprintf("system_description = %q", cfg.SystemDescription),
+		"# platform_string is advertised as the CDP platform and appended to the LLDP description (empty = \"nbor\")",
+		fmt.Sprintf("platform_string = %q", cfg.PlatformString),
 		"",
 		"# Protocol Listening",
 		fmt.Sprintf("cdp_listen = %t", cfg.CDPListen),
+		"# cdp_strict_checksum drops CDP frames with a bad checksum instead of parsing them",
+		fmt.Sprintf("cdp_strict_checksum = %t", cfg.CDPStrictChecksum),
 		fmt.Sprintf("lldp_listen = %t", cfg.LLDPListen),
+		"# capture_bpf replaces the built-in CDP/LLDP capture filter (empty = built-in)",
+		"# Only CDP and LLDP frames are decoded, whatever the filter lets through",
+		fmt.Sprintf("capture_bpf = %q", cfg.CaptureBPF),
+		"# capture_bpf_extra is ANDed onto the capture filter, e.g. \"ether src 00:11:22:33:44:55\"",
+		fmt.Sprintf("capture_bpf_extra = %q", cfg.CaptureBPFExtra),
+		"# capture_snaplen is the pcap snapshot length in bytes (1518-262144)",
+		fmt.Sprintf("capture_snaplen = %d", cfg.CaptureSnapLen),
+		"# capture_buffer_mb is the kernel capture buffer; raise it if busy links drop frames (0 = OS default)",
+		fmt.Sprintf("capture_buffer_mb = %d", cfg.CaptureBufferMB),
 		"",
 		"# Protocol Broadcasting",
 		fmt.Sprintf("cdp_broadcast = %t", cfg.CDPBroadcast),
 		fmt.Sprintf("lldp_broadcast = %t", cfg.LLDPBroadcast),
 		"# broadcast_on_startup controls whether broadcasting starts automatically",
 		fmt.Sprintf("broadcast_on_startup = %t", cfg.BroadcastOnStartup),
+		"# confirm_broadcast asks before the b key starts broadcasting",
+		fmt.Sprintf("confirm_broadcast = %t", cfg.ConfirmBroadcast),
+		"# broadcast_on_link_up sends immediately when the interface regains link",
+		fmt.Sprintf("broadcast_on_link_up = %t", cfg.BroadcastOnLinkUp),
+		"# broadcast_max_duration stops broadcasting after this many minutes (0 = no limit)",
+		fmt.Sprintf("broadcast_max_duration = %d", cfg.BroadcastMaxDuration),
 		"",
 		"# Broadcasting Settings",
 		"# advertise_interval is the time between broadcasts in seconds",
 		fmt.Sprintf("advertise_interval = %d", cfg.AdvertiseInterval),
 		"# ttl is the time-to-live for advertised information in seconds",
 		fmt.Sprintf("ttl = %d", cfg.TTL),
+		"# fix_short_ttl raises ttl to 3x advertise_interval if it isn't longer than the interval",
+		fmt.Sprintf("fix_short_ttl = %t", cfg.FixShortTTL),
+		"# lldp_max_frame_size is the largest LLDPDU in bytes; optional TLVs are trimmed to fit",
+		fmt.Sprintf("lldp_max_frame_size = %d", cfg.LLDPMaxFrameSize),
+		"# broadcast_variation changes the device ID and source MAC every interval (\"off\", \"counter\", \"random\")",
+		"# WARNING: lab/test use only - floods switch MAC and neighbor tables",
+		fmt.Sprintf("broadcast_variation = %q", cfg.BroadcastVariation),
 		"",
 		"# Capabilities to advertise (router, bridge, station, switch, phone, etc.)",
 		fmt.Sprintf("capabilities = %s", formatStringSlice(cfg.Capabilities)),
@@ -271,19 +653,86 @@ func Save(cfg Config) error {
 		fmt.Sprintf("filter_capabilities = %s", formatStringSlice(cfg.FilterCapabilities)),
 		"",
 		"# Staleness Settings",
-		"# staleness_timeout is seconds before a neighbor is grayed out (default 180)",
+		"# staleness_timeout is seconds before a neighbor is grayed out (default 180, 0 = never)",
 		fmt.Sprintf("staleness_timeout = %d", cfg.StalenessTimeout),
+		"# staleness_from_ttl uses each neighbor's advertised TTL instead, when it sent one",
+		fmt.Sprintf("staleness_from_ttl = %t", cfg.StalenessFromTTL),
 		"# stale_removal_time is seconds before stale neighbors are removed (0 = never)",
 		fmt.Sprintf("stale_removal_time = %d", cfg.StaleRemovalTime),
+		"# clear_on_link_down removes all neighbors when the interface loses link",
+		fmt.Sprintf("clear_on_link_down = %t", cfg.ClearOnLinkDown),
+		"# merge_across_interfaces shows a device heard on several interfaces once",
+		fmt.Sprintf("merge_across_interfaces = %t", cfg.MergeAcrossInterfaces),
+		"",
+		"# Link Instability Detection",
+		"# flag an interface when neighbors keep replacing each other",
+		"# flap_threshold changes within flap_window seconds (0 = disabled)",
+		fmt.Sprintf("flap_window = %d", cfg.FlapWindow),
+		fmt.Sprintf("flap_threshold = %d", cfg.FlapThreshold),
 		"",
 		"# Logging",
 		fmt.Sprintf("logging_enabled = %t", cfg.LoggingEnabled),
 		"# log_directory is where log files are stored (empty = default location)",
 		fmt.Sprintf("log_directory = %q", cfg.LogDirectory),
+		"# log_format is the format of the new neighbor log (\"csv\", \"jsonl\")",
+		fmt.Sprintf("log_format = %q", cfg.LogFormat),
+		"# log_max_size_mb starts a new log file when the current one reaches this size (0 = no limit)",
+		fmt.Sprintf("log_max_size_mb = %d", cfg.LogMaxSizeMB),
+		"# map_format is the format of the interface map written with m (\"markdown\", \"csv\", \"text\")",
+		fmt.Sprintf("map_format = %q", cfg.MapFormat),
+		"# sqlite_path exports every neighbor sighting to a SQLite database (empty = disabled)",
+		fmt.Sprintf("sqlite_path = %q", cfg.SQLitePath),
+		"# syslog_server gets an RFC 5424 message per new neighbor (host:port, udp:// or tcp://; empty = disabled)",
+		fmt.Sprintf("syslog_server = %q", cfg.SyslogServer),
+		"",
+		"# New Neighbor Hook",
+		"# new_neighbor_command runs when a neighbor first appears (empty = disabled)",
+		"# Placeholders: {hostname} {ip} {port} {mac} {platform} {protocol} {interface} {id}",
+		"# Values come from untrusted network packets; the command is never run through a shell",
+		fmt.Sprintf("new_neighbor_command = %q", cfg.NewNeighborCommand),
+		"# webhook_url gets a JSON POST when a neighbor first appears (empty = disabled)",
+		fmt.Sprintf("webhook_url = %q", cfg.WebhookURL),
 		"",
 		"# Interface Selection",
 		"# auto_select_interface skips the picker when only one wired interface is available",
 		fmt.Sprintf("auto_select_interface = %t", cfg.AutoSelectInterface),
+		"# picker_summary lists what was last seen on each interface (needs sqlite_path)",
+		fmt.Sprintf("picker_summary = %t", cfg.PickerSummary),
+		"",
+		"# Display",
+		"# flash_duration_ms is how long new/updated rows are highlighted (500-60000)",
+		fmt.Sprintf("flash_duration_ms = %d", cfg.FlashDurationMS),
+		"# event_strip_lines shows recent new/stale/removed neighbors above the footer (0 = off, max 2)",
+		fmt.Sprintf("event_strip_lines = %d", cfg.EventStripLines),
+		"# ping_count is how many pings p sends from the detail view (1-100)",
+		fmt.Sprintf("ping_count = %d", cfg.PingCount),
+		"# resolve_ptr looks up neighbor management IPs in reverse DNS for the detail view",
+		fmt.Sprintf("resolve_ptr = %t", cfg.ResolvePTR),
+		"# detail_wrap_fields wraps long detail popup fields instead of truncating them",
+		"# Any of: platform, description, location, ports, note",
+		fmt.Sprintf("detail_wrap_fields = %s", formatStringSlice(cfg.DetailWrapFields)),
+		"# refresh_key is what r does: \"redraw\" keeps your place, \"reset\" also clears highlights (R always resets)",
+		fmt.Sprintf("refresh_key = %q", cfg.RefreshKey),
+		"# ctrl_c_back makes Ctrl+C go back from popups and sub-menus instead of quitting",
+		fmt.Sprintf("ctrl_c_back = %t", cfg.CtrlCBack),
+		"# collapse_stacked shows one row per device for stacked/MLAG switches seen on several ports",
+		fmt.Sprintf("collapse_stacked = %t", cfg.CollapseStacked),
+		"# bell_on_new rings the terminal bell when a new neighbor appears (--quiet turns it off)",
+		fmt.Sprintf("bell_on_new = %t", cfg.BellOnNew),
+		"# listening_animation animates the ellipsis while waiting for neighbors",
+		fmt.Sprintf("listening_animation = %t", cfg.ListeningAnimation),
+		"# quiet_warning_seconds suggests what to check when no CDP/LLDP arrives for this long (0 = off)",
+		fmt.Sprintf("quiet_warning_seconds = %d", cfg.QuietWarningSeconds),
+		"# protocol_colors tints the Proto column by protocol (CDP, LLDP or both)",
+		fmt.Sprintf("protocol_colors = %t", cfg.ProtocolColors),
+		"# stale_fade fades rows to gray over a few seconds when a neighbor goes stale",
+		fmt.Sprintf("stale_fade = %t", cfg.StaleFade),
+		"# show_discovered_column adds a column with how long ago each neighbor was first seen",
+		fmt.Sprintf("show_discovered_column = %t", cfg.ShowDiscoveredColumn),
+		"# show_capture_stats shows packets captured and dropped by pcap in the footer",
+		fmt.Sprintf("show_capture_stats = %t", cfg.ShowCaptureStats),
+		"# show_local_addresses lists this interface's addresses in Broadcast Options",
+		fmt.Sprintf("show_local_addresses = %t", cfg.ShowLocalAddresses),
 		"",
 	}
 
@@ -293,7 +742,7 @@ func Save(cfg Config) error {
 		}
 	}
 
-	return nil
+	return writeProfiles(file, cfg.Profiles)
 }
 
 // formatStringSlice formats a string slice as a TOML array
@@ -320,12 +769,44 @@ func (c *Config) Validate() []string {
 			c.AdvertiseInterval, defaults.AdvertiseInterval))
 	}
 
+	// BroadcastMaxDuration: 0-1440 minutes (0 = no limit)
+	if c.BroadcastMaxDuration < 0 || c.BroadcastMaxDuration > 1440 {
+		errors = append(errors, fmt.Sprintf("broadcast_max_duration %d out of range (0-1440), using default %d",
+			c.BroadcastMaxDuration, defaults.BroadcastMaxDuration))
+	}
+
 	// TTL: 1-65535 seconds
 	if c.TTL < 1 || c.TTL > 65535 {
 		errors = append(errors, fmt.Sprintf("ttl %d out of range (1-65535), using default %d",
 			c.TTL, defaults.TTL))
 	}
 
+	// TTL must outlast the advertise interval or neighbors flap on the receiving side
+	if warning := c.ShortTTLWarning(); warning != "" {
+		if c.FixShortTTL && c.ShortTTLFixable() {
+			warning += fmt.Sprintf(", raising ttl to %d", c.RecommendedTTL())
+		}
+		errors = append(errors, warning)
+	}
+
+	// CaptureSnapLen: 1518-262144 bytes (0 = use default)
+	if c.CaptureSnapLen != 0 && (c.CaptureSnapLen < 1518 || c.CaptureSnapLen > 262144) {
+		errors = append(errors, fmt.Sprintf("capture_snaplen %d out of range (1518-262144), using default %d",
+			c.CaptureSnapLen, defaults.CaptureSnapLen))
+	}
+
+	// CaptureBufferMB: 0-1024 megabytes (0 = OS default)
+	if c.CaptureBufferMB < 0 || c.CaptureBufferMB > 1024 {
+		errors = append(errors, fmt.Sprintf("capture_buffer_mb %d out of range (0-1024), using default %d",
+			c.CaptureBufferMB, defaults.CaptureBufferMB))
+	}
+
+	// LLDPMaxFrameSize: 128-9216 bytes (0 = use default)
+	if c.LLDPMaxFrameSize != 0 && (c.LLDPMaxFrameSize < 128 || c.LLDPMaxFrameSize > 9216) {
+		errors = append(errors, fmt.Sprintf("lldp_max_frame_size %d out of range (128-9216), using default %d",
+			c.LLDPMaxFrameSize, defaults.LLDPMaxFrameSize))
+	}
+
 	// StalenessTimeout: 0-86400 seconds (0 = disable staleness)
 	if c.StalenessTimeout < 0 || c.StalenessTimeout > 86400 {
 		errors = append(errors, fmt.Sprintf("staleness_timeout %d out of range (0-86400), using default %d",
@@ -338,6 +819,106 @@ func (c *Config) Validate() []string {
 			c.StaleRemovalTime, defaults.StaleRemovalTime))
 	}
 
+	// FlapWindow: 0-86400 seconds (0 = use default)
+	if c.FlapWindow < 0 || c.FlapWindow > 86400 {
+		errors = append(errors, fmt.Sprintf("flap_window %d out of range (0-86400), using default %d",
+			c.FlapWindow, defaults.FlapWindow))
+	}
+
+	// FlapThreshold: 0-100 changes (0 = disabled)
+	if c.FlapThreshold < 0 || c.FlapThreshold > 100 {
+		errors = append(errors, fmt.Sprintf("flap_threshold %d out of range (0-100), using default %d",
+			c.FlapThreshold, defaults.FlapThreshold))
+	}
+
+	// ThemeFilter: all, dark or light (empty = use default)
+	if !validThemeFilter(c.ThemeFilter) {
+		errors = append(errors, fmt.Sprintf("theme_filter %q invalid (all, dark, light), using default %q",
+			c.ThemeFilter, defaults.ThemeFilter))
+	}
+
+	// FlashDurationMS: 500-60000 milliseconds (0 = use default)
+	if c.FlashDurationMS != 0 && (c.FlashDurationMS < 500 || c.FlashDurationMS > 60000) {
+		errors = append(errors, fmt.Sprintf("flash_duration_ms %d out of range (500-60000), using default %d",
+			c.FlashDurationMS, defaults.FlashDurationMS))
+	}
+
+	// QuietWarningSeconds: 0-3600 seconds (0 = off)
+	if c.QuietWarningSeconds < 0 || c.QuietWarningSeconds > 3600 {
+		errors = append(errors, fmt.Sprintf("quiet_warning_seconds %d out of range (0-3600), using default %d",
+			c.QuietWarningSeconds, defaults.QuietWarningSeconds))
+	}
+
+	// EventStripLines: 0-2 lines (0 = off)
+	if c.EventStripLines < 0 || c.EventStripLines > 2 {
+		errors = append(errors, fmt.Sprintf("event_strip_lines %d out of range (0-2), using default %d",
+			c.EventStripLines, defaults.EventStripLines))
+	}
+
+	// PingCount: 1-100 echo requests (0 = use default)
+	if c.PingCount < 0 || c.PingCount > 100 {
+		errors = append(errors, fmt.Sprintf("ping_count %d out of range (1-100), using default %d",
+			c.PingCount, defaults.PingCount))
+	}
+
+	// ColorProfile: auto, truecolor, 256, 16 or ascii (empty = use default)
+	if !validColorProfile(c.ColorProfile) {
+		errors = append(errors, fmt.Sprintf("color_profile %q invalid (auto, truecolor, 256, 16, ascii), using default %q",
+			c.ColorProfile, defaults.ColorProfile))
+	}
+
+	// CursorColor/FlashColor: Base16 slot names (empty = use default)
+	if !validColorSlot(c.CursorColor) {
+		errors = append(errors, fmt.Sprintf("cursor_color %q invalid (base00-base0f), using default %q",
+			c.CursorColor, defaults.CursorColor))
+	}
+	if !validColorSlot(c.FlashColor) {
+		errors = append(errors, fmt.Sprintf("flash_color %q invalid (base00-base0f), using default %q",
+			c.FlashColor, defaults.FlashColor))
+	}
+
+	// BroadcastVariation: off, counter or random (empty = use default)
+	if !validBroadcastVariation(c.BroadcastVariation) {
+		errors = append(errors, fmt.Sprintf("broadcast_variation %q invalid (off, counter, random), using default %q",
+			c.BroadcastVariation, defaults.BroadcastVariation))
+	}
+
+	// RefreshKey: redraw or reset (empty = use default)
+	if !validRefreshKey(c.RefreshKey) {
+		errors = append(errors, fmt.Sprintf("refresh_key %q invalid (redraw, reset), using default %q",
+			c.RefreshKey, defaults.RefreshKey))
+	}
+
+	// LogMaxSizeMB: 0-10240 megabytes (0 = no limit)
+	if c.LogMaxSizeMB < 0 || c.LogMaxSizeMB > 10240 {
+		errors = append(errors, fmt.Sprintf("log_max_size_mb %d out of range (0-10240), using default %d",
+			c.LogMaxSizeMB, defaults.LogMaxSizeMB))
+	}
+
+	// LogFormat: csv or jsonl (empty = use default)
+	if !validLogFormat(c.LogFormat) {
+		errors = append(errors, fmt.Sprintf("log_format %q invalid (csv, jsonl), using default %q",
+			c.LogFormat, defaults.LogFormat))
+	}
+
+	// MapFormat: markdown, csv or text (empty = use default)
+	if !validMapFormat(c.MapFormat) {
+		errors = append(errors, fmt.Sprintf("map_format %q invalid (markdown, csv, text), using default %q",
+			c.MapFormat, defaults.MapFormat))
+	}
+
+	// SyslogServer: host:port with an optional udp:// or tcp:// scheme (empty = disabled)
+	if !validSyslogServer(c.SyslogServer) {
+		errors = append(errors, fmt.Sprintf("syslog_server %q invalid (host:port, udp://host:port or tcp://host:port), syslog disabled",
+			c.SyslogServer))
+	}
+
+	// WebhookURL: http:// or https:// URL (empty = disabled)
+	if !validWebhookURL(c.WebhookURL) {
+		errors = append(errors, fmt.Sprintf("webhook_url %q invalid (http:// or https:// URL), webhook disabled",
+			c.WebhookURL))
+	}
+
 	return errors
 }
 
@@ -353,12 +934,42 @@ func (c *Config) ValidateAndFix() []string {
 		c.AdvertiseInterval = defaults.AdvertiseInterval
 	}
 
+	// BroadcastMaxDuration: 0-1440 minutes
+	if c.BroadcastMaxDuration < 0 || c.BroadcastMaxDuration > 1440 {
+		fixed = append(fixed, fmt.Sprintf("broadcast_max_duration: %d -> %d", c.BroadcastMaxDuration, defaults.BroadcastMaxDuration))
+		c.BroadcastMaxDuration = defaults.BroadcastMaxDuration
+	}
+
 	// TTL: 1-65535 seconds
 	if c.TTL < 1 || c.TTL > 65535 {
 		fixed = append(fixed, fmt.Sprintf("ttl: %d -> %d", c.TTL, defaults.TTL))
 		c.TTL = defaults.TTL
 	}
 
+	// TTL shorter than the advertise interval: only fixed when fix_short_ttl is set
+	if c.FixShortTTL && c.ShortTTLFixable() {
+		fixed = append(fixed, fmt.Sprintf("ttl: %d -> %d", c.TTL, c.RecommendedTTL()))
+		c.TTL = c.RecommendedTTL()
+	}
+
+	// CaptureSnapLen: 1518-262144 bytes (0 = use default)
+	if c.CaptureSnapLen != 0 && (c.CaptureSnapLen < 1518 || c.CaptureSnapLen > 262144) {
+		fixed = append(fixed, fmt.Sprintf("capture_snaplen: %d -> %d", c.CaptureSnapLen, defaults.CaptureSnapLen))
+		c.CaptureSnapLen = defaults.CaptureSnapLen
+	}
+
+	// CaptureBufferMB: 0-1024 megabytes
+	if c.CaptureBufferMB < 0 || c.CaptureBufferMB > 1024 {
+		fixed = append(fixed, fmt.Sprintf("capture_buffer_mb: %d -> %d", c.CaptureBufferMB, defaults.CaptureBufferMB))
+		c.CaptureBufferMB = defaults.CaptureBufferMB
+	}
+
+	// LLDPMaxFrameSize: 128-9216 bytes (0 = use default)
+	if c.LLDPMaxFrameSize != 0 && (c.LLDPMaxFrameSize < 128 || c.LLDPMaxFrameSize > 9216) {
+		fixed = append(fixed, fmt.Sprintf("lldp_max_frame_size: %d -> %d", c.LLDPMaxFrameSize, defaults.LLDPMaxFrameSize))
+		c.LLDPMaxFrameSize = defaults.LLDPMaxFrameSize
+	}
+
 	// StalenessTimeout: 0-86400 seconds
 	if c.StalenessTimeout < 0 || c.StalenessTimeout > 86400 {
 		fixed = append(fixed, fmt.Sprintf("staleness_timeout: %d -> %d", c.StalenessTimeout, defaults.StalenessTimeout))
@@ -371,9 +982,263 @@ func (c *Config) ValidateAndFix() []string {
 		c.StaleRemovalTime = defaults.StaleRemovalTime
 	}
 
+	// FlapWindow: 0-86400 seconds
+	if c.FlapWindow < 0 || c.FlapWindow > 86400 {
+		fixed = append(fixed, fmt.Sprintf("flap_window: %d -> %d", c.FlapWindow, defaults.FlapWindow))
+		c.FlapWindow = defaults.FlapWindow
+	}
+
+	// FlapThreshold: 0-100 changes
+	if c.FlapThreshold < 0 || c.FlapThreshold > 100 {
+		fixed = append(fixed, fmt.Sprintf("flap_threshold: %d -> %d", c.FlapThreshold, defaults.FlapThreshold))
+		c.FlapThreshold = defaults.FlapThreshold
+	}
+
+	// ThemeFilter: all, dark or light
+	if !validThemeFilter(c.ThemeFilter) {
+		fixed = append(fixed, fmt.Sprintf("theme_filter: %q -> %q", c.ThemeFilter, defaults.ThemeFilter))
+		c.ThemeFilter = defaults.ThemeFilter
+	}
+
+	// FlashDurationMS: 500-60000 milliseconds (0 = use default)
+	if c.FlashDurationMS != 0 && (c.FlashDurationMS < 500 || c.FlashDurationMS > 60000) {
+		fixed = append(fixed, fmt.Sprintf("flash_duration_ms: %d -> %d", c.FlashDurationMS, defaults.FlashDurationMS))
+		c.FlashDurationMS = defaults.FlashDurationMS
+	}
+
+	// QuietWarningSeconds: 0-3600 seconds
+	if c.QuietWarningSeconds < 0 || c.QuietWarningSeconds > 3600 {
+		fixed = append(fixed, fmt.Sprintf("quiet_warning_seconds: %d -> %d", c.QuietWarningSeconds, defaults.QuietWarningSeconds))
+		c.QuietWarningSeconds = defaults.QuietWarningSeconds
+	}
+
+	// EventStripLines: 0-2 lines
+	if c.EventStripLines < 0 || c.EventStripLines > 2 {
+		fixed = append(fixed, fmt.Sprintf("event_strip_lines: %d -> %d", c.EventStripLines, defaults.EventStripLines))
+		c.EventStripLines = defaults.EventStripLines
+	}
+
+	// PingCount: 1-100 echo requests (0 = use default)
+	if c.PingCount < 0 || c.PingCount > 100 {
+		fixed = append(fixed, fmt.Sprintf("ping_count: %d -> %d", c.PingCount, defaults.PingCount))
+		c.PingCount = defaults.PingCount
+	}
+
+	// ColorProfile: auto, truecolor, 256, 16 or ascii
+	if !validColorProfile(c.ColorProfile) {
+		fixed = append(fixed, fmt.Sprintf("color_profile: %q -> %q", c.ColorProfile, defaults.ColorProfile))
+		c.ColorProfile = defaults.ColorProfile
+	}
+
+	// CursorColor/FlashColor: Base16 slot names
+	if !validColorSlot(c.CursorColor) {
+		fixed = append(fixed, fmt.Sprintf("cursor_color: %q -> %q", c.CursorColor, defaults.CursorColor))
+		c.CursorColor = defaults.CursorColor
+	}
+	if !validColorSlot(c.FlashColor) {
+		fixed = append(fixed, fmt.Sprintf("flash_color: %q -> %q", c.FlashColor, defaults.FlashColor))
+		c.FlashColor = defaults.FlashColor
+	}
+
+	// BroadcastVariation: off, counter or random
+	if !validBroadcastVariation(c.BroadcastVariation) {
+		fixed = append(fixed, fmt.Sprintf("broadcast_variation: %q -> %q", c.BroadcastVariation, defaults.BroadcastVariation))
+		c.BroadcastVariation = defaults.BroadcastVariation
+	}
+
+	// RefreshKey: redraw or reset
+	if !validRefreshKey(c.RefreshKey) {
+		fixed = append(fixed, fmt.Sprintf("refresh_key: %q -> %q", c.RefreshKey, defaults.RefreshKey))
+		c.RefreshKey = defaults.RefreshKey
+	}
+
+	// LogMaxSizeMB: 0-10240 megabytes
+	if c.LogMaxSizeMB < 0 || c.LogMaxSizeMB > 10240 {
+		fixed = append(fixed, fmt.Sprintf("log_max_size_mb: %d -> %d", c.LogMaxSizeMB, defaults.LogMaxSizeMB))
+		c.LogMaxSizeMB = defaults.LogMaxSizeMB
+	}
+
+	// LogFormat: csv or jsonl
+	if !validLogFormat(c.LogFormat) {
+		fixed = append(fixed, fmt.Sprintf("log_format: %q -> %q", c.LogFormat, defaults.LogFormat))
+		c.LogFormat = defaults.LogFormat
+	}
+
+	// MapFormat: markdown, csv or text
+	if !validMapFormat(c.MapFormat) {
+		fixed = append(fixed, fmt.Sprintf("map_format: %q -> %q", c.MapFormat, defaults.MapFormat))
+		c.MapFormat = defaults.MapFormat
+	}
+
+	// SyslogServer: disable rather than guess at a destination
+	if !validSyslogServer(c.SyslogServer) {
+		fixed = append(fixed, fmt.Sprintf("syslog_server: %q -> %q", c.SyslogServer, ""))
+		c.SyslogServer = ""
+	}
+
+	// WebhookURL: disable rather than guess at a destination
+	if !validWebhookURL(c.WebhookURL) {
+		fixed = append(fixed, fmt.Sprintf("webhook_url: %q -> %q", c.WebhookURL, ""))
+		c.WebhookURL = ""
+	}
+
 	return fixed
 }
 
+// validThemeFilter returns whether s is an accepted theme_filter value
+func validThemeFilter(s string) bool {
+	switch s {
+	case "", "all", "dark", "light":
+		return true
+	}
+	return false
+}
+
+// validColorProfile returns whether s is an accepted color_profile value
+func validColorProfile(s string) bool {
+	switch strings.ToLower(s) {
+	case "", "auto", "truecolor", "256", "16", "ascii":
+		return true
+	}
+	return false
+}
+
+// validColorSlot returns whether s is a Base16 slot name (base00-base0f)
+func validColorSlot(s string) bool {
+	if s == "" {
+		return true
+	}
+	s = strings.ToLower(s)
+	return len(s) == 6 && strings.HasPrefix(s, "base0") && strings.ContainsRune("0123456789abcdef", rune(s[5]))
+}
+
+// validBroadcastVariation returns whether s is an accepted broadcast_variation value
+func validBroadcastVariation(s string) bool {
+	switch s {
+	case "", "off", "counter", "random":
+		return true
+	}
+	return false
+}
+
+// validRefreshKey returns whether s is an accepted refresh_key value
+func validRefreshKey(s string) bool {
+	switch s {
+	case "", "redraw", "reset":
+		return true
+	}
+	return false
+}
+
+// validLogFormat returns whether s is an accepted log_format value
+func validLogFormat(s string) bool {
+	switch s {
+	case "", "csv", "jsonl":
+		return true
+	}
+	return false
+}
+
+// validMapFormat returns whether s is an accepted map_format value
+func validMapFormat(s string) bool {
+	switch s {
+	case "", "markdown", "csv", "text":
+		return true
+	}
+	return false
+}
+
+// validSyslogServer returns whether s is an accepted syslog_server value
+func validSyslogServer(s string) bool {
+	if s == "" {
+		return true
+	}
+	if scheme, rest, ok := strings.Cut(s, "://"); ok {
+		scheme = strings.ToLower(scheme)
+		if scheme != "udp" && scheme != "tcp" {
+			return false
+		}
+		s = rest
+	}
+	return s != "" && !strings.ContainsAny(s, " /")
+}
+
+// validWebhookURL returns whether s is an accepted webhook_url value
+func validWebhookURL(s string) bool {
+	if s == "" {
+		return true
+	}
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// ListeningEnabled returns whether at least one protocol is being listened for
+// With both off nothing is captured
+func (c *Config) ListeningEnabled() bool {
+	return c.CDPListen || c.LLDPListen
+}
+
+// RecommendedTTLRatio is the conventional TTL to advertise interval ratio, so a
+// receiver keeps the neighbor even if two advertisements in a row are lost
+const RecommendedTTLRatio = 3
+
+// MaxCDPHoldtime is the largest TTL the one-byte CDP holdtime field can carry
+// Longer TTLs (LLDP allows up to 65535) are sent to CDP as this
+const MaxCDPHoldtime = 255
+
+// advertisedTTL returns the TTL receivers actually see: with CDP broadcasting
+// on, the TTL as cut to the CDP holdtime
+func (c *Config) advertisedTTL() int {
+	if c.CDPBroadcast {
+		return min(c.TTL, MaxCDPHoldtime)
+	}
+	return c.TTL
+}
+
+// RecommendedTTL returns RecommendedTTLRatio times the advertise interval, capped
+// at the largest TTL that can be advertised (the CDP holdtime with CDP broadcasting on)
+func (c *Config) RecommendedTTL() int {
+	ttl := c.AdvertiseInterval * RecommendedTTLRatio
+	if ttl > 65535 {
+		ttl = 65535
+	}
+	if c.CDPBroadcast && ttl > MaxCDPHoldtime {
+		ttl = MaxCDPHoldtime
+	}
+	return ttl
+}
+
+// ShortTTLWarning returns a warning when the TTL doesn't outlast the advertise
+// interval, so neighbors expire before the next advertisement arrives
+// With CDP broadcasting on the TTL is compared as cut to the CDP holdtime
+// Returns "" when the TTL is long enough or either value is out of range
+func (c *Config) ShortTTLWarning() string {
+	if c.AdvertiseInterval < 1 || c.AdvertiseInterval > 300 || c.TTL < 1 || c.TTL > 65535 {
+		return ""
+	}
+	if c.AdvertiseInterval < c.advertisedTTL() {
+		return ""
+	}
+	if c.CDPBroadcast && c.AdvertiseInterval >= MaxCDPHoldtime {
+		return fmt.Sprintf("advertise_interval %d is not shorter than the %ds CDP holdtime, so CDP neighbors will flap; use an interval under %d",
+			c.AdvertiseInterval, MaxCDPHoldtime, MaxCDPHoldtime)
+	}
+	return fmt.Sprintf("ttl %d is not longer than advertise_interval %d, so neighbors will flap; use at least %d (%dx the interval)",
+		c.TTL, c.AdvertiseInterval, c.RecommendedTTL(), RecommendedTTLRatio)
+}
+
+// ShortTTLFixable returns whether fix_short_ttl can cure a short TTL by raising
+// it to RecommendedTTL. A TTL already at or past the recommendation is left
+// alone, e.g. when the CDP holdtime caps the recommendation below the interval
+func (c *Config) ShortTTLFixable() bool {
+	return c.ShortTTLWarning() != "" && c.RecommendedTTL() > c.TTL
+}
+
+// BroadcastVariationEnabled returns whether broadcasts vary their identity each interval
+func (c *Config) BroadcastVariationEnabled() bool {
+	return c.BroadcastVariation == "counter" || c.BroadcastVariation == "random"
+}
+
 // EnsureConfigExists creates the default config file if it doesn't exist
 func EnsureConfigExists() error {
 	configPath, err := GetConfigPath()
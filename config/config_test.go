@@ -70,6 +70,16 @@ func TestValidate(t *testing.T) {
 			},
 			wantErrors: 1,
 		},
+		{
+			name: "broadcast max duration too high",
+			cfg: Config{
+				AdvertiseInterval:    5,
+				TTL:                  20,
+				StalenessTimeout:     180,
+				BroadcastMaxDuration: 1441,
+			},
+			wantErrors: 1,
+		},
 		{
 			name: "TTL too low",
 			cfg: Config{
@@ -120,6 +130,115 @@ func TestValidate(t *testing.T) {
 			},
 			wantErrors: 1,
 		},
+		{
+			name: "flash duration too short",
+			cfg: Config{
+				AdvertiseInterval: 5,
+				TTL:               20,
+				FlashDurationMS:   100,
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "ping count too high",
+			cfg: Config{
+				AdvertiseInterval: 5,
+				TTL:               20,
+				PingCount:         500,
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "ttl not longer than interval",
+			cfg: Config{
+				AdvertiseInterval: 30,
+				TTL:               20,
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "invalid cursor color slot",
+			cfg: Config{
+				AdvertiseInterval: 5,
+				TTL:               20,
+				CursorColor:       "base10",
+				FlashColor:        "BASE0E",
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "log max size out of range",
+			cfg: Config{
+				AdvertiseInterval: 5,
+				TTL:               20,
+				LogMaxSizeMB:      -1,
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "capture snaplen and buffer out of range",
+			cfg: Config{
+				AdvertiseInterval: 5,
+				TTL:               20,
+				CaptureSnapLen:    64,
+				CaptureBufferMB:   4096,
+			},
+			wantErrors: 2,
+		},
+		{
+			name: "quiet warning out of range",
+			cfg: Config{
+				AdvertiseInterval:   5,
+				TTL:                 20,
+				QuietWarningSeconds: -1,
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "invalid log format",
+			cfg: Config{
+				AdvertiseInterval: 5,
+				TTL:               20,
+				LogFormat:         "xml",
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "invalid color profile",
+			cfg: Config{
+				AdvertiseInterval: 5,
+				TTL:               20,
+				ColorProfile:      "8bit",
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "invalid syslog server",
+			cfg: Config{
+				AdvertiseInterval: 5,
+				TTL:               20,
+				SyslogServer:      "http://siem:514",
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "syslog server with scheme",
+			cfg: Config{
+				AdvertiseInterval: 5,
+				TTL:               20,
+				SyslogServer:      "tcp://10.0.0.5:6514",
+			},
+			wantErrors: 0,
+		},
+		{
+			name: "invalid webhook url",
+			cfg: Config{
+				AdvertiseInterval: 5,
+				TTL:               20,
+				WebhookURL:        "hooks.slack.com/services/T000",
+			},
+			wantErrors: 1,
+		},
 		{
 			name: "multiple errors",
 			cfg: Config{
@@ -169,6 +288,21 @@ func TestValidateAndFix(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "fixes negative broadcast max duration",
+			cfg: Config{
+				AdvertiseInterval:    5,
+				TTL:                  20,
+				StalenessTimeout:     180,
+				BroadcastMaxDuration: -1,
+			},
+			wantFixed: 1,
+			checkFn: func(t *testing.T, cfg *Config) {
+				if cfg.BroadcastMaxDuration != 0 {
+					t.Errorf("BroadcastMaxDuration = %d, want 0", cfg.BroadcastMaxDuration)
+				}
+			},
+		},
 		{
 			name: "fixes TTL too high",
 			cfg: Config{
@@ -184,6 +318,33 @@ func TestValidateAndFix(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "short TTL left alone without fix_short_ttl",
+			cfg: Config{
+				AdvertiseInterval: 30,
+				TTL:               20,
+			},
+			wantFixed: 0,
+			checkFn: func(t *testing.T, cfg *Config) {
+				if cfg.TTL != 20 {
+					t.Errorf("TTL = %d, want 20", cfg.TTL)
+				}
+			},
+		},
+		{
+			name: "raises short TTL with fix_short_ttl",
+			cfg: Config{
+				AdvertiseInterval: 30,
+				TTL:               30,
+				FixShortTTL:       true,
+			},
+			wantFixed: 1,
+			checkFn: func(t *testing.T, cfg *Config) {
+				if cfg.TTL != 90 {
+					t.Errorf("TTL = %d, want 90", cfg.TTL)
+				}
+			},
+		},
 		{
 			name: "fixes all invalid values",
 			cfg: Config{
@@ -285,3 +446,65 @@ func TestFormatStringSlice(t *testing.T) {
 		}
 	}
 }
+
+func TestShortTTLWarning(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval int
+		ttl      int
+		wantWarn bool
+	}{
+		{"default ratio", 5, 20, false},
+		{"ttl just longer", 10, 11, false},
+		{"ttl equals interval", 10, 10, true},
+		{"ttl shorter than interval", 60, 20, true},
+		{"out of range interval is left to the range check", 0, 20, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{AdvertiseInterval: tt.interval, TTL: tt.ttl}
+			if got := cfg.ShortTTLWarning() != ""; got != tt.wantWarn {
+				t.Errorf("ShortTTLWarning() warned = %v, want %v", got, tt.wantWarn)
+			}
+		})
+	}
+
+	// The recommendation is always 3x the interval, even for the longest one
+	cfg := Config{AdvertiseInterval: 300, TTL: 20}
+	if got := cfg.RecommendedTTL(); got != 900 {
+		t.Errorf("RecommendedTTL() = %d, want 900", got)
+	}
+}
+
+func TestShortTTLWarningCDPHoldtime(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval int
+		ttl      int
+		wantWarn bool
+		wantTTL  int // TTL after ValidateAndFix with fix_short_ttl
+	}{
+		// CDP carries min(ttl, 255), which still outlasts a 100s interval
+		{"interval 100, long ttl", 100, 300, false, 300},
+		{"interval 100, short ttl raised to the holdtime", 100, 90, true, 255},
+		// No TTL CDP can carry outlasts a 300s interval, and fixing it must not
+		// lower the LLDP TTL or raise it past the holdtime
+		{"interval 300, long ttl", 300, 900, true, 900},
+		{"interval 300, short ttl", 300, 200, true, 255},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{AdvertiseInterval: tt.interval, TTL: tt.ttl, CDPBroadcast: true, FixShortTTL: true}
+			if got := cfg.ShortTTLWarning() != ""; got != tt.wantWarn {
+				t.Errorf("ShortTTLWarning() warned = %v, want %v", got, tt.wantWarn)
+			}
+			if got := cfg.RecommendedTTL(); got > MaxCDPHoldtime {
+				t.Errorf("RecommendedTTL() = %d, want at most %d", got, MaxCDPHoldtime)
+			}
+			cfg.ValidateAndFix()
+			if cfg.TTL != tt.wantTTL {
+				t.Errorf("TTL after ValidateAndFix() = %d, want %d", cfg.TTL, tt.wantTTL)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// notesFile is the layout of the notes file: one note per neighbor, keyed by
+// the neighbor's key (capture interface and source MAC or device ID)
+type notesFile struct {
+	Notes map[string]string `toml:"notes"`
+}
+
+// GetNotesPath returns the file neighbor notes are kept in
+func GetNotesPath() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "notes.toml"), nil
+}
+
+// LoadNotes reads the neighbor notes. A missing file means no notes
+func LoadNotes() (map[string]string, error) {
+	path, err := GetNotesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var file notesFile
+	if _, err := toml.DecodeFile(path, &file); err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	if file.Notes == nil {
+		file.Notes = map[string]string{}
+	}
+	return file.Notes, nil
+}
+
+// SaveNotes writes the neighbor notes, replacing the file
+func SaveNotes(notes map[string]string) error {
+	path, err := GetNotesPath()
+	if err != nil {
+		return err
+	}
+
+	// Create config directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString("# nbor neighbor notes, edited with t in the capture view\n\n"); err != nil {
+		return err
+	}
+	return toml.NewEncoder(file).Encode(notesFile{Notes: notes})
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNotesRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("APPDATA", dir)
+
+	notes, err := LoadNotes()
+	if err != nil {
+		t.Fatalf("LoadNotes() with no file error = %v", err)
+	}
+	if len(notes) != 0 {
+		t.Errorf("LoadNotes() with no file = %v, want empty", notes)
+	}
+
+	want := map[string]string{
+		"eth0:00:11:22:33:44:55": "core switch B",
+		"eth1:sw-lab":            `rack 4 "top"`,
+	}
+	if err := SaveNotes(want); err != nil {
+		t.Fatalf("SaveNotes() error = %v", err)
+	}
+	got, err := LoadNotes()
+	if err != nil {
+		t.Fatalf("LoadNotes() error = %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("LoadNotes() = %v, want %v", got, want)
+	}
+	for key, note := range want {
+		if got[key] != note {
+			t.Errorf("note for %q = %q, want %q", key, got[key], note)
+		}
+	}
+}
+
+func TestLoadNotesMalformed(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("APPDATA", dir)
+
+	path, err := GetNotesPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("[notes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadNotes(); err == nil {
+		t.Error("LoadNotes() on a malformed file returned no error")
+	}
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ProfileNames returns the names of the profiles in the config, sorted
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyProfile overlays the named profile's settings on c. Settings the profile
+// doesn't mention keep their value. Out-of-range values are fixed as in
+// ValidateAndFix, whose list of fixes is returned. An unknown profile, or a
+// setting nbor doesn't recognize, is an error
+func (c *Config) ApplyProfile(name string) ([]string, error) {
+	settings, ok := c.Profiles[name]
+	if !ok {
+		if len(c.Profiles) == 0 {
+			return nil, fmt.Errorf("unknown profile %q: the config file has no [profiles.<name>] tables", name)
+		}
+		return nil, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(c.ProfileNames(), ", "))
+	}
+	if _, nested := settings["profiles"]; nested {
+		return nil, fmt.Errorf("profile %s: profiles can't contain other profiles", name)
+	}
+
+	// Round-trip through TOML so the profile decodes with the same rules as the file
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(settings); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+	applied := *c
+	meta, err := toml.Decode(buf.String(), &applied)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("profile %s: unknown setting %q", name, undecoded[0].String())
+	}
+
+	fixed := applied.ValidateAndFix()
+	*c = applied
+	return fixed, nil
+}
+
+// ApplyChanges copies onto c every setting that differs between before and after.
+// The config menu edits the running config, which has --profile applied, so this
+// carries just the user's edits over to the config file as loaded
+func (c *Config) ApplyChanges(before, after Config) {
+	dst := reflect.ValueOf(c).Elem()
+	b, a := reflect.ValueOf(before), reflect.ValueOf(after)
+	for i := 0; i < dst.NumField(); i++ {
+		if !reflect.DeepEqual(b.Field(i).Interface(), a.Field(i).Interface()) {
+			dst.Field(i).Set(a.Field(i))
+		}
+	}
+}
+
+// writeProfiles appends the profiles to a config file as [profiles.<name>] tables
+func writeProfiles(w io.Writer, profiles map[string]map[string]any) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+	if _, err := io.WriteString(w, "# Profiles: settings applied over the ones above with --profile <name>\n"); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		sep := ""
+		if i > 0 {
+			sep = "\n"
+		}
+		if _, err := fmt.Fprintf(w, "%s[profiles.%s]\n", sep, profileKey(name)); err != nil {
+			return err
+		}
+		if err := toml.NewEncoder(w).Encode(profiles[name]); err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// profileKey returns a profile name as a TOML key, quoted unless it's a bare key
+func profileKey(name string) string {
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return strconv.Quote(name)
+		}
+	}
+	if name == "" {
+		return `""`
+	}
+	return name
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const profilesConfig = `
+cdp_broadcast = true
+advertise_interval = 10
+capabilities = ["station"]
+
+[profiles.listen-only]
+cdp_broadcast = false
+lldp_broadcast = false
+
+[profiles.voip-survey]
+filter_capabilities = ["phone"]
+staleness_timeout = 60
+`
+
+// writeTestConfig points the config directory at a temp dir holding contents
+func writeTestConfig(t *testing.T, contents string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("APPDATA", dir)
+	path := filepath.Join(dir, "nbor", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestApplyProfile(t *testing.T) {
+	writeTestConfig(t, profilesConfig)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.ProfileNames(); !slices.Equal(got, []string{"listen-only", "voip-survey"}) {
+		t.Errorf("ProfileNames() = %v, want [listen-only voip-survey]", got)
+	}
+
+	listen := cfg
+	if _, err := listen.ApplyProfile("listen-only"); err != nil {
+		t.Fatalf("ApplyProfile(listen-only) error = %v", err)
+	}
+	if listen.CDPBroadcast || listen.LLDPBroadcast {
+		t.Error("listen-only should turn both broadcasts off")
+	}
+	if listen.AdvertiseInterval != 10 {
+		t.Errorf("AdvertiseInterval = %d, want 10 from the base config", listen.AdvertiseInterval)
+	}
+
+	voip := cfg
+	if _, err := voip.ApplyProfile("voip-survey"); err != nil {
+		t.Fatalf("ApplyProfile(voip-survey) error = %v", err)
+	}
+	if !slices.Equal(voip.FilterCapabilities, []string{"phone"}) || voip.StalenessTimeout != 60 {
+		t.Errorf("FilterCapabilities, StalenessTimeout = %v, %d, want [phone], 60", voip.FilterCapabilities, voip.StalenessTimeout)
+	}
+	if !voip.CDPBroadcast {
+		t.Error("voip-survey doesn't set cdp_broadcast, so the base value should stay")
+	}
+
+	if _, err := cfg.ApplyProfile("missing"); err == nil || !strings.Contains(err.Error(), "listen-only, voip-survey") {
+		t.Errorf("ApplyProfile(missing) error = %v, want one listing the profiles", err)
+	}
+
+	cfg.Profiles["typo"] = map[string]any{"cdp_brodcast": true}
+	if _, err := cfg.ApplyProfile("typo"); err == nil || !strings.Contains(err.Error(), "cdp_brodcast") {
+		t.Errorf("ApplyProfile(typo) error = %v, want an unknown setting error", err)
+	}
+
+	// Out-of-range values are fixed and reported, so main can warn about them
+	cfg.Profiles["bad-ttl"] = map[string]any{"ttl": 70000}
+	fixed, err := cfg.ApplyProfile("bad-ttl")
+	if err != nil {
+		t.Fatalf("ApplyProfile(bad-ttl) error = %v", err)
+	}
+	if len(fixed) != 1 || !strings.Contains(fixed[0], "ttl") || cfg.TTL != DefaultConfig().TTL {
+		t.Errorf("ApplyProfile(bad-ttl) fixed = %v, TTL = %d, want the ttl fix reported and the default TTL", fixed, cfg.TTL)
+	}
+}
+
+func TestSaveKeepsProfiles(t *testing.T) {
+	writeTestConfig(t, profilesConfig)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	saved, err := Load()
+	if err != nil {
+		t.Fatalf("Load() after Save error = %v", err)
+	}
+	if got := saved.ProfileNames(); !slices.Equal(got, []string{"listen-only", "voip-survey"}) {
+		t.Fatalf("profiles after Save = %v, want [listen-only voip-survey]", got)
+	}
+	if _, err := saved.ApplyProfile("voip-survey"); err != nil || saved.StalenessTimeout != 60 {
+		t.Errorf("saved voip-survey: error = %v, StalenessTimeout = %d, want nil, 60", err, saved.StalenessTimeout)
+	}
+}
+
+func TestApplyChanges(t *testing.T) {
+	writeTestConfig(t, profilesConfig)
+	base, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	running := base
+	if _, err := running.ApplyProfile("listen-only"); err != nil {
+		t.Fatal(err)
+	}
+
+	before := running
+	running.SystemName = "edited"
+	running.Capabilities = []string{"router"}
+	base.ApplyChanges(before, running)
+
+	if base.SystemName != "edited" || !slices.Equal(base.Capabilities, []string{"router"}) {
+		t.Errorf("edits not applied: SystemName = %q, Capabilities = %v", base.SystemName, base.Capabilities)
+	}
+	if !base.CDPBroadcast {
+		t.Error("profile's cdp_broadcast = false leaked into the base config")
+	}
+}
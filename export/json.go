@@ -0,0 +1,43 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"nbor/types"
+)
+
+// WriteJSON writes neighbors as an indented JSON array, sorted by interface and hostname
+func WriteJSON(w io.Writer, neighbors []*types.Neighbor) error {
+	sorted := make([]*types.Neighbor, len(neighbors))
+	copy(sorted, neighbors)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Interface != sorted[j].Interface {
+			return sorted[i].Interface < sorted[j].Interface
+		}
+		return sorted[i].Hostname < sorted[j].Hostname
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sorted)
+}
+
+// WriteJSONFile writes neighbors to a timestamped JSON file in directory
+// (the current directory if empty) and returns the file's path
+func WriteJSONFile(directory string, neighbors []*types.Neighbor) (string, error) {
+	file, filename, err := createExportFile(directory, "nbor-neighbors", "json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create JSON file: %w", err)
+	}
+	if err := WriteJSON(file, neighbors); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write JSON file: %w", err)
+	}
+	return filename, nil
+}
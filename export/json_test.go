@@ -0,0 +1,72 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"nbor/types"
+)
+
+func TestWriteJSON(t *testing.T) {
+	seen := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	neighbors := []*types.Neighbor{
+		{Hostname: "sw2", PortID: "Gi1/0/2", Interface: "eth0"},
+		{
+			ID:           "sw1",
+			Hostname:     "sw1",
+			PortID:       "Gi1/0/1",
+			Interface:    "eth0",
+			ManagementIP: net.ParseIP("10.0.0.1"),
+			Platform:     "C9300",
+			Capabilities: []types.Capability{types.CapSwitch, types.CapRouter},
+			Protocol:     types.ProtocolBoth,
+			FirstSeen:    seen,
+			LastSeen:     seen.Add(time.Minute),
+			SourceMAC:    net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, neighbors); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d neighbors, want 2", len(got))
+	}
+
+	first := got[0]
+	want := map[string]string{
+		"hostname":   "sw1",
+		"port":       "Gi1/0/1",
+		"mgmt_ip":    "10.0.0.1",
+		"platform":   "C9300",
+		"protocol":   "CDP+LLDP",
+		"first_seen": "2024-05-01T12:00:00Z",
+		"last_seen":  "2024-05-01T12:01:00Z",
+		"source_mac": "00:11:22:33:44:55",
+	}
+	for k, v := range want {
+		if first[k] != v {
+			t.Errorf("%s = %v, want %q", k, first[k], v)
+		}
+	}
+	if caps, _ := first["capabilities"].([]any); len(caps) != 2 || caps[0] != "Switch" {
+		t.Errorf("capabilities = %v, want [Switch Router]", first["capabilities"])
+	}
+
+	// Missing values are empty, never null
+	if got[1]["mgmt_ip"] != "" || got[1]["first_seen"] != "" {
+		t.Errorf("missing fields should be empty strings, got %v", got[1])
+	}
+	if caps, ok := got[1]["capabilities"].([]any); !ok || len(caps) != 0 {
+		t.Errorf("capabilities should be an empty list, got %v", got[1]["capabilities"])
+	}
+}
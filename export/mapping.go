@@ -0,0 +1,163 @@
+// Package export writes concise neighbor summaries for documentation.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"nbor/types"
+)
+
+// Mapping formats
+const (
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+	FormatText     = "text"
+)
+
+// MappingRow is one local interface to neighbor port connection
+type MappingRow struct {
+	Interface    string
+	Hostname     string
+	Port         string
+	ManagementIP string
+}
+
+// Mapping builds connection rows sorted by local interface, hostname and port
+func Mapping(neighbors []*types.Neighbor) []MappingRow {
+	rows := make([]MappingRow, 0, len(neighbors))
+	for _, n := range neighbors {
+		hostname := n.Hostname
+		if hostname == "" {
+			hostname = n.ID
+		}
+		row := MappingRow{
+			Interface: n.Interface,
+			Hostname:  hostname,
+			Port:      n.PortID,
+		}
+		if n.ManagementIP != nil {
+			row.ManagementIP = n.ManagementIP.String()
+		}
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Interface != rows[j].Interface {
+			return rows[i].Interface < rows[j].Interface
+		}
+		if rows[i].Hostname != rows[j].Hostname {
+			return rows[i].Hostname < rows[j].Hostname
+		}
+		return rows[i].Port < rows[j].Port
+	})
+	return rows
+}
+
+// WriteMapping writes the interface-to-neighbor mapping in the given format
+func WriteMapping(w io.Writer, neighbors []*types.Neighbor, format string) error {
+	rows := Mapping(neighbors)
+	switch format {
+	case FormatMarkdown, "":
+		return writeMarkdown(w, rows)
+	case FormatCSV:
+		return writeCSV(w, rows)
+	case FormatText:
+		return writeText(w, rows)
+	}
+	return fmt.Errorf("unknown mapping format %q (use markdown, csv or text)", format)
+}
+
+// WriteMappingFile writes the mapping to a timestamped file in directory
+// (the current directory if empty) and returns the file's path
+func WriteMappingFile(directory, format string, neighbors []*types.Neighbor) (string, error) {
+	ext := map[string]string{FormatMarkdown: "md", "": "md", FormatCSV: "csv", FormatText: "txt"}[format]
+	if ext == "" {
+		return "", fmt.Errorf("unknown mapping format %q (use markdown, csv or text)", format)
+	}
+
+	file, filename, err := createExportFile(directory, "nbor-map", ext)
+	if err != nil {
+		return "", fmt.Errorf("failed to create mapping file: %w", err)
+	}
+	if err := WriteMapping(file, neighbors, format); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write mapping file: %w", err)
+	}
+	return filename, nil
+}
+
+// createExportFile creates prefix-<timestamp>.ext in directory (the current
+// directory if empty), creating the directory if needed
+func createExportFile(directory, prefix, ext string) (*os.File, string, error) {
+	filename := fmt.Sprintf("%s-%s.%s", prefix, time.Now().Format("2006-01-02-150405"), ext)
+	if directory != "" {
+		if err := os.MkdirAll(directory, 0755); err != nil {
+			return nil, "", fmt.Errorf("failed to create export directory: %w", err)
+		}
+		filename = filepath.Join(directory, filename)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, "", err
+	}
+	return file, filename, nil
+}
+
+// writeMarkdown writes a Markdown table, the format most wikis accept
+func writeMarkdown(w io.Writer, rows []MappingRow) error {
+	var b strings.Builder
+	b.WriteString("| Local Interface | Neighbor | Port | Mgmt IP |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, r := range rows {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+			markdownCell(r.Interface), markdownCell(r.Hostname), markdownCell(r.Port), markdownCell(r.ManagementIP))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeCSV writes the mapping as CSV with a header row
+func writeCSV(w io.Writer, rows []MappingRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Local Interface", "Neighbor", "Port", "Mgmt IP"}); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := cw.Write([]string{r.Interface, r.Hostname, r.Port, r.ManagementIP}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeText writes aligned "interface → hostname:port (ip)" lines
+func writeText(w io.Writer, rows []MappingRow) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, r := range rows {
+		line := r.Interface + "\t→ " + r.Hostname + ":" + r.Port
+		if r.ManagementIP != "" {
+			line += " (" + r.ManagementIP + ")"
+		}
+		fmt.Fprintln(tw, line)
+	}
+	return tw.Flush()
+}
+
+// markdownCell escapes pipes and flattens whitespace so a value stays in its cell
+func markdownCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "|", "\\|")
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+
+	"nbor/types"
+)
+
+func mappingFixture() []*types.Neighbor {
+	return []*types.Neighbor{
+		{Hostname: "edge-rtr1", PortID: "Gi0/0/1", Interface: "eth1", ManagementIP: net.ParseIP("10.0.1.1")},
+		{Hostname: "core|sw1", PortID: "Gi1/0/24", Interface: "eth0", ManagementIP: net.ParseIP("10.0.0.2")},
+		{ID: "SEP001122334455", PortID: "Port 1", Interface: "eth0"},
+	}
+}
+
+func TestMappingSorted(t *testing.T) {
+	rows := Mapping(mappingFixture())
+	want := []string{"eth0 SEP001122334455", "eth0 core|sw1", "eth1 edge-rtr1"}
+	if len(rows) != len(want) {
+		t.Fatalf("Mapping() returned %d rows, want %d", len(rows), len(want))
+	}
+	for i, r := range rows {
+		if got := r.Interface + " " + r.Hostname; got != want[i] {
+			t.Errorf("Mapping()[%d] = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestWriteMapping(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{FormatMarkdown, []string{
+			"| Local Interface | Neighbor | Port | Mgmt IP |",
+			"| eth0 | core\\|sw1 | Gi1/0/24 | 10.0.0.2 |",
+			"| eth0 | SEP001122334455 | Port 1 |  |",
+		}},
+		{FormatCSV, []string{
+			"Local Interface,Neighbor,Port,Mgmt IP",
+			"eth1,edge-rtr1,Gi0/0/1,10.0.1.1",
+		}},
+		{FormatText, []string{
+			"eth1  → edge-rtr1:Gi0/0/1 (10.0.1.1)",
+			"eth0  → SEP001122334455:Port 1\n",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteMapping(&buf, mappingFixture(), tt.format); err != nil {
+				t.Fatalf("WriteMapping() error = %v", err)
+			}
+			for _, line := range tt.want {
+				if !strings.Contains(buf.String(), line) {
+					t.Errorf("WriteMapping(%s) missing %q in:\n%s", tt.format, line, buf.String())
+				}
+			}
+		})
+	}
+
+	if err := WriteMapping(&bytes.Buffer{}, nil, "yaml"); err == nil {
+		t.Error("WriteMapping() with unknown format should fail")
+	}
+}
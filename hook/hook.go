@@ -0,0 +1,144 @@
+// Package hook runs a user-configured command or POSTs to a webhook when a new
+// neighbor appears.
+package hook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"nbor/types"
+)
+
+// Timeout is how long a hook command may run before it is killed
+const Timeout = 10 * time.Second
+
+// Runner executes the new-neighbor command
+// The command is split into arguments once and run directly, never through a
+// shell, so neighbor-supplied values can't inject extra commands
+type Runner struct {
+	args []string
+
+	// Callback for commands that fail to start, exit non-zero or time out
+	OnError func(error)
+}
+
+// NewRunner parses a command line, honoring single and double quotes
+func NewRunner(command string) (*Runner, error) {
+	args, err := splitCommand(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, errors.New("new_neighbor_command is empty")
+	}
+	return &Runner{args: args}, nil
+}
+
+// Run starts the command for a neighbor in the background and returns immediately
+// Placeholders are substituted now, since the store keeps mutating the neighbor
+func (r *Runner) Run(n *types.Neighbor) {
+	values := placeholderValues(n)
+	args := make([]string, len(r.args))
+	for i, arg := range r.args {
+		args[i] = expand(arg, values)
+	}
+
+	env := os.Environ()
+	for _, p := range placeholders {
+		env = append(env, "NBOR_"+strings.ToUpper(p)+"="+values[p])
+	}
+
+	go r.exec(args, env)
+}
+
+// exec runs the command with the timeout, discarding its output
+func (r *Runner) exec(args, env []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = env
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", Timeout)
+	}
+	if err != nil && r.OnError != nil {
+		r.OnError(fmt.Errorf("new_neighbor_command %s: %w", args[0], err))
+	}
+}
+
+// placeholders are the {name} fields that can be used in the command
+// Each is also exported to the command as NBOR_<NAME>
+var placeholders = []string{"hostname", "ip", "port", "mac", "platform", "protocol", "interface", "id"}
+
+// placeholderValues returns the neighbor's value for each placeholder
+func placeholderValues(n *types.Neighbor) map[string]string {
+	values := map[string]string{
+		"hostname":  n.Hostname,
+		"port":      n.PortID,
+		"platform":  n.Platform,
+		"protocol":  string(n.Protocol),
+		"interface": n.Interface,
+		"id":        n.ID,
+	}
+	if n.ManagementIP != nil {
+		values["ip"] = n.ManagementIP.String()
+	}
+	if n.SourceMAC != nil {
+		values["mac"] = n.SourceMAC.String()
+	}
+	return values
+}
+
+// expand substitutes {name} placeholders in a single argument
+func expand(arg string, values map[string]string) string {
+	for _, p := range placeholders {
+		arg = strings.ReplaceAll(arg, "{"+p+"}", values[p])
+	}
+	return arg
+}
+
+// splitCommand splits a command line into arguments on whitespace
+// Single or double quotes group words; there are no escapes or expansions
+func splitCommand(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in new_neighbor_command", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
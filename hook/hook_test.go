@@ -0,0 +1,75 @@
+package hook
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"nbor/types"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{"simple", "notify-send hello", []string{"notify-send", "hello"}, false},
+		{"extra whitespace", "  a \t b  ", []string{"a", "b"}, false},
+		{"double quotes", `notify-send "New neighbor {hostname}"`, []string{"notify-send", "New neighbor {hostname}"}, false},
+		{"single quotes", `echo 'a "b" c'`, []string{"echo", `a "b" c`}, false},
+		{"empty quotes", `cmd ""`, []string{"cmd", ""}, false},
+		{"adjacent quotes", `cmd a"b c"d`, []string{"cmd", "ab cd"}, false},
+		{"unterminated", `cmd "oops`, nil, true},
+		{"empty", "", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitCommand(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	n := &types.Neighbor{
+		Hostname:     "switch1",
+		PortID:       "Gi0/1",
+		ManagementIP: net.ParseIP("10.0.0.1"),
+		SourceMAC:    mac,
+		Protocol:     types.ProtocolCDP,
+		Interface:    "eth0",
+	}
+	values := placeholderValues(n)
+
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"{hostname}", "switch1"},
+		{"{hostname} on {port} ({ip})", "switch1 on Gi0/1 (10.0.0.1)"},
+		{"{mac}/{protocol}/{interface}", "00:11:22:33:44:55/CDP/eth0"},
+		{"{platform}", ""},
+		{"{unknown}", "{unknown}"},
+	}
+
+	for _, tt := range tests {
+		if got := expand(tt.arg, values); got != tt.want {
+			t.Errorf("expand(%q) = %q, want %q", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestNewRunnerRejectsEmpty(t *testing.T) {
+	if _, err := NewRunner("   "); err == nil {
+		t.Error("NewRunner() with blank command should fail")
+	}
+}
@@ -0,0 +1,146 @@
+package hook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"nbor/types"
+)
+
+// Webhook timing: each attempt gets WebhookTimeout, and a failed attempt is
+// retried once after webhookRetryDelay
+const (
+	WebhookTimeout    = 5 * time.Second
+	webhookRetryDelay = time.Second
+)
+
+// Webhook POSTs a JSON description of each new neighbor to a URL
+type Webhook struct {
+	url    string
+	agent  string // This machine's hostname, so several nbor agents can be told apart
+	client *http.Client
+
+	// Callback for deliveries that still fail after the retry
+	OnError func(error)
+}
+
+// WebhookPayload is the JSON body sent for a new neighbor
+// Text is a one-line summary, which Slack and Teams incoming webhooks display as is
+type WebhookPayload struct {
+	Event     string          `json:"event"`
+	Agent     string          `json:"agent"`
+	Interface string          `json:"interface"`
+	Text      string          `json:"text"`
+	Neighbor  *types.Neighbor `json:"neighbor"`
+}
+
+// NewWebhook validates rawURL, which must be an http or https URL
+func NewWebhook(rawURL string) (*Webhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("webhook_url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("webhook_url must be an http:// or https:// URL")
+	}
+
+	agent, err := os.Hostname()
+	if err != nil {
+		agent = ""
+	}
+	return &Webhook{
+		url:    rawURL,
+		agent:  agent,
+		client: &http.Client{Timeout: WebhookTimeout},
+	}, nil
+}
+
+// Post sends the neighbor in the background and returns immediately
+// The body is encoded now, since the store keeps mutating the neighbor
+func (w *Webhook) Post(n *types.Neighbor) {
+	body, err := json.Marshal(w.payload(n))
+	if err != nil {
+		w.reportError(err)
+		return
+	}
+	go w.send(body)
+}
+
+// payload builds the JSON body for a neighbor
+func (w *Webhook) payload(n *types.Neighbor) WebhookPayload {
+	hostname := n.Hostname
+	if hostname == "" {
+		hostname = n.ID
+	}
+	text := fmt.Sprintf("%s newly visible on %s", hostname, n.Interface)
+	if n.PortID != "" {
+		text = fmt.Sprintf("%s port %s newly visible on %s", hostname, n.PortID, n.Interface)
+	}
+	if w.agent != "" {
+		text += " (" + w.agent + ")"
+	}
+	return WebhookPayload{
+		Event:     "new_neighbor",
+		Agent:     w.agent,
+		Interface: n.Interface,
+		Text:      text,
+		Neighbor:  n,
+	}
+}
+
+// send POSTs body, retrying once after a network error or 5xx response
+func (w *Webhook) send(body []byte) {
+	err := w.attempt(body)
+	if err != nil && retryable(err) {
+		time.Sleep(webhookRetryDelay)
+		err = w.attempt(body)
+	}
+	if err != nil {
+		w.reportError(err)
+	}
+}
+
+// statusError is a non-2xx webhook response
+type statusError struct {
+	code int
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("server returned %d %s", e.code, http.StatusText(e.code))
+}
+
+// retryable returns whether a failed attempt is worth repeating
+// Client errors (4xx) won't change on a retry
+func retryable(err error) bool {
+	var se statusError
+	if errors.As(err, &se) {
+		return se.code >= 500
+	}
+	return true
+}
+
+// attempt makes one POST
+func (w *Webhook) attempt(body []byte) error {
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return statusError{code: resp.StatusCode}
+	}
+	return nil
+}
+
+// reportError passes err to OnError, if set
+func (w *Webhook) reportError(err error) {
+	if w.OnError != nil {
+		w.OnError(fmt.Errorf("webhook: %w", err))
+	}
+}
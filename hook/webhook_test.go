@@ -0,0 +1,95 @@
+package hook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"nbor/types"
+)
+
+func TestNewWebhookRejectsBadURL(t *testing.T) {
+	for _, u := range []string{"", "ftp://example.com/hook", "example.com/hook", "http://"} {
+		if _, err := NewWebhook(u); err == nil {
+			t.Errorf("NewWebhook(%q) should fail", u)
+		}
+	}
+}
+
+func TestWebhookPost(t *testing.T) {
+	var attempts atomic.Int32
+	received := make(chan WebhookPayload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt so the retry is exercised
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		var p struct {
+			WebhookPayload
+			Neighbor map[string]any `json:"neighbor"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("invalid JSON body: %v", err)
+		}
+		if p.Neighbor["port"] != "Gi1/0/24" {
+			t.Errorf("neighbor port = %v, want Gi1/0/24", p.Neighbor["port"])
+		}
+		received <- p.WebhookPayload
+	}))
+	defer server.Close()
+
+	w, err := NewWebhook(server.URL)
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+	w.agent = "laptop"
+	w.OnError = func(err error) { t.Errorf("unexpected error: %v", err) }
+
+	w.Post(&types.Neighbor{Hostname: "core-sw1", PortID: "Gi1/0/24", Interface: "eth0"})
+
+	select {
+	case p := <-received:
+		if p.Event != "new_neighbor" || p.Agent != "laptop" || p.Interface != "eth0" {
+			t.Errorf("payload = %+v", p)
+		}
+		if want := "core-sw1 port Gi1/0/24 newly visible on eth0 (laptop)"; p.Text != want {
+			t.Errorf("Text = %q, want %q", p.Text, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not retried")
+	}
+}
+
+func TestWebhookNoRetryOnClientError(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	w, err := NewWebhook(server.URL)
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+	errs := make(chan error, 1)
+	w.OnError = func(err error) { errs <- err }
+
+	w.Post(&types.Neighbor{Hostname: "sw1", Interface: "eth0"})
+
+	select {
+	case <-errs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected an error for a 404")
+	}
+	if n := attempts.Load(); n != 1 {
+		t.Errorf("got %d attempts for a 404, want 1", n)
+	}
+}
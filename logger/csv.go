@@ -1,11 +1,11 @@
-// Package logger provides CSV logging for neighbor discovery events.
+// Package logger provides CSV, JSON Lines and syslog logging for neighbor discovery events.
 package logger
 
 import (
 	"encoding/csv"
 	"fmt"
 	"net"
-	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -16,77 +16,100 @@ import (
 // CSVLogger handles logging neighbor discoveries to a CSV file
 type CSVLogger struct {
 	mu                 sync.Mutex
-	file               *os.File
+	out                *logFile
 	writer             *csv.Writer
-	filepath           string
 	filterCapabilities []string // Capability filter (empty = log all)
 }
 
+// csvHeader is the first row of every CSV log file, including rotated ones
+var csvHeader = []string{
+	"Timestamp",
+	"Interface",
+	"Protocol",
+	"Hostname",
+	"Port ID",
+	"Port Description",
+	"Management IP",
+	"Platform",
+	"Description",
+	"Location",
+	"Capabilities",
+	"Source MAC",
+	"Port VLAN",
+	"VLAN Name",
+}
+
 // NewCSVLogger creates a new CSV logger with a timestamped filename
 // If directory is empty, logs are created in the current directory
 func NewCSVLogger(directory string, filterCapabilities []string) (*CSVLogger, error) {
-	// Generate filename with timestamp
-	timestamp := time.Now().Format("2006-01-02-150405")
-	filename := fmt.Sprintf("nbor-%s.csv", timestamp)
-
-	// If directory is specified, create it if needed and prepend to filename
-	if directory != "" {
-		if err := os.MkdirAll(directory, 0755); err != nil {
-			return nil, fmt.Errorf("failed to create log directory: %w", err)
-		}
-		filename = directory + string(os.PathSeparator) + filename
-	}
-
-	file, err := os.Create(filename)
+	out, err := openLogFile(directory, "csv")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create log file: %w", err)
+		return nil, err
 	}
 
-	writer := csv.NewWriter(file)
-
 	logger := &CSVLogger{
-		file:               file,
-		writer:             writer,
-		filepath:           filename,
+		out:                out,
+		writer:             csv.NewWriter(out),
 		filterCapabilities: filterCapabilities,
 	}
 
-	// Write header row
-	header := []string{
-		"Timestamp",
-		"Interface",
-		"Protocol",
-		"Hostname",
-		"Port ID",
-		"Port Description",
-		"Management IP",
-		"Platform",
-		"Description",
-		"Location",
-		"Capabilities",
-		"Source MAC",
+	if err := logger.writeHeader(); err != nil {
+		out.Close()
+		return nil, err
 	}
 
-	if err := writer.Write(header); err != nil {
-		file.Close()
-		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	return logger, nil
+}
+
+// writeHeader writes the header row to the start of a file
+func (l *CSVLogger) writeHeader() error {
+	if err := l.writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
-	writer.Flush()
+	l.writer.Flush()
+	return l.writer.Error()
+}
 
-	return logger, nil
+// SetRotation starts a new file, with its own header row, once the current one
+// reaches maxBytes (0 = never). onRotate is called with the new path
+func (l *CSVLogger) SetRotation(maxBytes int64, onRotate func(path string)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.out.maxBytes = maxBytes
+	l.out.onRotate = onRotate
+}
+
+// flush writes buffered records and rotates the file if it's full
+// A record is always finished in the file it was started in
+func (l *CSVLogger) flush() error {
+	l.writer.Flush()
+	if err := l.writer.Error(); err != nil {
+		return err
+	}
+	if l.out.full() {
+		return l.out.rotate(l.writeHeader)
+	}
+	return nil
 }
 
 // ShouldLog checks if a neighbor matches the capability filter
 // Returns true if the neighbor should be logged
 func (l *CSVLogger) ShouldLog(n *types.Neighbor) bool {
+	return matchesCapabilityFilter(n, l.filterCapabilities)
+}
+
+// matchesCapabilityFilter returns whether any of the neighbor's capabilities is
+// in filter. An empty filter matches every neighbor
+func matchesCapabilityFilter(n *types.Neighbor, filter []string) bool {
 	// Empty filter means log all
-	if len(l.filterCapabilities) == 0 {
+	if len(filter) == 0 {
 		return true
 	}
 
 	// Check if any of the neighbor's capabilities match the filter
 	for _, neighborCap := range n.Capabilities {
-		for _, filterCap := range l.filterCapabilities {
+		for _, filterCap := range filter {
 			if strings.EqualFold(string(neighborCap), filterCap) {
 				return true
 			}
@@ -128,6 +151,12 @@ func (l *CSVLogger) Log(n *types.Neighbor) error {
 		srcMAC = n.SourceMAC.String()
 	}
 
+	// Format port VLAN (0 = not advertised)
+	portVLAN := ""
+	if n.PortVLAN != 0 {
+		portVLAN = strconv.Itoa(n.PortVLAN)
+	}
+
 	record := []string{
 		n.LastSeen.Format(time.RFC3339),
 		n.Interface,
@@ -141,38 +170,62 @@ func (l *CSVLogger) Log(n *types.Neighbor) error {
 		n.Location,
 		strings.Join(caps, ","),
 		srcMAC,
+		portVLAN,
+		n.VLANName,
 	}
 
 	if err := l.writer.Write(record); err != nil {
 		return fmt.Errorf("failed to write CSV record: %w", err)
 	}
 
-	l.writer.Flush()
-	return l.writer.Error()
+	return l.flush()
 }
 
-// Close flushes and closes the CSV file
-func (l *CSVLogger) Close() error {
+// LogEvent writes an interface-level event (not tied to a single neighbor) to the CSV file
+// The message is placed in the Description column with "Event" as the protocol
+func (l *CSVLogger) LogEvent(at time.Time, iface, message string) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	if l.writer != nil {
-		l.writer.Flush()
-		l.writer = nil
+	if l.writer == nil {
+		return fmt.Errorf("logger is closed")
 	}
 
-	if l.file != nil {
-		err := l.file.Close()
-		l.file = nil
-		return err
+	record := []string{
+		at.Format(time.RFC3339),
+		iface,
+		"Event",
+		"", "", "", "", "",
+		sanitizeForCSV(message),
+		"", "", "", "", "",
 	}
 
-	return nil
+	if err := l.writer.Write(record); err != nil {
+		return fmt.Errorf("failed to write CSV record: %w", err)
+	}
+
+	return l.flush()
 }
 
-// Filepath returns the path to the log file
+// Close flushes and closes the CSV file
+func (l *CSVLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.writer == nil {
+		return nil
+	}
+	l.writer.Flush()
+	l.writer = nil
+	return l.out.Close()
+}
+
+// Filepath returns the path to the current log file
 func (l *CSVLogger) Filepath() string {
-	return l.filepath
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.out.path
 }
 
 // sanitizeForCSV removes or replaces characters that might cause issues in CSV
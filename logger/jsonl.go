@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"nbor/types"
+)
+
+// JSONLLogger logs neighbor discoveries as JSON Lines: one object per line,
+// so multi-valued fields like capabilities survive a round trip through jq
+type JSONLLogger struct {
+	mu                 sync.Mutex
+	out                *logFile
+	encoder            *json.Encoder
+	filterCapabilities []string // Capability filter (empty = log all)
+}
+
+// jsonlNeighbor is the line written for a new neighbor
+// Unknown values are empty strings, and port_vlan is 0 when not advertised
+type jsonlNeighbor struct {
+	Type            string   `json:"type"` // Always "neighbor"
+	Timestamp       string   `json:"timestamp"`
+	Interface       string   `json:"interface"`
+	Protocol        string   `json:"protocol"`
+	ID              string   `json:"id"`
+	Hostname        string   `json:"hostname"`
+	PortID          string   `json:"port"`
+	PortDescription string   `json:"port_description"`
+	ManagementIP    string   `json:"mgmt_ip"`
+	Platform        string   `json:"platform"`
+	Description     string   `json:"description"`
+	Location        string   `json:"location"`
+	Capabilities    []string `json:"capabilities"`
+	SourceMAC       string   `json:"source_mac"`
+	PortVLAN        int      `json:"port_vlan"`
+	VLANName        string   `json:"vlan_name"`
+	FirstSeen       string   `json:"first_seen"`
+	LastSeen        string   `json:"last_seen"`
+}
+
+// jsonlEvent is the line written for an interface-level event
+type jsonlEvent struct {
+	Type      string `json:"type"` // Always "event"
+	Timestamp string `json:"timestamp"`
+	Interface string `json:"interface"`
+	Message   string `json:"message"`
+}
+
+// NewJSONLLogger creates a new JSON Lines logger with a timestamped filename
+// If directory is empty, logs are created in the current directory
+func NewJSONLLogger(directory string, filterCapabilities []string) (*JSONLLogger, error) {
+	out, err := openLogFile(directory, "jsonl")
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONLLogger{
+		out:                out,
+		encoder:            json.NewEncoder(out),
+		filterCapabilities: filterCapabilities,
+	}, nil
+}
+
+// SetRotation starts a new file once the current one reaches maxBytes
+// (0 = never). onRotate is called with the new path
+func (l *JSONLLogger) SetRotation(maxBytes int64, onRotate func(path string)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.out.maxBytes = maxBytes
+	l.out.onRotate = onRotate
+}
+
+// Log writes a neighbor record as one JSON line
+// Respects the capability filter - neighbors not matching the filter are skipped
+func (l *JSONLLogger) Log(n *types.Neighbor) error {
+	if !matchesCapabilityFilter(n, l.filterCapabilities) {
+		return nil
+	}
+
+	capabilities := make([]string, len(n.Capabilities))
+	for i, c := range n.Capabilities {
+		capabilities[i] = string(c)
+	}
+
+	return l.write(jsonlNeighbor{
+		Type:            "neighbor",
+		Timestamp:       n.LastSeen.Format(time.RFC3339),
+		Interface:       n.Interface,
+		Protocol:        string(n.Protocol),
+		ID:              n.ID,
+		Hostname:        n.Hostname,
+		PortID:          n.PortID,
+		PortDescription: n.PortDescription,
+		ManagementIP:    FormatIP(n.ManagementIP),
+		Platform:        n.Platform,
+		Description:     n.Description,
+		Location:        n.Location,
+		Capabilities:    capabilities,
+		SourceMAC:       FormatMAC(n.SourceMAC),
+		PortVLAN:        n.PortVLAN,
+		VLANName:        n.VLANName,
+		FirstSeen:       n.FirstSeen.Format(time.RFC3339),
+		LastSeen:        n.LastSeen.Format(time.RFC3339),
+	})
+}
+
+// LogEvent writes an interface-level event (not tied to a single neighbor)
+func (l *JSONLLogger) LogEvent(at time.Time, iface, message string) error {
+	return l.write(jsonlEvent{
+		Type:      "event",
+		Timestamp: at.Format(time.RFC3339),
+		Interface: iface,
+		Message:   message,
+	})
+}
+
+// write encodes v as a line, then rotates the file if it's full
+// The encoder writes straight to the file, so a line is never split across files
+func (l *JSONLLogger) write(v any) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.encoder == nil {
+		return fmt.Errorf("logger is closed")
+	}
+	if err := l.encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to write JSON record: %w", err)
+	}
+	if l.out.full() {
+		return l.out.rotate(nil)
+	}
+	return nil
+}
+
+// Close closes the log file
+func (l *JSONLLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.encoder == nil {
+		return nil
+	}
+	l.encoder = nil
+	return l.out.Close()
+}
+
+// Filepath returns the path to the current log file
+func (l *JSONLLogger) Filepath() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.out.path
+}
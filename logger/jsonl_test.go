@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"nbor/types"
+)
+
+func TestJSONLLogger(t *testing.T) {
+	l, err := NewLogger("jsonl", t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("NewLogger(jsonl) error = %v", err)
+	}
+	if !strings.HasSuffix(l.Filepath(), ".jsonl") {
+		t.Errorf("Filepath() = %q, want a .jsonl file", l.Filepath())
+	}
+
+	seen := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	n := &types.Neighbor{
+		Hostname:     "sw1",
+		PortID:       "Gi1/0/1",
+		Description:  "line one\nline two",
+		ManagementIP: net.ParseIP("10.0.0.1"),
+		Capabilities: []types.Capability{types.CapRouter, types.CapBridge},
+		Protocol:     types.ProtocolLLDP,
+		SourceMAC:    net.HardwareAddr{0, 0x11, 0x22, 0x33, 0x44, 0x55},
+		Interface:    "eth0",
+		PortVLAN:     10,
+		FirstSeen:    seen,
+		LastSeen:     seen,
+	}
+	if err := l.Log(n); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if err := l.LogEvent(seen, "eth0", "link down"); err != nil {
+		t.Fatalf("LogEvent() error = %v", err)
+	}
+	l.Close()
+
+	f, err := os.Open(l.Filepath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var lines []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q isn't JSON: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+
+	got := lines[0]
+	if got["type"] != "neighbor" || got["hostname"] != "sw1" || got["mgmt_ip"] != "10.0.0.1" {
+		t.Errorf("neighbor line = %v", got)
+	}
+	if got["description"] != "line one\nline two" {
+		t.Errorf("description = %q, want the newline kept", got["description"])
+	}
+	caps, _ := got["capabilities"].([]any)
+	if len(caps) != 2 || caps[0] != "Router" || caps[1] != "Bridge" {
+		t.Errorf("capabilities = %v, want [Router Bridge]", got["capabilities"])
+	}
+	if got["port_vlan"] != float64(10) || got["first_seen"] != "2024-05-01T12:00:00Z" {
+		t.Errorf("port_vlan/first_seen = %v/%v", got["port_vlan"], got["first_seen"])
+	}
+	if lines[1]["type"] != "event" || lines[1]["message"] != "link down" {
+		t.Errorf("event line = %v", lines[1])
+	}
+}
+
+func TestNewLoggerUnknownFormat(t *testing.T) {
+	if _, err := NewLogger("xml", t.TempDir(), nil); err == nil {
+		t.Error("NewLogger(xml) should fail")
+	}
+}
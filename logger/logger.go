@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"nbor/types"
+)
+
+// NeighborLogger writes first-seen neighbors and interface events to a log file
+type NeighborLogger interface {
+	Log(n *types.Neighbor) error
+	LogEvent(at time.Time, iface, message string) error
+	Close() error
+
+	// Filepath returns the file currently being written, which changes on rotation
+	Filepath() string
+
+	// SetRotation starts a new file once the current one reaches maxBytes
+	// (0 = never), calling onRotate with the new path. Call before logging
+	SetRotation(maxBytes int64, onRotate func(path string))
+}
+
+// NewLogger creates a logger for format ("csv" or "jsonl") with a timestamped
+// filename. An empty format means CSV
+func NewLogger(format, directory string, filterCapabilities []string) (NeighborLogger, error) {
+	switch format {
+	case "", "csv":
+		return NewCSVLogger(directory, filterCapabilities)
+	case "jsonl":
+		return NewJSONLLogger(directory, filterCapabilities)
+	}
+	return nil, fmt.Errorf("unknown log format %q (use csv or jsonl)", format)
+}
+
+// logFile is a log file that can be replaced by a new timestamped file once it
+// reaches a size limit. It isn't safe for concurrent use; loggers hold their lock
+type logFile struct {
+	directory string
+	ext       string
+	file      *os.File
+	path      string
+	size      int64             // Bytes written to the current file
+	maxBytes  int64             // Rotate at this size (0 = never)
+	onRotate  func(path string) // Called with the new path after a rotation
+}
+
+// openLogFile creates the first file for a logger
+func openLogFile(directory, ext string) (*logFile, error) {
+	file, path, err := createLogFile(directory, ext)
+	if err != nil {
+		return nil, err
+	}
+	return &logFile{directory: directory, ext: ext, file: file, path: path}, nil
+}
+
+// Write writes to the current file, counting bytes toward the size limit
+func (f *logFile) Write(p []byte) (int, error) {
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// full returns whether the current file has reached the size limit
+func (f *logFile) full() bool {
+	return f.maxBytes > 0 && f.size >= f.maxBytes
+}
+
+// rotate switches to a new file and calls writeHeader to start it
+// If the new file can't be created, logging continues in the current one
+func (f *logFile) rotate(writeHeader func() error) error {
+	file, path, err := createLogFile(f.directory, f.ext)
+	if err != nil {
+		return fmt.Errorf("failed to rotate log: %w", err)
+	}
+	f.file.Close()
+	f.file, f.path, f.size = file, path, 0
+
+	if writeHeader != nil {
+		if err := writeHeader(); err != nil {
+			return err
+		}
+	}
+	if f.onRotate != nil {
+		f.onRotate(path)
+	}
+	return nil
+}
+
+// Close closes the current file
+func (f *logFile) Close() error {
+	return f.file.Close()
+}
+
+// createLogFile creates nbor-<timestamp>.<ext> in directory, creating the
+// directory if needed. If directory is empty, the file goes in the current directory
+// A rotation within the same second gets a -1, -2, ... suffix instead of
+// overwriting the file it replaces
+func createLogFile(directory, ext string) (*os.File, string, error) {
+	timestamp := time.Now().Format("2006-01-02-150405")
+	base := "nbor-" + timestamp
+
+	if directory != "" {
+		if err := os.MkdirAll(directory, 0755); err != nil {
+			return nil, "", fmt.Errorf("failed to create log directory: %w", err)
+		}
+		base = directory + string(os.PathSeparator) + base
+	}
+
+	for i := 0; ; i++ {
+		filename := base + "." + ext
+		if i > 0 {
+			filename = fmt.Sprintf("%s-%d.%s", base, i, ext)
+		}
+		file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create log file: %w", err)
+		}
+		return file, filename, nil
+	}
+}
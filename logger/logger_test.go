@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"nbor/types"
+)
+
+func TestLoggerRotation(t *testing.T) {
+	for _, format := range []string{"csv", "jsonl"} {
+		t.Run(format, func(t *testing.T) {
+			dir := t.TempDir()
+			l, err := NewLogger(format, dir, nil)
+			if err != nil {
+				t.Fatalf("NewLogger(%s) error = %v", format, err)
+			}
+			defer l.Close()
+
+			var rotated []string
+			l.SetRotation(200, func(path string) { rotated = append(rotated, path) })
+			first := l.Filepath()
+
+			n := &types.Neighbor{Hostname: "sw1", PortID: "Gi1/0/1", Interface: "eth0", LastSeen: time.Now()}
+			for i := 0; i < 10; i++ {
+				if err := l.Log(n); err != nil {
+					t.Fatalf("Log() error = %v", err)
+				}
+			}
+
+			if len(rotated) == 0 {
+				t.Fatal("log never rotated")
+			}
+			if l.Filepath() != rotated[len(rotated)-1] || l.Filepath() == first {
+				t.Errorf("Filepath() = %q, want the latest rotated file", l.Filepath())
+			}
+			entries, _ := os.ReadDir(dir)
+			if len(entries) != len(rotated)+1 {
+				t.Errorf("%d files in log directory, want %d", len(entries), len(rotated)+1)
+			}
+			if format == "csv" {
+				data, err := os.ReadFile(l.Filepath())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.HasPrefix(string(data), "Timestamp,Interface,") {
+					t.Errorf("rotated CSV should start with the header, got %q", data)
+				}
+			}
+		})
+	}
+}
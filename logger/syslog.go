@@ -0,0 +1,234 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"nbor/types"
+)
+
+// Syslog message settings
+const (
+	syslogFacilityUser = 1
+	syslogSeverityNote = 5
+	syslogAppName      = "nbor"
+	syslogMsgID        = "NEIGHBOR"
+	// RFC 5612 documentation enterprise number, for the structured data ID
+	syslogSDID = "neighbor@32473"
+
+	syslogDialTimeout  = 5 * time.Second
+	syslogWriteTimeout = 5 * time.Second
+	syslogQueueSize    = 64
+	syslogCloseTimeout = 2 * time.Second
+)
+
+// SyslogLogger sends an RFC 5424 message to a syslog server for each new neighbor
+// Messages are queued and sent in the background so an unreachable server
+// never blocks capture; failures are reported once per outage through OnError
+type SyslogLogger struct {
+	mu                 sync.Mutex
+	network            string
+	address            string
+	hostname           string
+	filterCapabilities []string
+	queue              chan []byte
+	done               chan struct{}
+	closed             bool
+
+	// Callback for send failures, called once until a message gets through again
+	OnError func(error)
+}
+
+// NewSyslogLogger creates a logger for server, given as host:port (UDP),
+// udp://host:port or tcp://host:port. The connection is made on the first message
+func NewSyslogLogger(server string, filterCapabilities []string) (*SyslogLogger, error) {
+	network, address, err := ParseSyslogServer(server)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	l := &SyslogLogger{
+		network:            network,
+		address:            address,
+		hostname:           syslogHeaderField(hostname),
+		filterCapabilities: filterCapabilities,
+		queue:              make(chan []byte, syslogQueueSize),
+		done:               make(chan struct{}),
+	}
+	go l.send()
+	return l, nil
+}
+
+// ParseSyslogServer splits a syslog_server value into a network and address
+// A missing scheme means UDP, and a missing port means 514
+func ParseSyslogServer(server string) (network, address string, err error) {
+	network = "udp"
+	address = server
+	if scheme, rest, ok := strings.Cut(server, "://"); ok {
+		network, address = strings.ToLower(scheme), rest
+	}
+	if network != "udp" && network != "tcp" {
+		return "", "", fmt.Errorf("syslog server %q: unsupported protocol %q (use udp or tcp)", server, network)
+	}
+	if address == "" {
+		return "", "", fmt.Errorf("syslog server %q: missing host", server)
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(strings.Trim(address, "[]"), "514")
+	}
+	return network, address, nil
+}
+
+// Log queues a message for a neighbor
+// Respects the capability filter; if the queue is full the message is dropped
+func (l *SyslogLogger) Log(n *types.Neighbor) error {
+	if !matchesCapabilityFilter(n, l.filterCapabilities) {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return fmt.Errorf("logger is closed")
+	}
+
+	select {
+	case l.queue <- l.format(n, time.Now()):
+	default:
+		return fmt.Errorf("syslog queue full, message for %s dropped", n.Hostname)
+	}
+	return nil
+}
+
+// Close stops the sender once queued messages have been sent or have failed,
+// waiting at most syslogCloseTimeout so an unreachable server can't delay exit
+func (l *SyslogLogger) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	close(l.queue)
+	l.mu.Unlock()
+
+	select {
+	case <-l.done:
+	case <-time.After(syslogCloseTimeout):
+	}
+	return nil
+}
+
+// send writes queued messages, reconnecting after failures
+func (l *SyslogLogger) send() {
+	defer close(l.done)
+
+	var conn net.Conn
+	failing := false
+	for msg := range l.queue {
+		err := func() error {
+			if conn == nil {
+				c, err := net.DialTimeout(l.network, l.address, syslogDialTimeout)
+				if err != nil {
+					return err
+				}
+				conn = c
+			}
+			if l.network == "tcp" {
+				// RFC 6587 octet counting, so messages can't run together
+				msg = append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+			_, err := conn.Write(msg)
+			return err
+		}()
+
+		if err != nil {
+			if conn != nil {
+				conn.Close()
+				conn = nil
+			}
+			if !failing && l.OnError != nil {
+				l.OnError(fmt.Errorf("syslog %s://%s: %w", l.network, l.address, err))
+			}
+			failing = true
+			continue
+		}
+		failing = false
+	}
+
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// format builds the RFC 5424 message for a neighbor
+func (l *SyslogLogger) format(n *types.Neighbor, at time.Time) []byte {
+	hostname := n.Hostname
+	if hostname == "" {
+		hostname = n.ID
+	}
+
+	params := [][2]string{
+		{"hostname", hostname},
+		{"port", n.PortID},
+		{"mgmt_ip", FormatIP(n.ManagementIP)},
+		{"interface", n.Interface},
+		{"platform", n.Platform},
+		{"protocol", string(n.Protocol)},
+		{"mac", FormatMAC(n.SourceMAC)},
+	}
+	var sd strings.Builder
+	sd.WriteString("[" + syslogSDID)
+	for _, p := range params {
+		fmt.Fprintf(&sd, " %s=\"%s\"", p[0], syslogParamValue(p[1]))
+	}
+	sd.WriteString("]")
+
+	msg := fmt.Sprintf("New neighbor %s on %s", sanitizeForCSV(hostname), n.Interface)
+	if n.PortID != "" {
+		msg += " (port " + sanitizeForCSV(n.PortID) + ")"
+	}
+
+	pri := syslogFacilityUser*8 + syslogSeverityNote
+	return []byte(fmt.Sprintf("<%d>1 %s %s %s %d %s %s %s",
+		pri,
+		at.Format("2006-01-02T15:04:05.000000Z07:00"),
+		l.hostname,
+		syslogAppName,
+		os.Getpid(),
+		syslogMsgID,
+		sd.String(),
+		msg,
+	))
+}
+
+// syslogParamValue escapes a structured data value as RFC 5424 requires
+func syslogParamValue(s string) string {
+	s = sanitizeForCSV(s)
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(s)
+}
+
+// syslogHeaderField makes s a valid header field: printable ASCII without spaces
+func syslogHeaderField(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return -1
+		}
+		return r
+	}, s)
+	if s == "" {
+		return "-"
+	}
+	return s
+}
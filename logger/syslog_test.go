@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"nbor/types"
+)
+
+func TestParseSyslogServer(t *testing.T) {
+	tests := []struct {
+		server      string
+		wantNetwork string
+		wantAddress string
+		wantErr     bool
+	}{
+		{"10.0.0.5:514", "udp", "10.0.0.5:514", false},
+		{"udp://siem.example.com:1514", "udp", "siem.example.com:1514", false},
+		{"TCP://10.0.0.5:6514", "tcp", "10.0.0.5:6514", false},
+		{"10.0.0.5", "udp", "10.0.0.5:514", false},
+		{"tcp://[2001:db8::1]", "tcp", "[2001:db8::1]:514", false},
+		{"http://10.0.0.5:514", "", "", true},
+		{"udp://", "", "", true},
+	}
+
+	for _, tt := range tests {
+		network, address, err := ParseSyslogServer(tt.server)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSyslogServer(%q) error = %v, wantErr %v", tt.server, err, tt.wantErr)
+			continue
+		}
+		if network != tt.wantNetwork || address != tt.wantAddress {
+			t.Errorf("ParseSyslogServer(%q) = %q, %q, want %q, %q", tt.server, network, address, tt.wantNetwork, tt.wantAddress)
+		}
+	}
+}
+
+func TestSyslogLoggerSendsRFC5424(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("can't listen on UDP: %v", err)
+	}
+	defer pc.Close()
+
+	l, err := NewSyslogLogger(pc.LocalAddr().String(), nil)
+	if err != nil {
+		t.Fatalf("NewSyslogLogger() error = %v", err)
+	}
+	defer l.Close()
+
+	n := &types.Neighbor{
+		Hostname:     `core"sw1]`,
+		PortID:       "Gi1/0/24",
+		ManagementIP: net.ParseIP("10.0.0.2"),
+		Interface:    "eth0",
+		Protocol:     types.ProtocolCDP,
+	}
+	if err := l.Log(n); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+
+	buf := make([]byte, 2048)
+	_ = pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	size, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("no syslog message received: %v", err)
+	}
+	msg := string(buf[:size])
+
+	header := regexp.MustCompile(`^<13>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}\S+ \S+ nbor \d+ NEIGHBOR \[neighbor@32473 `)
+	if !header.MatchString(msg) {
+		t.Errorf("message header not RFC 5424: %q", msg)
+	}
+	for _, want := range []string{
+		`hostname="core\"sw1\]"`,
+		`port="Gi1/0/24"`,
+		`mgmt_ip="10.0.0.2"`,
+		`interface="eth0"`,
+		`protocol="CDP"`,
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %s: %q", want, msg)
+		}
+	}
+}
+
+func TestSyslogLoggerWarnsOnce(t *testing.T) {
+	// Nothing listens on this TCP port, so every send fails
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("can't listen on TCP: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	l, err := NewSyslogLogger("tcp://"+addr, nil)
+	if err != nil {
+		t.Fatalf("NewSyslogLogger() error = %v", err)
+	}
+	var warnings int
+	l.OnError = func(error) { warnings++ }
+
+	n := &types.Neighbor{Hostname: "sw1", Interface: "eth0"}
+	for i := 0; i < 3; i++ {
+		if err := l.Log(n); err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+	}
+	l.Close()
+
+	if warnings != 1 {
+		t.Errorf("got %d warnings for an unreachable server, want 1", warnings)
+	}
+	if err := l.Log(n); err == nil {
+		t.Error("Log() after Close() should fail")
+	}
+}
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"os/signal"
 	"slices"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -19,9 +25,12 @@ import (
 	"nbor/capture"
 	"nbor/cli"
 	"nbor/config"
+	"nbor/hook"
 	"nbor/logger"
+	"nbor/metrics"
 	"nbor/parser"
 	"nbor/platform"
+	"nbor/sqlite"
 	"nbor/tui"
 	"nbor/types"
 	"nbor/version"
@@ -32,6 +41,7 @@ func init() {
 	// set COLORTERM environment variable. This enables proper background colors.
 	// Safe to call even on terminals that don't support true color - they'll
 	// just display the closest available colors.
+	// Overridden by --color-profile or the color_profile config option
 	lipgloss.SetColorProfile(termenv.TrueColor)
 }
 
@@ -48,6 +58,29 @@ func main() {
 	// Parse CLI arguments
 	opts := cli.ParseArgs()
 
+	// Apply the color profile flag early so help and theme listings honor it
+	if opts.ColorProfile != "" {
+		if err := tui.SetColorProfile(opts.ColorProfile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Listing commands and --tail print plain text with --no-color or NO_COLOR set
+	cli.SetNoColor(opts.ColorDisabled())
+
+	// The TUI's alt screen would hide warnings printed before it starts, so
+	// they're queued for its footer; the other modes print them right away
+	var startupWarnings []string
+	startupWarn := func(format string, args ...any) {
+		msg := fmt.Sprintf(format, args...)
+		if opts.RunsTUI() {
+			startupWarnings = append(startupWarnings, msg)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+	}
+
 	// Handle help flag
 	if opts.ShowHelp {
 		cli.PrintHelp()
@@ -60,6 +93,13 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Register user themes before they're listed or looked up
+	if dir, err := config.GetThemesDir(); err == nil {
+		for _, w := range tui.LoadCustomThemes(dir) {
+			startupWarn("%s", w)
+		}
+	}
+
 	// Handle list-themes flag
 	if opts.ListThemes {
 		cli.PrintThemes()
@@ -69,13 +109,37 @@ func main() {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to load config: %v\n", err)
+		startupWarn("failed to load config: %v", err)
 		cfg = config.DefaultConfig()
 	}
+	fileCfg := cfg // Kept without the profile and flags for the config menu to save
+
+	// Handle list-profiles flag
+	if opts.ListProfiles {
+		cli.PrintProfiles(&cfg)
+		os.Exit(0)
+	}
+
+	// Apply the selected profile, then CLI overrides on top of it
+	if opts.Profile != "" {
+		fixed, err := cfg.ApplyProfile(opts.Profile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, f := range fixed {
+			startupWarn("profile %s: invalid value fixed, %s", opts.Profile, f)
+		}
+	}
 
 	// Apply CLI overrides to config
 	cli.ApplyOverrides(&cfg, opts)
 
+	// Apply the configured color profile (the flag, if given, was copied into cfg)
+	if err := tui.SetColorProfile(cfg.ColorProfile); err != nil {
+		startupWarn("%v", err)
+	}
+
 	// Determine theme: CLI flag overrides config
 	themeName := cfg.Theme
 	if opts.ThemeName != "" {
@@ -87,9 +151,9 @@ func main() {
 		tui.SetTheme(*theme)
 	} else if themeName != "" && themeName != "solarized-dark" {
 		// Only warn if user explicitly specified an invalid theme
-		fmt.Fprintf(os.Stderr, "Warning: unknown theme '%s', using default\n", themeName)
-		fmt.Fprintf(os.Stderr, "Run 'nbor --list-themes' to see available themes\n")
+		startupWarn("unknown theme '%s', using default (see nbor --list-themes)", themeName)
 	}
+	tui.SetAccents(cfg.CursorColor, cfg.FlashColor)
 
 	// Check for Npcap on Windows
 	if err := platform.CheckNpcap(); err != nil {
@@ -103,6 +167,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Check a custom capture filter before anything is opened
+	if cfg.CaptureBPF != "" {
+		if err := capture.ValidateFilter(cfg.CaptureBPF); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: capture_bpf: %v\n", err)
+			os.Exit(1)
+		}
+		startupWarn("using custom capture filter %q instead of the CDP/LLDP filter; only CDP and LLDP frames are decoded", cfg.CaptureBPF)
+	}
+	if cfg.CaptureBPFExtra != "" {
+		if err := capture.ValidateFilter(capture.Filter(cfg.CaptureBPF, cfg.CaptureBPFExtra, cfg.CDPListen, cfg.LLDPListen)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: capture_bpf_extra: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Get available Ethernet interfaces
 	interfaces, err := platform.GetEthernetInterfaces()
 	if err != nil {
@@ -111,11 +190,24 @@ func main() {
 	}
 
 	// Handle list-interfaces flag
-	if opts.ListInterfaces {
+	if opts.ListInterfaces && !opts.ListAllInterfaces {
+		if opts.JSON {
+			if err := cli.PrintInterfacesJSON(os.Stdout, interfaces, nil); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing JSON: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
 		cli.PrintInterfaces(interfaces)
 		os.Exit(0)
 	}
 
+	// Handle why flag
+	if opts.WhyInterface != "" {
+		cli.PrintExplanation(platform.ExplainInterface(opts.WhyInterface))
+		os.Exit(0)
+	}
+
 	// Handle list-all-interfaces flag
 	if opts.ListAllInterfaces {
 		allInterfaces, err := platform.GetAllInterfaces()
@@ -123,6 +215,13 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error listing all interfaces: %v\n", err)
 			os.Exit(1)
 		}
+		if opts.JSON {
+			if err := cli.PrintInterfacesJSON(os.Stdout, interfaces, allInterfaces); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing JSON: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
 		cli.PrintAllInterfaces(interfaces, allInterfaces)
 		os.Exit(0)
 	}
@@ -155,8 +254,14 @@ func main() {
 		}
 	}
 
+	// --all-interfaces captures everywhere, so there's nothing to pick
+	if opts.AllInterfaces && opts.InterfaceName != "" {
+		fmt.Fprintf(os.Stderr, "Error: --all-interfaces can't be used with an interface name\n")
+		os.Exit(1)
+	}
+
 	// Auto-select interface if only one is available and up
-	if preselectedInterface == nil && cfg.AutoSelectInterface {
+	if preselectedInterface == nil && !opts.AllInterfaces && cfg.AutoSelectInterface {
 		var upInterfaces []types.InterfaceInfo
 		for _, iface := range interfaces {
 			if iface.IsUp {
@@ -168,26 +273,129 @@ func main() {
 		}
 	}
 
+	// Show the frames that would be advertised, without opening a capture
+	if opts.ShowFrame {
+		if opts.AllInterfaces || preselectedInterface == nil {
+			fmt.Fprintf(os.Stderr, "Error: --show-frame needs one interface (e.g. nbor --show-frame eth0)\n")
+			os.Exit(1)
+		}
+		cli.PrintFramePreview(os.Stdout, &cfg, preselectedInterface)
+		os.Exit(0)
+	}
+
+	// Serve Prometheus metrics (if requested); a bad address is fatal, since
+	// the user asked for it explicitly
+	var packetMetrics *metrics.Metrics
+	var metricsServer *metrics.Server
+	if opts.MetricsAddr != "" {
+		packetMetrics = metrics.New()
+		server, err := metrics.Serve(opts.MetricsAddr, packetMetrics)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		metricsServer = server
+	}
+
+	// Tail and JSON modes print neighbor events instead of running the TUI
+	if opts.Tail || opts.JSON {
+		mode := "--tail"
+		if opts.JSON {
+			mode = "--json"
+		}
+		if opts.Tail && opts.JSON {
+			fmt.Fprintf(os.Stderr, "Error: --tail and --json can't be used together\n")
+			os.Exit(1)
+		}
+		if opts.AllInterfaces {
+			fmt.Fprintf(os.Stderr, "Error: %s captures on one interface; --all-interfaces is for the TUI\n", mode)
+			os.Exit(1)
+		}
+		if preselectedInterface == nil {
+			fmt.Fprintf(os.Stderr, "Error: %s needs an interface (e.g. nbor %s eth0)\n", mode, mode)
+			os.Exit(1)
+		}
+		// Only color tail output on a terminal unless a profile was requested
+		if opts.ColorProfile == "" {
+			_ = tui.SetColorProfile("auto")
+		}
+		if !cfg.ListeningEnabled() {
+			fmt.Fprintf(os.Stderr, "Error: both CDP and LLDP listening are disabled - nothing would be captured\n")
+			fmt.Fprintf(os.Stderr, "Enable one with --cdp-listen or --lldp-listen\n")
+			os.Exit(1)
+		}
+
+		duration := time.Duration(opts.Timeout) * time.Second
+		if opts.Duration > 0 {
+			duration = opts.Duration
+		}
+		var err error
+		if opts.Watch {
+			interval := defaultWatchInterval
+			if opts.Interval > 0 {
+				interval = time.Duration(opts.Interval) * time.Second
+			}
+			err = runJSONWatch(*preselectedInterface, &cfg, interval, duration, packetMetrics)
+		} else if opts.JSON {
+			if duration == 0 {
+				duration = defaultJSONDuration
+			}
+			err = runJSON(*preselectedInterface, &cfg, duration, packetMetrics)
+		} else {
+			err = runTail(*preselectedInterface, &cfg, duration, packetMetrics)
+		}
+		if metricsServer != nil {
+			metricsServer.Close()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// The capture filter matches nothing until a protocol is turned on in the config menu
+	if !cfg.ListeningEnabled() {
+		startupWarn("both CDP and LLDP listening are disabled - capture will see nothing until one is turned on")
+	}
+
 	// Create neighbor store
 	store := types.NewNeighborStore()
+	store.SetFlapDetection(time.Duration(cfg.FlapWindow)*time.Second, cfg.FlapThreshold)
+	store.SetMergeAcrossInterfaces(cfg.MergeAcrossInterfaces)
+	packetMetrics.SetCurrent(store.Count)
 
 	// Create the TUI application
 	// If interface is preselected, start at interface picker, otherwise show main menu
 	var app tui.AppModel
-	if preselectedInterface != nil {
+	if preselectedInterface != nil || opts.AllInterfaces {
 		app = tui.NewAppAtInterfacePicker(interfaces, store, &cfg, selectedInterfaceChan, restartLogChan, restartCaptureChan, broadcastToggleChan, configUpdateChan)
 	} else {
 		app = tui.NewApp(interfaces, store, &cfg, selectedInterfaceChan, restartLogChan, restartCaptureChan, broadcastToggleChan, configUpdateChan)
 	}
+	app.SetBaseConfig(&fileCfg)
+	app.AddWarnings(startupWarnings...)
 
 	// Create program with options
 	p := tea.NewProgram(app, tea.WithAltScreen())
 
-	// Variables for capture state
-	var capturer *capture.Capturer
-	var csvLogger *logger.CSVLogger
-	var broadcaster *broadcast.Broadcaster
-	var pcapHandle *pcap.Handle
+	// Variables for capture state (one session per captured interface)
+	var sessions []*captureSession
+	var neighborLog logger.NeighborLogger
+	var syslogLogger *logger.SyslogLogger
+	var sqliteExporter *sqlite.Exporter
+
+	// Start a new log file at log_max_size_mb, pointing the footer at each new file
+	setLogRotation := func(l logger.NeighborLogger) {
+		l.SetRotation(int64(cfg.LogMaxSizeMB)<<20, func(path string) {
+			p.Send(tui.LogRestartedMsg{LogPath: path})
+		})
+	}
+
+	// Show a warning in the TUI; stderr is hidden behind the alt screen once it runs
+	warn := func(format string, args ...any) {
+		p.Send(tui.WarningMsg{Text: fmt.Sprintf(format, args...)})
+	}
 
 	// Handle graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -195,7 +403,7 @@ func main() {
 
 	go func() {
 		<-sigChan
-		cleanupAll(capturer, csvLogger, broadcaster)
+		cleanupAll(sessions, neighborLog, syslogLogger, sqliteExporter, metricsServer)
 		p.Quit()
 	}()
 
@@ -204,7 +412,9 @@ func main() {
 		var ifaceInfo types.InterfaceInfo
 
 		// If interface was preselected via CLI, use it directly
-		if preselectedInterface != nil {
+		if opts.AllInterfaces {
+			ifaceInfo = interfaces[0]
+		} else if preselectedInterface != nil {
 			ifaceInfo = *preselectedInterface
 			// Also send to channel so TUI knows to skip picker
 			select {
@@ -216,99 +426,260 @@ func main() {
 			ifaceInfo = <-selectedInterfaceChan
 		}
 
-		// Get internal name for pcap (important for Windows)
-		internalName := platform.GetInterfaceInternalName(ifaceInfo.Name)
-
-		// Open pcap handle for both capture and broadcast
-		// Use 100ms timeout instead of BlockForever to allow clean shutdown on Linux
-		handle, err := pcap.OpenLive(internalName, 65535, true, 100*time.Millisecond)
-		if err != nil {
-			p.Send(tui.ErrorMsg{Err: fmt.Errorf("failed to open interface: %w", err)})
-			return
+		captureIfaces := []types.InterfaceInfo{ifaceInfo}
+		if opts.AllInterfaces {
+			// Interfaces without link have nothing to hear and would show LINK DOWN all session
+			captureIfaces = nil
+			for _, iface := range interfaces {
+				if iface.IsUp {
+					captureIfaces = append(captureIfaces, iface)
+				}
+			}
+			if len(captureIfaces) == 0 {
+				p.Send(tui.ErrorMsg{Err: fmt.Errorf("none of the %d interfaces has link", len(interfaces))})
+				return
+			}
 		}
-		pcapHandle = handle
 
-		// Set BPF filter for capture
-		filter := "ether dst 01:00:0c:cc:cc:cc or ether dst 01:80:c2:00:00:0e"
-		if err := handle.SetBPFFilter(filter); err != nil {
-			handle.Close()
-			p.Send(tui.ErrorMsg{Err: fmt.Errorf("failed to set BPF filter: %w", err)})
+		// Open a pcap handle on each interface for both capture and broadcast
+		// With --all-interfaces, one that can't be opened is skipped
+		var opened []*captureSession
+		for _, iface := range captureIfaces {
+			session, err := openCaptureSession(iface, &cfg)
+			if err != nil {
+				if !opts.AllInterfaces {
+					p.Send(tui.ErrorMsg{Err: err})
+					return
+				}
+				warn("skipping %s: %v", iface.Name, err)
+				continue
+			}
+			name := session.iface.Name
+			// A broadcaster that fails stops on its own; the other interfaces keep sending
+			session.broadcaster.OnError = func(err error) {
+				session.broadcaster.Stop()
+				p.Send(tui.ErrorMsg{Err: fmt.Errorf("%s: %w", name, err), Broadcast: true, Interface: name})
+			}
+			session.broadcaster.OnWarning = func(msg string) {
+				warn("%s: %s", name, msg)
+			}
+			session.broadcaster.OnAutoStop = func(after time.Duration) {
+				p.Send(tui.BroadcastAutoStoppedMsg{After: after})
+			}
+			opened = append(opened, session)
+		}
+		if len(opened) == 0 {
+			p.Send(tui.ErrorMsg{Err: fmt.Errorf("couldn't open any of %d interfaces", len(captureIfaces))})
 			return
 		}
+		sessions = opened
+		ifaceInfo = sessions[0].iface
+		self := newSelfFilter(sessions, &cfg)
+		for _, session := range sessions {
+			session.self = self
+		}
+		captureIfaces = captureIfaces[:0]
+		ifaceNames := make([]string, len(sessions))
+		for i, session := range sessions {
+			captureIfaces = append(captureIfaces, session.iface)
+			ifaceNames[i] = session.iface.Name
+		}
 
-		// Create capturer using existing handle
-		cap := capture.NewCapturerWithHandle(handle, internalName)
-		capturer = cap
-
-		// Create CSV logger (if enabled)
+		// Create the CSV or JSON Lines logger (if enabled)
 		if cfg.LoggingEnabled {
-			csvLog, err := logger.NewCSVLogger(cfg.LogDirectory, cfg.FilterCapabilities)
+			newLog, err := logger.NewLogger(cfg.LogFormat, cfg.LogDirectory, cfg.FilterCapabilities)
 			if err != nil {
 				p.Send(tui.ErrorMsg{Err: fmt.Errorf("failed to create log file: %w", err)})
-				cap.Stop()
+				for _, session := range sessions {
+					session.stop()
+				}
 				return
 			}
-			csvLogger = csvLog
+			setLogRotation(newLog)
+			neighborLog = newLog
+		}
+
+		// The handle stays open for broadcasting even if nothing will be captured;
+		// the TUI shows a NOT LISTENING warning until CDP or LLDP is enabled
+		if !cfg.ListeningEnabled() && neighborLog != nil {
+			msg := "CDP and LLDP listening are both disabled; nothing will be captured"
+			for _, name := range ifaceNames {
+				if err := neighborLog.LogEvent(time.Now(), name, msg); err != nil {
+					warn("failed to log event: %v", err)
+				}
+			}
 		}
 
-		// Create broadcaster
-		bc := broadcast.NewBroadcaster(handle, &cfg, &ifaceInfo)
-		broadcaster = bc
+		// Create syslog logger (if configured)
+		// An unreachable server is reported once and never stops capture
+		if cfg.SyslogServer != "" {
+			sl, err := logger.NewSyslogLogger(cfg.SyslogServer, cfg.FilterCapabilities)
+			if err != nil {
+				warn("syslog disabled: %v", err)
+			} else {
+				sl.OnError = func(err error) {
+					warn("%v", err)
+				}
+				syslogLogger = sl
+			}
+		}
+
+		// Create SQLite exporter (if configured)
+		// Failures are reported but never stop capture
+		if cfg.SQLitePath != "" {
+			exporter, err := sqlite.NewExporter(cfg.SQLitePath, strings.Join(ifaceNames, ","))
+			if err != nil {
+				warn("SQLite export disabled: %v", err)
+			} else {
+				exporter.OnError = func(err error) {
+					warn("%v", err)
+				}
+				sqliteExporter = exporter
+			}
+		}
+
+		// Create new neighbor hook (if configured)
+		var neighborHook *hook.Runner
+		if cfg.NewNeighborCommand != "" {
+			runner, err := hook.NewRunner(cfg.NewNeighborCommand)
+			if err != nil {
+				warn("new neighbor command disabled: %v", err)
+			} else {
+				runner.OnError = func(err error) {
+					warn("%v", err)
+				}
+				neighborHook = runner
+			}
+		}
+
+		// Create webhook (if configured)
+		var neighborWebhook *hook.Webhook
+		if cfg.WebhookURL != "" {
+			wh, err := hook.NewWebhook(cfg.WebhookURL)
+			if err != nil {
+				warn("webhook disabled: %v", err)
+			} else {
+				wh.OnError = func(err error) {
+					warn("%v", err)
+				}
+				neighborWebhook = wh
+			}
+		}
+
+		if cfg.BroadcastVariationEnabled() {
+			warn("broadcast_variation = %q advertises a new device ID and source MAC every interval; use only in a lab", cfg.BroadcastVariation)
+		}
 
 		// Start broadcaster only if BroadcastOnStartup is enabled AND a protocol is configured
 		if cfg.BroadcastOnStartup && (cfg.CDPBroadcast || cfg.LLDPBroadcast) {
-			bc.Start()
+			for _, session := range sessions {
+				session.broadcaster.Start()
+			}
 		}
 
 		// Set up neighbor callback - only log first-seen neighbors
 		store.OnNewNeighbor = func(n *types.Neighbor) {
-			// Ring terminal bell
-			platform.Bell()
+			// Ring terminal bell, unless bell_on_new or --quiet turned it off
+			if cfg.BellOnNew {
+				platform.Bell()
+			}
 
-			// Log to CSV (only new neighbors, not updates) if logging is enabled
-			if csvLogger != nil {
-				if err := csvLogger.Log(n); err != nil {
+			// Log to CSV or JSONL (only new neighbors, not updates) if logging is enabled
+			if neighborLog != nil {
+				if err := neighborLog.Log(n); err != nil {
 					// Log error but don't crash
-					fmt.Fprintf(os.Stderr, "Warning: failed to log neighbor: %v\n", err)
+					warn("failed to log neighbor: %v", err)
+				}
+			}
+
+			// Send first-seen neighbors to syslog
+			if syslogLogger != nil {
+				if err := syslogLogger.Log(n); err != nil {
+					warn("%v", err)
 				}
 			}
 
+			// Record every sighting in SQLite for history
+			if sqliteExporter != nil {
+				sqliteExporter.Record(n)
+			}
+
+			// Run the user's command in the background
+			if neighborHook != nil {
+				neighborHook.Run(n)
+			}
+
+			// POST to the webhook in the background
+			if neighborWebhook != nil {
+				neighborWebhook.Post(n)
+			}
+
 			// Notify TUI
 			p.Send(tui.NewNeighborMsg{Neighbor: n})
 		}
-		// Note: OnUpdate not set - we only log first-seen neighbors
+		// The log only records first-seen neighbors; SQLite keeps every update
+		if sqliteExporter != nil {
+			store.OnUpdate = sqliteExporter.Record
+		}
+
+		// Record link instability in the event log
+		store.OnLinkInstability = func(iface string) {
+			if neighborLog != nil {
+				msg := "possible link instability: neighbors changing repeatedly"
+				if err := neighborLog.LogEvent(time.Now(), iface, msg); err != nil {
+					warn("failed to log event: %v", err)
+				}
+			}
+		}
 
 		// Determine log path for display
 		logPath := ""
-		if csvLogger != nil {
-			logPath = csvLogger.Filepath()
+		if neighborLog != nil {
+			logPath = neighborLog.Filepath()
 		}
 
 		// Signal TUI to transition to capture view
-		p.Send(tui.StartCaptureMsg{
+		startMsg := tui.StartCaptureMsg{
 			Interface: ifaceInfo,
 			LogPath:   logPath,
-		})
+		}
+		if opts.AllInterfaces {
+			startMsg.Interfaces = captureIfaces
+		}
+		p.Send(startMsg)
+
+		for _, session := range sessions {
+			session := session
+			session.onPacket = func() { p.Send(tui.PacketSeenMsg{}) }
+
+			// Reopen the capture when the link comes back, since a pcap handle can keep
+			// failing once its interface has gone down, then send right away if configured
+			onLinkUp := func() {
+				if err := session.reopen(store, &cfg, packetMetrics); err != nil {
+					warn("couldn't reopen capture on %s after link up: %v", session.iface.Name, err)
+				}
+				session.broadcaster.LinkUp()
+			}
 
-		// Start capturing
-		packets := cap.Start()
+			// Watch for the link dropping while capturing
+			go watchLinkState(p, session.iface.Name, onLinkUp)
 
-		// Process packets (pass local MAC to filter out own broadcasts)
-		localMAC := ""
-		if ifaceInfo.MAC != nil {
-			localMAC = ifaceInfo.MAC.String()
+			// Start capturing; the store is shared, so neighbors from every
+			// interface land in the same table
+			go processPackets(session.capturer.Start(), store, session.iface.Name, session.self, &cfg, packetMetrics, session.onPacket)
 		}
-		processPackets(packets, store, ifaceInfo.Name, localMAC, &cfg)
+
+		go reportCaptureStats(p, sessions)
 	}()
 
 	// Goroutine to handle broadcast toggle messages from TUI
 	go func() {
 		for enabled := range broadcastToggleChan {
-			if broadcaster != nil {
+			for _, session := range sessions {
 				if enabled {
-					broadcaster.Start()
+					session.broadcaster.Start()
 				} else {
-					broadcaster.Stop()
+					session.broadcaster.Stop()
 				}
 			}
 		}
@@ -318,10 +689,21 @@ func main() {
 	go func() {
 		for newCfg := range configUpdateChan {
 			// Update local config reference
+			listenChanged := newCfg.CDPListen != cfg.CDPListen || newCfg.LLDPListen != cfg.LLDPListen
 			cfg = *newCfg
-			// Update broadcaster config
-			if broadcaster != nil {
-				broadcaster.UpdateConfig(newCfg)
+			store.SetFlapDetection(time.Duration(newCfg.FlapWindow)*time.Second, newCfg.FlapThreshold)
+			// Update broadcaster config, and the capture filter if a protocol was turned on or off
+			for _, session := range sessions {
+				session.broadcaster.UpdateConfig(newCfg)
+				if listenChanged {
+					if err := session.setFilter(capture.Filter(newCfg.CaptureBPF, newCfg.CaptureBPFExtra, newCfg.CDPListen, newCfg.LLDPListen)); err != nil {
+						// Fall back to both protocols; processPackets still skips the one that's off
+						warn("%s: couldn't update the capture filter, capturing both protocols: %v", session.iface.Name, err)
+						if err := session.setFilter(capture.Filter(newCfg.CaptureBPF, newCfg.CaptureBPFExtra, true, true)); err != nil {
+							warn("%s: couldn't reset the capture filter: %v", session.iface.Name, err)
+						}
+					}
+				}
 			}
 		}
 	}()
@@ -332,30 +714,29 @@ func main() {
 			// Only restart if logging is enabled
 			if cfg.LoggingEnabled {
 				// Close old log file if exists
-				if csvLogger != nil {
-					csvLogger.Close()
+				if neighborLog != nil {
+					neighborLog.Close()
 				}
 
 				// Create new log file with current config
-				newLogger, err := logger.NewCSVLogger(cfg.LogDirectory, cfg.FilterCapabilities)
+				newLogger, err := logger.NewLogger(cfg.LogFormat, cfg.LogDirectory, cfg.FilterCapabilities)
 				if err != nil {
 					// Log error but continue with old logger
 					continue
 				}
-				csvLogger = newLogger
+				setLogRotation(newLogger)
+				neighborLog = newLogger
 
 				// Notify TUI of new log path
-				p.Send(tui.LogRestartedMsg{LogPath: csvLogger.Filepath()})
+				p.Send(tui.LogRestartedMsg{LogPath: neighborLog.Filepath()})
 			}
 		}
 	}()
 
 	// Run the TUI
 	if _, err := p.Run(); err != nil {
-		cleanupAll(capturer, csvLogger, broadcaster)
-		if pcapHandle != nil {
-			pcapHandle.Close()
-		}
+		cleanupAll(sessions, neighborLog, syslogLogger, sqliteExporter, metricsServer)
+		closeCaptureHandles(sessions)
 		fmt.Fprintf(os.Stderr, "Error running application: %v\n", err)
 		os.Exit(1)
 	}
@@ -364,10 +745,8 @@ func main() {
 	select {
 	case <-restartCaptureChan:
 		// Clean up current session
-		cleanupAll(capturer, csvLogger, broadcaster)
-		if pcapHandle != nil {
-			pcapHandle.Close()
-		}
+		cleanupAll(sessions, neighborLog, syslogLogger, sqliteExporter, metricsServer)
+		closeCaptureHandles(sessions)
 		// Re-exec the program to restart fresh, with --no-auto-select to force interface picker
 		exe, err := os.Executable()
 		if err != nil {
@@ -375,7 +754,8 @@ func main() {
 			os.Exit(1)
 		}
 		// Build args, adding --no-auto-select if not already present
-		args := os.Args[1:] // Skip program name for exec.Command
+		// --all-interfaces is dropped, since the user asked to pick one
+		args := slices.DeleteFunc(slices.Clone(os.Args[1:]), func(a string) bool { return a == "--all-interfaces" })
 		if !slices.Contains(args, "--no-auto-select") {
 			args = append(args, "--no-auto-select")
 		}
@@ -395,20 +775,359 @@ func main() {
 	}
 
 	// Clean up on exit
-	cleanupAll(capturer, csvLogger, broadcaster)
-	if pcapHandle != nil {
-		pcapHandle.Close()
+	cleanupAll(sessions, neighborLog, syslogLogger, sqliteExporter, metricsServer)
+	closeCaptureHandles(sessions)
+}
+
+// openRetries and openRetryDelay bound the retries of a failed capture open, keeping
+// the total wait well under a second so an interface that can't be opened still
+// errors out quickly
+const (
+	openRetries    = 2
+	openRetryDelay = 300 * time.Millisecond
+)
+
+// openCaptureHandle opens a pcap handle on the interface with the CDP/LLDP filter
+// set, or the custom capture_bpf filter if there is one, narrowed by capture_bpf_extra
+// The snapshot length and buffer size come from capture_snaplen and capture_buffer_mb
+// Returns the handle and the interface's internal pcap name
+func openCaptureHandle(ifaceName string, cfg *config.Config) (*pcap.Handle, string, error) {
+	// Get internal name for pcap (important for Windows)
+	internalName := platform.GetInterfaceInternalName(ifaceName)
+
+	// The first open right after privilege setup sometimes fails on some systems
+	// and a retry succeeds, so try a couple more times before giving up
+	handle, err := capture.OpenHandle(internalName, cfg.CaptureSnapLen, cfg.CaptureBufferMB)
+	for attempt := 0; err != nil && attempt < openRetries; attempt++ {
+		time.Sleep(openRetryDelay)
+		handle, err = capture.OpenHandle(internalName, cfg.CaptureSnapLen, cfg.CaptureBufferMB)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open interface: %w", err)
+	}
+
+	// Set BPF filter for capture, leaving out protocols that aren't listened for
+	filter := capture.Filter(cfg.CaptureBPF, cfg.CaptureBPFExtra, cfg.CDPListen, cfg.LLDPListen)
+	if err := handle.SetBPFFilter(filter); err != nil {
+		handle.Close()
+		return nil, "", fmt.Errorf("failed to set BPF filter: %w", err)
+	}
+
+	return handle, internalName, nil
+}
+
+// captureSession is the capture and broadcast state for one interface
+// --all-interfaces runs one per interface, all feeding the same store
+type captureSession struct {
+	iface        types.InterfaceInfo
+	internalName string
+	handle       *pcap.Handle
+	capturer     *capture.Capturer
+	broadcaster  *broadcast.Broadcaster
+
+	// mu guards handle and capturer while reopen replaces them
+	mu sync.Mutex
+	// Packets counted by handles that reopen has closed, since pcap's
+	// statistics start again from zero on a new handle
+	closedReceived, closedDropped int
+
+	// onPacket is called for every CDP or LLDP packet received; it may be nil
+	onPacket func()
+
+	// self recognizes our own broadcasts, from this or any other capture interface
+	self *selfFilter
+}
+
+// openCaptureSession opens the interface and creates its capturer and broadcaster
+// The broadcaster uses the handle and MAC of this interface, so each NIC
+// advertises from its own address
+func openCaptureSession(iface types.InterfaceInfo, cfg *config.Config) (*captureSession, error) {
+	handle, internalName, err := openCaptureHandle(iface.Name, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &captureSession{
+		iface:        iface,
+		internalName: internalName,
+		handle:       handle,
+		capturer:     capture.NewCapturerWithHandle(handle, internalName),
+	}
+	s.broadcaster = broadcast.NewBroadcaster(handle, cfg, &s.iface)
+	return s, nil
+}
+
+// selfFilter recognizes nbor's own advertisements so they don't show up as
+// neighbors: frames from a capture interface's MAC, or from the varied MACs and
+// names broadcast_variation sends, and frames carrying our system name (which
+// also catches one interface hearing another's broadcasts)
+type selfFilter struct {
+	macs []net.HardwareAddr
+	cfg  *config.Config
+}
+
+// newSelfFilter returns a selfFilter for the interfaces of sessions
+func newSelfFilter(sessions []*captureSession, cfg *config.Config) *selfFilter {
+	f := &selfFilter{cfg: cfg}
+	for _, s := range sessions {
+		if s.iface.MAC != nil {
+			f.macs = append(f.macs, s.iface.MAC)
+		}
+	}
+	return f
+}
+
+// ownMAC reports whether a frame from mac is one of ours
+func (f *selfFilter) ownMAC(mac net.HardwareAddr) bool {
+	if f == nil || mac == nil {
+		return false
+	}
+	for _, local := range f.macs {
+		if broadcast.IsOwnMAC(local, mac, f.cfg.BroadcastVariationEnabled()) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownNeighbor reports whether n, parsed from a frame, advertises our system name
+// as its device ID or system name
+func (f *selfFilter) ownNeighbor(n *types.Neighbor) bool {
+	if f == nil {
+		return false
+	}
+	name := broadcast.AdvertisedName(f.cfg)
+	varied := f.cfg.BroadcastVariationEnabled()
+	return broadcast.IsOwnName(name, n.Hostname, varied) || broadcast.IsOwnName(name, n.ID, varied)
+}
+
+// reopen replaces the capture handle, e.g. after the link returns
+// Stopping the old capturer ends its processPackets loop, and a new one is started
+func (s *captureSession) reopen(store *types.NeighborStore, cfg *config.Config, m *metrics.Metrics) error {
+	newHandle, _, err := openCaptureHandle(s.iface.Name, cfg)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	oldCapturer, oldHandle := s.capturer, s.handle
+	newCapturer := capture.NewCapturerWithHandle(newHandle, s.internalName)
+	s.broadcaster.SetHandle(newHandle)
+	s.capturer, s.handle = newCapturer, newHandle
+	if stats, err := oldHandle.Stats(); err == nil {
+		s.closedReceived += stats.PacketsReceived
+		s.closedDropped += stats.PacketsDropped
+	}
+	s.mu.Unlock()
+
+	oldCapturer.Stop()
+	oldHandle.Close()
+	go processPackets(newCapturer.Start(), store, s.iface.Name, s.self, cfg, m, s.onPacket)
+	return nil
+}
+
+// setFilter replaces the capture filter on the session's current handle
+func (s *captureSession) setFilter(filter string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.handle.SetBPFFilter(filter); err != nil {
+		return fmt.Errorf("failed to set BPF filter: %w", err)
+	}
+	return nil
+}
+
+// stats returns the packets the filter has passed and the packets dropped for
+// lack of buffer space since the session was opened, across reopens
+func (s *captureSession) stats() (received, dropped int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, err := s.handle.Stats()
+	if err != nil {
+		return 0, 0, err
+	}
+	return s.closedReceived + stats.PacketsReceived, s.closedDropped + stats.PacketsDropped, nil
+}
+
+// captureStatsInterval is how often pcap statistics are sent to the TUI
+const captureStatsInterval = 2 * time.Second
+
+// reportCaptureStats periodically sends the packet counts of every session to the TUI
+// Sessions whose statistics can't be read are left out of the totals
+func reportCaptureStats(p *tea.Program, sessions []*captureSession) {
+	ticker := time.NewTicker(captureStatsInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		var msg tui.CaptureStatsMsg
+		for _, s := range sessions {
+			received, dropped, err := s.stats()
+			if err != nil {
+				continue
+			}
+			msg.Received += received
+			msg.Dropped += dropped
+		}
+		p.Send(msg)
+	}
+}
+
+// stop stops broadcasting and capturing; the handle stays open
+func (s *captureSession) stop() {
+	s.broadcaster.Stop()
+	s.capturer.Stop()
+}
+
+// closeCaptureHandles closes the pcap handle of each session
+func closeCaptureHandles(sessions []*captureSession) {
+	for _, s := range sessions {
+		s.handle.Close()
+	}
+}
+
+// runTail captures on the interface and prints a line per new or updated neighbor
+// Runs until interrupted or, if timeout is non-zero, until it elapses
+func runTail(ifaceInfo types.InterfaceInfo, cfg *config.Config, timeout time.Duration, m *metrics.Metrics) error {
+	handle, internalName, err := openCaptureHandle(ifaceInfo.Name, cfg)
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+
+	printer := cli.NewTailPrinter(os.Stdout)
+	store := types.NewNeighborStore()
+	store.OnNewNeighbor = func(n *types.Neighbor) {
+		printer.Print(cli.TailEventNew, n)
+	}
+	store.OnUpdate = func(n *types.Neighbor) {
+		printer.Print(cli.TailEventUpdate, n)
+	}
+
+	cli.PrintTailHeader(os.Stderr, ifaceInfo.Name, timeout)
+	runHeadless(handle, internalName, ifaceInfo, cfg, store, timeout, m)
+	return nil
+}
+
+// defaultJSONDuration is how long --json captures without --duration
+// It covers a full default CDP announcement interval
+const defaultJSONDuration = 60 * time.Second
+
+// runJSON captures on the interface for duration and prints each neighbor
+// as a JSON object on its own line as soon as it is discovered
+func runJSON(ifaceInfo types.InterfaceInfo, cfg *config.Config, duration time.Duration, m *metrics.Metrics) error {
+	handle, internalName, err := openCaptureHandle(ifaceInfo.Name, cfg)
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+
+	// Callbacks all come from the packet loop, so the encoder needs no lock
+	enc := json.NewEncoder(os.Stdout)
+	store := types.NewNeighborStore()
+	store.OnNewNeighbor = func(n *types.Neighbor) {
+		if err := enc.Encode(n); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing JSON: %v\n", err)
+		}
+	}
+
+	cli.PrintTailHeader(os.Stderr, ifaceInfo.Name, duration)
+	runHeadless(handle, internalName, ifaceInfo, cfg, store, duration, m)
+	return nil
+}
+
+// defaultWatchInterval is how often --watch prints a snapshot without --interval
+const defaultWatchInterval = 5 * time.Second
+
+// runJSONWatch captures on the interface and prints a JSON array of the current
+// neighbors every interval, aging them as the TUI does so departed neighbors drop
+// out. Runs until interrupted or, if duration is non-zero, until it elapses
+func runJSONWatch(ifaceInfo types.InterfaceInfo, cfg *config.Config, interval, duration time.Duration, m *metrics.Metrics) error {
+	handle, internalName, err := openCaptureHandle(ifaceInfo.Name, cfg)
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+
+	store := types.NewNeighborStore()
+	out := bufio.NewWriter(os.Stdout)
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				tui.AgeNeighbors(store, cfg, nil)
+				err := cli.WriteSnapshot(out, store.GetAll())
+				if err == nil {
+					// Flush every cycle so consumers reading a pipe see each snapshot at once
+					err = out.Flush()
+				}
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error writing JSON: %v\n", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	cli.PrintTailHeader(os.Stderr, ifaceInfo.Name, duration)
+	runHeadless(handle, internalName, ifaceInfo, cfg, store, duration, m)
+	close(stop)
+	<-stopped
+	return nil
+}
+
+// runHeadless feeds packets from handle into store until interrupted, the
+// capture ends or, if timeout is non-zero, the timeout elapses
+func runHeadless(handle *pcap.Handle, internalName string, ifaceInfo types.InterfaceInfo, cfg *config.Config, store *types.NeighborStore, timeout time.Duration, m *metrics.Metrics) {
+	m.SetCurrent(store.Count)
+
+	cap := capture.NewCapturerWithHandle(handle, internalName)
+	packets := cap.Start()
+	defer cap.Stop()
+
+	self := &selfFilter{cfg: cfg}
+	if ifaceInfo.MAC != nil {
+		self.macs = []net.HardwareAddr{ifaceInfo.MAC}
+	}
+	done := make(chan struct{})
+	go func() {
+		processPackets(packets, store, ifaceInfo.Name, self, cfg, m, nil)
+		close(done)
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	var timeoutChan <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutChan = timer.C
+	}
+
+	select {
+	case <-sigChan:
+	case <-timeoutChan:
+	case <-done:
 	}
 }
 
 // processPackets processes incoming packets and updates the store
-// localMAC is used to filter out our own broadcast packets
+// self filters out our own broadcast packets
 // cfg is used to check listen settings (CDPListen, LLDPListen)
-func processPackets(packets <-chan gopacket.Packet, store *types.NeighborStore, ifaceName string, localMAC string, cfg *config.Config) {
+// m counts packets, parse errors and new neighbors; it may be nil
+// onPacket is called for each CDP or LLDP packet that's listened for; it may be nil
+func processPackets(packets <-chan gopacket.Packet, store *types.NeighborStore, ifaceName string, self *selfFilter, cfg *config.Config, m *metrics.Metrics, onPacket func()) {
 	for packet := range packets {
 		// Filter out our own broadcasts by checking source MAC
-		srcMAC := capture.GetSourceMAC(packet)
-		if srcMAC != nil && srcMAC.String() == localMAC {
+		if self.ownMAC(capture.GetSourceMAC(packet)) {
 			// This is our own broadcast, skip it
 			continue
 		}
@@ -421,37 +1140,91 @@ func processPackets(packets <-chan gopacket.Packet, store *types.NeighborStore,
 			if !cfg.CDPListen {
 				continue // CDP listening disabled
 			}
+			m.PacketReceived("cdp")
+			if errors.Is(parser.CheckCDPChecksum(packet), parser.ErrCDPChecksum) {
+				m.CDPChecksumError()
+				if cfg.CDPStrictChecksum {
+					// Corrupted on the way; don't trust its TLVs, but it still shows CDP is arriving
+					if onPacket != nil {
+						onPacket()
+					}
+					continue
+				}
+			}
 			neighbor, err = parser.ParseCDP(packet, ifaceName)
 		} else if capture.IsLLDPPacket(packet) {
 			if !cfg.LLDPListen {
 				continue // LLDP listening disabled
 			}
+			m.PacketReceived("lldp")
 			neighbor, err = parser.ParseLLDP(packet, ifaceName)
 		} else {
 			continue
 		}
 
+		if onPacket != nil {
+			onPacket()
+		}
+
 		if err != nil {
 			// Skip malformed packets silently
+			m.ParseError()
+			continue
+		}
+
+		if neighbor != nil && self.ownNeighbor(neighbor) {
+			// Our system name, e.g. another capture interface's broadcast
 			continue
 		}
 
 		if neighbor != nil {
 			neighbor.LastSeen = time.Now()
-			store.Update(neighbor)
+			if store.Update(neighbor) {
+				m.NeighborDiscovered()
+			}
 		}
 	}
 }
 
-// cleanupAll handles graceful shutdown of all components
-func cleanupAll(cap *capture.Capturer, log *logger.CSVLogger, bc *broadcast.Broadcaster) {
-	if bc != nil {
-		bc.Stop()
+// watchLinkState polls the interface's link state and notifies the TUI on changes
+// onLinkUp is called on each down-to-up transition
+func watchLinkState(p *tea.Program, ifaceName string, onLinkUp func()) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	lastUp := true
+	for range ticker.C {
+		up, err := platform.GetLinkState(ifaceName)
+		if err != nil {
+			// Link state unknown - don't report a change
+			continue
+		}
+		if up != lastUp {
+			lastUp = up
+			p.Send(tui.LinkStateMsg{Interface: ifaceName, Up: up})
+			if up && onLinkUp != nil {
+				onLinkUp()
+			}
+		}
 	}
-	if cap != nil {
-		cap.Stop()
+}
+
+// cleanupAll handles graceful shutdown of all components
+func cleanupAll(sessions []*captureSession, log logger.NeighborLogger, sl *logger.SyslogLogger, db *sqlite.Exporter, ms *metrics.Server) {
+	// Each interface has its own capturer and broadcaster
+	for _, s := range sessions {
+		s.stop()
 	}
 	if log != nil {
 		log.Close()
 	}
+	if sl != nil {
+		sl.Close()
+	}
+	if db != nil {
+		db.Close()
+	}
+	if ms != nil {
+		ms.Close()
+	}
 }
@@ -0,0 +1,174 @@
+// Package metrics counts captured packets and neighbors and serves them in the
+// Prometheus text format.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Metrics holds the counters. A nil *Metrics ignores every update, so callers
+// don't need to check whether metrics are enabled
+type Metrics struct {
+	mu             sync.Mutex
+	neighborsTotal uint64
+	packets        map[string]uint64 // Packets received by protocol
+	parseErrors    uint64
+	checksumErrors uint64     // CDP frames whose checksum didn't match
+	current        func() int // Neighbors in the store, for the gauge
+}
+
+// New creates an empty set of metrics
+func New() *Metrics {
+	return &Metrics{packets: make(map[string]uint64)}
+}
+
+// SetCurrent sets the function that reports how many neighbors are in the store
+func (m *Metrics) SetCurrent(current func() int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.current = current
+	m.mu.Unlock()
+}
+
+// NeighborDiscovered counts a newly discovered neighbor
+func (m *Metrics) NeighborDiscovered() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.neighborsTotal++
+	m.mu.Unlock()
+}
+
+// PacketReceived counts a CDP or LLDP packet
+func (m *Metrics) PacketReceived(protocol string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.packets[protocol]++
+	m.mu.Unlock()
+}
+
+// ParseError counts a packet that couldn't be parsed
+func (m *Metrics) ParseError() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.parseErrors++
+	m.mu.Unlock()
+}
+
+// CDPChecksumError counts a CDP frame whose checksum didn't match
+func (m *Metrics) CDPChecksumError() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.checksumErrors++
+	m.mu.Unlock()
+}
+
+// WriteText writes the metrics in the Prometheus text exposition format
+func (m *Metrics) WriteText(w io.Writer) error {
+	m.mu.Lock()
+	neighborsTotal := m.neighborsTotal
+	parseErrors := m.parseErrors
+	checksumErrors := m.checksumErrors
+	protocols := make([]string, 0, len(m.packets))
+	for p := range m.packets {
+		protocols = append(protocols, p)
+	}
+	sort.Strings(protocols)
+	packets := make([]uint64, len(protocols))
+	for i, p := range protocols {
+		packets[i] = m.packets[p]
+	}
+	currentFn := m.current
+	m.mu.Unlock()
+
+	current := 0
+	if currentFn != nil {
+		current = currentFn()
+	}
+
+	_, err := fmt.Fprintf(w, `# HELP nbor_neighbors_total Neighbors discovered since start.
+# TYPE nbor_neighbors_total counter
+nbor_neighbors_total %d
+# HELP nbor_neighbors_current Neighbors currently in the table.
+# TYPE nbor_neighbors_current gauge
+nbor_neighbors_current %d
+# HELP nbor_parse_errors_total CDP and LLDP packets that couldn't be parsed.
+# TYPE nbor_parse_errors_total counter
+nbor_parse_errors_total %d
+# HELP nbor_cdp_checksum_errors_total CDP packets whose checksum didn't match.
+# TYPE nbor_cdp_checksum_errors_total counter
+nbor_cdp_checksum_errors_total %d
+# HELP nbor_packets_received_total CDP and LLDP packets received.
+# TYPE nbor_packets_received_total counter
+`, neighborsTotal, current, parseErrors, checksumErrors)
+	if err != nil {
+		return err
+	}
+	for i, p := range protocols {
+		if _, err := fmt.Fprintf(w, "nbor_packets_received_total{protocol=%q} %d\n", p, packets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServeHTTP serves the metrics, so Metrics can be mounted on any path
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.WriteText(w)
+}
+
+// Server serves metrics over HTTP at /metrics
+type Server struct {
+	srv  *http.Server
+	addr string
+}
+
+// shutdownTimeout is how long Close waits for in-flight scrapes
+const shutdownTimeout = 2 * time.Second
+
+// Serve starts serving m on addr (e.g. ":9105") in the background
+// The address is bound before returning, so a port in use is reported here
+func Serve(addr string, m *Metrics) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("metrics server: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	s := &Server{
+		srv:  &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second},
+		addr: ln.Addr().String(),
+	}
+	go func() { _ = s.srv.Serve(ln) }()
+	return s, nil
+}
+
+// Addr returns the address the server is listening on
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// Close shuts the server down
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return s.srv.Shutdown(ctx)
+}
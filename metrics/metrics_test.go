@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	m := New()
+	m.SetCurrent(func() int { return 2 })
+	m.NeighborDiscovered()
+	m.NeighborDiscovered()
+	m.NeighborDiscovered()
+	m.PacketReceived("lldp")
+	m.PacketReceived("cdp")
+	m.PacketReceived("lldp")
+	m.ParseError()
+	m.CDPChecksumError()
+
+	var b strings.Builder
+	if err := m.WriteText(&b); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"# TYPE nbor_neighbors_total counter\nnbor_neighbors_total 3\n",
+		"# TYPE nbor_neighbors_current gauge\nnbor_neighbors_current 2\n",
+		"nbor_parse_errors_total 1\n",
+		"nbor_cdp_checksum_errors_total 1\n",
+		"nbor_packets_received_total{protocol=\"cdp\"} 1\nnbor_packets_received_total{protocol=\"lldp\"} 2\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestNilMetricsIgnoresUpdates(t *testing.T) {
+	var m *Metrics
+	m.NeighborDiscovered()
+	m.PacketReceived("cdp")
+	m.ParseError()
+	m.CDPChecksumError()
+	m.SetCurrent(func() int { return 1 })
+}
+
+func TestServe(t *testing.T) {
+	m := New()
+	m.PacketReceived("cdp")
+
+	s, err := Serve("127.0.0.1:0", m)
+	if err != nil {
+		t.Skipf("can't listen: %v", err)
+	}
+	defer s.Close()
+
+	resp, err := http.Get("http://" + s.Addr() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		t.Errorf("Content-Type = %q", resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(body), `nbor_packets_received_total{protocol="cdp"} 1`) {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+}
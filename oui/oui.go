@@ -0,0 +1,69 @@
+// Package oui maps MAC addresses to the vendor that owns their OUI (the first
+// three bytes), using a trimmed copy of the IEEE registry built into the binary.
+//
+// The table covers network, phone, server and virtualization vendors likely to
+// turn up on a switch port; anything else is unknown.
+package oui
+
+import (
+	_ "embed"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LocallyAdministered is the vendor reported for a MAC with the
+// locally administered bit set: software chose it, so its OUI means nothing
+const LocallyAdministered = "Locally administered"
+
+//go:embed oui.txt
+var registry string
+
+// vendors maps the OUI, as a 24-bit number, to the vendor name
+var vendors = sync.OnceValue(func() map[uint32]string {
+	table, err := parse(registry)
+	if err != nil {
+		panic("oui: " + err.Error())
+	}
+	return table
+})
+
+// parse reads the registry: one "XXXXXX Vendor Name" per line, # comments and
+// blank lines ignored
+func parse(data string) (map[uint32]string, error) {
+	table := make(map[uint32]string)
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		prefix, vendor, ok := strings.Cut(line, " ")
+		vendor = strings.TrimSpace(vendor)
+		if !ok || len(prefix) != 6 || vendor == "" {
+			return nil, fmt.Errorf("line %d: want \"XXXXXX Vendor\", got %q", i+1, line)
+		}
+		n, err := strconv.ParseUint(prefix, 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %q is not a hex OUI", i+1, prefix)
+		}
+		if _, dup := table[uint32(n)]; dup {
+			return nil, fmt.Errorf("line %d: %s listed twice", i+1, prefix)
+		}
+		table[uint32(n)] = vendor
+	}
+	return table, nil
+}
+
+// Lookup returns the vendor of mac, LocallyAdministered when the MAC isn't
+// from the registry at all, or "" when the OUI isn't in the table
+func Lookup(mac net.HardwareAddr) string {
+	if len(mac) < 3 {
+		return ""
+	}
+	if mac[0]&0x02 != 0 {
+		return LocallyAdministered
+	}
+	return vendors()[uint32(mac[0])<<16|uint32(mac[1])<<8|uint32(mac[2])]
+}
@@ -0,0 +1,54 @@
+package oui
+
+import (
+	"net"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		mac  string
+		want string
+	}{
+		{"00:00:0c:12:34:56", "Cisco"},
+		{"00:1c:73:aa:bb:cc", "Arista"},
+		{"00:50:56:01:02:03", "VMware"},
+		{"52:54:00:12:34:56", LocallyAdministered}, // QEMU
+		{"02:00:00:00:00:01", LocallyAdministered},
+		{"00:00:00:00:00:01", ""},
+	}
+	for _, tt := range tests {
+		mac, err := net.ParseMAC(tt.mac)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Lookup(mac); got != tt.want {
+			t.Errorf("Lookup(%s) = %q, want %q", tt.mac, got, tt.want)
+		}
+	}
+
+	if got := Lookup(nil); got != "" {
+		t.Errorf("Lookup(nil) = %q, want \"\"", got)
+	}
+}
+
+func TestParseRegistry(t *testing.T) {
+	table, err := parse(registry)
+	if err != nil {
+		t.Fatalf("built-in registry: %v", err)
+	}
+	if len(table) < 100 {
+		t.Errorf("built-in registry has %d prefixes, want at least 100", len(table))
+	}
+
+	for _, bad := range []string{
+		"00000C",             // No vendor
+		"00000 Cisco",        // Short prefix
+		"00000G Cisco",       // Not hex
+		"00000C A\n00000C B", // Duplicate
+	} {
+		if _, err := parse(bad); err == nil {
+			t.Errorf("parse(%q) succeeded, want an error", bad)
+		}
+	}
+}
@@ -2,9 +2,12 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"net"
+	"slices"
 	"time"
 
 	"github.com/google/gopacket"
@@ -17,18 +20,18 @@ import (
 // ParseCDP parses a CDP packet and returns a Neighbor struct
 func ParseCDP(packet gopacket.Packet, ifaceName string) (*types.Neighbor, error) {
 	// Get the CDP layer
-	cdpLayer := packet.Layer(layers.LayerTypeCiscoDiscovery)
-	if cdpLayer == nil {
+	cdp := findCDPLayer(packet)
+	if cdp == nil {
 		return nil, fmt.Errorf("not a CDP packet")
 	}
 
-	cdp := cdpLayer.(*layers.CiscoDiscovery)
-
 	neighbor := &types.Neighbor{
 		Protocol:  types.ProtocolCDP,
 		LastSeen:  time.Now(),
 		Interface: ifaceName,
+		TTL:       time.Duration(cdp.TTL) * time.Second,
 	}
+	neighbor.RecordFrameLength(frameLength(packet))
 
 	// Get source MAC from ethernet layer
 	if ethLayer := packet.Layer(layers.LayerTypeEthernet); ethLayer != nil {
@@ -50,23 +53,42 @@ func ParseCDP(packet gopacket.Packet, ifaceName string) (*types.Neighbor, error)
 			neighbor.Platform = string(tlv.Value)
 
 		case layers.CDPTLVVersion:
-			neighbor.Description = string(tlv.Value)
+			// The full software version banner, newlines and all
+			neighbor.Description = protocol.CleanString(string(tlv.Value))
 
 		case layers.CDPTLVCapabilities:
 			neighbor.Capabilities = parseCDPCapabilities(tlv.Value)
-
-		case layers.CDPTLVAddress:
-			if ip := parseCDPAddresses(tlv.Value); ip != nil {
-				neighbor.ManagementIP = ip
+			if len(tlv.Value) >= 4 {
+				neighbor.CDPCapabilityBits = binary.BigEndian.Uint32(tlv.Value)
 			}
 
-		case layers.CDPTLVMgmtAddresses:
-			if ip := parseCDPAddresses(tlv.Value); ip != nil {
+		case layers.CDPTLVAddress, layers.CDPTLVMgmtAddresses:
+			// The management addresses TLV comes later and wins, as it should
+			addrs := parseCDPAddresses(tlv.Value)
+			if ip := primaryAddress(addrs); ip != nil {
 				neighbor.ManagementIP = ip
 			}
+			neighbor.AllAddresses = appendNewAddresses(neighbor.AllAddresses, addrs)
 
 		case layers.CDPTLVLocation:
 			neighbor.Location = parseCDPLocation(tlv.Value)
+
+		case layers.CDPTLVVTPDomain:
+			neighbor.VTPDomain = protocol.CleanString(string(tlv.Value))
+
+		case layers.CDPTLVNativeVLAN:
+			if len(tlv.Value) >= 2 {
+				neighbor.NativeVLAN = int(binary.BigEndian.Uint16(tlv.Value[:2]))
+			}
+
+		case layers.CDPTLVFullDuplex:
+			neighbor.Duplex = parseCDPDuplex(tlv.Value)
+
+		case layers.CDPTLVPower:
+			// Power consumption in milliwatts
+			if len(tlv.Value) >= 2 {
+				neighbor.PowerMW = int(binary.BigEndian.Uint16(tlv.Value[:2]))
+			}
 		}
 	}
 
@@ -78,64 +100,185 @@ func ParseCDP(packet gopacket.Packet, ifaceName string) (*types.Neighbor, error)
 	return neighbor, nil
 }
 
+// ErrCDPChecksum is returned by CheckCDPChecksum for a frame whose CDP checksum
+// doesn't match its contents
+var ErrCDPChecksum = errors.New("CDP checksum mismatch")
+
+// CheckCDPChecksum verifies a CDP packet's checksum, returning ErrCDPChecksum
+// when it doesn't match. It doesn't stop ParseCDP from parsing the packet;
+// callers decide whether a mismatch rejects the frame
+func CheckCDPChecksum(packet gopacket.Packet) error {
+	cdp := findCDPLayer(packet)
+	if cdp == nil {
+		return fmt.Errorf("not a CDP packet")
+	}
+	data := append(slices.Clip(cdp.Contents), cdp.Payload...)
+	if !cdpChecksumValid(data) {
+		return ErrCDPChecksum
+	}
+	return nil
+}
+
+// cdpChecksumValid reports whether the checksum in a CDP payload (header and
+// TLVs) matches. Odd-length payloads are also accepted with the checksum Cisco
+// devices send, which puts the last byte in the low half of the final word and
+// sign-extends it, with an off-by-one fix-up when it's 0x80 or above
+func cdpChecksumValid(data []byte) bool {
+	if len(data) < 4 {
+		return false
+	}
+	sent := binary.BigEndian.Uint16(data[2:4])
+	zeroed := slices.Clone(data)
+	zeroed[2], zeroed[3] = 0, 0
+	if protocol.Checksum(zeroed) == sent {
+		return true
+	}
+	if len(zeroed)%2 == 0 {
+		return false
+	}
+
+	last := zeroed[len(zeroed)-1]
+	padded := append(zeroed[:len(zeroed)-1], 0, 0)
+	if last&0x80 != 0 {
+		padded[len(padded)-2], padded[len(padded)-1] = 0xff, last-1
+	} else {
+		padded[len(padded)-2], padded[len(padded)-1] = 0x00, last
+	}
+	return protocol.Checksum(padded) == sent
+}
+
+// cdpSNAPHeader is the SNAP OUI and protocol ID that precede the CDP header
+var cdpSNAPHeader = []byte{0x00, 0x00, 0x0c, 0x20, 0x00}
+
+// cdpHeaderScanLimit bounds how far into a frame findCDPLayer looks for the
+// SNAP header, so TLV contents further on can't be mistaken for it
+const cdpHeaderScanLimit = 64
+
+// findCDPLayer returns the packet's CDP layer. When gopacket didn't decode one,
+// e.g. the frame is VLAN-tagged or its LLC header isn't the usual AA-AA-03, it
+// scans the start of the frame for the SNAP OUI and protocol ID and decodes the
+// CDP header that follows. Returns nil when there's no valid CDP header
+func findCDPLayer(packet gopacket.Packet) *layers.CiscoDiscovery {
+	if cdpLayer := packet.Layer(layers.LayerTypeCiscoDiscovery); cdpLayer != nil {
+		return cdpLayer.(*layers.CiscoDiscovery)
+	}
+
+	// gopacket trims padding using the 802.3 length only when it decodes the
+	// frame itself, so trim it here too before the checksum and TLVs see it
+	data := packet.Data()
+	data = data[:unpaddedLength(data)]
+	head := data[:min(len(data), cdpHeaderScanLimit)]
+	for off := 0; off < len(head); {
+		i := bytes.Index(head[off:], cdpSNAPHeader)
+		if i < 0 {
+			return nil
+		}
+		start := off + i + len(cdpSNAPHeader)
+		if len(data)-start >= 4 {
+			inner := gopacket.NewPacket(data[start:], layers.LayerTypeCiscoDiscovery, gopacket.Default)
+			if cdpLayer := inner.Layer(layers.LayerTypeCiscoDiscovery); cdpLayer != nil {
+				return cdpLayer.(*layers.CiscoDiscovery)
+			}
+		}
+		off += i + 1
+	}
+	return nil
+}
+
 // parseCDPCapabilities parses the CDP capabilities field
 func parseCDPCapabilities(data []byte) []types.Capability {
 	return protocol.ParseCDPCapabilities(data)
 }
 
-// parseCDPAddresses parses the CDP address TLV
-func parseCDPAddresses(data []byte) net.IP {
+// parseCDPAddresses parses every IP address in a CDP address TLV
+// Addresses of other protocols are skipped; parsing stops at a truncated entry
+func parseCDPAddresses(data []byte) []net.IP {
 	if len(data) < 4 {
 		return nil
 	}
 
 	// Number of addresses
 	numAddrs := binary.BigEndian.Uint32(data[:4])
-	if numAddrs == 0 {
-		return nil
-	}
-
 	offset := 4
 
-	// Parse first address
-	// Protocol type (1 byte) + Protocol length (1 byte)
-	if offset+2 > len(data) {
-		return nil
-	}
+	var addrs []net.IP
+	for i := uint32(0); i < numAddrs; i++ {
+		// Protocol type (1 byte) + Protocol length (1 byte)
+		if offset+2 > len(data) {
+			break
+		}
+		protoType := data[offset]
+		protoLen := int(data[offset+1])
+		offset += 2
 
-	protoType := data[offset]
-	protoLen := int(data[offset+1])
-	offset += 2
+		// Skip protocol identifier
+		if offset+protoLen > len(data) {
+			break
+		}
+		offset += protoLen
 
-	// Skip protocol identifier
-	if offset+protoLen > len(data) {
-		return nil
-	}
-	offset += protoLen
+		// Address length (2 bytes)
+		if offset+2 > len(data) {
+			break
+		}
+		addrLen := int(binary.BigEndian.Uint16(data[offset : offset+2]))
+		offset += 2
 
-	// Address length (2 bytes)
-	if offset+2 > len(data) {
-		return nil
+		// Address
+		if offset+addrLen > len(data) {
+			break
+		}
+		addr := data[offset : offset+addrLen]
+		offset += addrLen
+
+		switch {
+		case protoType == 1 && addrLen == 4:
+			// NLPID protocol type, 0xCC = IPv4
+			addrs = append(addrs, net.IP(addr))
+		case addrLen == 16:
+			// IPv6 uses an 802.2 protocol type
+			addrs = append(addrs, net.IP(addr))
+		}
 	}
-	addrLen := int(binary.BigEndian.Uint16(data[offset : offset+2]))
-	offset += 2
+	return addrs
+}
 
-	// Address
-	if offset+addrLen > len(data) {
-		return nil
+// primaryAddress picks the management IP from a device's addresses: the first
+// IPv4 address, or the first address if there's no IPv4
+func primaryAddress(addrs []net.IP) net.IP {
+	for _, ip := range addrs {
+		if ip.To4() != nil {
+			return ip
+		}
 	}
-
-	// Check if this is an IP address (protocol type 1 = NLPID, 0xCC = IPv4)
-	if protoType == 1 && addrLen == 4 {
-		return net.IP(data[offset : offset+4])
+	if len(addrs) > 0 {
+		return addrs[0]
 	}
+	return nil
+}
 
-	// Could also be IPv6
-	if addrLen == 16 {
-		return net.IP(data[offset : offset+16])
+// appendNewAddresses appends the addresses in add that aren't already in addrs
+func appendNewAddresses(addrs, add []net.IP) []net.IP {
+	for _, ip := range add {
+		if !slices.ContainsFunc(addrs, ip.Equal) {
+			addrs = append(addrs, ip)
+		}
 	}
+	return addrs
+}
 
-	return nil
+// parseCDPDuplex parses the CDP duplex TLV: 0 = half, 1 = full
+func parseCDPDuplex(data []byte) string {
+	if len(data) < 1 {
+		return ""
+	}
+	switch data[0] {
+	case 0:
+		return "half"
+	case 1:
+		return "full"
+	}
+	return ""
 }
 
 // parseCDPLocation parses the CDP location TLV
@@ -0,0 +1,268 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/gopacket"
+
+	"nbor/protocol"
+)
+
+// cdpPacket wraps hex-dumped CDP TLVs, after a "switch" Device ID, in an
+// 802.3 LLC/SNAP frame
+func cdpPacket(t *testing.T, tlvs string) gopacket.Packet {
+	t.Helper()
+	tlvs = strings.Join(strings.Fields(tlvs), "")
+	// LLC/SNAP (8 bytes) + CDP header (4 bytes) + Device ID TLV (10 bytes) + TLVs
+	length := 8 + 4 + 10 + len(tlvs)/2
+	return hexPacket(t, fmt.Sprintf(`
+		01000ccccccc 001122334455 %04x
+		aaaa03 00000c2000
+		02 b4 0000
+		0001 000a 737769746368
+		%s`, length, tlvs))
+}
+
+func TestParseCDPNativeVLAN(t *testing.T) {
+	tests := []struct {
+		name string
+		tlvs string
+		want int
+	}{
+		{"native VLAN 10", "000a 0006 000a", 10},
+		{"native VLAN 4094", "000a 0006 0ffe", 4094},
+		{"not advertised", "", 0},
+		{"short TLV", "000a 0005 0a", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := ParseCDP(cdpPacket(t, tt.tlvs), "eth0")
+			if err != nil {
+				t.Fatalf("ParseCDP() error = %v", err)
+			}
+			if n.Hostname != "switch" {
+				t.Errorf("Hostname = %q, want %q", n.Hostname, "switch")
+			}
+			if n.NativeVLAN != tt.want {
+				t.Errorf("NativeVLAN = %d, want %d", n.NativeVLAN, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCDPDuplex(t *testing.T) {
+	tests := []struct {
+		name string
+		tlvs string
+		want string
+	}{
+		{"half", "000b 0005 00", "half"},
+		{"full", "000b 0005 01", "full"},
+		{"unknown value", "000b 0005 07", ""},
+		{"not advertised", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := ParseCDP(cdpPacket(t, tt.tlvs), "eth0")
+			if err != nil {
+				t.Fatalf("ParseCDP() error = %v", err)
+			}
+			if n.Duplex != tt.want {
+				t.Errorf("Duplex = %q, want %q", n.Duplex, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCDPPower(t *testing.T) {
+	tests := []struct {
+		name string
+		tlvs string
+		want int
+	}{
+		{"15.4 W", "0010 0006 3c28", 15400},
+		{"short TLV", "0010 0005 3c", 0},
+		{"not advertised", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := ParseCDP(cdpPacket(t, tt.tlvs), "eth0")
+			if err != nil {
+				t.Fatalf("ParseCDP() error = %v", err)
+			}
+			if n.PowerMW != tt.want {
+				t.Errorf("PowerMW = %d, want %d", n.PowerMW, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCDPAddresses(t *testing.T) {
+	// IPv6 (802.2 protocol type) first, then IPv4 (NLPID 0xCC)
+	tlvs := `0002 002d 00000002
+		02 08 aaaa0300000086dd 0010 20010db8000000000000000000000001
+		01 01 cc 0004 0a000001`
+
+	n, err := ParseCDP(cdpPacket(t, tlvs), "eth0")
+	if err != nil {
+		t.Fatalf("ParseCDP() error = %v", err)
+	}
+	if got := n.ManagementIP.String(); got != "10.0.0.1" {
+		t.Errorf("ManagementIP = %s, want the IPv4 address 10.0.0.1", got)
+	}
+	if len(n.AllAddresses) != 2 || n.AllAddresses[0].String() != "2001:db8::1" || n.AllAddresses[1].String() != "10.0.0.1" {
+		t.Errorf("AllAddresses = %v, want [2001:db8::1 10.0.0.1]", n.AllAddresses)
+	}
+}
+
+func TestParseCDPVTPDomain(t *testing.T) {
+	tests := []struct {
+		name string
+		tlvs string
+		want string
+	}{
+		{"domain", "0009 000a 63616d707573", "campus"},
+		{"null padded", "0009 000a 6c6162 000000", "lab"},
+		{"empty TLV", "0009 0004", ""},
+		{"not advertised", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := ParseCDP(cdpPacket(t, tt.tlvs), "eth0")
+			if err != nil {
+				t.Fatalf("ParseCDP() error = %v", err)
+			}
+			if n.VTPDomain != tt.want {
+				t.Errorf("VTPDomain = %q, want %q", n.VTPDomain, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCDPSoftwareVersion(t *testing.T) {
+	// "Cisco IOS\n Version 15.2\n" plus a trailing null
+	n, err := ParseCDP(cdpPacket(t, "0005 001d 436973636f20494f530a2056657273696f6e2031352e320a00"), "eth0")
+	if err != nil {
+		t.Fatalf("ParseCDP() error = %v", err)
+	}
+	if want := "Cisco IOS\n Version 15.2"; n.Description != want {
+		t.Errorf("Description = %q, want %q", n.Description, want)
+	}
+}
+
+func TestParseCDPEncapsulations(t *testing.T) {
+	// Device ID "switch" and Port ID "Gi0/1" after the CDP header
+	const cdp = `02 b4 0000 0001 000a 737769746368 0003 0009 4769302f31`
+
+	tests := []struct {
+		name string
+		dump string
+	}{
+		{
+			name: "802.3 LLC/SNAP",
+			dump: `01000ccccccc 001122334455 001f aaaa03 00000c2000 ` + cdp,
+		},
+		{
+			name: "802.1Q tagged LLC/SNAP",
+			dump: `01000ccccccc 001122334455 8100 0064 001f aaaa03 00000c2000 ` + cdp,
+		},
+		{
+			name: "Ethernet II",
+			dump: `01000ccccccc 001122334455 2000 ` + cdp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := ParseCDPBytes(hexFrame(t, tt.dump), "eth0")
+			if err != nil {
+				t.Fatalf("ParseCDPBytes() error = %v", err)
+			}
+			if n.Hostname != "switch" || n.PortID != "Gi0/1" {
+				t.Errorf("Hostname, PortID = %q, %q, want switch, Gi0/1", n.Hostname, n.PortID)
+			}
+		})
+	}
+
+	// A SNAP header followed by something that isn't CDP is still rejected
+	bogus := hexFrame(t, `01000ccccccc 001122334455 000e aaaa03 00000c2000 07 b4 0000 0000`)
+	if _, err := ParseCDPBytes(bogus, "eth0"); err == nil {
+		t.Error("ParseCDPBytes() accepted a bad CDP version")
+	}
+}
+
+func TestCDPChecksumValid(t *testing.T) {
+	tests := []struct {
+		name string
+		dump string
+		want bool
+	}{
+		{"odd length, Cisco low-byte padding", "02b4 fd04 0001 0005 41", true},
+		{"odd length, Cisco sign extension", "02b4 fd84 0001 0005 c1", true},
+		{"odd length, RFC 1071 padding", "02b4 bc45 0001 0005 41", true},
+		{"odd length, wrong checksum", "02b4 fd05 0001 0005 41", false},
+		{"too short", "02b4", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cdpChecksumValid(hexFrame(t, tt.dump)); got != tt.want {
+				t.Errorf("cdpChecksumValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A frame checksummed the way the broadcaster does it verifies, and stops
+	// verifying once a byte changes
+	frame := hexFrame(t, `
+		01000ccccccc 001122334455 001e
+		aaaa03 00000c2000
+		02 b4 0000
+		0001 000a 737769746368`)
+	binary.BigEndian.PutUint16(frame[24:26], protocol.Checksum(frame[22:]))
+	if err := CheckCDPChecksum(decodeFrame(frame)); err != nil {
+		t.Errorf("CheckCDPChecksum() = %v, want nil", err)
+	}
+	frame[len(frame)-1] ^= 0x01
+	if err := CheckCDPChecksum(decodeFrame(frame)); !errors.Is(err, ErrCDPChecksum) {
+		t.Errorf("CheckCDPChecksum() on a corrupted frame = %v, want ErrCDPChecksum", err)
+	}
+}
+
+func TestCDPChecksumPaddedFrame(t *testing.T) {
+	// Short frames are padded to 60 bytes, and the padding isn't always zeros;
+	// only the bytes the 802.3 length covers are checksummed
+	const cdp = `02 b4 0000 0001 000b 73776974636831`
+	tests := []struct {
+		name string
+		head string
+	}{
+		{"untagged", `01000ccccccc 001122334455 0017`},
+		{"VLAN-tagged", `01000ccccccc 001122334455 8100 0064 0017`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame := hexFrame(t, tt.head+` aaaa03 00000c2000 `+cdp)
+			start := len(frame) - 15
+			binary.BigEndian.PutUint16(frame[start+2:start+4], protocol.Checksum(frame[start:]))
+			padded := append(frame, bytes.Repeat([]byte{0xaa}, 60-len(frame))...)
+
+			if err := CheckCDPChecksum(decodeFrame(padded)); err != nil {
+				t.Errorf("CheckCDPChecksum() on a padded frame = %v, want nil", err)
+			}
+			n, err := ParseCDPBytes(padded, "eth0")
+			if err != nil || n.Hostname != "switch1" {
+				t.Errorf("ParseCDPBytes() = %v, %v, want switch1", n, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"nbor/types"
+)
+
+// frameLength returns the on-the-wire length of a captured frame
+// Falls back to the captured bytes when capture metadata isn't available
+func frameLength(packet gopacket.Packet) int {
+	if md := packet.Metadata(); md != nil && md.Length > 0 {
+		return md.Length
+	}
+	return len(packet.Data())
+}
+
+// unpaddedLength returns how much of an Ethernet frame is real data: up to the end
+// of the 802.3 length field's payload, past any VLAN tags, so the padding that
+// brings short frames to 60 bytes is left out. Ethernet II frames, and lengths
+// longer than the frame, give len(data)
+func unpaddedLength(data []byte) int {
+	off := 12
+	for off+4 <= len(data) {
+		switch binary.BigEndian.Uint16(data[off:]) {
+		case 0x8100, 0x88a8, 0x9100:
+			off += 4
+			continue
+		}
+		break
+	}
+	if off+2 > len(data) {
+		return len(data)
+	}
+	length := int(binary.BigEndian.Uint16(data[off:]))
+	if length >= 0x600 {
+		return len(data)
+	}
+	return min(off+2+length, len(data))
+}
+
+// decodeFrame decodes a raw Ethernet frame into a packet
+func decodeFrame(data []byte) gopacket.Packet {
+	return gopacket.NewPacket(data, layers.LayerTypeEthernet, gopacket.Default)
+}
+
+// ParseCDPBytes parses a raw Ethernet frame carrying CDP
+func ParseCDPBytes(data []byte, ifaceName string) (*types.Neighbor, error) {
+	return ParseCDP(decodeFrame(data), ifaceName)
+}
+
+// ParseLLDPBytes parses a raw Ethernet frame carrying LLDP
+func ParseLLDPBytes(data []byte, ifaceName string) (*types.Neighbor, error) {
+	return ParseLLDP(decodeFrame(data), ifaceName)
+}
+
+// ParseFrame parses a raw Ethernet frame as CDP or LLDP, whichever it carries
+func ParseFrame(data []byte, ifaceName string) (*types.Neighbor, error) {
+	packet := decodeFrame(data)
+	switch {
+	case packet.Layer(layers.LayerTypeCiscoDiscovery) != nil:
+		return ParseCDP(packet, ifaceName)
+	case packet.Layer(layers.LayerTypeLinkLayerDiscovery) != nil:
+		return ParseLLDP(packet, ifaceName)
+	}
+	return nil, fmt.Errorf("not a CDP or LLDP packet")
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"testing"
+
+	"nbor/types"
+)
+
+// goldenFrames are complete CDP and LLDP frames as captured on the wire
+var goldenFrames = []struct {
+	name         string
+	dump         string
+	protocol     types.Protocol
+	hostname     string
+	port         string
+	platform     string
+	description  string
+	mgmtIP       string
+	capabilities []types.Capability
+}{
+	{
+		name: "cdp catalyst",
+		dump: `
+			01000ccccccc 001122334455 0060
+			aaaa03 00000c2000
+			02 b4 0000
+			0001 000c 636f72652d737731
+			0003 0019 4769676162697445746865726e6574312f302f3234
+			0006 0016 636973636f2057532d43333835302d323450
+			0004 0008 00000028
+			0002 0011 00000001 0101cc 0004 0a000101`,
+		protocol:     types.ProtocolCDP,
+		hostname:     "core-sw1",
+		port:         "GigabitEthernet1/0/24",
+		platform:     "cisco WS-C3850-24P",
+		mgmtIP:       "10.0.1.1",
+		capabilities: []types.Capability{types.CapSwitch},
+	},
+	{
+		name: "lldp juniper",
+		dump: `
+			0180c200000e 00aabbccdd01 88cc
+			0207 04 00aabbccdd01
+			0408 05 4769312f302f31
+			0602 0078
+			080e 55706c696e6b20746f20636f7265
+			0a14 646973742d7377322e6578616d706c652e6e6574
+			0c17 4a756e69706572204e6574776f726b7320455834333030
+			0e04 0014 0014
+			100c 05 01 0a000201 02 00000001 00
+			0000`,
+		protocol:     types.ProtocolLLDP,
+		hostname:     "dist-sw2.example.net",
+		port:         "Gi1/0/1",
+		description:  "Juniper Networks EX4300",
+		mgmtIP:       "10.0.2.1",
+		capabilities: []types.Capability{types.CapRouter, types.CapBridge},
+	},
+}
+
+func TestParseFrameGolden(t *testing.T) {
+	for _, tt := range goldenFrames {
+		t.Run(tt.name, func(t *testing.T) {
+			frame := hexFrame(t, tt.dump)
+
+			n, err := ParseFrame(frame, "eth0")
+			if err != nil {
+				t.Fatalf("ParseFrame() error = %v", err)
+			}
+			if n.Protocol != tt.protocol {
+				t.Errorf("Protocol = %v, want %v", n.Protocol, tt.protocol)
+			}
+			if n.Hostname != tt.hostname {
+				t.Errorf("Hostname = %q, want %q", n.Hostname, tt.hostname)
+			}
+			if n.PortID != tt.port {
+				t.Errorf("PortID = %q, want %q", n.PortID, tt.port)
+			}
+			if n.Platform != tt.platform {
+				t.Errorf("Platform = %q, want %q", n.Platform, tt.platform)
+			}
+			if n.Description != tt.description {
+				t.Errorf("Description = %q, want %q", n.Description, tt.description)
+			}
+			if got := n.ManagementIP.String(); got != tt.mgmtIP {
+				t.Errorf("ManagementIP = %s, want %s", got, tt.mgmtIP)
+			}
+			if !sameCapabilities(n.Capabilities, tt.capabilities) {
+				t.Errorf("Capabilities = %v, want %v", n.Capabilities, tt.capabilities)
+			}
+			if n.Interface != "eth0" {
+				t.Errorf("Interface = %q, want eth0", n.Interface)
+			}
+		})
+	}
+}
+
+func TestParseBytesWrongProtocol(t *testing.T) {
+	cdp := hexFrame(t, goldenFrames[0].dump)
+	lldp := hexFrame(t, goldenFrames[1].dump)
+
+	if _, err := ParseCDPBytes(cdp, "eth0"); err != nil {
+		t.Errorf("ParseCDPBytes(cdp) error = %v", err)
+	}
+	if _, err := ParseLLDPBytes(lldp, "eth0"); err != nil {
+		t.Errorf("ParseLLDPBytes(lldp) error = %v", err)
+	}
+	if _, err := ParseCDPBytes(lldp, "eth0"); err == nil {
+		t.Error("ParseCDPBytes(lldp) succeeded, want an error")
+	}
+	if _, err := ParseLLDPBytes(cdp, "eth0"); err == nil {
+		t.Error("ParseLLDPBytes(cdp) succeeded, want an error")
+	}
+
+	// An ARP request is neither
+	arp := hexFrame(t, `
+		ffffffffffff 001122334455 0806
+		0001 0800 06 04 0001 001122334455 0a000001 000000000000 0a000002`)
+	if _, err := ParseFrame(arp, "eth0"); err == nil {
+		t.Error("ParseFrame(arp) succeeded, want an error")
+	}
+}
@@ -28,7 +28,9 @@ func ParseLLDP(packet gopacket.Packet, ifaceName string) (*types.Neighbor, error
 		Protocol:  types.ProtocolLLDP,
 		LastSeen:  time.Now(),
 		Interface: ifaceName,
+		TTL:       time.Duration(lldp.TTL) * time.Second,
 	}
+	neighbor.RecordFrameLength(frameLength(packet))
 
 	// Get source MAC from ethernet layer
 	if ethLayer := packet.Layer(layers.LayerTypeEthernet); ethLayer != nil {
@@ -42,6 +44,22 @@ func ParseLLDP(packet gopacket.Packet, ifaceName string) (*types.Neighbor, error
 	// Parse Port ID
 	neighbor.PortID = parseLLDPPortID(lldp.PortID)
 
+	// Keep the raw enabled capability bits (decoded struct loses reserved bits)
+	// and every management address (the decoded struct keeps only the last)
+	hasCapabilities := false
+	for _, tlv := range lldp.Values {
+		switch {
+		case tlv.Type == layers.LLDPTLVSysCapabilities && len(tlv.Value) >= 4:
+			neighbor.LLDPCapabilityBits = binary.BigEndian.Uint16(tlv.Value[2:4])
+			hasCapabilities = true
+		case tlv.Type == layers.LLDPTLVMgmtAddress:
+			if ip := parseLLDPMgmtAddress(decodeLLDPMgmtAddress(tlv.Value)); ip != nil {
+				neighbor.AllAddresses = appendNewAddresses(neighbor.AllAddresses, []net.IP{ip})
+			}
+		}
+	}
+	neighbor.ManagementIP = primaryAddress(neighbor.AllAddresses)
+
 	// Get LLDP info layer for additional TLVs
 	lldpInfoLayer := packet.Layer(layers.LayerTypeLinkLayerDiscoveryInfo)
 	if lldpInfoLayer != nil {
@@ -51,21 +69,58 @@ func ParseLLDP(packet gopacket.Packet, ifaceName string) (*types.Neighbor, error
 		neighbor.Hostname = lldpInfo.SysName
 		neighbor.Description = lldpInfo.SysDescription
 
-		// Parse capabilities from the struct
+		// Parse capabilities from the struct; the table goes by what's enabled
 		neighbor.Capabilities = parseLLDPCapabilitiesStruct(lldpInfo.SysCapabilities.EnabledCap)
-
-		// Parse management address
-		if len(lldpInfo.MgmtAddress.Address) > 0 {
-			neighbor.ManagementIP = parseLLDPMgmtAddress(lldpInfo.MgmtAddress)
+		if hasCapabilities {
+			neighbor.SupportedCapabilities = parseLLDPCapabilitiesStruct(lldpInfo.SysCapabilities.SystemCap)
 		}
 
-		// Parse organization-specific TLVs for location
+		// Parse organization-specific TLVs
+		var vlanNames []lldpVLANName
 		for _, orgTLV := range lldpInfo.OrgTLVs {
-			// Check for LLDP-MED location TLV
-			if orgTLV.OUI == 0x0012bb && orgTLV.SubType == 3 {
+			switch {
+			case orgTLV.OUI == layers.IEEEOUIMedia && orgTLV.SubType == uint8(layers.LLDPMediaTypeLocation):
+				// LLDP-MED location
 				neighbor.Location = parseLLDPLocation(orgTLV.Info)
+
+			case orgTLV.OUI == layers.IEEEOUIMedia && orgTLV.SubType == uint8(layers.LLDPMediaTypeNetwork):
+				// Phones get voice and voice signaling policies; keep voice if both are sent
+				if p, ok := parseLLDPMEDPolicy(orgTLV.Info); ok {
+					if neighbor.MEDPolicy == nil || p.Application == medApplicationVoice {
+						neighbor.MEDPolicy = &p
+					}
+				}
+
+			case orgTLV.OUI == layers.IEEEOUIMedia && orgTLV.SubType == uint8(layers.LLDPMediaTypePower):
+				// Power type, source and priority (1 byte), then the value in 0.1 W
+				if len(orgTLV.Info) >= 3 {
+					neighbor.PowerMW = int(binary.BigEndian.Uint16(orgTLV.Info[1:3])) * 100
+				}
+
+			case orgTLV.OUI == layers.IEEEOUIMedia && orgTLV.SubType >= uint8(layers.LLDPMediaTypeHardware) &&
+				orgTLV.SubType <= uint8(layers.LLDPMediaTypeAssetID):
+				// Inventory: one TLV per field, each a plain string
+				if neighbor.Inventory == nil {
+					neighbor.Inventory = &types.Inventory{}
+				}
+				setLLDPInventoryField(neighbor.Inventory, layers.LLDPMediaSubtype(orgTLV.SubType), orgTLV.Info)
+
+			case orgTLV.OUI == layers.IEEEOUI8023 && orgTLV.SubType == layers.LLDP8023SubtypeMACPHY:
+				// The other 802.3 subtypes (power, aggregation, MTU) don't carry the link mode
+				neighbor.Speed, neighbor.Duplex = parseLLDPMACPHY(orgTLV.Info)
+
+			case orgTLV.OUI == layers.IEEEOUI8021 && orgTLV.SubType == layers.LLDP8021SubtypePortVLANID:
+				if len(orgTLV.Info) >= 2 {
+					neighbor.PortVLAN = int(binary.BigEndian.Uint16(orgTLV.Info[:2]))
+				}
+
+			case orgTLV.OUI == layers.IEEEOUI8021 && orgTLV.SubType == layers.LLDP8021SubtypeVLANName:
+				if v, ok := parseLLDPVLANName(orgTLV.Info); ok {
+					vlanNames = append(vlanNames, v)
+				}
 			}
 		}
+		neighbor.VLANName = pickVLANName(vlanNames, neighbor.PortVLAN)
 	}
 
 	// Use source MAC as ID if chassis ID parsing failed
@@ -76,6 +131,27 @@ func ParseLLDP(packet gopacket.Packet, ifaceName string) (*types.Neighbor, error
 	return neighbor, nil
 }
 
+// setLLDPInventoryField stores the value of an LLDP-MED inventory TLV
+func setLLDPInventoryField(inv *types.Inventory, subtype layers.LLDPMediaSubtype, info []byte) {
+	value := protocol.CleanString(string(info))
+	switch subtype {
+	case layers.LLDPMediaTypeHardware:
+		inv.HardwareRevision = value
+	case layers.LLDPMediaTypeFirmware:
+		inv.FirmwareRevision = value
+	case layers.LLDPMediaTypeSoftware:
+		inv.SoftwareRevision = value
+	case layers.LLDPMediaTypeSerial:
+		inv.SerialNumber = value
+	case layers.LLDPMediaTypeManufacturer:
+		inv.Manufacturer = value
+	case layers.LLDPMediaTypeModel:
+		inv.Model = value
+	case layers.LLDPMediaTypeAssetID:
+		inv.AssetID = value
+	}
+}
+
 // parseLLDPChassisID parses the chassis ID TLV
 func parseLLDPChassisID(chassisID layers.LLDPChassisID) string {
 	switch chassisID.Subtype {
@@ -177,6 +253,138 @@ func parseLLDPCapabilitiesStruct(caps layers.LLDPCapabilities) []types.Capabilit
 	return result
 }
 
+// medApplicationVoice is the LLDP-MED network policy application type for voice
+const medApplicationVoice = 1
+
+// parseLLDPMEDPolicy parses the LLDP-MED network policy TLV: the application
+// type (1 byte), then 3 bytes of unknown and tagged flags, a reserved bit,
+// a 12-bit VLAN ID, 3-bit L2 priority and 6-bit DSCP
+func parseLLDPMEDPolicy(info []byte) (types.MEDPolicy, bool) {
+	if len(info) < 4 {
+		return types.MEDPolicy{}, false
+	}
+	bits := uint32(info[1])<<16 | uint32(info[2])<<8 | uint32(info[3])
+	return types.MEDPolicy{
+		Application: info[0],
+		Unknown:     bits&(1<<23) != 0,
+		Tagged:      bits&(1<<22) != 0,
+		VLAN:        int(bits >> 9 & 0xfff),
+		Priority:    int(bits >> 6 & 0x7),
+		DSCP:        int(bits & 0x3f),
+	}, true
+}
+
+// lldpVLANName is one entry from an 802.1 VLAN Name TLV
+type lldpVLANName struct {
+	id   int
+	name string
+}
+
+// parseLLDPVLANName parses the 802.1 VLAN Name TLV:
+// VLAN ID (2 bytes), name length (1 byte) and the name
+func parseLLDPVLANName(info []byte) (lldpVLANName, bool) {
+	if len(info) < 3 {
+		return lldpVLANName{}, false
+	}
+	nameLen := int(info[2])
+	if 3+nameLen > len(info) {
+		return lldpVLANName{}, false
+	}
+	return lldpVLANName{
+		id:   int(binary.BigEndian.Uint16(info[:2])),
+		name: protocol.CleanString(string(info[3 : 3+nameLen])),
+	}, true
+}
+
+// pickVLANName returns the name of the port VLAN when it was advertised,
+// otherwise the first VLAN name. A port can list every VLAN it carries
+func pickVLANName(names []lldpVLANName, portVLAN int) string {
+	for _, v := range names {
+		if v.id == portVLAN {
+			return v.name
+		}
+	}
+	if len(names) > 0 {
+		return names[0].name
+	}
+	return ""
+}
+
+// mauType is an operational MAU type from the IANA MAU MIB
+type mauType struct {
+	name   string
+	duplex string
+}
+
+// mauTypes lists the common twisted pair and fiber MAU types
+var mauTypes = map[uint16]mauType{
+	10: {"10BASE-T", "half"},
+	11: {"10BASE-T", "full"},
+	12: {"10BASE-FL", "half"},
+	13: {"10BASE-FL", "full"},
+	15: {"100BASE-TX", "half"},
+	16: {"100BASE-TX", "full"},
+	17: {"100BASE-FX", "half"},
+	18: {"100BASE-FX", "full"},
+	19: {"100BASE-T2", "half"},
+	20: {"100BASE-T2", "full"},
+	21: {"1000BASE-X", "half"},
+	22: {"1000BASE-X", "full"},
+	23: {"1000BASE-LX", "half"},
+	24: {"1000BASE-LX", "full"},
+	25: {"1000BASE-SX", "half"},
+	26: {"1000BASE-SX", "full"},
+	27: {"1000BASE-CX", "half"},
+	28: {"1000BASE-CX", "full"},
+	29: {"1000BASE-T", "half"},
+	30: {"1000BASE-T", "full"},
+	31: {"10GBASE-X", "full"},
+	32: {"10GBASE-LX4", "full"},
+	33: {"10GBASE-R", "full"},
+	34: {"10GBASE-ER", "full"},
+	35: {"10GBASE-LR", "full"},
+	36: {"10GBASE-SR", "full"},
+	37: {"10GBASE-W", "full"},
+	38: {"10GBASE-EW", "full"},
+	39: {"10GBASE-LW", "full"},
+	40: {"10GBASE-SW", "full"},
+}
+
+// parseLLDPMACPHY parses the IEEE 802.3 MAC/PHY configuration TLV into a speed
+// like "1000BASE-T full" and a duplex. The info is autonegotiation status (1 byte),
+// advertised capabilities (2 bytes) and the operational MAU type (2 bytes)
+func parseLLDPMACPHY(info []byte) (speed, duplex string) {
+	if len(info) < 5 {
+		return "", ""
+	}
+	mau := binary.BigEndian.Uint16(info[3:5])
+	if mau == 0 {
+		return "", ""
+	}
+	t, ok := mauTypes[mau]
+	if !ok {
+		return fmt.Sprintf("MAU type %d", mau), ""
+	}
+	return t.name + " " + t.duplex, t.duplex
+}
+
+// decodeLLDPMgmtAddress decodes the address family and address of a management
+// address TLV: address string length, subtype, address, then interface fields
+// A malformed TLV decodes to an empty address
+func decodeLLDPMgmtAddress(data []byte) layers.LLDPMgmtAddress {
+	if len(data) < 2 {
+		return layers.LLDPMgmtAddress{}
+	}
+	addrLen := int(data[0]) // Includes the subtype byte
+	if addrLen < 1 || 1+addrLen > len(data) {
+		return layers.LLDPMgmtAddress{}
+	}
+	return layers.LLDPMgmtAddress{
+		Subtype: layers.IANAAddressFamily(data[1]),
+		Address: data[2 : 1+addrLen],
+	}
+}
+
 // parseLLDPMgmtAddress parses the management address TLV
 func parseLLDPMgmtAddress(mgmtAddr layers.LLDPMgmtAddress) net.IP {
 	if len(mgmtAddr.Address) == 0 {
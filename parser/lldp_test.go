@@ -0,0 +1,331 @@
+package parser
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/google/gopacket"
+
+	"nbor/types"
+)
+
+// hexFrame decodes a hex dump (whitespace ignored) into raw frame bytes
+func hexFrame(t *testing.T, dump string) []byte {
+	t.Helper()
+	frame, err := hex.DecodeString(strings.Join(strings.Fields(dump), ""))
+	if err != nil {
+		t.Fatalf("bad hex dump: %v", err)
+	}
+	return frame
+}
+
+// hexPacket decodes a hex dump (whitespace ignored) into an Ethernet packet
+func hexPacket(t *testing.T, dump string) gopacket.Packet {
+	t.Helper()
+	return decodeFrame(hexFrame(t, dump))
+}
+
+// lldpPacket wraps hex-dumped LLDP TLVs, after the Chassis ID, an "eth0" Port ID
+// and TTL, in an LLDP frame ending with the End TLV
+func lldpPacket(t *testing.T, tlvs string) gopacket.Packet {
+	t.Helper()
+	return hexPacket(t, `
+		0180c200000e 001122334455 88cc
+		0207 04 001122334455
+		0405 05 65746830
+		0602 0078
+		`+tlvs+`
+		0000`)
+}
+
+func TestParseLLDPSupportedCapabilities(t *testing.T) {
+	tests := []struct {
+		name          string
+		capsTLV       string
+		wantEnabled   []types.Capability
+		wantSupported []types.Capability
+	}{
+		{
+			name:          "router supported but disabled",
+			capsTLV:       "0e04 0014 0004",
+			wantEnabled:   []types.Capability{types.CapBridge},
+			wantSupported: []types.Capability{types.CapRouter, types.CapBridge},
+		},
+		{
+			name:          "all supported enabled",
+			capsTLV:       "0e04 0014 0014",
+			wantEnabled:   []types.Capability{types.CapRouter, types.CapBridge},
+			wantSupported: []types.Capability{types.CapRouter, types.CapBridge},
+		},
+		{
+			name:        "no capabilities TLV",
+			capsTLV:     "",
+			wantEnabled: []types.Capability{types.CapSwitch},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packet := lldpPacket(t, "0a06 686f73743031 "+tt.capsTLV)
+
+			n, err := ParseLLDP(packet, "eth0")
+			if err != nil {
+				t.Fatalf("ParseLLDP() error = %v", err)
+			}
+			if !sameCapabilities(n.Capabilities, tt.wantEnabled) {
+				t.Errorf("Capabilities = %v, want %v", n.Capabilities, tt.wantEnabled)
+			}
+			if !sameCapabilities(n.SupportedCapabilities, tt.wantSupported) {
+				t.Errorf("SupportedCapabilities = %v, want %v", n.SupportedCapabilities, tt.wantSupported)
+			}
+		})
+	}
+}
+
+func sameCapabilities(got, want []types.Capability) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseLLDPMACPHY(t *testing.T) {
+	tests := []struct {
+		name       string
+		orgTLVs    string
+		wantSpeed  string
+		wantDuplex string
+	}{
+		{
+			name:       "1000BASE-T full",
+			orgTLVs:    "fe09 00120f 01 03 6c01 001e",
+			wantSpeed:  "1000BASE-T full",
+			wantDuplex: "full",
+		},
+		{
+			name:       "100BASE-TX half",
+			orgTLVs:    "fe09 00120f 01 03 6c01 000f",
+			wantSpeed:  "100BASE-TX half",
+			wantDuplex: "half",
+		},
+		{
+			name:      "unknown MAU type",
+			orgTLVs:   "fe09 00120f 01 03 6c01 00c8",
+			wantSpeed: "MAU type 200",
+		},
+		{
+			// Max frame size and power via MDI come before MAC/PHY; only subtype 1 counts
+			name:       "other 802.3 subtypes",
+			orgTLVs:    "fe06 00120f 04 05ee  fe07 00120f 02 07 01 00  fe09 00120f 01 03 6c01 0010",
+			wantSpeed:  "100BASE-TX full",
+			wantDuplex: "full",
+		},
+		{
+			name:    "short TLV",
+			orgTLVs: "fe06 00120f 01 03 6c",
+		},
+		{
+			name: "not advertised",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packet := lldpPacket(t, tt.orgTLVs)
+
+			n, err := ParseLLDP(packet, "eth0")
+			if err != nil {
+				t.Fatalf("ParseLLDP() error = %v", err)
+			}
+			if n.Speed != tt.wantSpeed {
+				t.Errorf("Speed = %q, want %q", n.Speed, tt.wantSpeed)
+			}
+			if n.Duplex != tt.wantDuplex {
+				t.Errorf("Duplex = %q, want %q", n.Duplex, tt.wantDuplex)
+			}
+		})
+	}
+}
+
+func TestParseLLDPVLAN(t *testing.T) {
+	tests := []struct {
+		name      string
+		orgTLVs   string
+		wantPVID  int
+		wantVLANs string
+	}{
+		{
+			name:      "port VLAN and its name",
+			orgTLVs:   "fe06 0080c2 01 000a  fe0c 0080c2 03 000a 05 7573657273",
+			wantPVID:  10,
+			wantVLANs: "users",
+		},
+		{
+			name:      "name of the port VLAN among several",
+			orgTLVs:   "fe0c 0080c2 03 0014 05 766f696365  fe0c 0080c2 03 000a 05 7573657273  fe06 0080c2 01 000a",
+			wantPVID:  10,
+			wantVLANs: "users",
+		},
+		{
+			name:      "name without port VLAN",
+			orgTLVs:   "fe0c 0080c2 03 0014 05 766f696365",
+			wantVLANs: "voice",
+		},
+		{
+			name:     "name length past the TLV",
+			orgTLVs:  "fe0c 0080c2 03 000a 09 7573657273  fe06 0080c2 01 000a",
+			wantPVID: 10,
+		},
+		{
+			name:    "short port VLAN TLV",
+			orgTLVs: "fe05 0080c2 01 0a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packet := lldpPacket(t, tt.orgTLVs)
+
+			n, err := ParseLLDP(packet, "eth0")
+			if err != nil {
+				t.Fatalf("ParseLLDP() error = %v", err)
+			}
+			if n.PortVLAN != tt.wantPVID {
+				t.Errorf("PortVLAN = %d, want %d", n.PortVLAN, tt.wantPVID)
+			}
+			if n.VLANName != tt.wantVLANs {
+				t.Errorf("VLANName = %q, want %q", n.VLANName, tt.wantVLANs)
+			}
+		})
+	}
+}
+
+func TestParseLLDPMEDPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		orgTLVs string
+		want    *types.MEDPolicy
+	}{
+		{
+			name:    "voice VLAN",
+			orgTLVs: "fe08 0012bb 02 01 41916e",
+			want:    &types.MEDPolicy{Application: 1, Tagged: true, VLAN: 200, Priority: 5, DSCP: 46},
+		},
+		{
+			name:    "voice kept over voice signaling",
+			orgTLVs: "fe08 0012bb 02 02 4190d8  fe08 0012bb 02 01 41916e",
+			want:    &types.MEDPolicy{Application: 1, Tagged: true, VLAN: 200, Priority: 5, DSCP: 46},
+		},
+		{
+			name:    "voice signaling only",
+			orgTLVs: "fe08 0012bb 02 02 4190d8",
+			want:    &types.MEDPolicy{Application: 2, Tagged: true, VLAN: 200, Priority: 3, DSCP: 24},
+		},
+		{
+			name:    "unknown policy",
+			orgTLVs: "fe08 0012bb 02 01 800000",
+			want:    &types.MEDPolicy{Application: 1, Unknown: true},
+		},
+		{
+			name:    "short TLV",
+			orgTLVs: "fe06 0012bb 02 01 41",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packet := lldpPacket(t, tt.orgTLVs)
+
+			n, err := ParseLLDP(packet, "eth0")
+			if err != nil {
+				t.Fatalf("ParseLLDP() error = %v", err)
+			}
+			switch {
+			case tt.want == nil && n.MEDPolicy != nil:
+				t.Errorf("MEDPolicy = %+v, want nil", *n.MEDPolicy)
+			case tt.want != nil && n.MEDPolicy == nil:
+				t.Errorf("MEDPolicy = nil, want %+v", *tt.want)
+			case tt.want != nil && *n.MEDPolicy != *tt.want:
+				t.Errorf("MEDPolicy = %+v, want %+v", *n.MEDPolicy, *tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLLDPMEDPower(t *testing.T) {
+	tests := []struct {
+		name    string
+		orgTLVs string
+		want    int
+	}{
+		{"15.4 W", "fe07 0012bb 04 53 009a", 15400},
+		{"30 W", "fe07 0012bb 04 53 012c", 30000},
+		{"short TLV", "fe06 0012bb 04 53 00", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packet := lldpPacket(t, tt.orgTLVs)
+
+			n, err := ParseLLDP(packet, "eth0")
+			if err != nil {
+				t.Fatalf("ParseLLDP() error = %v", err)
+			}
+			if n.PowerMW != tt.want {
+				t.Errorf("PowerMW = %d, want %d", n.PowerMW, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLLDPMgmtAddresses(t *testing.T) {
+	packet := lldpPacket(t, `
+		1018 11 02 20010db8000000000000000000000001 02 00000001 00
+		100c 05 01 0a000001 02 00000001 00`)
+
+	n, err := ParseLLDP(packet, "eth0")
+	if err != nil {
+		t.Fatalf("ParseLLDP() error = %v", err)
+	}
+	if got := n.ManagementIP.String(); got != "10.0.0.1" {
+		t.Errorf("ManagementIP = %s, want the IPv4 address 10.0.0.1", got)
+	}
+	if len(n.AllAddresses) != 2 || n.AllAddresses[0].String() != "2001:db8::1" || n.AllAddresses[1].String() != "10.0.0.1" {
+		t.Errorf("AllAddresses = %v, want [2001:db8::1 10.0.0.1]", n.AllAddresses)
+	}
+}
+
+func TestParseLLDPMEDInventory(t *testing.T) {
+	// Serial, model and a NUL-padded hardware revision; the rest aren't sent
+	packet := lldpPacket(t, `
+		fe0c 0012bb 08 464f433132333458
+		fe11 0012bb 0a 49502050686f6e652038383431
+		fe08 0012bb 05 56303100`)
+
+	n, err := ParseLLDP(packet, "eth0")
+	if err != nil {
+		t.Fatalf("ParseLLDP() error = %v", err)
+	}
+	want := types.Inventory{SerialNumber: "FOC1234X", Model: "IP Phone 8841", HardwareRevision: "V01"}
+	if n.Inventory == nil || *n.Inventory != want {
+		t.Errorf("Inventory = %+v, want %+v", n.Inventory, want)
+	}
+}
+
+func TestParseLLDPNoInventory(t *testing.T) {
+	packet := lldpPacket(t, "")
+
+	n, err := ParseLLDP(packet, "eth0")
+	if err != nil {
+		t.Fatalf("ParseLLDP() error = %v", err)
+	}
+	if n.Inventory != nil {
+		t.Errorf("Inventory = %+v, want nil", n.Inventory)
+	}
+}
@@ -4,10 +4,31 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"sync"
+	"time"
 )
 
-// Bell sends a terminal bell/alert
+// bellMinInterval is the shortest gap between bells, so a burst of new
+// neighbors (e.g. plugging into a busy switch) rings once instead of per neighbor
+const bellMinInterval = 2 * time.Second
+
+var (
+	bellMu   sync.Mutex
+	lastBell time.Time
+)
+
+// Bell sends a terminal bell/alert, unless one was sent in the last bellMinInterval
+// Safe to call from several capture goroutines
 func Bell() {
+	bellMu.Lock()
+	now := time.Now()
+	if now.Sub(lastBell) < bellMinInterval {
+		bellMu.Unlock()
+		return
+	}
+	lastBell = now
+	bellMu.Unlock()
+
 	// Use \a (ASCII bell) which works on most terminals
 	// On Windows Terminal and modern terminals this works fine
 	// On legacy cmd.exe it may not work but won't crash
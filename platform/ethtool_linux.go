@@ -0,0 +1,70 @@
+//go:build linux
+
+package platform
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// ethtoolCmd is struct ethtool_cmd from <linux/ethtool.h>, used with ETHTOOL_GSET
+type ethtoolCmd struct {
+	cmd           uint32
+	supported     uint32
+	advertising   uint32
+	speed         uint16
+	duplex        uint8
+	port          uint8
+	phyAddress    uint8
+	transceiver   uint8
+	autoneg       uint8
+	mdioSupport   uint8
+	maxtxpkt      uint32
+	maxrxpkt      uint32
+	speedHi       uint16
+	ethTpMdix     uint8
+	ethTpMdixCtrl uint8
+	lpAdvertising uint32
+	reserved      [2]uint32
+}
+
+// ethtoolIfreq is struct ifreq with ifr_data pointing at the ethtool command
+type ethtoolIfreq struct {
+	name [unix.IFNAMSIZ]byte
+	data unsafe.Pointer
+	_    [24 - unsafe.Sizeof(uintptr(0))]byte // Pad the union to its full size
+}
+
+// ethtoolSpeedUnknown is SPEED_UNKNOWN, reported while the link is down
+const ethtoolSpeedUnknown = 0xffffffff
+
+// ethtoolSpeed asks the driver for the link speed in Mb/s with the SIOCETHTOOL ioctl
+// Returns false if the ioctl isn't supported or the speed is unknown
+func ethtoolSpeed(name string) (int, bool) {
+	if len(name) >= unix.IFNAMSIZ {
+		return 0, false
+	}
+
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return 0, false
+	}
+	defer unix.Close(fd)
+
+	cmd := ethtoolCmd{cmd: unix.ETHTOOL_GSET}
+	var ifr ethtoolIfreq
+	copy(ifr.name[:], name)
+	ifr.data = unsafe.Pointer(&cmd)
+
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), unix.SIOCETHTOOL, uintptr(unsafe.Pointer(&ifr)))
+	if errno != 0 {
+		return 0, false
+	}
+
+	speed := uint32(cmd.speedHi)<<16 | uint32(cmd.speed)
+	if speed == 0 || speed == ethtoolSpeedUnknown || speed == 0xffff {
+		return 0, false
+	}
+	return int(speed), true
+}
@@ -0,0 +1,127 @@
+package platform
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/gopacket/pcap"
+
+	"nbor/types"
+)
+
+// FilterCheck is one interface filtering rule and whether an interface passed it
+type FilterCheck struct {
+	Rule   string // What the rule checks
+	Passed bool
+	Detail string // Why the rule matched, or what was observed
+}
+
+// InterfaceExplanation describes how interface filtering treats a single interface
+type InterfaceExplanation struct {
+	Name string
+
+	// Raw properties from the OS
+	Found bool // Whether the OS knows an interface by this name
+	MAC   net.HardwareAddr
+	MTU   int
+	Flags net.Flags
+	Addrs []net.IP
+
+	// Raw properties from pcap
+	PcapListed      bool // Whether pcap lists the interface
+	PcapName        string
+	PcapDescription string
+	PcapFlags       uint32
+	OpenError       error // Result of actually opening the interface for capture
+
+	// Filtering rules in the order they're applied
+	Checks []FilterCheck
+
+	Usable     bool // Shown in the interface picker and --list-interfaces
+	Selectable bool // Accepted when named on the command line (with a filter warning)
+}
+
+// Filtered returns whether any filtering rule excluded the interface
+func (e *InterfaceExplanation) Filtered() bool {
+	for _, c := range e.Checks {
+		if !c.Passed {
+			return true
+		}
+	}
+	return false
+}
+
+// ExplainInterface evaluates the platform filtering rules against an interface
+// and collects its raw properties, for diagnosing why an interface is hidden
+func ExplainInterface(name string) InterfaceExplanation {
+	e := InterfaceExplanation{Name: name}
+
+	if usable, err := GetEthernetInterfaces(); err == nil {
+		e.Usable = findInterface(usable, name) != nil
+	}
+	if all, err := GetAllInterfaces(); err == nil {
+		if info := findInterface(all, name); info != nil {
+			e.Selectable = true
+			e.Found = true
+			e.MAC = info.MAC
+			e.MTU = info.MTU
+			e.Addrs = append(append([]net.IP{}, info.IPv4Addrs...), info.IPv6Addrs...)
+		}
+	}
+
+	// The OS view also covers interfaces GetAllInterfaces skips (e.g. non-Ethernet)
+	internalName := GetInterfaceInternalName(name)
+	if iface, err := net.InterfaceByName(internalName); err == nil {
+		e.Found = true
+		e.Flags = iface.Flags
+		if !e.Selectable {
+			e.MAC = iface.HardwareAddr
+			e.MTU = iface.MTU
+			ipv4Addrs, ipv6Addrs := types.GetInterfaceAddresses(iface)
+			e.Addrs = append(ipv4Addrs, ipv6Addrs...)
+		}
+	}
+
+	if devices, err := pcap.FindAllDevs(); err == nil {
+		for _, dev := range devices {
+			if dev.Name == internalName || (dev.Description != "" && dev.Description == name) {
+				e.PcapListed = true
+				e.PcapName = dev.Name
+				e.PcapDescription = dev.Description
+				e.PcapFlags = dev.Flags
+				break
+			}
+		}
+	}
+
+	if e.PcapListed {
+		if handle, err := pcap.OpenLive(e.PcapName, 1600, false, time.Second); err != nil {
+			e.OpenError = err
+		} else {
+			handle.Close()
+		}
+	}
+
+	e.Checks = filterChecks(&e)
+	return e
+}
+
+// findInterface returns the interface matching name (case-insensitive), or nil
+func findInterface(interfaces []types.InterfaceInfo, name string) *types.InterfaceInfo {
+	for i := range interfaces {
+		if strings.EqualFold(interfaces[i].Name, name) {
+			return &interfaces[i]
+		}
+	}
+	return nil
+}
+
+// pcapCheck is the final filtering rule on every platform
+func pcapCheck(e *InterfaceExplanation) FilterCheck {
+	check := FilterCheck{Rule: "listed by pcap", Passed: e.PcapListed}
+	if !e.PcapListed {
+		check.Detail = "not available for capture"
+	}
+	return check
+}
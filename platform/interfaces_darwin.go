@@ -269,3 +269,30 @@ func GetFilterReason(name string) string {
 
 	return ""
 }
+
+// filterChecks evaluates the GetEthernetInterfaces rules against an interface
+func filterChecks(e *InterfaceExplanation) []FilterCheck {
+	var checks []FilterCheck
+
+	loopbackCheck := FilterCheck{Rule: "not loopback", Passed: e.Flags&net.FlagLoopback == 0}
+	checks = append(checks, loopbackCheck)
+
+	macCheck := FilterCheck{Rule: "has a MAC address", Passed: len(e.MAC) > 0}
+	checks = append(checks, macCheck)
+
+	wifiCheck := FilterCheck{Rule: "not WiFi (networksetup hardware ports)", Passed: true}
+	if getWiFiInterfaces()[e.Name] {
+		wifiCheck.Passed = false
+		wifiCheck.Detail = "WiFi interface"
+	}
+	checks = append(checks, wifiCheck)
+
+	prefixCheck := FilterCheck{Rule: "name not a virtual interface prefix", Passed: true}
+	if reason := findPrefixReason(e.Name, darwinPrefixReasons); reason != "" {
+		prefixCheck.Passed = false
+		prefixCheck.Detail = reason
+	}
+	checks = append(checks, prefixCheck)
+
+	return append(checks, pcapCheck(e))
+}
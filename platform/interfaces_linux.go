@@ -103,28 +103,40 @@ func isVirtualInterface(name string) bool {
 	return hasExcludedPrefix(name, linuxExcludedPrefixes)
 }
 
-// getInterfaceSpeed reads the interface speed from sysfs
+// getInterfaceSpeed reads the interface speed from sysfs, falling back to an
+// ethtool ioctl for drivers that don't expose it there
+// Returns "" when the link is down or the speed is unknown
 func getInterfaceSpeed(name string) string {
-	speedFile := filepath.Join(sysClassNet, name, "speed")
-	data, err := os.ReadFile(speedFile)
-	if err != nil {
-		return ""
+	speed, ok := sysfsSpeed(name)
+	if !ok {
+		speed, ok = ethtoolSpeed(name)
 	}
-
-	speedMbps := strings.TrimSpace(string(data))
-	if speedMbps == "" || speedMbps == "-1" {
+	if !ok {
 		return ""
 	}
+	return formatSpeed(speed)
+}
 
-	speed, err := strconv.Atoi(speedMbps)
+// sysfsSpeed reads the speed in Mb/s from /sys/class/net/<name>/speed
+// The file reads -1 (or can't be read) while the link is down
+func sysfsSpeed(name string) (int, bool) {
+	data, err := os.ReadFile(filepath.Join(sysClassNet, name, "speed"))
 	if err != nil {
-		return ""
+		return 0, false
+	}
+	speed, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || speed <= 0 {
+		return 0, false
 	}
+	return speed, true
+}
 
-	if speed >= 1000 {
-		return strconv.Itoa(speed/1000) + " Gbps"
+// formatSpeed formats a speed in Mb/s, using Gbps from 1000 up
+func formatSpeed(speedMbps int) string {
+	if speedMbps >= 1000 {
+		return strconv.Itoa(speedMbps/1000) + " Gbps"
 	}
-	return speedMbps + " Mbps"
+	return strconv.Itoa(speedMbps) + " Mbps"
 }
 
 // GetInterfaceDisplayName returns the display name for an interface
@@ -229,3 +241,38 @@ func GetFilterReason(name string) string {
 
 	return ""
 }
+
+// filterChecks evaluates the GetEthernetInterfaces rules against an interface
+func filterChecks(e *InterfaceExplanation) []FilterCheck {
+	ifacePath := filepath.Join(sysClassNet, e.Name)
+	var checks []FilterCheck
+
+	typeCheck := FilterCheck{Rule: "Ethernet link type (sysfs type 1)"}
+	if typeData, err := os.ReadFile(filepath.Join(ifacePath, "type")); err != nil {
+		typeCheck.Detail = "no sysfs entry"
+	} else {
+		ifaceType := strings.TrimSpace(string(typeData))
+		typeCheck.Passed = ifaceType == "1"
+		typeCheck.Detail = "type " + ifaceType
+	}
+	checks = append(checks, typeCheck)
+
+	wirelessCheck := FilterCheck{Rule: "not wireless", Passed: true}
+	if _, err := os.Stat(filepath.Join(ifacePath, "wireless")); err == nil {
+		wirelessCheck.Passed = false
+		wirelessCheck.Detail = "WiFi interface (sysfs wireless directory exists)"
+	}
+	checks = append(checks, wirelessCheck)
+
+	prefixCheck := FilterCheck{Rule: "name not a virtual interface prefix", Passed: true}
+	if reason := findPrefixReason(e.Name, linuxPrefixReasons); reason != "" {
+		prefixCheck.Passed = false
+		prefixCheck.Detail = reason
+	}
+	checks = append(checks, prefixCheck)
+
+	macCheck := FilterCheck{Rule: "has a MAC address", Passed: len(e.MAC) > 0}
+	checks = append(checks, macCheck)
+
+	return append(checks, pcapCheck(e))
+}
@@ -341,3 +341,30 @@ func GetFilterReason(name string) string {
 
 	return ""
 }
+
+// filterChecks evaluates the GetEthernetInterfaces rules against an interface
+// Windows filtering is based on the pcap adapter description
+func filterChecks(e *InterfaceExplanation) []FilterCheck {
+	desc := e.PcapDescription
+	if desc == "" {
+		desc = e.Name
+	}
+
+	checks := []FilterCheck{pcapCheck(e)}
+
+	keywordCheck := FilterCheck{Rule: "description has no excluded keyword", Passed: true}
+	if reason := findKeywordReason(desc, windowsKeywordReasons); reason != "" {
+		keywordCheck.Passed = false
+		keywordCheck.Detail = reason
+	}
+	checks = append(checks, keywordCheck)
+
+	ethernetCheck := FilterCheck{Rule: "description looks like an Ethernet adapter", Passed: isEthernetInterface(strings.ToLower(desc))}
+	if !ethernetCheck.Passed {
+		ethernetCheck.Detail = "not recognized as Ethernet adapter"
+	}
+	checks = append(checks, ethernetCheck)
+
+	addrCheck := FilterCheck{Rule: "has a MAC or IP address", Passed: len(e.MAC) > 0 || len(e.Addrs) > 0}
+	return append(checks, addrCheck)
+}
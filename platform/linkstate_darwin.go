@@ -0,0 +1,29 @@
+//go:build darwin
+
+package platform
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// GetLinkState reports whether the interface currently has link
+// Uses the "status:" line from ifconfig, falling back to the UP/RUNNING flags
+func GetLinkState(name string) (bool, error) {
+	output, err := exec.Command("ifconfig", name).Output()
+	if err != nil {
+		return false, err
+	}
+
+	out := string(output)
+	if strings.Contains(out, "status: active") {
+		return true, nil
+	}
+	if strings.Contains(out, "status: inactive") {
+		return false, nil
+	}
+
+	// No status line - use the flags on the first line
+	firstLine, _, _ := strings.Cut(out, "\n")
+	return strings.Contains(firstLine, "<UP") && strings.Contains(firstLine, "RUNNING"), nil
+}
@@ -0,0 +1,34 @@
+//go:build linux
+
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GetLinkState reports whether the interface currently has link (carrier)
+// Reads operstate and carrier from sysfs
+func GetLinkState(name string) (bool, error) {
+	ifacePath := filepath.Join(sysClassNet, name)
+
+	operstate, err := os.ReadFile(filepath.Join(ifacePath, "operstate"))
+	if err != nil {
+		return false, err
+	}
+	switch strings.TrimSpace(string(operstate)) {
+	case "up":
+		return true, nil
+	case "down", "lowerlayerdown", "notpresent":
+		return false, nil
+	}
+
+	// operstate "unknown" is common for drivers that don't report it; fall back to carrier
+	// Reading carrier fails when the interface is administratively down
+	carrier, err := os.ReadFile(filepath.Join(ifacePath, "carrier"))
+	if err != nil {
+		return false, nil
+	}
+	return strings.TrimSpace(string(carrier)) == "1", nil
+}
@@ -0,0 +1,48 @@
+//go:build windows
+
+package platform
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/google/gopacket/pcap"
+)
+
+// pcap connection status flags (libpcap 1.9+ / Npcap)
+const (
+	pcapIfConnectionStatus             = 0x30
+	pcapIfConnectionStatusConnected    = 0x10
+	pcapIfConnectionStatusDisconnected = 0x20
+)
+
+// GetLinkState reports whether the interface currently has link
+// Uses the pcap connection status, falling back to the matching net.Interface flags
+func GetLinkState(name string) (bool, error) {
+	devices, err := pcap.FindAllDevs()
+	if err != nil {
+		return false, err
+	}
+
+	internalName := GetInterfaceInternalName(name)
+	for _, dev := range devices {
+		if dev.Name != internalName {
+			continue
+		}
+
+		switch dev.Flags & pcapIfConnectionStatus {
+		case pcapIfConnectionStatusConnected:
+			return true, nil
+		case pcapIfConnectionStatusDisconnected:
+			return false, nil
+		}
+
+		if iface := findNetInterfaceByPcap(dev); iface != nil {
+			return iface.Flags&net.FlagUp != 0 && iface.Flags&net.FlagRunning != 0, nil
+		}
+		return false, fmt.Errorf("link state unavailable for %s", name)
+	}
+
+	// Device no longer listed (e.g. USB adapter unplugged)
+	return false, nil
+}
@@ -0,0 +1,27 @@
+package protocol
+
+import "encoding/binary"
+
+// Checksum calculates the CDP checksum (RFC 1071 Internet checksum)
+// Odd-length data is padded with a zero low byte
+func Checksum(data []byte) uint16 {
+	var sum uint32
+
+	// Sum all 16-bit words
+	for i := 0; i+1 < len(data); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(data[i : i+2]))
+	}
+
+	// Add odd byte if present
+	if len(data)%2 == 1 {
+		sum += uint32(data[len(data)-1]) << 8
+	}
+
+	// Fold 32-bit sum to 16 bits
+	for sum > 0xFFFF {
+		sum = (sum & 0xFFFF) + (sum >> 16)
+	}
+
+	// One's complement
+	return ^uint16(sum)
+}
@@ -0,0 +1,23 @@
+package protocol
+
+import "testing"
+
+func TestChecksum(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want uint16
+	}{
+		{"RFC 1071 example", []byte{0x00, 0x01, 0xf2, 0x03, 0xf4, 0xf5, 0xf6, 0xf7}, 0x220d},
+		{"odd length pads low byte", []byte{0x01}, 0xfeff},
+		{"empty", nil, 0xffff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Checksum(tt.data); got != tt.want {
+				t.Errorf("Checksum() = %#04x, want %#04x", got, tt.want)
+			}
+		})
+	}
+}
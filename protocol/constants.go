@@ -55,6 +55,7 @@ const (
 
 // LLDP Port ID subtypes
 const (
+	LLDPPortIDSubtypeMACAddr   uint8 = 3
 	LLDPPortIDSubtypeIfaceName uint8 = 5
 )
 
@@ -0,0 +1,456 @@
+// Package qr encodes short text, such as a management URL, as a QR code.
+//
+// It's a small self-contained encoder: byte mode, error correction level L and
+// versions 1-5, which is enough for up to 106 bytes - any http:// URL built from
+// an IPv4 or IPv6 address fits.
+package qr
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrTooLong is returned when the text doesn't fit in the largest supported version
+var ErrTooLong = errors.New("text too long for a QR code (max 106 bytes)")
+
+// Code is an encoded QR symbol
+type Code struct {
+	Size    int
+	Modules [][]bool // Modules[y][x] is true for dark modules
+}
+
+// version describes a QR version at error correction level L
+// All supported versions use a single Reed-Solomon block at level L
+type version struct {
+	dataCodewords int
+	ecCodewords   int
+	alignment     int // Center of the single alignment pattern (0 = none)
+}
+
+var versions = []version{
+	{19, 7, 0},
+	{34, 10, 18},
+	{55, 15, 22},
+	{80, 20, 26},
+	{108, 26, 30},
+}
+
+// Encode encodes text as a QR code using the smallest version it fits in
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+
+	// Byte mode needs 4 bits of mode and 8 bits of length before the data
+	v := -1
+	for i, ver := range versions {
+		if len(data)+2 <= ver.dataCodewords {
+			v = i
+			break
+		}
+	}
+	if v < 0 {
+		return nil, ErrTooLong
+	}
+	ver := versions[v]
+
+	codewords := encodeData(data, ver.dataCodewords)
+	codewords = append(codewords, rsRemainder(codewords, ver.ecCodewords)...)
+
+	size := 17 + 4*(v+1)
+	m := newMatrix(size)
+	m.drawFunctionPatterns(v+1, ver.alignment)
+	m.drawCodewords(codewords)
+
+	// Use the mask with the lowest penalty, as the spec requires
+	best := -1
+	var bestModules [][]bool
+	for mask := 0; mask < 8; mask++ {
+		candidate := m.clone()
+		candidate.applyMask(mask)
+		candidate.drawFormatBits(mask)
+		if p := candidate.penalty(); best < 0 || p < best {
+			best = p
+			bestModules = candidate.modules
+		}
+	}
+
+	return &Code{Size: size, Modules: bestModules}, nil
+}
+
+// String renders the code with Unicode half blocks, two module rows per line,
+// surrounded by a quiet zone of quiet modules. Dark modules are drawn as blocks,
+// so the output should be shown dark-on-light
+func (c *Code) String(quiet int) string {
+	dark := func(x, y int) bool {
+		x -= quiet
+		y -= quiet
+		if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+			return false
+		}
+		return c.Modules[y][x]
+	}
+
+	total := c.Size + 2*quiet
+	var b strings.Builder
+	for y := 0; y < total; y += 2 {
+		for x := 0; x < total; x++ {
+			top, bottom := dark(x, y), dark(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		if y+2 < total {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// encodeData builds the data codewords: mode, length, data, terminator and padding
+func encodeData(data []byte, capacity int) []byte {
+	var bits bitBuffer
+	bits.append(0x4, 4) // Byte mode
+	bits.append(len(data), 8)
+	for _, c := range data {
+		bits.append(int(c), 8)
+	}
+
+	// Terminator of up to four zero bits, then pad to a whole byte
+	capacityBits := capacity * 8
+	terminator := capacityBits - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	if rem := len(bits) % 8; rem != 0 {
+		bits.append(0, 8-rem)
+	}
+
+	codewords := bits.bytes()
+	for pad := byte(0xEC); len(codewords) < capacity; pad ^= 0xEC ^ 0x11 {
+		codewords = append(codewords, pad)
+	}
+	return codewords
+}
+
+// bitBuffer is a sequence of bits, most significant first
+type bitBuffer []bool
+
+func (b *bitBuffer) append(val, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, (val>>i)&1 == 1)
+	}
+}
+
+func (b bitBuffer) bytes() []byte {
+	out := make([]byte, len(b)/8)
+	for i, bit := range b {
+		if bit {
+			out[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return out
+}
+
+// matrix is a QR symbol under construction
+type matrix struct {
+	size     int
+	modules  [][]bool
+	function [][]bool // Modules used by function patterns, which aren't masked
+}
+
+func newMatrix(size int) *matrix {
+	m := &matrix{size: size}
+	m.modules = make([][]bool, size)
+	m.function = make([][]bool, size)
+	for y := range m.modules {
+		m.modules[y] = make([]bool, size)
+		m.function[y] = make([]bool, size)
+	}
+	return m
+}
+
+func (m *matrix) clone() *matrix {
+	c := newMatrix(m.size)
+	for y := range m.modules {
+		copy(c.modules[y], m.modules[y])
+		copy(c.function[y], m.function[y])
+	}
+	return c
+}
+
+// set sets a function module
+func (m *matrix) set(x, y int, dark bool) {
+	m.modules[y][x] = dark
+	m.function[y][x] = true
+}
+
+// drawFunctionPatterns draws the finder, timing and alignment patterns and
+// reserves the format information areas
+func (m *matrix) drawFunctionPatterns(ver, alignment int) {
+	// Timing patterns
+	for i := 0; i < m.size; i++ {
+		m.set(6, i, i%2 == 0)
+		m.set(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns with their separators
+	m.drawFinder(3, 3)
+	m.drawFinder(m.size-4, 3)
+	m.drawFinder(3, m.size-4)
+
+	if alignment > 0 {
+		for dy := -2; dy <= 2; dy++ {
+			for dx := -2; dx <= 2; dx++ {
+				m.set(alignment+dx, alignment+dy, max(abs(dx), abs(dy)) != 1)
+			}
+		}
+	}
+
+	// Reserve format areas; the real bits are drawn per mask
+	m.drawFormatBits(0)
+}
+
+// drawFinder draws a finder pattern centered at (cx, cy) with its light separator
+func (m *matrix) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || y < 0 || x >= m.size || y >= m.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			m.set(x, y, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawFormatBits draws both copies of the format information for level L and mask
+func (m *matrix) drawFormatBits(mask int) {
+	data := 1<<3 | mask // Level L is 01
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	// Around the top-left finder
+	for i := 0; i <= 5; i++ {
+		m.set(8, i, bit(i))
+	}
+	m.set(8, 7, bit(6))
+	m.set(8, 8, bit(7))
+	m.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.set(14-i, 8, bit(i))
+	}
+
+	// Split between the other two finders
+	for i := 0; i < 8; i++ {
+		m.set(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.set(8, m.size-15+i, bit(i))
+	}
+	m.set(8, m.size-8, true) // Always-dark module
+}
+
+// drawCodewords places the codeword bits in the zigzag order, skipping function modules
+// Remainder bits left over at the end stay light
+func (m *matrix) drawCodewords(codewords []byte) {
+	i := 0
+	total := len(codewords) * 8
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < m.size; vert++ {
+			y := vert
+			if upward {
+				y = m.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if m.function[y][x] || i >= total {
+					continue
+				}
+				m.modules[y][x] = (codewords[i/8]>>(7-i%8))&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules selected by the mask pattern
+func (m *matrix) applyMask(mask int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if m.function[y][x] {
+				continue
+			}
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if flip {
+				m.modules[y][x] = !m.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol with the spec's four rules; lower is better
+func (m *matrix) penalty() int {
+	score := 0
+	get := func(x, y int, transpose bool) bool {
+		if transpose {
+			return m.modules[x][y]
+		}
+		return m.modules[y][x]
+	}
+
+	for _, transpose := range []bool{false, true} {
+		for y := 0; y < m.size; y++ {
+			// Rule 1: runs of five or more modules of the same color
+			run := 1
+			for x := 1; x < m.size; x++ {
+				if get(x, y, transpose) == get(x-1, y, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += run - 2
+				}
+				run = 1
+			}
+			if run >= 5 {
+				score += run - 2
+			}
+
+			// Rule 3: finder-like 1:1:3:1:1 patterns with four light modules on a side
+			for x := 0; x+11 <= m.size; x++ {
+				if matchesAt(get, x, y, transpose, finderLike) || matchesAt(get, x, y, transpose, finderLikeReversed) {
+					score += 40
+				}
+			}
+		}
+	}
+
+	// Rule 2: 2x2 blocks of the same color
+	dark := 0
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if m.modules[y][x] {
+				dark++
+			}
+			if x+1 < m.size && y+1 < m.size {
+				c := m.modules[y][x]
+				if m.modules[y][x+1] == c && m.modules[y+1][x] == c && m.modules[y+1][x+1] == c {
+					score += 3
+				}
+			}
+		}
+	}
+
+	// Rule 4: 10 points for every 5% the dark share is away from 50%
+	total := m.size * m.size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	score += k * 10
+
+	return score
+}
+
+var (
+	finderLike         = []bool{true, false, true, true, true, false, true, false, false, false, false}
+	finderLikeReversed = []bool{false, false, false, false, true, false, true, true, true, false, true}
+)
+
+func matchesAt(get func(x, y int, transpose bool) bool, x, y int, transpose bool, pattern []bool) bool {
+	for i, want := range pattern {
+		if get(x+i, y, transpose) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// GF(256) tables for Reed-Solomon, using the QR polynomial x^8+x^4+x^3+x^2+1
+var gfExp, gfLog = func() ([512]byte, [256]byte) {
+	var exp [512]byte
+	var log [256]byte
+	x := 1
+	for i := 0; i < 255; i++ {
+		exp[i] = byte(x)
+		log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	for i := 255; i < 512; i++ {
+		exp[i] = exp[i-255]
+	}
+	return exp, log
+}()
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+// rsRemainder returns the n Reed-Solomon error correction codewords for data
+func rsRemainder(data []byte, n int) []byte {
+	// Generator polynomial (x - a^0)(x - a^1)...(x - a^(n-1)), highest degree first
+	gen := []byte{1}
+	for i := 0; i < n; i++ {
+		next := make([]byte, len(gen)+1)
+		for j, c := range gen {
+			next[j] ^= c
+			next[j+1] ^= gfMul(c, gfExp[i])
+		}
+		gen = next
+	}
+
+	rem := make([]byte, n)
+	for _, b := range data {
+		factor := b ^ rem[0]
+		copy(rem, rem[1:])
+		rem[n-1] = 0
+		for j := 0; j < n; j++ {
+			rem[j] ^= gfMul(gen[j+1], factor)
+		}
+	}
+	return rem
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,108 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// "HELLO WORLD" as 1-M from the standard's worked example
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, 10); !bytes.Equal(got, want) {
+		t.Errorf("rsRemainder = %v, want %v", got, want)
+	}
+}
+
+func TestFormatBits(t *testing.T) {
+	tests := []struct {
+		mask int
+		want string // Bits 14..0
+	}{
+		{0, "111011111000100"},
+		{4, "110011000101111"},
+		{7, "110100101110110"},
+	}
+
+	for _, tt := range tests {
+		m := newMatrix(21)
+		m.drawFormatBits(tt.mask)
+		// The second copy runs bit 14 at the bottom of the column up to bit 8,
+		// then bit 7 at the left of the row out to bit 0 at the right edge
+		var got strings.Builder
+		for y := 20; y >= 21-7; y-- {
+			got.WriteString(bit(m.modules[y][8]))
+		}
+		for x := 21 - 8; x < 21; x++ {
+			got.WriteString(bit(m.modules[8][x]))
+		}
+		if got.String() != tt.want {
+			t.Errorf("mask %d: format bits = %s, want %s", tt.mask, got.String(), tt.want)
+		}
+	}
+}
+
+func bit(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		text     string
+		wantSize int
+	}{
+		{"http://10.0.0.1", 21},
+		{"http://192.168.100.200", 25},
+		{"http://[2001:db8:85a3::8a2e:370:7334]", 29},
+		{strings.Repeat("x", 106), 37},
+	}
+
+	for _, tt := range tests {
+		code, err := Encode(tt.text)
+		if err != nil {
+			t.Fatalf("Encode(%q) error: %v", tt.text, err)
+		}
+		if code.Size != tt.wantSize || len(code.Modules) != tt.wantSize {
+			t.Errorf("Encode(%q) size = %d, want %d", tt.text, code.Size, tt.wantSize)
+		}
+
+		// Finder patterns sit in three corners
+		for _, corner := range [][2]int{{0, 0}, {code.Size - 7, 0}, {0, code.Size - 7}} {
+			x, y := corner[0], corner[1]
+			if !code.Modules[y][x] || code.Modules[y+1][x+1] || !code.Modules[y+3][x+3] {
+				t.Errorf("Encode(%q): no finder pattern at %v", tt.text, corner)
+			}
+		}
+		if !code.Modules[code.Size-8][8] {
+			t.Errorf("Encode(%q): dark module missing", tt.text)
+		}
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(strings.Repeat("x", 107)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Encode(107 bytes) error = %v, want ErrTooLong", err)
+	}
+}
+
+func TestString(t *testing.T) {
+	code, err := Encode("http://10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(code.String(2), "\n")
+	// 21 modules + 4 quiet = 25 rows, two per line
+	if len(lines) != 13 {
+		t.Errorf("got %d lines, want 13", len(lines))
+	}
+	for i, line := range lines {
+		if n := len([]rune(line)); n != 25 {
+			t.Errorf("line %d is %d wide, want 25", i, n)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+package sqlite
+
+// Register the pure-Go SQLite driver, so the export needs no C toolchain
+import _ "modernc.org/sqlite"
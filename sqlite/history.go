@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"nbor/types"
+)
+
+// latestSightings selects the most recent row for every neighbor key along with
+// when that key was first seen
+const latestSightings = `SELECT n.seen_at, f.first_seen, n.interface, n.protocol, n.hostname, n.port_id,
+	n.port_description, n.management_ip, n.platform, n.description, n.location, n.capabilities, n.source_mac
+FROM neighbors n
+JOIN (SELECT key, MIN(seen_at) AS first_seen, MAX(seen_at) AS last_seen FROM neighbors GROUP BY key) f
+	ON n.key = f.key AND n.seen_at = f.last_seen`
+
+// LoadDevices reads every device recorded in the database at path, one per
+// neighbor key, using the values from its most recent sighting
+// The database is never created; a missing file is reported as an error
+func LoadDevices(path string) ([]*types.Neighbor, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	db, err := sql.Open(DriverName, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(latestSightings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SQLite history: %w", err)
+	}
+	defer rows.Close()
+
+	var devices []*types.Neighbor
+	for rows.Next() {
+		var seenAt, firstSeen string
+		var iface, protocol, hostname, portID, portDesc, mgmtIP sql.NullString
+		var platform, description, location, caps, srcMAC sql.NullString
+		if err := rows.Scan(&seenAt, &firstSeen, &iface, &protocol, &hostname, &portID, &portDesc,
+			&mgmtIP, &platform, &description, &location, &caps, &srcMAC); err != nil {
+			return nil, fmt.Errorf("failed to read SQLite history: %w", err)
+		}
+
+		n := &types.Neighbor{
+			Hostname:        hostname.String,
+			PortID:          portID.String,
+			PortDescription: portDesc.String,
+			ManagementIP:    net.ParseIP(mgmtIP.String),
+			Platform:        platform.String,
+			Description:     description.String,
+			Location:        location.String,
+			Protocol:        types.Protocol(protocol.String),
+			Interface:       iface.String,
+		}
+		n.LastSeen, _ = time.Parse(time.RFC3339Nano, seenAt)
+		n.FirstSeen, _ = time.Parse(time.RFC3339Nano, firstSeen)
+		if mac, err := net.ParseMAC(srcMAC.String); err == nil {
+			n.SourceMAC = mac
+		}
+		for _, c := range strings.Split(caps.String, ",") {
+			if c != "" {
+				n.Capabilities = append(n.Capabilities, types.Capability(c))
+			}
+		}
+		n.SeenCDP = n.Protocol == types.ProtocolCDP || n.Protocol == types.ProtocolBoth
+		n.SeenLLDP = n.Protocol == types.ProtocolLLDP || n.Protocol == types.ProtocolBoth
+
+		devices = append(devices, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read SQLite history: %w", err)
+	}
+
+	return devices, nil
+}
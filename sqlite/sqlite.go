@@ -0,0 +1,281 @@
+// Package sqlite provides optional SQLite export of neighbor discoveries for
+// querying history across sessions.
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"nbor/types"
+	"nbor/version"
+)
+
+// DriverName is the database/sql driver used to open the SQLite file
+// The driver is registered in driver.go
+const DriverName = "sqlite"
+
+// timeLayout is how timestamps are stored: UTC with every fraction digit kept,
+// so comparing the text (MIN, MAX, ORDER BY) orders them in time
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+const (
+	batchSize     = 50              // Flush once this many rows are pending
+	flushInterval = 5 * time.Second // Flush pending rows at least this often
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS sessions (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at TEXT NOT NULL,
+	ended_at   TEXT,
+	interface  TEXT NOT NULL,
+	version    TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS neighbors (
+	key              TEXT NOT NULL,
+	seen_at          TEXT NOT NULL,
+	session_id       INTEGER NOT NULL REFERENCES sessions(id),
+	interface        TEXT,
+	protocol         TEXT,
+	hostname         TEXT,
+	port_id          TEXT,
+	port_description TEXT,
+	management_ip    TEXT,
+	platform         TEXT,
+	description      TEXT,
+	location         TEXT,
+	capabilities     TEXT,
+	source_mac       TEXT,
+	PRIMARY KEY (key, seen_at)
+);
+CREATE INDEX IF NOT EXISTS idx_neighbors_hostname ON neighbors(hostname);
+CREATE INDEX IF NOT EXISTS idx_neighbors_management_ip ON neighbors(management_ip);
+`
+
+const insertNeighbor = `INSERT OR REPLACE INTO neighbors (
+	key, seen_at, session_id, interface, protocol, hostname, port_id, port_description,
+	management_ip, platform, description, location, capabilities, source_mac
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// row is a snapshot of a neighbor taken when it was recorded
+// Neighbors are mutated in place by the store, so values are copied immediately
+type row struct {
+	key             string
+	seenAt          string
+	iface           string
+	protocol        string
+	hostname        string
+	portID          string
+	portDescription string
+	managementIP    string
+	platform        string
+	description     string
+	location        string
+	capabilities    string
+	sourceMAC       string
+}
+
+// Exporter writes neighbor sightings to a SQLite database in batches
+type Exporter struct {
+	mu        sync.Mutex // Guards pending and db
+	writeMu   sync.Mutex // Serializes database writes so Record never waits on I/O
+	db        *sql.DB
+	path      string
+	sessionID int64
+	pending   []row
+	stopChan  chan struct{}
+	doneChan  chan struct{}
+
+	// Callback for database errors; errors never stop capture
+	OnError func(error)
+}
+
+// NewExporter opens (or creates) the database at path, ensures the schema
+// exists and starts a new session for the given interface
+func NewExporter(path, iface string) (*Exporter, error) {
+	db, err := sql.Open(DriverName, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create SQLite schema: %w", err)
+	}
+
+	result, err := db.Exec("INSERT INTO sessions (started_at, interface, version) VALUES (?, ?, ?)",
+		formatTime(time.Now()), iface, version.Version)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create SQLite session: %w", err)
+	}
+	sessionID, err := result.LastInsertId()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to read SQLite session id: %w", err)
+	}
+
+	e := &Exporter{
+		db:        db,
+		path:      path,
+		sessionID: sessionID,
+		stopChan:  make(chan struct{}),
+		doneChan:  make(chan struct{}),
+	}
+	go e.flushLoop()
+
+	return e, nil
+}
+
+// Record queues a neighbor sighting for insertion
+// Safe to call from store callbacks; the database write happens in the background
+func (e *Exporter) Record(n *types.Neighbor) {
+	caps := make([]string, len(n.Capabilities))
+	for i, cap := range n.Capabilities {
+		caps[i] = string(cap)
+	}
+
+	r := row{
+		key:             n.NeighborKey(),
+		seenAt:          formatTime(n.LastSeen),
+		iface:           n.Interface,
+		protocol:        string(n.Protocol),
+		hostname:        n.Hostname,
+		portID:          n.PortID,
+		portDescription: n.PortDescription,
+		platform:        n.Platform,
+		description:     n.Description,
+		location:        n.Location,
+		capabilities:    strings.Join(caps, ","),
+	}
+	if n.ManagementIP != nil {
+		r.managementIP = n.ManagementIP.String()
+	}
+	if n.SourceMAC != nil {
+		r.sourceMAC = n.SourceMAC.String()
+	}
+
+	e.mu.Lock()
+	e.pending = append(e.pending, r)
+	full := len(e.pending) >= batchSize
+	e.mu.Unlock()
+
+	if full {
+		go e.flush()
+	}
+}
+
+// flushLoop periodically writes pending rows until Close is called
+func (e *Exporter) flushLoop() {
+	defer close(e.doneChan)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.stopChan:
+			return
+		case <-ticker.C:
+			e.flush()
+		}
+	}
+}
+
+// flush writes all pending rows in a single transaction
+// Rows are dropped on failure so a broken database can't grow memory unbounded
+func (e *Exporter) flush() {
+	e.writeMu.Lock()
+	defer e.writeMu.Unlock()
+
+	e.mu.Lock()
+	rows := e.pending
+	e.pending = nil
+	db := e.db
+	e.mu.Unlock()
+
+	if len(rows) == 0 || db == nil {
+		return
+	}
+
+	if err := e.insert(db, rows); err != nil {
+		e.reportError(err)
+	}
+}
+
+// insert writes rows in one transaction
+func (e *Exporter) insert(db *sql.DB, rows []row) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin SQLite transaction: %w", err)
+	}
+
+	stmt, err := tx.Prepare(insertNeighbor)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to prepare SQLite insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, r := range rows {
+		_, err := stmt.Exec(r.key, r.seenAt, e.sessionID, r.iface, r.protocol, r.hostname, r.portID,
+			r.portDescription, r.managementIP, r.platform, r.description, r.location, r.capabilities, r.sourceMAC)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to insert neighbor into SQLite: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit SQLite transaction: %w", err)
+	}
+	return nil
+}
+
+// reportError passes an error to OnError if set
+func (e *Exporter) reportError(err error) {
+	if e.OnError != nil {
+		e.OnError(err)
+	}
+}
+
+// Close flushes pending rows, ends the session and closes the database
+func (e *Exporter) Close() error {
+	e.mu.Lock()
+	if e.db == nil {
+		e.mu.Unlock()
+		return nil
+	}
+	e.mu.Unlock()
+
+	close(e.stopChan)
+	<-e.doneChan
+	e.flush()
+
+	e.writeMu.Lock()
+	defer e.writeMu.Unlock()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, err := e.db.Exec("UPDATE sessions SET ended_at = ? WHERE id = ?",
+		formatTime(time.Now()), e.sessionID); err != nil {
+		e.reportError(fmt.Errorf("failed to end SQLite session: %w", err))
+	}
+
+	err := e.db.Close()
+	e.db = nil
+	return err
+}
+
+// Path returns the path to the database file
+func (e *Exporter) Path() string {
+	return e.path
+}
+
+// formatTime formats t in timeLayout
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
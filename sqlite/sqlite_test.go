@@ -0,0 +1,168 @@
+package sqlite
+
+import (
+	"database/sql"
+	"net"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"nbor/types"
+)
+
+func TestExporterRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nbor.db")
+	e, err := NewExporter(path, "eth0")
+	if err != nil {
+		t.Fatalf("NewExporter: %v", err)
+	}
+	var errs []error
+	e.OnError = func(err error) { errs = append(errs, err) }
+
+	first := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	sw := &types.Neighbor{
+		ID:           "sw1",
+		Hostname:     "sw1",
+		PortID:       "Gi1/0/1",
+		ManagementIP: net.ParseIP("10.0.0.1"),
+		Platform:     "C9300",
+		Capabilities: []types.Capability{types.CapBridge, types.CapRouter},
+		Protocol:     types.ProtocolCDP,
+		Interface:    "eth0",
+		SourceMAC:    mac,
+		LastSeen:     first,
+	}
+	e.Record(sw)
+
+	// A later sighting replaces the values; the neighbor is copied when recorded
+	sw.PortID = "Gi1/0/2"
+	sw.Protocol = types.ProtocolBoth
+	sw.LastSeen = first.Add(time.Minute)
+	e.Record(sw)
+
+	phoneMAC, _ := net.ParseMAC("00:04:f2:00:00:01")
+	e.Record(&types.Neighbor{
+		ID:        "phone",
+		Hostname:  "phone",
+		Protocol:  types.ProtocolLLDP,
+		Interface: "eth0",
+		SourceMAC: phoneMAC,
+		LastSeen:  first.Add(2 * time.Minute),
+	})
+
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := e.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if len(errs) > 0 {
+		t.Fatalf("OnError: %v", errs)
+	}
+
+	devices, err := LoadDevices(path)
+	if err != nil {
+		t.Fatalf("LoadDevices: %v", err)
+	}
+	if len(devices) != 2 {
+		t.Fatalf("LoadDevices returned %d devices, want 2", len(devices))
+	}
+	slices.SortFunc(devices, func(a, b *types.Neighbor) int { return a.LastSeen.Compare(b.LastSeen) })
+
+	got := devices[0]
+	if got.Hostname != "sw1" || got.PortID != "Gi1/0/2" || got.Platform != "C9300" {
+		t.Errorf("sw1 = %q %q %q, want the latest sighting", got.Hostname, got.PortID, got.Platform)
+	}
+	if !got.ManagementIP.Equal(sw.ManagementIP) || got.SourceMAC.String() != mac.String() {
+		t.Errorf("sw1 addresses = %v %v, want %v %v", got.ManagementIP, got.SourceMAC, sw.ManagementIP, mac)
+	}
+	if !got.FirstSeen.Equal(first) || !got.LastSeen.Equal(first.Add(time.Minute)) {
+		t.Errorf("sw1 seen %v - %v, want %v - %v", got.FirstSeen, got.LastSeen, first, first.Add(time.Minute))
+	}
+	if !slices.Equal(got.Capabilities, sw.Capabilities) {
+		t.Errorf("sw1 capabilities = %v, want %v", got.Capabilities, sw.Capabilities)
+	}
+	if got.Protocol != types.ProtocolBoth || !got.SeenCDP || !got.SeenLLDP {
+		t.Errorf("sw1 protocol = %s (CDP %v, LLDP %v), want both", got.Protocol, got.SeenCDP, got.SeenLLDP)
+	}
+	if devices[1].Hostname != "phone" || devices[1].ManagementIP != nil {
+		t.Errorf("phone = %q %v, want phone with no management IP", devices[1].Hostname, devices[1].ManagementIP)
+	}
+}
+
+func TestExporterSightingOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nbor.db")
+	e, err := NewExporter(path, "eth0")
+	if err != nil {
+		t.Fatalf("NewExporter: %v", err)
+	}
+
+	// A whole-second sighting, then one a tenth of a second later in another
+	// zone; as RFC 3339 text in local time these sort the wrong way round
+	first := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	later := first.Add(100 * time.Millisecond).In(time.FixedZone("EST", -5*3600))
+	sw := &types.Neighbor{ID: "sw1", Hostname: "sw1", PortID: "Gi1/0/1", Interface: "eth0", LastSeen: first}
+	e.Record(sw)
+	sw.PortID = "Gi1/0/2"
+	sw.LastSeen = later
+	e.Record(sw)
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	devices, err := LoadDevices(path)
+	if err != nil {
+		t.Fatalf("LoadDevices: %v", err)
+	}
+	if len(devices) != 1 {
+		t.Fatalf("LoadDevices returned %d devices, want 1", len(devices))
+	}
+	got := devices[0]
+	if got.PortID != "Gi1/0/2" || !got.LastSeen.Equal(later) {
+		t.Errorf("latest sighting = %q at %v, want Gi1/0/2 at %v", got.PortID, got.LastSeen, later)
+	}
+	if !got.FirstSeen.Equal(first) {
+		t.Errorf("FirstSeen = %v, want %v", got.FirstSeen, first)
+	}
+}
+
+func TestExporterSessions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nbor.db")
+	for _, iface := range []string{"eth0", "eth1"} {
+		e, err := NewExporter(path, iface)
+		if err != nil {
+			t.Fatalf("NewExporter(%s): %v", iface, err)
+		}
+		if err := e.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	}
+
+	db, err := sql.Open(DriverName, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var sessions, ended int
+	if err := db.QueryRow("SELECT COUNT(*), COUNT(ended_at) FROM sessions").Scan(&sessions, &ended); err != nil {
+		t.Fatal(err)
+	}
+	if sessions != 2 || ended != 2 {
+		t.Errorf("sessions = %d (%d ended), want 2 and 2", sessions, ended)
+	}
+}
+
+func TestLoadDevicesMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.db")
+	if _, err := LoadDevices(path); err == nil {
+		t.Error("LoadDevices succeeded on a missing file")
+	}
+	if _, err := sql.Open(DriverName, path); err != nil {
+		t.Fatal(err)
+	}
+	if devices, err := LoadDevices(path); err == nil || devices != nil {
+		t.Errorf("LoadDevices created the database: %v, %v", devices, err)
+	}
+}
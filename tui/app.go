@@ -3,6 +3,7 @@ package tui
 
 import (
 	"fmt"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
@@ -17,6 +18,7 @@ const (
 	StateSelectInterface AppState = iota
 	StateConfigMenu
 	StateCapturing
+	StateHistory
 )
 
 // AppModel is the main application model
@@ -25,12 +27,20 @@ type AppModel struct {
 	picker     InterfacePickerModel
 	configMenu ConfigMenuModel
 	neighbors  NeighborTableModel
+	history    NeighborTableModel // Read-only view of devices recorded in SQLite
 	store      *types.NeighborStore
 	config     *config.Config
+	baseConfig *config.Config // The config file as loaded, saved by the config menu
 	err        error
 	width      int
 	height     int
 
+	// State to return to when the history view closes
+	historyReturn AppState
+
+	// Warnings sent before the capture view opened, shown once it does
+	warnings []string
+
 	// Channel for sending selected interface back to main
 	selectChan chan<- types.InterfaceInfo
 
@@ -78,6 +88,7 @@ func (m AppModel) Init() tea.Cmd {
 	case StateSelectInterface:
 		return tea.Batch(
 			m.picker.Init(),
+			m.pickerSummaryCmd(),
 			tea.EnterAltScreen,
 		)
 	case StateConfigMenu:
@@ -93,12 +104,33 @@ func (m AppModel) Init() tea.Cmd {
 // ErrorMsg represents an error message
 type ErrorMsg struct {
 	Err error
+
+	// Broadcast marks a broadcaster failure (e.g. a frame that couldn't be built)
+	// Capture keeps running: the failed broadcaster stops and the error is shown in the footer
+	Broadcast bool
+
+	// Interface names the interface whose broadcaster failed. Main has stopped
+	// just that one, and the others keep sending; empty means all of them
+	Interface string
+}
+
+// WarningMsg reports a problem that doesn't stop capture, e.g. an export that
+// couldn't be set up. It's shown as a footer notice, since stderr is hidden
+// behind the alt screen
+type WarningMsg struct {
+	Text string
+}
+
+// BroadcastAutoStoppedMsg reports that broadcast_max_duration stopped broadcasting
+type BroadcastAutoStoppedMsg struct {
+	After time.Duration
 }
 
 // StartCaptureMsg signals to start capturing on the selected interface
 type StartCaptureMsg struct {
-	Interface types.InterfaceInfo
-	LogPath   string
+	Interface  types.InterfaceInfo
+	Interfaces []types.InterfaceInfo // Every interface in --all-interfaces mode, starting with Interface
+	LogPath    string
 }
 
 // RestartLogMsg signals that a new log file should be started
@@ -135,24 +167,60 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			var cmd tea.Cmd
 			m.neighbors, cmd = m.neighbors.Update(msg)
 			return m, cmd
+		case StateHistory:
+			var cmd tea.Cmd
+			m.history, cmd = m.history.Update(msg)
+			return m, cmd
+		}
+
+	case GoToHistoryMsg:
+		// Open the seen devices view over the current screen
+		if m.state != StateHistory {
+			m.historyReturn = m.state
 		}
+		m.state = StateHistory
+		m.history = NewHistoryTable(m.config)
+		m.history.width = m.width
+		m.history.height = m.height
+		return m, loadHistoryCmd(m.config.SQLitePath)
+
+	case HistoryLoadedMsg:
+		m.history.setHistory(msg)
+		return m, nil
+
+	case HistoryClosedMsg:
+		m.state = m.historyReturn
+		if m.state == StateCapturing {
+			return m, m.neighbors.Init()
+		}
+		return m, nil
 
 	case GoToConfigMenuMsg:
 		// Navigate to config menu from capture screen
 		m.state = StateConfigMenu
 		m.configMenu = NewConfigMenu(m.config)
+		m.configMenu.base = m.baseConfig
 		m.configMenu.width = m.width
 		m.configMenu.height = m.height
+		m.configMenu.ifaceInfo = m.neighbors.ifaceInfo
+		if msg.SubState != SubStateMain {
+			m.configMenu = m.configMenu.openSubState(msg.SubState)
+		}
 		return m, m.configMenu.Init()
 
 	case ConfigSavedMsg:
 		// Config was saved, return to capturing
 		m.config = msg.Config
-		// Update the neighbors model with new config
+		// Update the neighbors model with new config and the current theme's styles
 		m.neighbors.config = m.config
+		m.neighbors.styles = DefaultStyles
 		newBroadcasting := m.config.CDPBroadcast || m.config.LLDPBroadcast
 		m.neighbors.broadcasting = newBroadcasting
 		m.state = StateCapturing
+		if msg.Warning != "" {
+			m.neighbors.notice = msg.Warning
+			m.neighbors.noticeAt = time.Now()
+		}
 
 		// Signal config update to main goroutine (for broadcaster, etc.)
 		if m.configUpdateChan != nil {
@@ -195,6 +263,20 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, tea.Quit
 
+	case LinkStateMsg:
+		// Track link state even while the config menu is open
+		m.neighbors, _ = m.neighbors.Update(msg)
+		return m, nil
+
+	case CaptureStatsMsg:
+		// Keep the footer's statistics current even while the config menu is open
+		m.neighbors, _ = m.neighbors.Update(msg)
+		return m, nil
+
+	case PacketSeenMsg:
+		m.neighbors, _ = m.neighbors.Update(msg)
+		return m, nil
+
 	case LogRestartedMsg:
 		// Update the log path in the neighbors view
 		m.neighbors.logPath = msg.LogPath
@@ -225,18 +307,52 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Transition to capturing state
 		m.state = StateCapturing
 		m.neighbors = NewNeighborTable(m.store, msg.Interface, msg.LogPath, m.config)
+		m.neighbors.interfaces = msg.Interfaces
 		m.neighbors.width = m.width
 		m.neighbors.height = m.height
+		for _, w := range m.warnings {
+			m.neighbors.warn(w)
+		}
+		m.warnings = nil
 		return m, m.neighbors.Init()
 
+	case PTRResolvedMsg:
+		// A lookup can finish while another view is open, e.g. the config menu
+		m.neighbors.setPTR(msg)
+		return m, nil
+
+	case WarningMsg:
+		// The table only exists once capture has started
+		if m.neighbors.store == nil {
+			m.warnings = append(m.warnings, msg.Text)
+		} else {
+			m.neighbors.warn(msg.Text)
+		}
+		return m, nil
+
+	case BroadcastAutoStoppedMsg:
+		// Stop the other interfaces' broadcasters too, so TX means nothing is sent
+		m.neighbors.broadcastStopped("")
+		m.neighbors.notice = fmt.Sprintf("broadcast stopped after %d min (broadcast_max_duration)", int(msg.After.Minutes()))
+		m.neighbors.noticeAt = time.Now()
+		return m.Update(ToggleBroadcastMsg{Enabled: false})
+
 	case ErrorMsg:
+		if msg.Broadcast {
+			m.neighbors.notice = "broadcast stopped: " + msg.Err.Error()
+			m.neighbors.noticeAt = time.Now()
+			if m.neighbors.broadcastStopped(msg.Interface) {
+				return m, nil
+			}
+			return m.Update(ToggleBroadcastMsg{Enabled: false})
+		}
 		m.err = msg.Err
 		return m, tea.Quit
 
 	case tea.KeyMsg:
 		// Handle global quit
 		if msg.String() == "ctrl+c" {
-			return m, tea.Quit
+			return m.handleCtrlC()
 		}
 	}
 
@@ -258,11 +374,63 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.neighbors, cmd = m.neighbors.Update(msg)
 		return m, cmd
+
+	case StateHistory:
+		var cmd tea.Cmd
+		m.history, cmd = m.history.Update(msg)
+		return m, cmd
 	}
 
 	return m, nil
 }
 
+// pickerSummaryCmd loads the per-interface history summaries shown in the picker
+// Returns nil when picker_summary is off or there is no database to read
+func (m AppModel) pickerSummaryCmd() tea.Cmd {
+	if m.config == nil || !m.config.PickerSummary {
+		return nil
+	}
+	return loadInterfaceSummaryCmd(m.config.SQLitePath)
+}
+
+// handleCtrlC quits, or with ctrl_c_back set, backs out of the current sub-screen
+// and only quits from top-level screens (the neighbor table and interface picker)
+func (m AppModel) handleCtrlC() (tea.Model, tea.Cmd) {
+	if m.config == nil || !m.config.CtrlCBack {
+		return m, tea.Quit
+	}
+
+	esc := tea.KeyMsg{Type: tea.KeyEsc}
+	switch m.state {
+	case StateConfigMenu:
+		var newConfig tea.Model
+		var cmd tea.Cmd
+		if m.configMenu.subState == SubStateMain {
+			// ESC does nothing on the config main menu, so cancel it
+			newConfig, cmd = m.configMenu.cancel()
+		} else {
+			newConfig, cmd = m.configMenu.Update(esc)
+		}
+		m.configMenu = newConfig.(ConfigMenuModel)
+		return m, cmd
+
+	case StateHistory:
+		var cmd tea.Cmd
+		m.history, cmd = m.history.Update(esc)
+		return m, cmd
+
+	case StateCapturing:
+		if m.neighbors.showDetail || m.neighbors.showLegend || m.neighbors.showSummary || m.neighbors.confirmClear ||
+			m.neighbors.editingNote || m.neighbors.confirmBroadcast {
+			var cmd tea.Cmd
+			m.neighbors, cmd = m.neighbors.Update(esc)
+			return m, cmd
+		}
+	}
+
+	return m, tea.Quit
+}
+
 // View renders the application
 func (m AppModel) View() string {
 	if m.err != nil {
@@ -276,11 +444,26 @@ func (m AppModel) View() string {
 		return m.configMenu.View()
 	case StateCapturing:
 		return m.neighbors.View()
+	case StateHistory:
+		return m.history.View()
 	}
 
 	return ""
 }
 
+// SetBaseConfig sets the config as loaded from the file, before --profile and
+// flag overrides. The config menu saves its edits over this one, so a profile
+// or flag in use doesn't end up written to the file
+func (m *AppModel) SetBaseConfig(base *config.Config) {
+	m.baseConfig = base
+}
+
+// AddWarnings queues warnings from before the program started. They're shown
+// in the footer once capture starts, like a WarningMsg
+func (m *AppModel) AddWarnings(warnings ...string) {
+	m.warnings = append(m.warnings, warnings...)
+}
+
 // GetStore returns the neighbor store
 func (m *AppModel) GetStore() *types.NeighborStore {
 	return m.store
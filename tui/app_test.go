@@ -0,0 +1,300 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"nbor/config"
+	"nbor/types"
+)
+
+func TestCtrlCBack(t *testing.T) {
+	ctrlC := tea.KeyMsg{Type: tea.KeyCtrlC}
+
+	tests := []struct {
+		name      string
+		ctrlCBack bool
+		setup     func(m AppModel) AppModel
+		wantQuit  bool
+		wantState AppState
+	}{
+		{
+			name:     "quits by default",
+			setup:    func(m AppModel) AppModel { m.state = StateHistory; return m },
+			wantQuit: true,
+		},
+		{
+			name:      "closes history",
+			ctrlCBack: true,
+			setup:     func(m AppModel) AppModel { m.state = StateHistory; return m },
+			wantState: StateHistory, // HistoryClosedMsg is delivered by the returned command
+		},
+		{
+			name:      "closes detail popup",
+			ctrlCBack: true,
+			setup: func(m AppModel) AppModel {
+				m.state = StateCapturing
+				m.neighbors.showDetail = true
+				return m
+			},
+			wantState: StateCapturing,
+		},
+		{
+			name:      "leaves config sub-menu",
+			ctrlCBack: true,
+			setup: func(m AppModel) AppModel {
+				m.state = StateConfigMenu
+				m.configMenu = NewConfigMenu(m.config).openSubState(SubStateLogging)
+				return m
+			},
+			wantState: StateConfigMenu,
+		},
+		{
+			name:      "quits from neighbor table",
+			ctrlCBack: true,
+			setup:     func(m AppModel) AppModel { m.state = StateCapturing; return m },
+			wantQuit:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.CtrlCBack = tt.ctrlCBack
+			store := types.NewNeighborStore()
+			m := NewApp(nil, store, &cfg, nil, nil, nil, nil, nil)
+			m.neighbors = NewNeighborTable(store, types.InterfaceInfo{Name: "eth0"}, "", &cfg)
+			m.history = NewHistoryTable(&cfg)
+			m = tt.setup(m)
+
+			newModel, cmd := m.Update(ctrlC)
+			quit := false
+			if cmd != nil {
+				_, quit = cmd().(tea.QuitMsg)
+			}
+			if quit != tt.wantQuit {
+				t.Fatalf("Ctrl+C quit = %v, want %v", quit, tt.wantQuit)
+			}
+			if quit {
+				return
+			}
+
+			got := newModel.(AppModel)
+			if got.state != tt.wantState {
+				t.Errorf("state = %v, want %v", got.state, tt.wantState)
+			}
+			if got.neighbors.showDetail {
+				t.Error("detail popup should be closed")
+			}
+			if got.state == StateConfigMenu && got.configMenu.subState != SubStateMain {
+				t.Errorf("config subState = %v, want main menu", got.configMenu.subState)
+			}
+		})
+	}
+}
+
+func TestBroadcastErrorKeepsCapturing(t *testing.T) {
+	cfg := config.DefaultConfig()
+	store := types.NewNeighborStore()
+	toggle := make(chan bool, 1)
+	m := NewApp(nil, store, &cfg, nil, nil, nil, toggle, nil)
+	m.state = StateCapturing
+	m.neighbors = NewNeighborTable(store, types.InterfaceInfo{Name: "eth0"}, "", &cfg)
+	m.neighbors.broadcasting = true
+
+	newModel, cmd := m.Update(ErrorMsg{Err: errors.New("eth0: CDP not sent"), Broadcast: true})
+	if cmd != nil {
+		if _, quit := cmd().(tea.QuitMsg); quit {
+			t.Fatal("a broadcast error shouldn't quit")
+		}
+	}
+	got := newModel.(AppModel)
+	if got.err != nil {
+		t.Errorf("err = %v, want nil", got.err)
+	}
+	if got.neighbors.broadcasting {
+		t.Error("broadcasting should be turned off")
+	}
+	if !strings.Contains(got.neighbors.notice, "CDP not sent") {
+		t.Errorf("notice = %q, want the error", got.neighbors.notice)
+	}
+	select {
+	case enabled := <-toggle:
+		if enabled {
+			t.Error("broadcaster should be told to stop")
+		}
+	default:
+		t.Error("broadcaster wasn't told to stop")
+	}
+}
+
+func TestBroadcastAutoStopped(t *testing.T) {
+	cfg := config.DefaultConfig()
+	store := types.NewNeighborStore()
+	toggle := make(chan bool, 1)
+	m := NewApp(nil, store, &cfg, nil, nil, nil, toggle, nil)
+	m.state = StateCapturing
+	m.neighbors = NewNeighborTable(store, types.InterfaceInfo{Name: "eth0"}, "", &cfg)
+	m.neighbors.broadcasting = true
+
+	newModel, _ := m.Update(BroadcastAutoStoppedMsg{After: 30 * time.Minute})
+	got := newModel.(AppModel)
+	if got.neighbors.broadcasting {
+		t.Error("broadcasting should be turned off")
+	}
+	if !strings.Contains(got.neighbors.notice, "after 30 min") {
+		t.Errorf("notice = %q, want how long it ran", got.neighbors.notice)
+	}
+	if footer := got.neighbors.renderFooter(); !strings.Contains(footer, "broadcast:--") {
+		t.Errorf("footer doesn't show broadcast off:\n%s", footer)
+	}
+	select {
+	case enabled := <-toggle:
+		if enabled {
+			t.Error("broadcasters should be told to stop")
+		}
+	default:
+		t.Error("broadcasters weren't told to stop")
+	}
+}
+
+func TestWarningsShownInFooter(t *testing.T) {
+	cfg := config.DefaultConfig()
+	store := types.NewNeighborStore()
+	m := NewApp(nil, store, &cfg, nil, nil, nil, nil, nil)
+
+	// Startup warnings and those sent before capture starts wait for the table
+	m.AddWarnings("unknown theme 'nope', using default (see nbor --list-themes)")
+	newModel, _ := m.Update(WarningMsg{Text: "syslog disabled: no route"})
+	newModel, _ = newModel.Update(StartCaptureMsg{Interface: types.InterfaceInfo{Name: "eth0"}})
+	newModel, _ = newModel.Update(WarningMsg{Text: "webhook disabled: bad URL"})
+	got := newModel.(AppModel)
+	if !strings.HasPrefix(got.neighbors.notice, "warning: unknown theme 'nope'") {
+		t.Errorf("notice = %q, want the startup warning first", got.neighbors.notice)
+	}
+
+	// Each next warning replaces the last once it has been shown long enough
+	for _, want := range []string{"warning: syslog disabled: no route", "warning: webhook disabled: bad URL"} {
+		got.neighbors.noticeAt = time.Now().Add(-noticeDuration - time.Second)
+		got.neighbors, _ = got.neighbors.Update(TickMsg(time.Now()))
+		if got.neighbors.notice != want {
+			t.Errorf("notice after expiry = %q, want %q", got.neighbors.notice, want)
+		}
+	}
+}
+
+func TestPTRResolvedOutsideCapture(t *testing.T) {
+	cfg := config.DefaultConfig()
+	store := types.NewNeighborStore()
+	m := NewApp(nil, store, &cfg, nil, nil, nil, nil, nil)
+	m.neighbors = NewNeighborTable(store, types.InterfaceInfo{Name: "eth0"}, "", &cfg)
+	m.state = StateConfigMenu
+	m.configMenu = NewConfigMenu(&cfg)
+
+	newModel, _ := m.Update(PTRResolvedMsg{IP: "10.0.0.1", Name: "core-sw1.example.net"})
+	if got := newModel.(AppModel).neighbors.ptrNames["10.0.0.1"]; got != "core-sw1.example.net" {
+		t.Errorf("PTR answered in the config menu cached as %q, want the name", got)
+	}
+}
+
+func TestBroadcastErrorStopsOneInterface(t *testing.T) {
+	cfg := config.DefaultConfig()
+	store := types.NewNeighborStore()
+	toggle := make(chan bool, 1)
+	m := NewApp(nil, store, &cfg, nil, nil, nil, toggle, nil)
+	m.state = StateCapturing
+	m.neighbors = NewNeighborTable(store, types.InterfaceInfo{Name: "eth0"}, "", &cfg)
+	m.neighbors.interfaces = []types.InterfaceInfo{{Name: "eth0"}, {Name: "eth1"}}
+	m.neighbors.broadcasting = true
+	m.neighbors.width = 160
+
+	newModel, _ := m.Update(ErrorMsg{Err: errors.New("eth1: CDP not sent"), Broadcast: true, Interface: "eth1"})
+	got := newModel.(AppModel)
+	if !got.neighbors.broadcasting {
+		t.Fatal("eth0 still broadcasts, so broadcasting should stay on")
+	}
+	if footer := got.neighbors.renderFooter(); !strings.Contains(footer, "TX (eth1 --)") {
+		t.Errorf("footer doesn't show eth1 stopped:\n%s", footer)
+	}
+	select {
+	case <-toggle:
+		t.Error("the other interfaces' broadcasters shouldn't be told to stop")
+	default:
+	}
+
+	newModel, _ = got.Update(ErrorMsg{Err: errors.New("eth0: LLDP not sent"), Broadcast: true, Interface: "eth0"})
+	got = newModel.(AppModel)
+	if got.neighbors.broadcasting {
+		t.Error("broadcasting should be off once every interface has failed")
+	}
+	select {
+	case enabled := <-toggle:
+		if enabled {
+			t.Error("broadcasters should be told to stop")
+		}
+	default:
+		t.Error("broadcasters weren't told to stop")
+	}
+}
+
+func TestConfigMenuSaveSkipsProfile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("APPDATA", dir)
+
+	base := config.DefaultConfig()
+	running := base
+	running.CDPBroadcast = !base.CDPBroadcast // As a --profile would set it
+
+	m := NewConfigMenu(&running)
+	m.base = &base
+	m.systemNameInput.SetValue("edited")
+	m.saveConfig()
+
+	saved, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+	if saved.SystemName != "edited" {
+		t.Errorf("saved SystemName = %q, want the menu edit", saved.SystemName)
+	}
+	if saved.CDPBroadcast != base.CDPBroadcast {
+		t.Errorf("saved CDPBroadcast = %v, want the file's %v, not the profile's", saved.CDPBroadcast, base.CDPBroadcast)
+	}
+	if running.SystemName != "edited" {
+		t.Errorf("running SystemName = %q, want the edit applied to the running config too", running.SystemName)
+	}
+}
+
+func TestConfigMenuSaveErrorShown(t *testing.T) {
+	// A file where the config directory should be makes every save fail
+	dir := filepath.Join(t.TempDir(), "not-a-dir")
+	if err := os.WriteFile(dir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("APPDATA", dir)
+
+	cfg := config.DefaultConfig()
+	m := NewConfigMenu(&cfg)
+	m.width = 200
+	m.saveTheme()
+	if !strings.HasPrefix(m.notice, "theme not saved: ") {
+		t.Errorf("notice = %q, want the theme save error", m.notice)
+	}
+	if footer := m.renderFooter(); !strings.Contains(footer, "theme not saved") {
+		t.Errorf("footer %q doesn't show the save error", footer)
+	}
+
+	_, cmd := m.saveConfig()
+	saved, ok := cmd().(ConfigSavedMsg)
+	if !ok || !strings.HasPrefix(saved.Warning, "config not saved: ") {
+		t.Errorf("ConfigSavedMsg.Warning = %q, want the config save error", saved.Warning)
+	}
+}
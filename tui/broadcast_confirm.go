@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"nbor/types"
+)
+
+// routableAddresses returns the addresses of ifaces that reach beyond the link:
+// IPv4 outside 169.254.0.0/16 and global IPv6
+func routableAddresses(ifaces []types.InterfaceInfo) []string {
+	var addrs []string
+	for _, iface := range ifaces {
+		for _, ip := range iface.IPv4Addrs {
+			if !ip.IsLinkLocalUnicast() && !ip.IsLoopback() {
+				addrs = append(addrs, iface.Name+" "+ip.String())
+			}
+		}
+		for _, ip := range iface.IPv6Addrs {
+			if ip.IsGlobalUnicast() {
+				addrs = append(addrs, iface.Name+" "+ip.String())
+			}
+		}
+	}
+	return addrs
+}
+
+// broadcastInterfaces returns the interfaces that broadcasting sends on
+func (m NeighborTableModel) broadcastInterfaces() []types.InterfaceInfo {
+	if m.multiInterface() {
+		return m.interfaces
+	}
+	return []types.InterfaceInfo{m.ifaceInfo}
+}
+
+// setBroadcasting turns broadcasting on or off and tells main to start or stop
+// the broadcaster (runtime only, doesn't change protocol config)
+func (m NeighborTableModel) setBroadcasting(on bool) (NeighborTableModel, tea.Cmd) {
+	m.broadcasting = on
+	m.broadcastFailed = nil // Toggling restarts or stops every interface's broadcaster
+	return m, func() tea.Msg {
+		return ToggleBroadcastMsg{Enabled: on}
+	}
+}
+
+// broadcastStopped records that iface's broadcaster stopped on an error and
+// reports whether any interface is still broadcasting. An empty iface means
+// broadcasting stopped everywhere
+func (m *NeighborTableModel) broadcastStopped(iface string) bool {
+	if iface == "" {
+		m.broadcasting, m.broadcastFailed = false, nil
+		return false
+	}
+	if m.broadcastFailed == nil {
+		m.broadcastFailed = make(map[string]bool)
+	}
+	m.broadcastFailed[iface] = true
+	for _, name := range m.captureInterfaceNames() {
+		if !m.broadcastFailed[name] {
+			return true
+		}
+	}
+	m.broadcasting, m.broadcastFailed = false, nil
+	return false
+}
+
+// failedBroadcasts returns the interfaces whose broadcaster stopped on an error, sorted
+func (m NeighborTableModel) failedBroadcasts() []string {
+	names := make([]string, 0, len(m.broadcastFailed))
+	for name := range m.broadcastFailed {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// updateConfirmBroadcast handles the key pressed at the broadcast prompt
+// b or enter starts broadcasting; any other key cancels
+func (m NeighborTableModel) updateConfirmBroadcast(msg tea.KeyMsg) (NeighborTableModel, tea.Cmd) {
+	m.confirmBroadcast = false
+	if !key.Matches(msg, neighborKeys.ConfirmBroadcast) {
+		return m, nil
+	}
+	return m.setBroadcasting(true)
+}
+
+// maxConfirmAddresses caps the addresses listed so the prompt stays small
+const maxConfirmAddresses = 4
+
+// renderBroadcastConfirmPopup renders the prompt shown before broadcasting starts
+func (m NeighborTableModel) renderBroadcastConfirmPopup(contentHeight int) string {
+	theme := DefaultTheme
+	bg := theme.Base00
+
+	titleStyle := lipgloss.NewStyle().Foreground(theme.Base08).Background(bg).Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(theme.Base05).Background(bg)
+	addrStyle := lipgloss.NewStyle().Foreground(theme.Base0A).Background(bg)
+	keyStyle := lipgloss.NewStyle().Foreground(theme.Base0C).Background(bg).Bold(true)
+	hintStyle := lipgloss.NewStyle().Foreground(theme.Base03).Background(bg)
+
+	var protocols []string
+	if m.config.CDPBroadcast {
+		protocols = append(protocols, "CDP")
+	}
+	if m.config.LLDPBroadcast {
+		protocols = append(protocols, "LLDP")
+	}
+	sending := strings.Join(protocols, " and ")
+	if sending == "" {
+		sending = "nothing (no protocol is set to broadcast)"
+	}
+
+	lines := []string{
+		titleStyle.Render("Start broadcasting?"),
+		"",
+		textStyle.Render("nbor will send " + sending + " frames on " + strings.Join(m.captureInterfaceNames(), ", ") + "."),
+		textStyle.Render("Switches list it as a neighbor and monitoring may pick it up."),
+	}
+
+	if addrs := routableAddresses(m.broadcastInterfaces()); len(addrs) > 0 {
+		lines = append(lines, "", titleStyle.Render("This looks like a production network:"))
+		for i, addr := range addrs {
+			if i == maxConfirmAddresses {
+				lines = append(lines, hintStyle.Render(fmt.Sprintf("  and %d more", len(addrs)-i)))
+				break
+			}
+			lines = append(lines, addrStyle.Render("  "+addr))
+		}
+	}
+
+	lines = append(lines, "",
+		keyStyle.Render("b")+hintStyle.Render("/")+keyStyle.Render("enter")+textStyle.Render(" broadcast  ")+
+			keyStyle.Render("esc")+textStyle.Render(" cancel"),
+		hintStyle.Render("Set confirm_broadcast = false to skip this prompt"),
+	)
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Base08).
+		BorderBackground(bg).
+		Background(bg).
+		Padding(0, 1)
+
+	return lipgloss.Place(
+		m.width,
+		contentHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		borderStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)),
+		lipgloss.WithWhitespaceBackground(bg),
+	)
+}
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"nbor/types"
+)
+
+// capabilityLegend lists every capability in display order with what it means
+var capabilityLegend = []struct {
+	cap     types.Capability
+	meaning string
+}{
+	{types.CapRouter, "Routes between networks (layer 3)"},
+	{types.CapSwitch, "Switches frames (layer 2)"},
+	{types.CapBridge, "Bridge; LLDP switches report this"},
+	{types.CapAccessPoint, "Wireless access point"},
+	{types.CapPhone, "IP phone"},
+	{types.CapDocsis, "Cable modem (DOCSIS)"},
+	{types.CapStation, "End station, e.g. a PC or server"},
+	{types.CapRepeater, "Repeater or hub"},
+	{types.CapOther, "Other or unknown"},
+}
+
+// capabilityCounts returns how many neighbors have each capability enabled
+func capabilityCounts(neighbors []*types.Neighbor) map[types.Capability]int {
+	counts := make(map[types.Capability]int)
+	for _, n := range neighbors {
+		for _, c := range n.Capabilities {
+			counts[c]++
+		}
+	}
+	return counts
+}
+
+// inCapabilityFilter reports whether filter_capabilities names c
+func (m NeighborTableModel) inCapabilityFilter(c types.Capability) bool {
+	for _, f := range m.config.FilterCapabilities {
+		if strings.EqualFold(string(c), f) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderLegendPopup renders the capabilities legend with a live count of the
+// neighbors in the store that have each one, centered in the content area
+func (m NeighborTableModel) renderLegendPopup(contentHeight int) string {
+	theme := DefaultTheme
+	bg := theme.Base00
+
+	titleStyle := lipgloss.NewStyle().Foreground(theme.Base0D).Background(bg).Bold(true)
+	capStyle := lipgloss.NewStyle().Foreground(theme.Base0A).Background(bg)
+	countStyle := lipgloss.NewStyle().Foreground(theme.Base05).Background(bg).Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(theme.Base05).Background(bg)
+	hintStyle := lipgloss.NewStyle().Foreground(theme.Base03).Background(bg)
+
+	counts := capabilityCounts(m.store.GetAll())
+	filtered := len(m.config.FilterCapabilities) > 0
+
+	lines := []string{titleStyle.Render("Capabilities"), ""}
+	for _, entry := range capabilityLegend {
+		count := countStyle.Render(fmt.Sprintf("%4d", counts[entry.cap]))
+		if counts[entry.cap] == 0 {
+			count = hintStyle.Render(fmt.Sprintf("%4d", 0))
+		}
+		marker := " "
+		if m.inCapabilityFilter(entry.cap) {
+			marker = "*"
+		}
+		lines = append(lines, capStyle.Render(fmt.Sprintf("%s %-9s", marker, entry.cap))+
+			count+textStyle.Render("  "+entry.meaning))
+	}
+
+	lines = append(lines, "")
+	if filtered {
+		lines = append(lines, hintStyle.Render("* shown by filter_capabilities"))
+	}
+	lines = append(lines, hintStyle.Render("Counts include every neighbor in the store"))
+	lines = append(lines, hintStyle.Render("R clears new-neighbor highlights and scrolls to the top"))
+	lines = append(lines, hintStyle.Render("ESC or C to close"))
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Base0D).
+		BorderBackground(bg).
+		Background(bg).
+		Padding(0, 1)
+
+	return lipgloss.Place(
+		m.width,
+		contentHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		borderStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)),
+		lipgloss.WithWhitespaceBackground(bg),
+	)
+}
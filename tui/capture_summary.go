@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"nbor/types"
+)
+
+// captureSummary is a one-glance tally of the neighbors in the store
+type captureSummary struct {
+	total        int
+	byProtocol   map[types.Protocol]int
+	capabilities map[types.Capability]int
+	active       int
+	stale        int
+	subnets      []string // Management subnets, sorted
+}
+
+// summaryProtocols lists the protocols in the order the summary shows them
+var summaryProtocols = []types.Protocol{types.ProtocolCDP, types.ProtocolLLDP, types.ProtocolBoth}
+
+// summarizeNeighbors tallies neighbors by protocol, capability and state, and
+// collects the management subnets they advertise
+func summarizeNeighbors(neighbors []*types.Neighbor) captureSummary {
+	s := captureSummary{
+		total:        len(neighbors),
+		byProtocol:   make(map[types.Protocol]int),
+		capabilities: capabilityCounts(neighbors),
+	}
+	subnets := make(map[string]bool)
+	for _, n := range neighbors {
+		s.byProtocol[n.Protocol]++
+		if n.IsStale {
+			s.stale++
+		} else {
+			s.active++
+		}
+		addrs := n.AllAddresses
+		if len(addrs) == 0 && n.ManagementIP != nil {
+			addrs = []net.IP{n.ManagementIP}
+		}
+		for _, ip := range addrs {
+			if subnet := managementSubnet(ip); subnet != "" {
+				subnets[subnet] = true
+			}
+		}
+	}
+	for subnet := range subnets {
+		s.subnets = append(s.subnets, subnet)
+	}
+	sort.Strings(s.subnets)
+	return s
+}
+
+// managementSubnet returns the subnet an address is assumed to sit in. CDP and
+// LLDP don't carry prefix lengths, so IPv4 uses /24 and IPv6 /64
+func managementSubnet(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return (&net.IPNet{IP: ip4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	if ip.To16() != nil {
+		return (&net.IPNet{IP: ip.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}).String()
+	}
+	return ""
+}
+
+// maxSummarySubnets caps the subnets listed so the popup fits short terminals
+const maxSummarySubnets = 6
+
+// renderSummaryPopup renders the capture summary centered in the content area
+func (m NeighborTableModel) renderSummaryPopup(contentHeight int) string {
+	theme := DefaultTheme
+	bg := theme.Base00
+
+	titleStyle := lipgloss.NewStyle().Foreground(theme.Base0D).Background(bg).Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Base0A).Background(bg)
+	countStyle := lipgloss.NewStyle().Foreground(theme.Base05).Background(bg).Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(theme.Base05).Background(bg)
+	hintStyle := lipgloss.NewStyle().Foreground(theme.Base03).Background(bg)
+
+	s := summarizeNeighbors(m.store.GetAll())
+	row := func(label string, count int) string {
+		return labelStyle.Render(fmt.Sprintf("  %-10s", label)) + countStyle.Render(fmt.Sprintf("%4d", count))
+	}
+
+	lines := []string{
+		titleStyle.Render("Capture Summary"),
+		"",
+		labelStyle.Render(fmt.Sprintf("%-12s", "Neighbors")) + countStyle.Render(fmt.Sprintf("%4d", s.total)),
+		row("Active", s.active),
+		row("Stale", s.stale),
+		"",
+		textStyle.Render("By protocol"),
+	}
+	for _, p := range summaryProtocols {
+		lines = append(lines, row(string(p), s.byProtocol[p]))
+	}
+
+	lines = append(lines, "", textStyle.Render("By capability"))
+	listed := 0
+	for _, entry := range capabilityLegend {
+		if count := s.capabilities[entry.cap]; count > 0 {
+			lines = append(lines, row(string(entry.cap), count))
+			listed++
+		}
+	}
+	if listed == 0 {
+		lines = append(lines, hintStyle.Render("  none advertised"))
+	}
+
+	lines = append(lines, "", textStyle.Render(fmt.Sprintf("Management subnets (%d)", len(s.subnets))))
+	for i, subnet := range s.subnets {
+		if i == maxSummarySubnets {
+			lines = append(lines, hintStyle.Render(fmt.Sprintf("  and %d more", len(s.subnets)-i)))
+			break
+		}
+		lines = append(lines, textStyle.Render("  "+subnet))
+	}
+	if len(s.subnets) == 0 {
+		lines = append(lines, hintStyle.Render("  none advertised"))
+	}
+
+	lines = append(lines, "", hintStyle.Render("Subnets assume /24 (IPv4) and /64 (IPv6)"))
+	lines = append(lines, hintStyle.Render("ESC or i to close"))
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Base0D).
+		BorderBackground(bg).
+		Background(bg).
+		Padding(0, 1)
+
+	return lipgloss.Place(
+		m.width,
+		contentHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		borderStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)),
+		lipgloss.WithWhitespaceBackground(bg),
+	)
+}
@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// ColorProfiles lists the accepted color profile names
+var ColorProfiles = []string{"auto", "truecolor", "256", "16", "ascii"}
+
+// monochrome is set when output has no color at all. Styles then mark state with
+// text attributes instead: stale rows are faint, new rows bold, the selection reversed
+var monochrome bool
+
+// SetColorProfile sets the color profile used for all TUI and CLI output
+// "auto" detects the terminal's capabilities from the environment
+// The ascii profile switches to monochrome styles
+func SetColorProfile(name string) error {
+	switch strings.ToLower(name) {
+	case "auto":
+		lipgloss.SetColorProfile(termenv.EnvColorProfile())
+	case "truecolor":
+		lipgloss.SetColorProfile(termenv.TrueColor)
+	case "256":
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	case "16":
+		lipgloss.SetColorProfile(termenv.ANSI)
+	case "ascii":
+		lipgloss.SetColorProfile(termenv.Ascii)
+	default:
+		return fmt.Errorf("unknown color profile %q (use %s)", name, strings.Join(ColorProfiles, ", "))
+	}
+
+	monochrome = lipgloss.ColorProfile() == termenv.Ascii
+	DefaultStyles = NewStyles(DefaultTheme)
+	return nil
+}
@@ -1,7 +1,7 @@
 package tui
 
 import (
-	"os"
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -10,7 +10,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"nbor/broadcast"
 	"nbor/config"
+	"nbor/types"
 )
 
 // ConfigSubState represents the current sub-menu state
@@ -59,11 +61,14 @@ type ConfigMenuModel struct {
 	subCursor  int // Cursor position in current sub-menu
 
 	config *config.Config
+	base   *config.Config // The config file's own settings, without --profile or flags
+	notice string         // Shown in the footer, e.g. a theme that couldn't be saved
 
 	// Theme preview
 	previousTheme     Theme
-	themeIndex        int  // Current theme index being previewed
-	themePreviewDirty bool // True if theme has been changed
+	themeIndex        int         // Current theme index being previewed
+	themeFilter       ThemeFilter // Which themes the theme list shows
+	themePreviewDirty bool        // True if theme has been changed
 
 	// Text inputs for Broadcast Options
 	systemNameInput textinput.Model
@@ -91,6 +96,7 @@ type ConfigMenuModel struct {
 	cdpBroadcast       bool
 	lldpBroadcast      bool
 	broadcastOnStartup bool
+	broadcastOnLinkUp  bool
 	capRouter          bool
 	capBridge          bool
 	capStation         bool
@@ -106,6 +112,9 @@ type ConfigMenuModel struct {
 	// Resolved hostname (for display when SystemName is empty)
 	resolvedHostname string
 
+	// Capture interface, for listing the addresses that get advertised
+	ifaceInfo types.InterfaceInfo
+
 	width  int
 	height int
 	styles Styles
@@ -113,15 +122,10 @@ type ConfigMenuModel struct {
 
 // NewConfigMenu creates a new config menu model
 func NewConfigMenu(cfg *config.Config) ConfigMenuModel {
-	// Resolve the actual hostname that will be used
-	resolvedHostname := cfg.SystemName
-	if resolvedHostname == "" {
-		if hostname, err := os.Hostname(); err == nil {
-			resolvedHostname = hostname
-		} else {
-			resolvedHostname = "nbor"
-		}
-	}
+	// Resolve the name advertised when system_name is left empty
+	hostnameCfg := *cfg
+	hostnameCfg.SystemName = ""
+	resolvedHostname := broadcast.AdvertisedName(&hostnameCfg)
 
 	// Create text inputs for Broadcast Options
 	systemNameInput := textinput.New()
@@ -211,6 +215,7 @@ func NewConfigMenu(cfg *config.Config) ConfigMenuModel {
 		config:             cfg,
 		previousTheme:      DefaultTheme,
 		themeIndex:         themeIndex,
+		themeFilter:        ThemeFilter(cfg.ThemeFilter),
 		systemNameInput:    systemNameInput,
 		systemDescInput:    systemDescInput,
 		intervalInput:      intervalInput,
@@ -228,6 +233,7 @@ func NewConfigMenu(cfg *config.Config) ConfigMenuModel {
 		cdpBroadcast:       cfg.CDPBroadcast,
 		lldpBroadcast:      cfg.LLDPBroadcast,
 		broadcastOnStartup: cfg.BroadcastOnStartup,
+		broadcastOnLinkUp:  cfg.BroadcastOnLinkUp,
 		capRouter:          capRouter,
 		capBridge:          capBridge,
 		capStation:         capStation,
@@ -255,6 +261,7 @@ type configMenuKeyMap struct {
 	Back   key.Binding
 	Save   key.Binding
 	Tab    key.Binding
+	Filter key.Binding
 }
 
 var configMenuKeys = configMenuKeyMap{
@@ -290,12 +297,17 @@ var configMenuKeys = configMenuKeyMap{
 		key.WithKeys("tab"),
 		key.WithHelp("tab", "next"),
 	),
+	Filter: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "filter"),
+	),
 }
 
 // Messages
 type ConfigSavedMsg struct {
 	Config                *config.Config
 	ListenSettingsChanged bool
+	Warning               string // Shown after saving, e.g. a TTL shorter than the interval
 }
 
 type ConfigCancelledMsg struct{}
@@ -386,6 +398,7 @@ func (m ConfigMenuModel) saveConfig() (tea.Model, tea.Cmd) {
 	}
 
 	// Update config
+	before := *m.config
 	m.config.SystemName = m.systemNameInput.Value()
 	m.config.SystemDescription = m.systemDescInput.Value()
 	m.config.CDPListen = m.cdpListen
@@ -393,6 +406,7 @@ func (m ConfigMenuModel) saveConfig() (tea.Model, tea.Cmd) {
 	m.config.CDPBroadcast = m.cdpBroadcast
 	m.config.LLDPBroadcast = m.lldpBroadcast
 	m.config.BroadcastOnStartup = m.broadcastOnStartup
+	m.config.BroadcastOnLinkUp = m.broadcastOnLinkUp
 	m.config.AdvertiseInterval = interval
 	m.config.TTL = ttl
 	m.config.Capabilities = caps
@@ -407,16 +421,37 @@ func (m ConfigMenuModel) saveConfig() (tea.Model, tea.Cmd) {
 	if themeSlug != "" {
 		m.config.Theme = themeSlug
 	}
+	m.config.ThemeFilter = string(m.themeFilter)
+
+	// A TTL that doesn't outlast the interval makes us flap on the other side
+	warning := m.config.ShortTTLWarning()
+	if m.config.FixShortTTL && m.config.ShortTTLFixable() {
+		m.config.TTL = m.config.RecommendedTTL()
+		warning = fmt.Sprintf("ttl raised to %d", m.config.TTL)
+	}
 
 	// Check if listen settings changed
 	listenChanged := m.cdpListen != m.originalCDPListen || m.lldpListen != m.originalLLDPListen
 
-	// Save to file
-	_ = config.Save(*m.config)
+	// Save to file; the settings still apply to this session if it fails
+	if err := m.save(before); err != nil {
+		warning = "config not saved: " + err.Error()
+	}
 
 	return m, func() tea.Msg {
-		return ConfigSavedMsg{Config: m.config, ListenSettingsChanged: listenChanged}
+		return ConfigSavedMsg{Config: m.config, ListenSettingsChanged: listenChanged, Warning: warning}
+	}
+}
+
+// save writes the menu's edits to the config file. The running config has the
+// --profile and flag overrides applied, so only the settings changed since
+// before are carried over to the file's own settings
+func (m *ConfigMenuModel) save(before config.Config) error {
+	if m.base == nil {
+		return config.Save(*m.config)
 	}
+	m.base.ApplyChanges(before, *m.config)
+	return config.Save(*m.base)
 }
 
 // View renders the config menu
@@ -507,8 +542,13 @@ func (m ConfigMenuModel) renderFooter() string {
 			keyStyle.Render("enter") + textStyle.Render(" select") + sep +
 			keyStyle.Render("ctrl+s") + textStyle.Render(" save")
 	case SubStateTheme:
+		filter := m.themeFilter
+		if filter == "" {
+			filter = ThemeFilterAll
+		}
 		content = keyStyle.Render("↑↓/jk") + textStyle.Render(" preview") + sep +
 			keyStyle.Render("enter") + textStyle.Render(" select") + sep +
+			keyStyle.Render("f") + textStyle.Render(" show:"+string(filter)) + sep +
 			keyStyle.Render("esc") + textStyle.Render(" cancel")
 	case SubStateAbout:
 		content = keyStyle.Render("esc") + textStyle.Render(" back") + sep +
@@ -524,6 +564,12 @@ func (m ConfigMenuModel) renderFooter() string {
 			keyStyle.Render("esc") + textStyle.Render(" back") + sep +
 			keyStyle.Render("ctrl+s") + textStyle.Render(" save")
 	}
+	if m.notice != "" {
+		noticeStyle := lipgloss.NewStyle().
+			Foreground(theme.Base08).
+			Background(bg)
+		content += sep + noticeStyle.Render(m.notice)
+	}
 
 	return RenderFooter(content, m.width)
 }
@@ -1,11 +1,15 @@
 package tui
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"nbor/config"
 )
 
 // updateBroadcast handles key events for the Broadcast Options sub-menu
@@ -14,7 +18,7 @@ func (m ConfigMenuModel) updateBroadcast(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Row 0: System Name (0)
 	// Row 1: Description (1)
 	// Row 2: CDP Broadcast (2), LLDP Broadcast (3)
-	// Row 3: Start on Launch (4)
+	// Row 3: Start on Launch (4), Send on Link Up (11)
 	// Row 4: Interval (5)
 	// Row 5: TTL (6)
 	// Row 6: Cap Router (7), Cap Bridge (8), Cap Station (9)
@@ -25,7 +29,7 @@ func (m ConfigMenuModel) updateBroadcast(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		{0},       // System Name
 		{1},       // Description
 		{2, 3},    // CDP, LLDP
-		{4},       // Start on Launch
+		{4, 11},   // Start on Launch, Send on Link Up
 		{5},       // Interval
 		{6},       // TTL
 		{7, 8, 9}, // Router, Bridge, Station
@@ -91,6 +95,8 @@ func (m ConfigMenuModel) updateBroadcast(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.lldpBroadcast = !m.lldpBroadcast
 		case 4:
 			m.broadcastOnStartup = !m.broadcastOnStartup
+		case 11:
+			m.broadcastOnLinkUp = !m.broadcastOnLinkUp
 		case 7:
 			m.capRouter = !m.capRouter
 		case 8:
@@ -180,6 +186,10 @@ func (m ConfigMenuModel) renderBroadcast() string {
 	b.WriteString(m.systemDescInput.View())
 	b.WriteString("\n\n")
 
+	if m.config.ShowLocalAddresses && m.ifaceInfo.Name != "" {
+		b.WriteString(m.renderLocalAddresses())
+	}
+
 	// Protocol Broadcasting
 	b.WriteString("  ")
 	b.WriteString(sectionStyle.Render("Protocol Broadcasting"))
@@ -206,6 +216,13 @@ func (m ConfigMenuModel) renderBroadcast() string {
 	b.WriteString(renderCheckbox(m.broadcastOnStartup, m.subCursor == 4, theme))
 	b.WriteString(" ")
 	b.WriteString(renderLabel("Start on launch", m.subCursor == 4, theme))
+	b.WriteString("  ")
+
+	// Send on Link Up
+	b.WriteString(renderCursor(m.subCursor == 11, theme))
+	b.WriteString(renderCheckbox(m.broadcastOnLinkUp, m.subCursor == 11, theme))
+	b.WriteString(" ")
+	b.WriteString(renderLabel("Send on link up", m.subCursor == 11, theme))
 	b.WriteString("\n\n")
 
 	// Timing
@@ -229,7 +246,14 @@ func (m ConfigMenuModel) renderBroadcast() string {
 	b.WriteString("            ")
 	b.WriteString(m.ttlInput.View())
 	b.WriteString(dimStyle.Render(" seconds"))
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	if warning := m.ttlWarning(); warning != "" {
+		warnStyle := lipgloss.NewStyle().Foreground(theme.Base09)
+		b.WriteString("    ")
+		b.WriteString(warnStyle.Render(warning))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
 
 	// Capabilities
 	b.WriteString("  ")
@@ -266,3 +290,83 @@ func (m ConfigMenuModel) renderBroadcast() string {
 
 	return b.String()
 }
+
+// ttlWarning returns a short warning when the entered TTL doesn't outlast the
+// entered interval, or "" when it does or either value isn't a number yet
+func (m ConfigMenuModel) ttlWarning() string {
+	interval, err := strconv.Atoi(m.intervalInput.Value())
+	if err != nil {
+		return ""
+	}
+	ttl, err := strconv.Atoi(m.ttlInput.Value())
+	if err != nil {
+		return ""
+	}
+	cfg := config.Config{AdvertiseInterval: interval, TTL: ttl, CDPBroadcast: m.cdpBroadcast}
+	if cfg.ShortTTLWarning() == "" {
+		return ""
+	}
+	if cfg.CDPBroadcast && interval >= config.MaxCDPHoldtime {
+		return fmt.Sprintf("⚠ Interval isn't shorter than the %ds CDP holdtime - CDP neighbors will flap", config.MaxCDPHoldtime)
+	}
+	if m.config.FixShortTTL && cfg.ShortTTLFixable() {
+		return fmt.Sprintf("⚠ TTL isn't longer than the interval - will be raised to %d on save", cfg.RecommendedTTL())
+	}
+	return fmt.Sprintf("⚠ TTL isn't longer than the interval - neighbors will flap (use %d+)", cfg.RecommendedTTL())
+}
+
+// renderLocalAddresses renders the capture interface's own addresses, marking which
+// ones the broadcaster puts in the CDP Addresses and LLDP Management Address TLVs
+func (m ConfigMenuModel) renderLocalAddresses() string {
+	theme := DefaultTheme
+	var b strings.Builder
+
+	sectionStyle := lipgloss.NewStyle().Foreground(theme.Base0D).Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Base04)
+	valueStyle := lipgloss.NewStyle().Foreground(theme.Base05)
+	sentStyle := lipgloss.NewStyle().Foreground(theme.Base0B)
+	dimStyle := lipgloss.NewStyle().Foreground(theme.Base03)
+
+	b.WriteString("  ")
+	b.WriteString(sectionStyle.Render("Local Addresses"))
+	b.WriteString(dimStyle.Render(" (" + m.ifaceInfo.Name + ")"))
+	b.WriteString("\n\n")
+
+	row := func(label, value, note string, style lipgloss.Style) {
+		b.WriteString("    ")
+		b.WriteString(labelStyle.Render(fmt.Sprintf("%-6s", label)))
+		b.WriteString(valueStyle.Render(fmt.Sprintf("%-28s", value)))
+		b.WriteString(style.Render(note))
+		b.WriteString("\n")
+	}
+
+	if m.ifaceInfo.MAC != nil {
+		row("MAC", m.ifaceInfo.MAC.String(), "frame source", dimStyle)
+	}
+
+	// Mirrors broadcast.BuildCDPFrame and BuildLLDPFrame: CDP lists every IPv4
+	// address, LLDP uses the first one as its management address, or the
+	// first IPv6 address when there's no IPv4
+	for i, ip := range m.ifaceInfo.IPv4Addrs {
+		note := "CDP"
+		if i == 0 {
+			note = "CDP + LLDP mgmt"
+		}
+		row("IPv4", ip.String(), note, sentStyle)
+	}
+	for i, ip := range m.ifaceInfo.IPv6Addrs {
+		if i == 0 && len(m.ifaceInfo.IPv4Addrs) == 0 {
+			row("IPv6", ip.String(), "LLDP mgmt", sentStyle)
+			continue
+		}
+		row("IPv6", ip.String(), "not advertised", dimStyle)
+	}
+	if len(m.ifaceInfo.IPv4Addrs) == 0 && len(m.ifaceInfo.IPv6Addrs) == 0 {
+		b.WriteString("    ")
+		b.WriteString(dimStyle.Render("No IP address - no management address is advertised"))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
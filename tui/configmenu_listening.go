@@ -201,7 +201,7 @@ func (m ConfigMenuModel) renderListening() string {
 	b.WriteString(renderLabel("Staleness Timeout", m.subCursor == 5, theme))
 	b.WriteString("  ")
 	b.WriteString(m.stalenessInput.View())
-	b.WriteString(dimStyle.Render(" seconds (gray out)"))
+	b.WriteString(dimStyle.Render(" seconds (gray out, 0 = never)"))
 	b.WriteString("\n")
 
 	// Stale Removal
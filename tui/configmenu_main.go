@@ -8,6 +8,44 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// subStateMenuItems maps each sub-menu to its main menu item
+var subStateMenuItems = map[ConfigSubState]ConfigMenuItem{
+	SubStateListening: ConfigMenuListening,
+	SubStateBroadcast: ConfigMenuBroadcast,
+	SubStateLogging:   ConfigMenuLogging,
+	SubStateTheme:     ConfigMenuTheme,
+	SubStateAbout:     ConfigMenuAbout,
+}
+
+// openSubState enters a sub-menu and moves the main cursor to its item
+// so that ESC returns to the matching main menu entry
+func (m ConfigMenuModel) openSubState(state ConfigSubState) ConfigMenuModel {
+	item, ok := subStateMenuItems[state]
+	if !ok {
+		return m
+	}
+	m.mainCursor = int(item)
+	m.subState = state
+	m.subCursor = 0
+
+	switch state {
+	case SubStateBroadcast:
+		m.systemNameInput.Focus()
+	case SubStateTheme:
+		m.subCursor = m.themeCursor()
+		m.previousTheme = DefaultTheme
+	}
+	return m
+}
+
+// cancel discards all changes, reverting a previewed theme, and leaves the menu
+func (m ConfigMenuModel) cancel() (tea.Model, tea.Cmd) {
+	if m.themePreviewDirty {
+		SetTheme(m.previousTheme)
+	}
+	return m, func() tea.Msg { return ConfigCancelledMsg{} }
+}
+
 // updateMain handles key events for the main menu
 func (m ConfigMenuModel) updateMain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
@@ -28,29 +66,19 @@ func (m ConfigMenuModel) updateMain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case ConfigMenuChangeInterface:
 			return m, func() tea.Msg { return ChangeInterfaceMsg{} }
 		case ConfigMenuListening:
-			m.subState = SubStateListening
-			m.subCursor = 0
+			m = m.openSubState(SubStateListening)
 		case ConfigMenuBroadcast:
-			m.subState = SubStateBroadcast
-			m.subCursor = 0
-			m.systemNameInput.Focus()
+			m = m.openSubState(SubStateBroadcast)
 		case ConfigMenuLogging:
-			m.subState = SubStateLogging
-			m.subCursor = 0
+			m = m.openSubState(SubStateLogging)
 		case ConfigMenuTheme:
-			m.subState = SubStateTheme
-			m.subCursor = m.themeIndex
-			m.previousTheme = DefaultTheme
+			m = m.openSubState(SubStateTheme)
 		case ConfigMenuAbout:
-			m.subState = SubStateAbout
+			m = m.openSubState(SubStateAbout)
 		case ConfigMenuSaveExit:
 			return m.saveConfig()
 		case ConfigMenuCancel:
-			// Revert theme if it was changed
-			if m.themePreviewDirty {
-				SetTheme(m.previousTheme)
-			}
-			return m, func() tea.Msg { return ConfigCancelledMsg{} }
+			return m.cancel()
 		}
 
 	// ESC does nothing on main menu - must select Cancel
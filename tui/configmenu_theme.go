@@ -10,7 +10,7 @@ import (
 
 // updateTheme handles key events for the Change Theme sub-menu
 func (m ConfigMenuModel) updateTheme(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	themeCount := GetThemeCount()
+	themeCount := len(ListThemesFiltered(m.themeFilter))
 
 	switch {
 	case key.Matches(msg, configMenuKeys.Back):
@@ -33,19 +33,79 @@ func (m ConfigMenuModel) updateTheme(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.previewTheme()
 
+	case key.Matches(msg, configMenuKeys.Filter):
+		// Keep the previewed theme under the cursor if it's still listed
+		slug := m.cursorThemeSlug()
+		m.themeFilter = m.themeFilter.Next()
+		m.subCursor = 0
+		for i, t := range ListThemesFiltered(m.themeFilter) {
+			if t[0] == slug {
+				m.subCursor = i
+				break
+			}
+		}
+		m.previewTheme()
+
 	case key.Matches(msg, configMenuKeys.Select):
 		// Confirm theme selection - just update the index, don't modify config yet
 		// Config will be updated when Save & Exit or Ctrl+S is pressed
-		m.themeIndex = m.subCursor
+		if idx := GetThemeIndex(m.cursorThemeSlug()); idx >= 0 {
+			m.themeIndex = idx
+		}
 		m.themePreviewDirty = true
 		m.subState = SubStateMain
+
+		if m.config.AutoSaveTheme {
+			m.saveTheme()
+		}
 	}
 
 	return m, nil
 }
 
+// saveTheme writes the selected theme to the config file on its own
+// Other pending menu edits aren't in m.config yet, so they aren't saved with it
+func (m *ConfigMenuModel) saveTheme() {
+	slug, _, _ := GetThemeByIndex(m.themeIndex)
+	if slug == "" {
+		return
+	}
+	before := *m.config
+	m.config.Theme = slug
+	if err := m.save(before); err != nil {
+		m.notice = "theme not saved: " + err.Error()
+		return
+	}
+	m.notice = ""
+
+	// The theme is saved now, so cancelling the menu keeps it
+	m.previousTheme = DefaultTheme
+	m.themePreviewDirty = false
+}
+
+// cursorThemeSlug returns the slug of the theme under the cursor in the filtered list
+func (m ConfigMenuModel) cursorThemeSlug() string {
+	themes := ListThemesFiltered(m.themeFilter)
+	if m.subCursor < 0 || m.subCursor >= len(themes) {
+		return ""
+	}
+	return themes[m.subCursor][0]
+}
+
+// themeCursor returns the position of the current theme in the filtered list
+// Returns 0 if the current theme is hidden by the filter
+func (m ConfigMenuModel) themeCursor() int {
+	current, _, _ := GetThemeByIndex(m.themeIndex)
+	for i, t := range ListThemesFiltered(m.themeFilter) {
+		if t[0] == current {
+			return i
+		}
+	}
+	return 0
+}
+
 func (m *ConfigMenuModel) previewTheme() {
-	_, _, theme := GetThemeByIndex(m.subCursor)
+	theme := GetThemeByName(m.cursorThemeSlug())
 	if theme != nil {
 		SetTheme(*theme)
 	}
@@ -63,10 +123,11 @@ func (m ConfigMenuModel) renderTheme() string {
 
 	b.WriteString("\n")
 	b.WriteString("  ")
-	b.WriteString(dimStyle.Render("Use ↑/↓ to preview, Enter to select, Esc to cancel"))
+	b.WriteString(dimStyle.Render("Use ↑/↓ to preview, Enter to select, F to filter, Esc to cancel"))
 	b.WriteString("\n\n")
 
-	themes := ListThemes()
+	themes := ListThemesFiltered(m.themeFilter)
+	currentSlug, _, _ := GetThemeByIndex(m.themeIndex)
 
 	// Calculate visible range (show ~15 themes at a time)
 	visibleCount := 15
@@ -102,19 +163,20 @@ func (m ConfigMenuModel) renderTheme() string {
 
 	for i := startIdx; i < endIdx; i++ {
 		focused := i == m.subCursor
-		_, name := themes[i][0], themes[i][1]
+		slug, name := themes[i][0], themes[i][1]
+		current := slug == currentSlug
 
 		b.WriteString("  ")
 		if focused {
 			b.WriteString(cursorStyle.Render(">"))
 			b.WriteString(" ")
 			b.WriteString(focusedStyle.Render(name))
-			if i == m.themeIndex {
+			if current {
 				b.WriteString(dimStyle.Render(" (current)"))
 			}
 		} else {
 			b.WriteString("  ")
-			if i == m.themeIndex {
+			if current {
 				b.WriteString(labelStyle.Render(name))
 				b.WriteString(dimStyle.Render(" (current)"))
 			} else {
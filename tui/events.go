@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"nbor/types"
+)
+
+// eventKind is the type of a recent-events strip entry
+type eventKind int
+
+const (
+	eventNew eventKind = iota
+	eventStale
+	eventRemoved
+)
+
+// maxRecentEvents is how many events the strip remembers; more than fit on two lines
+const maxRecentEvents = 20
+
+// recentEvent is one discovery, stale or removal event
+type recentEvent struct {
+	kind  eventKind
+	label string
+}
+
+// recentEvents is a small ring of the latest neighbor events, newest last
+// It's a pointer in the model so store callbacks and model copies share it
+type recentEvents struct {
+	events []recentEvent
+}
+
+// add records an event for n, dropping the oldest once the ring is full
+func (r *recentEvents) add(kind eventKind, n *types.Neighbor) {
+	r.events = append(r.events, recentEvent{kind: kind, label: eventLabel(n)})
+	if len(r.events) > maxRecentEvents {
+		r.events = r.events[len(r.events)-maxRecentEvents:]
+	}
+}
+
+// eventLabel names a neighbor for the strip: hostname (or ID, or MAC) and port
+func eventLabel(n *types.Neighbor) string {
+	name := n.Hostname
+	if name == "" {
+		name = n.ID
+	}
+	if name == "" && n.SourceMAC != nil {
+		name = n.SourceMAC.String()
+	}
+	if n.PortID != "" {
+		name += " " + abbreviateInterface(n.PortID)
+	}
+	return name
+}
+
+// watchStore records stale and removal events from the store
+// Both callbacks run from the tick handler, on the same goroutine as the view
+func (r *recentEvents) watchStore(store *types.NeighborStore) {
+	store.OnStale = func(n *types.Neighbor) { r.add(eventStale, n) }
+	store.OnRemove = func(n *types.Neighbor) { r.add(eventRemoved, n) }
+}
+
+// eventStripLines returns how many lines the recent events strip takes (0 = off)
+func (m NeighborTableModel) eventStripLines() int {
+	if m.readOnly || m.config == nil || m.events == nil {
+		return 0
+	}
+	return m.config.EventStripLines
+}
+
+// renderEventStrip renders the most recent events, newest first, across the
+// configured number of lines. Events that don't fit are left off
+func (m NeighborTableModel) renderEventStrip() string {
+	lines := m.eventStripLines()
+	if lines == 0 {
+		return ""
+	}
+
+	theme := DefaultTheme
+	bg := theme.Base00
+	styles := map[eventKind]lipgloss.Style{
+		eventNew:     lipgloss.NewStyle().Foreground(theme.Base0B).Background(bg),
+		eventStale:   lipgloss.NewStyle().Foreground(theme.Base0A).Background(bg),
+		eventRemoved: lipgloss.NewStyle().Foreground(theme.Base08).Background(bg),
+	}
+	symbols := map[eventKind]string{
+		eventNew:     "+",
+		eventStale:   "~",
+		eventRemoved: "−",
+	}
+	dimStyle := lipgloss.NewStyle().Foreground(theme.Base03).Background(bg)
+	sep := dimStyle.Render(" · ")
+
+	// Account for padding (1 on each side)
+	width := m.width - 2
+	rendered := make([][]string, lines)
+	row := 0
+	rowWidth := 0
+	for i := len(m.events.events) - 1; i >= 0 && row < lines; i-- {
+		e := m.events.events[i]
+		text := truncateEvent(symbols[e.kind]+" "+e.label, width)
+		w := lipgloss.Width(text)
+		if rowWidth > 0 {
+			w += lipgloss.Width(sep)
+		}
+		if rowWidth+w > width {
+			row++
+			rowWidth = 0
+			w = lipgloss.Width(text)
+			if row >= lines {
+				break
+			}
+		}
+		rendered[row] = append(rendered[row], styles[e.kind].Render(text))
+		rowWidth += w
+	}
+
+	if len(m.events.events) == 0 {
+		rendered[0] = []string{dimStyle.Render("No neighbor events yet")}
+	}
+
+	lineStyle := lipgloss.NewStyle().
+		Background(bg).
+		Padding(0, 1).
+		Width(m.width)
+	out := make([]string, lines)
+	for i := range out {
+		out[i] = lineStyle.Render(strings.Join(rendered[i], sep))
+	}
+	return strings.Join(out, "\n")
+}
+
+// truncateEvent shortens an event that is wider than the whole strip
+func truncateEvent(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	return truncate(s, width)
+}
@@ -0,0 +1,235 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"nbor/config"
+	"nbor/sqlite"
+	"nbor/types"
+)
+
+// GoToHistoryMsg signals to open the seen devices (history) view
+type GoToHistoryMsg struct{}
+
+// HistoryClosedMsg signals that the history view was closed
+type HistoryClosedMsg struct{}
+
+// HistoryLoadedMsg carries devices read from the SQLite database
+type HistoryLoadedMsg struct {
+	Devices []*types.Neighbor
+	Err     error
+}
+
+// historyKeys are the extra bindings used by the read-only history view
+var historyKeys = struct {
+	Search key.Binding
+	Open   key.Binding
+}{
+	Search: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search"),
+	),
+	Open: key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "seen devices"),
+	),
+}
+
+// NewHistoryTable creates a read-only neighbor table for devices recorded in the
+// SQLite database. Devices are added once HistoryLoadedMsg arrives
+func NewHistoryTable(cfg *config.Config) NeighborTableModel {
+	search := textinput.New()
+	search.Placeholder = "hostname, MAC or IP"
+	search.Prompt = "/"
+	search.CharLimit = 64
+	search.Width = 30
+
+	m := NewNeighborTable(types.NewNeighborStore(), types.InterfaceInfo{}, "", cfg)
+	m.readOnly = true
+	m.historyPath = cfg.SQLitePath
+	m.historyLoading = cfg.SQLitePath != ""
+	m.search = search
+	return m
+}
+
+// loadHistoryCmd reads the seen devices from the database in the background
+func loadHistoryCmd(path string) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		devices, err := sqlite.LoadDevices(path)
+		return HistoryLoadedMsg{Devices: devices, Err: err}
+	}
+}
+
+// setHistory fills the read-only store with loaded devices
+// First-seen times come from the database rather than the store
+func (m *NeighborTableModel) setHistory(msg HistoryLoadedMsg) {
+	m.historyLoading = false
+	m.historyErr = msg.Err
+	for _, n := range msg.Devices {
+		firstSeen := n.FirstSeen
+		m.store.Update(n)
+		n.FirstSeen = firstSeen
+	}
+	m.store.ClearNewFlags()
+}
+
+// updateSearch handles key events while typing a history search
+func (m NeighborTableModel) updateSearch(msg tea.KeyMsg) (NeighborTableModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		// Cancel the search entirely
+		m.searching = false
+		m.search.Blur()
+		m.search.SetValue("")
+	case "enter":
+		// Keep the query and return to the table
+		m.searching = false
+		m.search.Blur()
+		return m, nil
+	default:
+		var cmd tea.Cmd
+		m.search, cmd = m.search.Update(msg)
+		m.selectedIndex = 0
+		m.scrollOffset = 0
+		return m, cmd
+	}
+	m.selectedIndex = 0
+	m.scrollOffset = 0
+	return m, nil
+}
+
+// matchesSearch reports whether a neighbor's hostname, MAC or IP contains the search query
+// Matching is case-insensitive and an empty query matches everything
+func (m *NeighborTableModel) matchesSearch(n *types.Neighbor) bool {
+	query := strings.ToLower(strings.TrimSpace(m.search.Value()))
+	if !m.readOnly || query == "" {
+		return true
+	}
+
+	fields := []string{n.Hostname, n.ID}
+	if n.SourceMAC != nil {
+		fields = append(fields, n.SourceMAC.String())
+	}
+	if n.ManagementIP != nil {
+		fields = append(fields, n.ManagementIP.String())
+	}
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// historyMessage returns the text shown when the history view has no rows
+func (m NeighborTableModel) historyMessage() string {
+	switch {
+	case m.historyPath == "":
+		return "History is off - set sqlite_path in config.toml to record seen devices"
+	case m.historyLoading:
+		return "Loading " + m.historyPath + "..."
+	case m.historyErr != nil:
+		return "Couldn't read history: " + m.historyErr.Error()
+	case m.search.Value() != "":
+		return "No recorded devices match \"" + m.search.Value() + "\""
+	}
+	return "No devices recorded in " + m.historyPath + " yet"
+}
+
+// InterfaceSummaryMsg carries the per-interface capability summaries shown in the picker
+type InterfaceSummaryMsg struct {
+	Summaries map[string]string
+}
+
+// loadInterfaceSummaryCmd reads the recorded devices and summarizes them per interface
+// Errors are ignored; the picker simply shows no summaries
+func loadInterfaceSummaryCmd(path string) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		devices, err := sqlite.LoadDevices(path)
+		if err != nil {
+			return nil
+		}
+		return InterfaceSummaryMsg{Summaries: summarizeInterfaces(devices)}
+	}
+}
+
+// capabilityNouns are the singular and plural words used in picker summaries
+var capabilityNouns = map[types.Capability][2]string{
+	types.CapRouter:      {"router", "routers"},
+	types.CapSwitch:      {"switch", "switches"},
+	types.CapBridge:      {"bridge", "bridges"},
+	types.CapAccessPoint: {"AP", "APs"},
+	types.CapPhone:       {"phone", "phones"},
+	types.CapDocsis:      {"DOCSIS device", "DOCSIS devices"},
+	types.CapStation:     {"station", "stations"},
+	types.CapRepeater:    {"repeater", "repeaters"},
+	types.CapOther:       {"other", "other"},
+}
+
+// summarizeInterfaces counts devices per interface and capability, e.g.
+// "eth0" -> "8 switches, 2 routers". Devices count toward each capability they
+// advertise; devices without any are counted as "other"
+func summarizeInterfaces(devices []*types.Neighbor) map[string]string {
+	counts := make(map[string]map[types.Capability]int)
+	for _, n := range devices {
+		if n.Interface == "" {
+			continue
+		}
+		if counts[n.Interface] == nil {
+			counts[n.Interface] = make(map[types.Capability]int)
+		}
+		if len(n.Capabilities) == 0 {
+			counts[n.Interface][types.CapOther]++
+			continue
+		}
+		for _, c := range n.Capabilities {
+			counts[n.Interface][c]++
+		}
+	}
+
+	summaries := make(map[string]string, len(counts))
+	for iface, byCap := range counts {
+		caps := make([]types.Capability, 0, len(byCap))
+		for c := range byCap {
+			caps = append(caps, c)
+		}
+		// Most common first, then alphabetical for a stable order
+		sort.Slice(caps, func(i, j int) bool {
+			if byCap[caps[i]] != byCap[caps[j]] {
+				return byCap[caps[i]] > byCap[caps[j]]
+			}
+			return caps[i] < caps[j]
+		})
+
+		parts := make([]string, len(caps))
+		for i, c := range caps {
+			parts[i] = fmt.Sprintf("%d %s", byCap[c], capabilityNoun(c, byCap[c]))
+		}
+		summaries[iface] = strings.Join(parts, ", ")
+	}
+	return summaries
+}
+
+// capabilityNoun returns the word for count devices with capability c
+func capabilityNoun(c types.Capability, count int) string {
+	nouns, ok := capabilityNouns[c]
+	if !ok {
+		nouns = [2]string{strings.ToLower(string(c)), strings.ToLower(string(c))}
+	}
+	if count == 1 {
+		return nouns[0]
+	}
+	return nouns[1]
+}
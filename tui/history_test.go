@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"nbor/config"
+	"nbor/types"
+)
+
+func TestHistoryTableSearch(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SQLitePath = "nbor.db"
+
+	m := NewHistoryTable(&cfg)
+	m.width = 120
+	m.height = 30
+
+	first := time.Now().Add(-48 * time.Hour)
+	var devices []*types.Neighbor
+	for i, host := range []string{"core-sw1", "edge-rtr1"} {
+		mac, _ := net.ParseMAC(fmt.Sprintf("00:11:22:33:44:%02x", i))
+		devices = append(devices, &types.Neighbor{
+			Hostname:     host,
+			PortID:       "Gi0/1",
+			ManagementIP: net.ParseIP(fmt.Sprintf("10.0.0.%d", i+1)),
+			SourceMAC:    mac,
+			Interface:    "eth0",
+			FirstSeen:    first,
+			LastSeen:     time.Now().Add(-time.Hour),
+		})
+	}
+	m.setHistory(HistoryLoadedMsg{Devices: devices})
+
+	if got := len(m.getFilteredNeighbors()); got != 2 {
+		t.Fatalf("getFilteredNeighbors() returned %d rows, want 2", got)
+	}
+	if n := m.getFilteredNeighbors()[0]; !n.FirstSeen.Equal(first) || n.IsNew {
+		t.Errorf("history neighbor FirstSeen = %v, IsNew = %v; want %v, false", n.FirstSeen, n.IsNew, first)
+	}
+	if view := m.View(); !strings.Contains(view, "HISTORY") {
+		t.Errorf("history view should be marked as historical:\n%s", view)
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"core", 1},
+		{"10.0.0.2", 1},
+		{"00:11:22:33:44:00", 1},
+		{"EDGE", 1},
+		{"10.0.0", 2},
+		{"nomatch", 0},
+	}
+	for _, tt := range tests {
+		m.search.SetValue(tt.query)
+		if got := len(m.getFilteredNeighbors()); got != tt.want {
+			t.Errorf("search %q matched %d devices, want %d", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizeInterfaces(t *testing.T) {
+	devices := []*types.Neighbor{
+		{Interface: "eth0", Capabilities: []types.Capability{types.CapSwitch}},
+		{Interface: "eth0", Capabilities: []types.Capability{types.CapSwitch}},
+		{Interface: "eth0", Capabilities: []types.Capability{types.CapRouter, types.CapSwitch}},
+		{Interface: "eth1", Capabilities: []types.Capability{types.CapPhone}},
+		{Interface: "eth1"},
+	}
+
+	summaries := summarizeInterfaces(devices)
+	if got, want := summaries["eth0"], "3 switches, 1 router"; got != want {
+		t.Errorf("eth0 summary = %q, want %q", got, want)
+	}
+	if got, want := summaries["eth1"], "1 other, 1 phone"; got != want {
+		t.Errorf("eth1 summary = %q, want %q", got, want)
+	}
+
+	// Summaries appear under the matching interface in the picker
+	picker := NewInterfacePicker([]types.InterfaceInfo{{Name: "eth0", IsUp: true}, {Name: "eth2", IsUp: true}})
+	updated, _ := picker.Update(InterfaceSummaryMsg{Summaries: summaries})
+	content := updated.(InterfacePickerModel).renderContent()
+	if !strings.Contains(content, "3 switches, 1 router") {
+		t.Error("picker doesn't show the eth0 summary")
+	}
+	if strings.Contains(content, "phone") {
+		t.Error("picker shows a summary for an interface that isn't listed")
+	}
+}
@@ -21,6 +21,9 @@ type InterfacePickerModel struct {
 	height     int
 	styles     Styles
 	err        error
+
+	// What was last seen on each interface, keyed by interface name (from SQLite history)
+	summaries map[string]string
 }
 
 // NewInterfacePicker creates a new interface picker model
@@ -36,10 +39,9 @@ func NewInterfacePicker(interfaces []types.InterfaceInfo) InterfacePickerModel {
 }
 
 // sortInterfaces sorts interfaces by priority:
-// 1. Up with IPv4 address
-// 2. Up with IPv6 (non-link-local) address
-// 3. Up without IP
-// 4. Down
+// 1. Up with an IPv4 or global IPv6 address
+// 2. Up without IP
+// 3. Down
 func sortInterfaces(interfaces []types.InterfaceInfo) {
 	sort.Slice(interfaces, func(i, j int) bool {
 		// Calculate priority score (lower is better)
@@ -56,20 +58,17 @@ func sortInterfaces(interfaces []types.InterfaceInfo) {
 }
 
 // interfacePriority returns a priority score for sorting (lower = higher priority)
+// A global IPv6 address counts the same as IPv4, so v6-only labs sort up too
 func interfacePriority(iface types.InterfaceInfo) int {
 	if !iface.IsUp {
 		return 100 // Down interfaces last
 	}
 
-	if len(iface.IPv4Addrs) > 0 {
-		return 0 // Up with IPv4 = highest priority
-	}
-
-	if len(iface.IPv6Addrs) > 0 {
-		return 10 // Up with IPv6 = second priority
+	if len(iface.IPv4Addrs) > 0 || iface.HasGlobalIPv6() {
+		return 0 // Up with IP = highest priority
 	}
 
-	return 50 // Up without IP = third priority
+	return 50 // Up without IP = second priority
 }
 
 // Init initializes the interface picker
@@ -84,10 +83,11 @@ type InterfaceSelectedMsg struct {
 
 // interfacePickerKeyMap defines the key bindings for the interface picker
 type interfacePickerKeyMap struct {
-	Up     key.Binding
-	Down   key.Binding
-	Select key.Binding
-	Quit   key.Binding
+	Up      key.Binding
+	Down    key.Binding
+	Select  key.Binding
+	History key.Binding
+	Quit    key.Binding
 }
 
 var interfaceKeys = interfacePickerKeyMap{
@@ -103,6 +103,10 @@ var interfaceKeys = interfacePickerKeyMap{
 		key.WithKeys("enter", " "),
 		key.WithHelp("enter", "select"),
 	),
+	History: key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "seen devices"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("ctrl+c", "q"),
 		key.WithHelp("ctrl+c/q", "quit"),
@@ -128,10 +132,17 @@ func (m InterfacePickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return InterfaceSelectedMsg{Interface: m.interfaces[m.cursor]}
 				}
 			}
+		case key.Matches(msg, interfaceKeys.History):
+			return m, func() tea.Msg {
+				return GoToHistoryMsg{}
+			}
 		case key.Matches(msg, interfaceKeys.Quit):
 			return m, tea.Quit
 		}
 
+	case InterfaceSummaryMsg:
+		m.summaries = msg.Summaries
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -249,6 +260,9 @@ func (m InterfacePickerModel) renderContent() string {
 		var status string
 		if iface.IsUp {
 			status = upStyle.Render("●")
+		} else if monochrome {
+			// Down is only grayed out otherwise
+			status = downStyle.Render("○")
 		} else {
 			status = downStyle.Render("●")
 		}
@@ -265,12 +279,17 @@ func (m InterfacePickerModel) renderContent() string {
 			speed = fmt.Sprintf("[%s]", iface.Speed)
 		}
 
-		// Format IP addresses
+		// Format IP addresses, shown brighter on up interfaces since they're
+		// how an interface is usually recognized - a v6-only one included
 		ips := iface.FormatIPs()
 		ipDisplay := ""
 		if ips != "" {
 			ipDisplay = fmt.Sprintf("(%s)", ips)
 		}
+		ipStyle := dimStyle
+		if iface.IsUp {
+			ipStyle = normalStyle
+		}
 
 		if i == m.cursor {
 			b.WriteString("  ")
@@ -287,7 +306,7 @@ func (m InterfacePickerModel) renderContent() string {
 			}
 			if ipDisplay != "" {
 				b.WriteString(" ")
-				b.WriteString(dimStyle.Render(ipDisplay))
+				b.WriteString(ipStyle.Render(ipDisplay))
 			}
 		} else {
 			b.WriteString("    ")
@@ -302,10 +321,17 @@ func (m InterfacePickerModel) renderContent() string {
 			}
 			if ipDisplay != "" {
 				b.WriteString(" ")
-				b.WriteString(dimStyle.Render(ipDisplay))
+				b.WriteString(ipStyle.Render(ipDisplay))
 			}
 		}
 		b.WriteString("\n")
+
+		// Last-seen summary from history, aligned under the interface name
+		if summary := m.summaries[iface.Name]; summary != "" {
+			b.WriteString("      ")
+			b.WriteString(dimStyle.Render(summary))
+			b.WriteString("\n")
+		}
 	}
 
 	return b.String()
@@ -331,6 +357,7 @@ func (m InterfacePickerModel) renderFooter() string {
 
 	footerContent := keyStyle.Render("↑/↓") + textStyle.Render(" navigate") + sep +
 		keyStyle.Render("enter") + textStyle.Render(" select") + sep +
+		keyStyle.Render("h") + textStyle.Render(" seen devices") + sep +
 		keyStyle.Render("q") + textStyle.Render(" quit")
 
 	contentLen := lipgloss.Width(footerContent)
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"net"
+	"testing"
+
+	"nbor/types"
+)
+
+func TestInterfacePriorityIPv6Only(t *testing.T) {
+	v4 := []net.IP{net.ParseIP("192.0.2.10").To4()}
+	global := []net.IP{net.ParseIP("2001:db8::10")}
+	ula := []net.IP{net.ParseIP("fd00::10")}
+
+	tests := []struct {
+		name  string
+		iface types.InterfaceInfo
+		want  int
+	}{
+		{"IPv4", types.InterfaceInfo{IsUp: true, IPv4Addrs: v4}, 0},
+		{"global IPv6 only", types.InterfaceInfo{IsUp: true, IPv6Addrs: global}, 0},
+		{"unique local IPv6 only", types.InterfaceInfo{IsUp: true, IPv6Addrs: ula}, 0},
+		{"dual stack", types.InterfaceInfo{IsUp: true, IPv4Addrs: v4, IPv6Addrs: global}, 0},
+		{"no IP", types.InterfaceInfo{IsUp: true}, 50},
+		{"down with IPv6", types.InterfaceInfo{IPv6Addrs: global}, 100},
+	}
+	for _, tt := range tests {
+		if got := interfacePriority(tt.iface); got != tt.want {
+			t.Errorf("%s: interfacePriority = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSortInterfacesIPv6Only(t *testing.T) {
+	interfaces := []types.InterfaceInfo{
+		{Name: "eth0", IsUp: true},
+		{Name: "eth1", IsUp: true, IPv6Addrs: []net.IP{net.ParseIP("2001:db8::1")}},
+		{Name: "eth2", IsUp: false, IPv4Addrs: []net.IP{net.ParseIP("192.0.2.2").To4()}},
+		{Name: "eth3", IsUp: true, IPv4Addrs: []net.IP{net.ParseIP("192.0.2.3").To4()}},
+	}
+	sortInterfaces(interfaces)
+
+	want := []string{"eth1", "eth3", "eth0", "eth2"}
+	for i, name := range want {
+		if interfaces[i].Name != name {
+			t.Fatalf("sorted order = %v, want %v", interfaceNames(interfaces), want)
+		}
+	}
+}
+
+func interfaceNames(interfaces []types.InterfaceInfo) []string {
+	names := make([]string, len(interfaces))
+	for i, iface := range interfaces {
+		names[i] = iface.Name
+	}
+	return names
+}
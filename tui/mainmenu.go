@@ -16,6 +16,7 @@ type MainMenuItem int
 const (
 	MenuItemStartCapture MainMenuItem = iota
 	MenuItemConfiguration
+	MenuItemHistory
 	MenuItemQuit
 )
 
@@ -35,6 +36,7 @@ func NewMainMenu() MainMenuModel {
 		items: []MainMenuItem{
 			MenuItemStartCapture,
 			MenuItemConfiguration,
+			MenuItemHistory,
 			MenuItemQuit,
 		},
 		styles: DefaultStyles,
@@ -77,7 +79,10 @@ var mainMenuKeys = mainMenuKeyMap{
 type GoToInterfacePickerMsg struct{}
 
 // GoToConfigMenuMsg signals to navigate to config menu
-type GoToConfigMenuMsg struct{}
+// SubState opens a sub-menu directly; the zero value opens the main menu
+type GoToConfigMenuMsg struct {
+	SubState ConfigSubState
+}
 
 // Update handles messages for the main menu
 func (m MainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -117,6 +122,10 @@ func (m MainMenuModel) handleSelect() (tea.Model, tea.Cmd) {
 		return m, func() tea.Msg {
 			return GoToConfigMenuMsg{}
 		}
+	case MenuItemHistory:
+		return m, func() tea.Msg {
+			return GoToHistoryMsg{}
+		}
 	case MenuItemQuit:
 		return m, tea.Quit
 	}
@@ -205,12 +214,14 @@ func (m MainMenuModel) renderContent() string {
 	menuLabels := map[MainMenuItem]string{
 		MenuItemStartCapture:  "Start Capturing",
 		MenuItemConfiguration: "Configuration",
+		MenuItemHistory:       "Seen Devices",
 		MenuItemQuit:          "Quit",
 	}
 
 	menuDescriptions := map[MainMenuItem]string{
 		MenuItemStartCapture:  "Select an interface and listen for neighbors",
 		MenuItemConfiguration: "Configure listening, broadcasting, and identity",
+		MenuItemHistory:       "Browse every device recorded in the SQLite history",
 		MenuItemQuit:          "Exit the application",
 	}
 
@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"runtime"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+// PingFinishedMsg is sent when the ping started from the detail view exits
+type PingFinishedMsg struct {
+	IP  net.IP
+	Err error
+}
+
+// copyToClipboard copies text using the terminal's OSC 52 clipboard sequence,
+// which also works over SSH. Terminals without OSC 52 support ignore it
+func copyToClipboard(text string) tea.Cmd {
+	return func() tea.Msg {
+		termenv.Copy(text)
+		return nil
+	}
+}
+
+// managementURL is the web UI address for a management IP, bracketing IPv6
+func managementURL(ip net.IP) string {
+	if ip.To4() == nil {
+		return "http://[" + ip.String() + "]"
+	}
+	return "http://" + ip.String()
+}
+
+// pingCommand builds the command that pings ip count times and then waits for a
+// key press, so the output stays readable before the TUI comes back
+// The IP is passed as an argument rather than spliced into the shell script
+func pingCommand(goos string, ip net.IP, count int) *exec.Cmd {
+	n := strconv.Itoa(count)
+	if goos == "windows" {
+		return exec.Command("cmd", "/c", "ping", "-n", n, ip.String(), "&", "pause")
+	}
+	script := `ping -c "$1" "$2"; printf '\nPress Enter to return to nbor'; read _`
+	return exec.Command("sh", "-c", script, "sh", n, ip.String())
+}
+
+// pingCmd suspends the TUI and pings ip in the terminal
+func pingCmd(ip net.IP, count int) tea.Cmd {
+	return tea.ExecProcess(pingCommand(runtime.GOOS, ip, count), func(err error) tea.Msg {
+		return PingFinishedMsg{IP: ip, Err: err}
+	})
+}
+
+// pingNotice describes how a ping run ended, for the footer
+func pingNotice(msg PingFinishedMsg) string {
+	if msg.Err != nil {
+		return fmt.Sprintf("ping %s: %v", msg.IP, msg.Err)
+	}
+	return fmt.Sprintf("pinged %s", msg.IP)
+}
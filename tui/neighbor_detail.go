@@ -2,14 +2,21 @@ package tui
 
 import (
 	"fmt"
+	"net"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 
+	"nbor/oui"
 	"nbor/types"
 )
 
+// detailPopupBaseLines is the popup height (including border) when no field wraps
+const detailPopupBaseLines = 19
+
 // renderDetailPopup renders a centered popup in the content area
 func (m NeighborTableModel) renderDetailPopup(n *types.Neighbor, contentHeight int) string {
 	theme := DefaultTheme
@@ -100,9 +107,57 @@ func (m NeighborTableModel) renderDetailPopup(n *types.Neighbor, contentHeight i
 		b.WriteString("\n")
 	}
 
+	// Lines left over for wrapped fields once every row has its first line
+	note := m.neighborNote(n)
+	extraLines := contentHeight - detailPopupBaseLines - detailOptionalRows(n)
+	if note != "" {
+		extraLines--
+	}
+	if m.showsPTR(n) {
+		extraLines--
+	}
+	if m.showAdvanced {
+		extraLines -= 4
+	}
+	invRows := inventoryRows(n.Inventory)
+	if m.showInventory && len(invRows) > 1 {
+		extraLines -= len(invRows) - 1 // Beyond the collapsed row
+	}
+
+	// Helper to render a long field, wrapping or truncating per config
+	valueWidth := contentWidth - 15
+	renderLongRow := func(field, label, value string) {
+		if !m.wrapsDetailField(field) || extraLines <= 0 {
+			renderRow(label, truncateValue(firstLine(value), valueWidth))
+			return
+		}
+		lines := wrapText(value, valueWidth)
+		if len(lines) == 0 {
+			renderRow(label, "")
+			return
+		}
+		// Keep within the remaining height; the last kept line gets the ellipsis
+		if maxLines := 1 + extraLines; len(lines) > maxLines {
+			lines[maxLines-1] = truncateValue(strings.Join(lines[maxLines-1:], " "), valueWidth)
+			lines = lines[:maxLines]
+		}
+		extraLines -= len(lines) - 1
+		renderRow(label, lines[0])
+		for _, line := range lines[1:] {
+			renderRow("", line)
+		}
+	}
+
 	// Device Identity
+	if note != "" {
+		renderLongRow("note", "Note:", note)
+	}
 	renderRow("Device ID:", n.ID)
-	renderRow("Port:", formatPortInfo(n))
+	if ports, ok := m.stackedPorts()[stackKey(n)]; ok {
+		renderLongRow("ports", "Ports:", strings.Join(ports, ", "))
+	} else {
+		renderRow("Port:", formatPortInfo(n))
+	}
 	renderRow("Protocol:", string(n.Protocol))
 
 	// Network Info
@@ -111,30 +166,107 @@ func (m NeighborTableModel) renderDetailPopup(n *types.Neighbor, contentHeight i
 		mgmtIP = n.ManagementIP.String()
 	}
 	renderRow("Mgmt IP:", mgmtIP)
+	if m.showsPTR(n) {
+		renderRow("PTR:", truncateValue(m.ptrName(n), valueWidth))
+	}
+	if len(n.AllAddresses) > 1 {
+		renderRow("Addresses:", formatAddresses(n.AllAddresses))
+	}
 
 	srcMAC := ""
 	if n.SourceMAC != nil {
 		srcMAC = n.SourceMAC.String()
 	}
 	renderRow("Source MAC:", srcMAC)
+	if vendor := neighborVendor(n); vendor != "" {
+		renderRow("Vendor:", truncateValue(vendor, valueWidth))
+	}
+	if n.NativeVLAN != 0 {
+		renderRow("Native VLAN:", strconv.Itoa(n.NativeVLAN))
+	}
+	if n.PortVLAN != 0 || n.VLANName != "" {
+		renderRow("Port VLAN:", formatPortVLAN(n))
+	}
+	if n.VTPDomain != "" {
+		renderRow("VTP Domain:", n.VTPDomain)
+	}
+	if n.MEDPolicy != nil {
+		renderRow(formatMEDPolicy(n.MEDPolicy))
+	}
+	if n.PowerMW != 0 {
+		renderRow("PoE:", fmt.Sprintf("%d mW", n.PowerMW))
+	}
+	// The LLDP link mode already includes the duplex
+	switch {
+	case n.Speed != "":
+		renderRow("Link:", n.Speed)
+	case n.Duplex == "half":
+		renderRow("Duplex:", "half (check for a mismatch)")
+	case n.Duplex == "full":
+		renderRow("Duplex:", "full")
+	}
 
 	// Platform Info
-	renderRow("Platform:", truncateValue(n.Platform, contentWidth-15))
-	renderRow("Description:", truncateValue(n.Description, contentWidth-15))
-	renderRow("Location:", truncateValue(n.Location, contentWidth-15))
+	renderLongRow("platform", "Platform:", n.Platform)
+	renderLongRow("description", "Description:", n.Description)
+	renderLongRow("location", "Location:", n.Location)
 
-	// Capabilities
+	// Capabilities, split into enabled and supported when the device supports more
+	// than it has enabled
 	caps := formatCapabilitiesList(n.Capabilities)
-	renderRow("Capabilities:", caps)
+	if hasDisabledCapabilities(n) {
+		renderRow("Enabled:", caps)
+		renderRow("Supported:", formatCapabilitiesList(n.SupportedCapabilities))
+	} else {
+		renderRow("Capabilities:", caps)
+	}
+
+	// LLDP-MED inventory, collapsed to one row until i is pressed
+	if n.Inventory != nil {
+		if m.showInventory && len(invRows) > 0 {
+			for _, row := range invRows {
+				renderRow(row[0], row[1])
+			}
+		} else {
+			renderRow("Inventory:", "i to show")
+		}
+	}
 
 	// Timing Info
 	renderRow("First Seen:", formatTime(n.FirstSeen))
+	renderRow("Discovered:", formatLastSeen(n.FirstSeen))
 	renderRow("Last Seen:", formatLastSeen(n.LastSeen))
-	renderRow("Interface:", n.Interface)
+	if n.TTL > 0 {
+		renderRow("TTL:", formatTTL(n.TTL, n.LastSeen, time.Now()))
+	}
+	if len(n.Interfaces) > 1 {
+		renderRow("Interfaces:", strings.Join(n.Interfaces, ", "))
+	} else {
+		renderRow("Interface:", n.Interface)
+	}
+
+	// Advanced: raw protocol values for debugging
+	if m.showAdvanced {
+		b.WriteString(separatorStyle.Render(strings.Repeat("─", contentWidth)))
+		b.WriteString("\n")
+		renderRow("CDP Caps:", formatCapabilityBits(n.SeenCDP, n.CDPCapabilityBits, 8))
+		renderRow("LLDP Caps:", formatCapabilityBits(n.SeenLLDP, uint32(n.LLDPCapabilityBits), 4))
+		renderRow("Frame Len:", formatFrameLengths(n))
+	}
 
 	b.WriteString(blankLineStyle.Render(""))
 	b.WriteString("\n")
-	b.WriteString(hintStyle.Render("ESC to close"))
+	switch {
+	case n.ManagementIP != nil && m.showAdvanced:
+		// Shorter wording to make room for the IP actions
+		b.WriteString(hintStyle.Render("ESC close · a hide adv · y copy · p ping · u QR"))
+	case n.ManagementIP != nil:
+		b.WriteString(hintStyle.Render("ESC close · a adv · y copy · p ping · u QR"))
+	case m.showAdvanced:
+		b.WriteString(hintStyle.Render("ESC to close · a to hide advanced"))
+	default:
+		b.WriteString(hintStyle.Render("ESC to close · a for advanced"))
+	}
 
 	// Apply border style
 	borderStyle := lipgloss.NewStyle().
@@ -159,6 +291,113 @@ func (m NeighborTableModel) renderDetailPopup(n *types.Neighbor, contentHeight i
 	)
 }
 
+// renderQRPopup renders the neighbor's management URL as a QR code, centered in
+// the content area like the detail popup
+func (m NeighborTableModel) renderQRPopup(n *types.Neighbor, contentHeight int) string {
+	theme := DefaultTheme
+	bg := theme.Base00
+
+	url := managementURL(n.ManagementIP)
+	textStyle := lipgloss.NewStyle().Foreground(theme.Base05).Background(bg)
+	hintStyle := lipgloss.NewStyle().Foreground(theme.Base03).Background(bg)
+
+	// QR codes scan as dark on light, whatever the theme, so the code itself
+	// always gets a black-on-white style
+	var body string
+	code, err := renderQR(url)
+	if err != nil {
+		body = lipgloss.NewStyle().Foreground(theme.Base08).Background(bg).Render(err.Error())
+	} else {
+		codeStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#000000")).
+			Background(lipgloss.Color("#ffffff"))
+		lines := strings.Split(code, "\n")
+		for i, line := range lines {
+			lines[i] = codeStyle.Render(line)
+		}
+		body = strings.Join(lines, "\n")
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		body,
+		textStyle.Render(url),
+		hintStyle.Render("ESC or u to close"),
+	)
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Base0D).
+		BorderBackground(bg).
+		Background(bg).
+		Padding(0, 1)
+
+	return lipgloss.Place(
+		m.width,
+		contentHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		borderStyle.Render(content),
+		lipgloss.WithWhitespaceBackground(bg),
+	)
+}
+
+// neighborVLAN returns the VLAN shown in the table: the LLDP port VLAN, else
+// the CDP native VLAN (0 = not advertised)
+func neighborVLAN(n *types.Neighbor) int {
+	if n.PortVLAN != 0 {
+		return n.PortVLAN
+	}
+	return n.NativeVLAN
+}
+
+// neighborVendor names the vendor of n's source MAC, falling back to a MAC
+// chassis ID when the source MAC's OUI isn't known or was locally administered
+// (LLDP uses the chassis MAC as the ID). "" when neither is in the OUI table
+func neighborVendor(n *types.Neighbor) string {
+	vendor := oui.Lookup(n.SourceMAC)
+	if vendor != "" && vendor != oui.LocallyAdministered {
+		return vendor
+	}
+	if chassis, err := net.ParseMAC(n.ID); err == nil {
+		if v := oui.Lookup(chassis); v != "" && v != oui.LocallyAdministered {
+			return v
+		}
+	}
+	return vendor
+}
+
+// formatPortVLAN formats the LLDP port VLAN as "10 (users)"
+func formatPortVLAN(n *types.Neighbor) string {
+	switch {
+	case n.PortVLAN == 0:
+		return n.VLANName
+	case n.VLANName == "":
+		return strconv.Itoa(n.PortVLAN)
+	}
+	return fmt.Sprintf("%d (%s)", n.PortVLAN, n.VLANName)
+}
+
+// formatMEDPolicy returns the detail row label and value for an LLDP-MED network
+// policy, e.g. "Voice VLAN:" and "200 (DSCP 46)"
+func formatMEDPolicy(p *types.MEDPolicy) (string, string) {
+	label := "Policy VLAN:"
+	if p.Application == 1 {
+		label = "Voice VLAN:"
+	}
+	if p.Unknown {
+		return label, "policy unknown"
+	}
+
+	vlan := strconv.Itoa(p.VLAN)
+	if !p.Tagged {
+		vlan += " untagged"
+	}
+	if p.Application == 1 {
+		return label, fmt.Sprintf("%s (DSCP %d)", vlan, p.DSCP)
+	}
+	return label, fmt.Sprintf("%s (%s, DSCP %d)", vlan, p.ApplicationName(), p.DSCP)
+}
+
 // formatPortInfo formats port ID and description
 func formatPortInfo(n *types.Neighbor) string {
 	if n.PortDescription != "" && n.PortDescription != n.PortID {
@@ -167,6 +406,89 @@ func formatPortInfo(n *types.Neighbor) string {
 	return n.PortID
 }
 
+// detailOptionalRows returns how many rows the popup shows for n beyond the
+// ones every neighbor gets
+func detailOptionalRows(n *types.Neighbor) int {
+	rows := 0
+	if neighborVendor(n) != "" {
+		rows++
+	}
+	if len(n.AllAddresses) > 1 {
+		rows++
+	}
+	if n.NativeVLAN != 0 {
+		rows++
+	}
+	if n.PortVLAN != 0 || n.VLANName != "" {
+		rows++
+	}
+	if n.VTPDomain != "" {
+		rows++
+	}
+	if n.MEDPolicy != nil {
+		rows++
+	}
+	if n.PowerMW != 0 {
+		rows++
+	}
+	if n.Speed != "" || n.Duplex != "" {
+		rows++ // Link or duplex
+	}
+	if hasDisabledCapabilities(n) {
+		rows++ // Supported capabilities
+	}
+	if n.TTL > 0 {
+		rows++
+	}
+	if n.Inventory != nil {
+		rows++ // Collapsed inventory
+	}
+	return rows
+}
+
+// inventoryRows returns the label and value of each inventory field the
+// device advertised, for the expanded inventory section
+func inventoryRows(inv *types.Inventory) [][2]string {
+	if inv == nil {
+		return nil
+	}
+	var rows [][2]string
+	for _, f := range [][2]string{
+		{"Serial:", inv.SerialNumber},
+		{"Model:", inv.Model},
+		{"Manufacturer:", inv.Manufacturer},
+		{"Hardware Rev:", inv.HardwareRevision},
+		{"Firmware Rev:", inv.FirmwareRevision},
+		{"Software Rev:", inv.SoftwareRevision},
+		{"Asset ID:", inv.AssetID},
+	} {
+		if f[1] != "" {
+			rows = append(rows, f)
+		}
+	}
+	return rows
+}
+
+// formatTTL formats the advertised TTL with how long until it runs out, counted
+// from the last announcement: "120s, expires in 85s" or "120s, expired 5s ago"
+func formatTTL(ttl time.Duration, lastSeen, now time.Time) string {
+	left := lastSeen.Add(ttl).Sub(now).Truncate(time.Second)
+	if left < 0 {
+		return fmt.Sprintf("%ds, expired %ds ago", int(ttl.Seconds()), int(-left.Seconds()))
+	}
+	return fmt.Sprintf("%ds, expires in %ds", int(ttl.Seconds()), int(left.Seconds()))
+}
+
+// hasDisabledCapabilities returns whether n supports capabilities it doesn't have enabled
+func hasDisabledCapabilities(n *types.Neighbor) bool {
+	for _, sc := range n.SupportedCapabilities {
+		if !slices.Contains(n.Capabilities, sc) {
+			return true
+		}
+	}
+	return false
+}
+
 // formatCapabilitiesList formats capabilities as a comma-separated string
 func formatCapabilitiesList(caps []types.Capability) string {
 	if len(caps) == 0 {
@@ -179,6 +501,35 @@ func formatCapabilitiesList(caps []types.Capability) string {
 	return strings.Join(strs, ", ")
 }
 
+// formatCapabilityBits formats raw capability bits as zero-padded hex
+// Returns empty if the protocol hasn't been seen from this neighbor
+func formatCapabilityBits(seen bool, bits uint32, digits int) string {
+	if !seen {
+		return ""
+	}
+	return fmt.Sprintf("0x%0*X", digits, bits)
+}
+
+// formatAddresses formats every management address as a comma-separated list
+func formatAddresses(addrs []net.IP) string {
+	strs := make([]string, len(addrs))
+	for i, ip := range addrs {
+		strs[i] = ip.String()
+	}
+	return strings.Join(strs, ", ")
+}
+
+// formatFrameLengths formats the last/min/max observed frame lengths
+func formatFrameLengths(n *types.Neighbor) string {
+	if n.FrameLenLast == 0 {
+		return ""
+	}
+	if n.FrameLenMin == n.FrameLenMax {
+		return fmt.Sprintf("%d bytes", n.FrameLenLast)
+	}
+	return fmt.Sprintf("%d bytes (min %d, max %d)", n.FrameLenLast, n.FrameLenMin, n.FrameLenMax)
+}
+
 // formatTime formats a time for display
 func formatTime(t time.Time) string {
 	if t.IsZero() {
@@ -203,6 +554,72 @@ func formatLastSeen(t time.Time) string {
 	return t.Format("2006-01-02 15:04")
 }
 
+// wrapsDetailField returns whether a long popup field wraps instead of truncating
+func (m NeighborTableModel) wrapsDetailField(field string) bool {
+	if m.config == nil {
+		return false
+	}
+	for _, f := range m.config.DetailWrapFields {
+		if strings.EqualFold(f, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// wrapText splits s into lines no wider than width, breaking at spaces
+// Words longer than width are split across lines
+func wrapText(s string, width int) []string {
+	if width <= 0 {
+		return []string{s}
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		// Hard-split words that can never fit on a line
+		for lipgloss.Width(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			cut := 0
+			for cut < len(runes) && lipgloss.Width(string(runes[:cut+1])) <= width {
+				cut++
+			}
+			lines = append(lines, string(runes[:cut]))
+			word = string(runes[cut:])
+		}
+		if word == "" {
+			continue
+		}
+
+		switch {
+		case line == "":
+			line = word
+		case lipgloss.Width(line)+1+lipgloss.Width(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// firstLine returns s up to its first line break, e.g. the headline of a CDP
+// software version banner
+func firstLine(s string) string {
+	if i := strings.IndexAny(s, "\r\n"); i >= 0 {
+		return strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
 // truncateValue truncates a string to fit within maxWidth
 func truncateValue(s string, maxWidth int) string {
 	if maxWidth <= 3 {
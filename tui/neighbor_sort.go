@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"nbor/logger"
+	"nbor/types"
+)
+
+// defaultSortColumn is the column the table is sorted by until s is pressed
+const defaultSortColumn = "Hostname"
+
+// sortKeys compares two neighbors by a table column, returning <0, 0 or >0
+// Columns not listed here sort by defaultSortColumn (Hostname)
+var sortKeys = map[string]func(a, b *types.Neighbor) int{
+	"Hostname": func(a, b *types.Neighbor) int { return strings.Compare(a.Hostname, b.Hostname) },
+	"Port":     func(a, b *types.Neighbor) int { return strings.Compare(a.PortID, b.PortID) },
+	"Interface": func(a, b *types.Neighbor) int {
+		return strings.Compare(neighborInterfaces(a), neighborInterfaces(b))
+	},
+	"Last Seen": func(a, b *types.Neighbor) int {
+		return a.LastSeen.Compare(b.LastSeen)
+	},
+	"Mgmt IP": func(a, b *types.Neighbor) int {
+		return bytes.Compare(a.ManagementIP.To16(), b.ManagementIP.To16())
+	},
+	"VLAN": func(a, b *types.Neighbor) int {
+		return neighborVLAN(a) - neighborVLAN(b)
+	},
+	"Platform": func(a, b *types.Neighbor) int { return strings.Compare(a.Platform, b.Platform) },
+	"Location": func(a, b *types.Neighbor) int { return strings.Compare(a.Location, b.Location) },
+	"Proto":    func(a, b *types.Neighbor) int { return strings.Compare(string(a.Protocol), string(b.Protocol)) },
+	"Capabilities": func(a, b *types.Neighbor) int {
+		return strings.Compare(logger.FormatCapabilities(a.Capabilities), logger.FormatCapabilities(b.Capabilities))
+	},
+	"Discovered": func(a, b *types.Neighbor) int {
+		return a.FirstSeen.Compare(b.FirstSeen)
+	},
+}
+
+// sortNeighbors sorts neighbors in place by column, falling back to hostname
+// and then the neighbor key so rows with equal values keep a stable order
+// Last Seen compares times, so descending puts the most recently heard first
+func sortNeighbors(neighbors []*types.Neighbor, column string, asc bool) {
+	cmp, ok := sortKeys[column]
+	if !ok {
+		cmp = sortKeys[defaultSortColumn]
+	}
+	sort.Slice(neighbors, func(i, j int) bool {
+		a, b := neighbors[i], neighbors[j]
+		c := cmp(a, b)
+		if !asc {
+			c = -c
+		}
+		if c == 0 {
+			c = strings.Compare(a.Hostname, b.Hostname)
+		}
+		if c == 0 {
+			c = strings.Compare(a.NeighborKey(), b.NeighborKey())
+		}
+		return c < 0
+	})
+}
+
+// sortIndicator is the arrow shown after the sorted column's header
+func (m NeighborTableModel) sortIndicator() string {
+	if m.sortAsc {
+		return " ▲"
+	}
+	return " ▼"
+}
+
+// cycleSortColumn moves the sort to the next visible column, wrapping around
+func (m NeighborTableModel) cycleSortColumn() NeighborTableModel {
+	columns := m.getVisibleColumns()
+	if len(columns) == 0 {
+		return m
+	}
+	next := columns[0].name
+	for i, col := range columns {
+		if col.name == m.sortColumn && i+1 < len(columns) {
+			next = columns[i+1].name
+			break
+		}
+	}
+	return m.resort(next, m.sortAsc)
+}
+
+// resort applies a new sort order, keeping the selected neighbor selected
+func (m NeighborTableModel) resort(column string, asc bool) NeighborTableModel {
+	selected := m.getSelectedNeighbor()
+	m.sortColumn = column
+	m.sortAsc = asc
+
+	neighbors := m.getFilteredNeighbors()
+	for i, n := range neighbors {
+		if n == selected {
+			m.selectedIndex = i
+			break
+		}
+	}
+	m.clampSelection(len(neighbors))
+	return m
+}
@@ -1,14 +1,17 @@
 package tui
 
 import (
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"nbor/config"
+	"nbor/export"
 	"nbor/types"
 )
 
@@ -21,11 +24,70 @@ type NeighborTableModel struct {
 	height        int
 	styles        Styles
 	scrollOffset  int
-	selectedIndex int                   // Currently selected row index
-	showDetail    bool                  // Whether detail popup is visible
-	flashRows     map[string]time.Time  // Track rows to flash
+	selectedIndex int                  // Currently selected row index
+	showDetail    bool                 // Whether detail popup is visible
+	showAdvanced  bool                 // Whether the detail popup shows the advanced section
+	showInventory bool                 // Whether the detail popup expands the LLDP-MED inventory
+	showQR        bool                 // Whether the detail view shows the management URL QR code
+	showLegend    bool                 // Whether the capabilities legend is visible
+	showSummary   bool                 // Whether the capture summary is visible
+	flashRows     map[string]time.Time // Track rows to flash
 	logPath       string
-	broadcasting  bool // Whether broadcasting is currently active
+	broadcasting  bool   // Whether broadcasting is currently active
+	tickCount     int    // Number of ticks received, drives the listening animation
+	lockedLayout  bool   // Keep all columns and scroll horizontally instead of dropping them
+	colOffset     int    // First visible column in locked layout
+	sortColumn    string // Name of the column the table is sorted by
+	sortAsc       bool   // Sort direction for sortColumn
+	confirmClear  bool   // Whether the footer is asking to confirm clearing all neighbors
+
+	// Whether the popup is asking before broadcasting starts (confirm_broadcast)
+	confirmBroadcast bool
+
+	// Interfaces whose broadcaster stopped on an error while others kept sending
+	broadcastFailed map[string]bool
+
+	// Every capture interface in --all-interfaces mode; ifaceInfo is the first
+	interfaces []types.InterfaceInfo
+
+	// Link state of the capture interfaces
+	downLinks map[string]bool      // Capture interfaces that have lost link
+	agingFrom map[string]time.Time // Per interface, neighbors aren't aged before this (set when link returns)
+
+	// Read-only history view of devices recorded in SQLite
+	readOnly       bool
+	historyPath    string
+	historyLoading bool
+	historyErr     error
+	search         textinput.Model // Hostname/MAC/IP filter
+	searching      bool            // Whether the search input has focus
+
+	// Free-text notes keyed by NeighborKey, kept in the config directory
+	notes       map[string]string
+	noteInput   textinput.Model
+	noteKey     string // Neighbor the note being typed is for
+	editingNote bool   // Whether the footer is taking a note
+
+	// Reverse DNS names of management IPs (resolve_ptr), "" when there is none
+	ptrNames map[string]string
+
+	// Short-lived footer notice, e.g. where an export was written
+	notice   string
+	noticeAt time.Time
+
+	// Warnings waiting for the current notice to clear, shown one at a time
+	warnings []string
+
+	// Latest new/stale/removed neighbors for the recent events strip
+	events *recentEvents
+
+	// Latest pcap statistics, shown in the footer with show_capture_stats
+	captureStats *CaptureStatsMsg
+
+	// When capture started and when the last CDP/LLDP packet arrived, for the
+	// quiet warning on an empty table
+	listenStart  time.Time
+	lastPacketAt time.Time
 }
 
 // NewNeighborTable creates a new neighbor table model
@@ -34,6 +96,9 @@ func NewNeighborTable(store *types.NeighborStore, ifaceInfo types.InterfaceInfo,
 	// Broadcasting only starts if BroadcastOnStartup is true AND a protocol is configured
 	broadcasting := cfg.BroadcastOnStartup && (cfg.CDPBroadcast || cfg.LLDPBroadcast)
 
+	events := &recentEvents{}
+	events.watchStore(store)
+
 	return NeighborTableModel{
 		store:         store,
 		ifaceInfo:     ifaceInfo,
@@ -44,12 +109,16 @@ func NewNeighborTable(store *types.NeighborStore, ifaceInfo types.InterfaceInfo,
 		broadcasting:  broadcasting,
 		selectedIndex: 0,
 		showDetail:    false,
+		events:        events,
+		sortColumn:    defaultSortColumn,
+		sortAsc:       true,
+		listenStart:   time.Now(),
 	}
 }
 
 // Init initializes the neighbor table
 func (m NeighborTableModel) Init() tea.Cmd {
-	return tickCmd()
+	return tea.Batch(tickCmd(), loadNotesCmd())
 }
 
 // TickMsg triggers periodic updates
@@ -60,6 +129,97 @@ type NewNeighborMsg struct {
 	Neighbor *types.Neighbor
 }
 
+// LinkStateMsg reports a link up/down transition on the capture interface
+type LinkStateMsg struct {
+	Interface string
+	Up        bool
+}
+
+// CaptureStatsMsg reports pcap statistics summed over the capture interfaces
+type CaptureStatsMsg struct {
+	Received int // Packets passed by the capture filter
+	Dropped  int // Packets dropped because the capture buffer was full
+}
+
+// PacketSeenMsg reports that a CDP or LLDP packet arrived, even one that
+// couldn't be parsed
+type PacketSeenMsg struct{}
+
+// ttlStaleGrace is added to a neighbor's advertised TTL before it's marked stale,
+// so an announcement arriving right at the deadline doesn't flicker the row
+const ttlStaleGrace = 5 * time.Second
+
+// AgeNeighbors marks neighbors stale by their TTL or cfg's staleness timeout and
+// removes those stale past stale_removal_time, measuring age from the later of
+// LastSeen and since (nil: from LastSeen). A staleness timeout of 0 turns aging
+// off, TTL aging with staleness_from_ttl included
+func AgeNeighbors(store *types.NeighborStore, cfg *config.Config, since types.AgingStart) {
+	if cfg.StalenessTimeout <= 0 {
+		return
+	}
+
+	// Mark stale neighbors based on their TTL or the configured timeout
+	stalenessTimeout := time.Duration(cfg.StalenessTimeout) * time.Second
+	if cfg.StalenessFromTTL {
+		store.MarkStaleByTTL(stalenessTimeout, ttlStaleGrace, since)
+	} else {
+		store.MarkStaleSince(stalenessTimeout, since)
+	}
+
+	// Remove stale neighbors if configured (0 = never remove)
+	if cfg.StaleRemovalTime > 0 {
+		store.RemoveStale(time.Duration(cfg.StaleRemovalTime) * time.Second)
+	}
+}
+
+// noticeDuration is how long a footer notice stays visible
+const noticeDuration = 5 * time.Second
+
+// warn shows a warning as the footer notice, or queues it behind the notice
+// already showing so several warnings at once are each seen
+func (m *NeighborTableModel) warn(text string) {
+	text = "warning: " + text
+	if m.notice != "" {
+		m.warnings = append(m.warnings, text)
+		return
+	}
+	m.notice, m.noticeAt = text, time.Now()
+}
+
+// ExportedMsg reports the result of exporting the interface map or neighbor list
+type ExportedMsg struct {
+	Kind string // "map" or "json", shown in the notice
+	Path string
+	Err  error
+}
+
+// exportMapCmd writes the interface-to-neighbor map in the background
+func exportMapCmd(directory, format string, neighbors []*types.Neighbor) tea.Cmd {
+	return func() tea.Msg {
+		path, err := export.WriteMappingFile(directory, format, neighbors)
+		return ExportedMsg{Kind: "map", Path: path, Err: err}
+	}
+}
+
+// exportJSONCmd writes the neighbor list as JSON in the background
+func exportJSONCmd(directory string, neighbors []*types.Neighbor) tea.Cmd {
+	return func() tea.Msg {
+		path, err := export.WriteJSONFile(directory, neighbors)
+		return ExportedMsg{Kind: "json", Path: path, Err: err}
+	}
+}
+
+// defaultFlashDuration is used when the config doesn't set flash_duration_ms
+const defaultFlashDuration = 2 * time.Second
+
+// flashDuration returns how long new or updated rows stay highlighted
+func (m NeighborTableModel) flashDuration() time.Duration {
+	if m.config == nil || m.config.FlashDurationMS <= 0 {
+		return defaultFlashDuration
+	}
+	return time.Duration(m.config.FlashDurationMS) * time.Millisecond
+}
+
 func tickCmd() tea.Cmd {
 	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
 		return TickMsg(t)
@@ -68,14 +228,43 @@ func tickCmd() tea.Cmd {
 
 // neighborTableKeyMap defines key bindings for the neighbor table
 type neighborTableKeyMap struct {
-	Refresh   key.Binding
-	Broadcast key.Binding
-	Config    key.Binding
-	Quit      key.Binding
-	Up        key.Binding
-	Down      key.Binding
-	Select    key.Binding
-	Back      key.Binding
+	Refresh       key.Binding
+	Reset         key.Binding
+	Broadcast     key.Binding
+	Config        key.Binding
+	ListenMenu    key.Binding
+	BroadcastMenu key.Binding
+	Quit          key.Binding
+	Up            key.Binding
+	Down          key.Binding
+	PageUp        key.Binding
+	PageDown      key.Binding
+	Home          key.Binding
+	End           key.Binding
+	Left          key.Binding
+	Right         key.Binding
+	Layout        key.Binding
+	Select        key.Binding
+	Back          key.Binding
+	Advanced      key.Binding
+	Inventory     key.Binding
+	ExportMap     key.Binding
+	ExportJSON    key.Binding
+	CopyIP        key.Binding
+	Ping          key.Binding
+	QRCode        key.Binding
+	Sort          key.Binding
+	SortOrder     key.Binding
+	Clear         key.Binding
+	ConfirmClear  key.Binding
+	Legend        key.Binding
+	Summary       key.Binding
+	NextNeighbor  key.Binding
+	PrevNeighbor  key.Binding
+	Note          key.Binding
+
+	// b again or enter at the broadcast prompt
+	ConfirmBroadcast key.Binding
 }
 
 var neighborKeys = neighborTableKeyMap{
@@ -83,6 +272,10 @@ var neighborKeys = neighborTableKeyMap{
 		key.WithKeys("r"),
 		key.WithHelp("r", "refresh display"),
 	),
+	Reset: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "reset display"),
+	),
 	Broadcast: key.NewBinding(
 		key.WithKeys("b"),
 		key.WithHelp("b", "toggle broadcast"),
@@ -91,6 +284,14 @@ var neighborKeys = neighborTableKeyMap{
 		key.WithKeys("c"),
 		key.WithHelp("c", "configuration"),
 	),
+	ListenMenu: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "listening options"),
+	),
+	BroadcastMenu: key.NewBinding(
+		key.WithKeys("B"),
+		key.WithHelp("B", "broadcast options"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("ctrl+c", "q"),
 		key.WithHelp("ctrl+c", "quit"),
@@ -103,6 +304,34 @@ var neighborKeys = neighborTableKeyMap{
 		key.WithKeys("down", "j"),
 		key.WithHelp("↓/j", "move down"),
 	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup"),
+		key.WithHelp("pgup", "page up"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown"),
+		key.WithHelp("pgdn", "page down"),
+	),
+	Home: key.NewBinding(
+		key.WithKeys("home"),
+		key.WithHelp("home", "go to first neighbor"),
+	),
+	End: key.NewBinding(
+		key.WithKeys("end"),
+		key.WithHelp("end", "go to last neighbor"),
+	),
+	Left: key.NewBinding(
+		key.WithKeys("left", "h"),
+		key.WithHelp("←/h", "scroll columns left"),
+	),
+	Right: key.NewBinding(
+		key.WithKeys("right", "l"),
+		key.WithHelp("→/l", "scroll columns right"),
+	),
+	Layout: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "toggle locked layout"),
+	),
 	Select: key.NewBinding(
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "view details"),
@@ -111,6 +340,74 @@ var neighborKeys = neighborTableKeyMap{
 		key.WithKeys("esc"),
 		key.WithHelp("esc", "close"),
 	),
+	Advanced: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "toggle advanced details"),
+	),
+	Inventory: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "toggle LLDP-MED inventory"),
+	),
+	ExportMap: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "export interface map"),
+	),
+	ExportJSON: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "export neighbors as JSON"),
+	),
+	CopyIP: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy management IP"),
+	),
+	Ping: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "ping management IP"),
+	),
+	QRCode: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "show management URL QR code"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "sort by next column"),
+	),
+	SortOrder: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "reverse sort order"),
+	),
+	Clear: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "clear all neighbors"),
+	),
+	ConfirmClear: key.NewBinding(
+		key.WithKeys("y", "Y"),
+		key.WithHelp("y", "confirm clear"),
+	),
+	Legend: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "capabilities legend"),
+	),
+	Summary: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "capture summary"),
+	),
+	NextNeighbor: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next neighbor's details"),
+	),
+	PrevNeighbor: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "previous neighbor's details"),
+	),
+	Note: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "add a note to the neighbor"),
+	),
+	ConfirmBroadcast: key.NewBinding(
+		key.WithKeys("b", "enter"),
+		key.WithHelp("b/enter", "start broadcasting"),
+	),
 }
 
 // ToggleBroadcastMsg is sent when broadcast is toggled
@@ -122,10 +419,29 @@ type ToggleBroadcastMsg struct {
 func (m NeighborTableModel) Update(msg tea.Msg) (NeighborTableModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// A note being typed takes every key, over the table or the detail popup
+		if m.editingNote {
+			return m.updateNoteInput(msg)
+		}
 		// Handle detail popup mode separately
+		if m.confirmBroadcast {
+			return m.updateConfirmBroadcast(msg)
+		}
 		if m.showDetail {
 			return m.updateDetailMode(msg)
 		}
+		if m.showLegend {
+			return m.updateLegendMode(msg)
+		}
+		if m.showSummary {
+			return m.updateSummaryMode(msg)
+		}
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+		if m.confirmClear {
+			return m.updateConfirmClear(msg)
+		}
 		return m.updateTableMode(msg)
 
 	case tea.WindowSizeMsg:
@@ -133,35 +449,110 @@ func (m NeighborTableModel) Update(msg tea.Msg) (NeighborTableModel, tea.Cmd) {
 		m.height = msg.Height
 
 	case TickMsg:
-		// Mark stale neighbors based on config
-		stalenessTimeout := time.Duration(m.config.StalenessTimeout) * time.Second
-		m.store.MarkStale(stalenessTimeout)
-
-		// Remove stale neighbors if configured (0 = never remove)
-		if m.config.StaleRemovalTime > 0 {
-			removalTimeout := time.Duration(m.config.StaleRemovalTime) * time.Second
-			m.store.RemoveStale(removalTimeout)
+		// History is a snapshot; nothing ages or flashes
+		if m.readOnly {
+			return m, nil
 		}
+		m.tickCount++
+
+		// Neighbors on an interface whose link is down don't age - the outage is the problem, not them
+		AgeNeighbors(m.store, m.config, m.agingStart)
 
 		// Clear old flash entries
 		now := time.Now()
+		if m.notice != "" && now.Sub(m.noticeAt) > noticeDuration {
+			m.notice = ""
+		}
+		if m.notice == "" && len(m.warnings) > 0 {
+			m.notice, m.noticeAt = m.warnings[0], now
+			m.warnings = m.warnings[1:]
+		}
+		flashDuration := m.flashDuration()
 		for k, t := range m.flashRows {
-			if now.Sub(t) > 2*time.Second {
+			if now.Sub(t) > flashDuration {
 				delete(m.flashRows, k)
 			}
 		}
 
-		// Ensure selectedIndex stays valid if neighbors were removed
+		// Keep the selection and scroll position valid if neighbors were removed
 		neighbors := m.getFilteredNeighbors()
-		if m.selectedIndex >= len(neighbors) && len(neighbors) > 0 {
-			m.selectedIndex = len(neighbors) - 1
+		m.clampSelection(len(neighbors))
+		if len(neighbors) == 0 {
+			m.showDetail = false
 		}
 
 		return m, tickCmd()
 
+	case PingFinishedMsg:
+		m.notice = pingNotice(msg)
+		m.noticeAt = time.Now()
+
+	case ExportedMsg:
+		if msg.Err != nil {
+			m.notice = "export failed: " + msg.Err.Error()
+		} else {
+			m.notice = msg.Kind + ": " + msg.Path
+		}
+		m.noticeAt = time.Now()
+
+	case NotesLoadedMsg:
+		if msg.Err != nil {
+			m.notice = "notes not loaded: " + msg.Err.Error()
+			m.noticeAt = time.Now()
+			break
+		}
+		m.notes = msg.Notes
+
+	case NotesSavedMsg:
+		if msg.Err != nil {
+			m.notice = "note not saved: " + msg.Err.Error()
+			m.noticeAt = time.Now()
+		}
+
+	case CaptureStatsMsg:
+		m.captureStats = &msg
+
+	case PacketSeenMsg:
+		m.lastPacketAt = time.Now()
+
 	case NewNeighborMsg:
 		// Mark this row for flashing
 		m.flashRows[msg.Neighbor.NeighborKey()] = time.Now()
+		m.events.add(eventNew, msg.Neighbor)
+		return m, m.resolvePTR(msg.Neighbor)
+
+	case PTRResolvedMsg:
+		m.setPTR(msg)
+
+	case LinkStateMsg:
+		if !m.capturesOn(msg.Interface) {
+			break
+		}
+		if !msg.Up && !m.downLinks[msg.Interface] {
+			if m.downLinks == nil {
+				m.downLinks = make(map[string]bool)
+			}
+			m.downLinks[msg.Interface] = true
+			if m.config.ClearOnLinkDown {
+				if m.multiInterface() {
+					// Neighbors on the other interfaces are still there
+					m.store.ClearInterface(msg.Interface)
+					m.clampSelection(len(m.getFilteredNeighbors()))
+				} else {
+					m.store.Clear()
+					m.selectedIndex = 0
+					m.scrollOffset = 0
+					m.showDetail = false
+				}
+			}
+		} else if msg.Up && m.downLinks[msg.Interface] {
+			// Give existing neighbors a full timeout to re-announce
+			delete(m.downLinks, msg.Interface)
+			if m.agingFrom == nil {
+				m.agingFrom = make(map[string]time.Time)
+			}
+			m.agingFrom[msg.Interface] = time.Now()
+		}
 	}
 
 	return m, nil
@@ -172,9 +563,39 @@ func (m NeighborTableModel) updateTableMode(msg tea.KeyMsg) (NeighborTableModel,
 	neighbors := m.getFilteredNeighbors()
 	neighborCount := len(neighbors)
 
+	if m.readOnly {
+		switch {
+		case key.Matches(msg, historyKeys.Search):
+			m.searching = true
+			return m, m.search.Focus()
+		case key.Matches(msg, neighborKeys.Back) && m.search.Value() != "":
+			// First esc clears the search, the next closes the view
+			m.search.SetValue("")
+			m.selectedIndex = 0
+			m.scrollOffset = 0
+			return m, nil
+		case key.Matches(msg, neighborKeys.Back), key.Matches(msg, neighborKeys.Quit):
+			return m, func() tea.Msg {
+				return HistoryClosedMsg{}
+			}
+		case key.Matches(msg, neighborKeys.Broadcast), key.Matches(msg, neighborKeys.Config),
+			key.Matches(msg, neighborKeys.ListenMenu), key.Matches(msg, neighborKeys.BroadcastMenu):
+			// Live-capture actions don't apply to history
+			return m, nil
+		}
+	} else if key.Matches(msg, historyKeys.Open) {
+		return m, func() tea.Msg {
+			return GoToHistoryMsg{}
+		}
+	}
+
 	switch {
-	case key.Matches(msg, neighborKeys.Refresh):
-		// Clear stale entries and refresh
+	case key.Matches(msg, neighborKeys.Refresh) && m.config.RefreshKey != "reset":
+		// Light redraw that keeps the selection and scroll position
+		return m, tea.ClearScreen
+
+	case key.Matches(msg, neighborKeys.Refresh), key.Matches(msg, neighborKeys.Reset):
+		// Clear highlights, go back to the top and redraw
 		m.store.ClearNewFlags()
 		m.flashRows = make(map[string]time.Time)
 		m.scrollOffset = 0
@@ -182,12 +603,53 @@ func (m NeighborTableModel) updateTableMode(msg tea.KeyMsg) (NeighborTableModel,
 		// Force a screen clear/redraw
 		return m, tea.ClearScreen
 
+	case key.Matches(msg, neighborKeys.Clear) && !m.readOnly:
+		// Ask first; the footer shows the prompt until a key is pressed
+		m.confirmClear = true
+
+	case key.Matches(msg, neighborKeys.Note) && !m.readOnly:
+		return m.startNote()
+
+	case key.Matches(msg, neighborKeys.Legend):
+		m.showLegend = true
+
+	case key.Matches(msg, neighborKeys.Summary):
+		m.showSummary = true
+
 	case key.Matches(msg, neighborKeys.Broadcast):
-		// Toggle broadcasting on/off (runtime only, doesn't change protocol config)
-		m.broadcasting = !m.broadcasting
-		// Send message to main to start/stop broadcaster
-		return m, func() tea.Msg {
-			return ToggleBroadcastMsg{Enabled: m.broadcasting}
+		if !m.broadcasting && m.config.ConfirmBroadcast {
+			// Ask first: the frames reach every switch on the segment
+			m.confirmBroadcast = true
+			return m, nil
+		}
+		return m.setBroadcasting(!m.broadcasting)
+
+	case key.Matches(msg, neighborKeys.ExportMap):
+		// Export what's shown, so the capability filter applies
+		return m, exportMapCmd(m.config.LogDirectory, m.config.MapFormat, neighbors)
+
+	case key.Matches(msg, neighborKeys.ExportJSON):
+		// Everything in the store, regardless of the capability filter
+		return m, exportJSONCmd(m.config.LogDirectory, m.store.GetAll())
+
+	case key.Matches(msg, neighborKeys.Sort):
+		m = m.cycleSortColumn()
+
+	case key.Matches(msg, neighborKeys.SortOrder):
+		m = m.resort(m.sortColumn, !m.sortAsc)
+
+	case key.Matches(msg, neighborKeys.Layout):
+		m.lockedLayout = !m.lockedLayout
+		m.colOffset = 0
+
+	case key.Matches(msg, neighborKeys.Left):
+		if m.lockedLayout && m.colOffset > 0 {
+			m.colOffset--
+		}
+
+	case key.Matches(msg, neighborKeys.Right):
+		if m.lockedLayout && m.colOffset < len(m.getAllColumns())-1 {
+			m.colOffset++
 		}
 
 	case key.Matches(msg, neighborKeys.Config):
@@ -196,6 +658,18 @@ func (m NeighborTableModel) updateTableMode(msg tea.KeyMsg) (NeighborTableModel,
 			return GoToConfigMenuMsg{}
 		}
 
+	case key.Matches(msg, neighborKeys.ListenMenu):
+		// Jump straight to Listening Options
+		return m, func() tea.Msg {
+			return GoToConfigMenuMsg{SubState: SubStateListening}
+		}
+
+	case key.Matches(msg, neighborKeys.BroadcastMenu):
+		// Jump straight to Broadcast Options
+		return m, func() tea.Msg {
+			return GoToConfigMenuMsg{SubState: SubStateBroadcast}
+		}
+
 	case key.Matches(msg, neighborKeys.Quit):
 		return m, tea.Quit
 
@@ -230,38 +704,272 @@ func (m NeighborTableModel) updateTableMode(msg tea.KeyMsg) (NeighborTableModel,
 			}
 		}
 
+	case key.Matches(msg, neighborKeys.PageUp):
+		// Move the selection and the viewport up a page together
+		rows := m.visibleRows()
+		m.selectedIndex -= rows
+		m.scrollOffset = max(m.scrollOffset-rows, 0)
+		m.clampSelection(neighborCount)
+
+	case key.Matches(msg, neighborKeys.PageDown):
+		rows := m.visibleRows()
+		m.selectedIndex += rows
+		m.scrollOffset += rows
+		m.clampSelection(neighborCount)
+
+	case key.Matches(msg, neighborKeys.Home):
+		m.selectedIndex = 0
+		m.scrollOffset = 0
+
+	case key.Matches(msg, neighborKeys.End):
+		m.selectedIndex = neighborCount - 1
+		m.clampSelection(neighborCount)
+
 	case key.Matches(msg, neighborKeys.Select):
 		// Open detail popup if we have a valid selection
 		if neighborCount > 0 && m.selectedIndex < neighborCount {
 			m.showDetail = true
+			m.showQR = false
+			return m, m.resolvePTR(m.getSelectedNeighbor())
 		}
 	}
 
 	return m, nil
 }
 
+// updateConfirmClear handles the key pressed at the clear-all prompt
+// y empties the store and starts the table fresh; any other key, such as esc, cancels
+func (m NeighborTableModel) updateConfirmClear(msg tea.KeyMsg) (NeighborTableModel, tea.Cmd) {
+	m.confirmClear = false
+	if !key.Matches(msg, neighborKeys.ConfirmClear) {
+		return m, nil
+	}
+
+	m.store.Clear()
+	m.flashRows = make(map[string]time.Time)
+	m.selectedIndex = 0
+	m.scrollOffset = 0
+	m.notice = "cleared all neighbors"
+	m.noticeAt = time.Now()
+	return m, nil
+}
+
+// updateLegendMode handles key events when viewing the capabilities legend
+func (m NeighborTableModel) updateLegendMode(msg tea.KeyMsg) (NeighborTableModel, tea.Cmd) {
+	switch {
+	case key.Matches(msg, neighborKeys.Back), key.Matches(msg, neighborKeys.Select),
+		key.Matches(msg, neighborKeys.Legend):
+		m.showLegend = false
+	case key.Matches(msg, neighborKeys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// updateSummaryMode handles key events while the capture summary is shown
+func (m NeighborTableModel) updateSummaryMode(msg tea.KeyMsg) (NeighborTableModel, tea.Cmd) {
+	switch {
+	case key.Matches(msg, neighborKeys.Back), key.Matches(msg, neighborKeys.Select),
+		key.Matches(msg, neighborKeys.Summary):
+		m.showSummary = false
+	case key.Matches(msg, neighborKeys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
 // updateDetailMode handles key events when viewing the detail popup
 func (m NeighborTableModel) updateDetailMode(msg tea.KeyMsg) (NeighborTableModel, tea.Cmd) {
+	// The QR overlay sits on top of the popup; closing it returns to the popup
+	if m.showQR {
+		switch {
+		case key.Matches(msg, neighborKeys.Back), key.Matches(msg, neighborKeys.Select),
+			key.Matches(msg, neighborKeys.QRCode):
+			m.showQR = false
+		case key.Matches(msg, neighborKeys.Quit):
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
 	switch {
 	case key.Matches(msg, neighborKeys.Back), key.Matches(msg, neighborKeys.Select):
 		// Close detail popup
 		m.showDetail = false
+	case key.Matches(msg, neighborKeys.Down), key.Matches(msg, neighborKeys.NextNeighbor):
+		m.moveDetail(1)
+		return m, m.resolvePTR(m.getSelectedNeighbor())
+	case key.Matches(msg, neighborKeys.Up), key.Matches(msg, neighborKeys.PrevNeighbor):
+		m.moveDetail(-1)
+		return m, m.resolvePTR(m.getSelectedNeighbor())
+	case key.Matches(msg, neighborKeys.Advanced):
+		m.showAdvanced = !m.showAdvanced
+	case key.Matches(msg, neighborKeys.Inventory):
+		m.showInventory = !m.showInventory
+	case key.Matches(msg, neighborKeys.Note) && !m.readOnly:
+		return m.startNote()
+	case key.Matches(msg, neighborKeys.CopyIP), key.Matches(msg, neighborKeys.Ping),
+		key.Matches(msg, neighborKeys.QRCode):
+		n := m.getSelectedNeighbor()
+		if n == nil || n.ManagementIP == nil {
+			m.notice = "no management IP to use"
+			m.noticeAt = time.Now()
+			return m, nil
+		}
+		if key.Matches(msg, neighborKeys.Ping) {
+			return m, pingCmd(n.ManagementIP, m.config.PingCount)
+		}
+		if key.Matches(msg, neighborKeys.QRCode) {
+			m.showQR = true
+			return m, nil
+		}
+		// OSC 52 has no reply, so whether the terminal took it can't be known
+		m.notice = "copy sent to terminal: " + n.ManagementIP.String()
+		m.noticeAt = time.Now()
+		return m, copyToClipboard(n.ManagementIP.String())
 	case key.Matches(msg, neighborKeys.Quit):
 		return m, tea.Quit
 	}
 	return m, nil
 }
 
+// moveDetail shows the next (delta 1) or previous (delta -1) neighbor in the
+// detail popup, in the table's current order, wrapping at either end. The
+// selection follows so closing the popup leaves the cursor on that neighbor
+func (m *NeighborTableModel) moveDetail(delta int) {
+	count := len(m.getFilteredNeighbors())
+	if count == 0 {
+		return
+	}
+	m.selectedIndex = ((m.selectedIndex+delta)%count + count) % count
+	m.clampSelection(count)
+}
+
+// quietFor returns how long capture has gone without a CDP or LLDP packet,
+// counting from capture start, or from the link coming back if that's later
+func (m NeighborTableModel) quietFor(now time.Time) time.Duration {
+	since := m.listenStart
+	if m.lastPacketAt.After(since) {
+		since = m.lastPacketAt
+	}
+	for _, t := range m.agingFrom {
+		if t.After(since) {
+			since = t
+		}
+	}
+	return now.Sub(since)
+}
+
+// showQuietWarning reports whether the empty table should suggest what to check
+// because nothing has been heard for quiet_warning_seconds
+func (m NeighborTableModel) showQuietWarning(now time.Time) bool {
+	if m.readOnly || m.config.QuietWarningSeconds <= 0 {
+		return false
+	}
+	return m.quietFor(now) >= time.Duration(m.config.QuietWarningSeconds)*time.Second
+}
+
 // visibleRows returns the number of visible table rows
 func (m NeighborTableModel) visibleRows() int {
 	// Account for header (1 line) + blank line + table header (1 line) + footer (1 line) + padding
-	available := m.height - 6
+	// plus the recent events strip when it's on
+	available := m.height - 6 - m.eventStripLines()
 	if available < 1 {
 		available = 1
 	}
 	return available
 }
 
+// clampSelection keeps the selection and scroll position inside a table of
+// count rows, with the selected row visible
+func (m *NeighborTableModel) clampSelection(count int) {
+	if m.selectedIndex >= count {
+		m.selectedIndex = count - 1
+	}
+	if m.selectedIndex < 0 {
+		m.selectedIndex = 0
+	}
+
+	rows := m.visibleRows()
+	if maxScroll := count - rows; m.scrollOffset > maxScroll {
+		m.scrollOffset = max(maxScroll, 0)
+	}
+	if m.selectedIndex < m.scrollOffset {
+		m.scrollOffset = m.selectedIndex
+	} else if m.selectedIndex >= m.scrollOffset+rows {
+		m.scrollOffset = m.selectedIndex - rows + 1
+	}
+}
+
+// multiInterface returns whether the table shows neighbors from several interfaces
+func (m NeighborTableModel) multiInterface() bool {
+	return len(m.interfaces) > 1
+}
+
+// captureInterfaceNames returns the names of the interfaces being captured on
+func (m NeighborTableModel) captureInterfaceNames() []string {
+	if !m.multiInterface() {
+		return []string{m.ifaceInfo.Name}
+	}
+	names := make([]string, len(m.interfaces))
+	for i, iface := range m.interfaces {
+		names[i] = iface.Name
+	}
+	return names
+}
+
+// capturesOn returns whether name is one of the capture interfaces
+func (m NeighborTableModel) capturesOn(name string) bool {
+	return slices.Contains(m.captureInterfaceNames(), name)
+}
+
+// linkDown returns whether any capture interface has lost link
+func (m NeighborTableModel) linkDown() bool {
+	return len(m.downLinks) > 0
+}
+
+// heardOn returns the interfaces n was heard on
+func heardOn(n *types.Neighbor) []string {
+	if len(n.Interfaces) > 0 {
+		return n.Interfaces
+	}
+	return []string{n.Interface}
+}
+
+// agingFromFor returns when n's interfaces last got link back, the latest if it
+// was heard on several, or the zero time if none lost link
+func (m NeighborTableModel) agingFromFor(n *types.Neighbor) time.Time {
+	var from time.Time
+	for _, name := range heardOn(n) {
+		if t := m.agingFrom[name]; t.After(from) {
+			from = t
+		}
+	}
+	return from
+}
+
+// agingStart is the AgingStart for the tick: neighbors on an interface that's
+// down don't age at all, and those on one that came back age from then
+func (m NeighborTableModel) agingStart(n *types.Neighbor) time.Time {
+	for _, name := range heardOn(n) {
+		if m.downLinks[name] {
+			return time.Now()
+		}
+	}
+	return m.agingFromFor(n)
+}
+
+// downLinkNames returns the capture interfaces that have lost link, in capture order
+func (m NeighborTableModel) downLinkNames() []string {
+	var names []string
+	for _, name := range m.captureInterfaceNames() {
+		if m.downLinks[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // MarkNewNeighbor marks a neighbor for flashing
 func (m *NeighborTableModel) MarkNewNeighbor(n *types.Neighbor) {
 	m.flashRows[n.NeighborKey()] = time.Now()
@@ -286,27 +994,30 @@ func (m *NeighborTableModel) matchesCapabilityFilter(n *types.Neighbor) bool {
 	return false
 }
 
-// getFilteredNeighbors returns neighbors that match the capability filter, sorted by hostname
+// getFilteredNeighbors returns neighbors that match the capability filter and
+// history search, in the table's sort order
 func (m *NeighborTableModel) getFilteredNeighbors() []*types.Neighbor {
 	allNeighbors := m.store.GetAll()
 
 	var filtered []*types.Neighbor
 	// If no filter, use all
-	if len(m.config.FilterCapabilities) == 0 {
+	if len(m.config.FilterCapabilities) == 0 && m.search.Value() == "" {
 		filtered = allNeighbors
 	} else {
 		// Filter neighbors
 		for _, n := range allNeighbors {
-			if m.matchesCapabilityFilter(n) {
+			if m.matchesCapabilityFilter(n) && m.matchesSearch(n) {
 				filtered = append(filtered, n)
 			}
 		}
 	}
 
-	// Sort by hostname for consistent ordering
-	sort.Slice(filtered, func(i, j int) bool {
-		return filtered[i].Hostname < filtered[j].Hostname
-	})
+	// Show stacked/MLAG devices once, listing their ports
+	if m.config.CollapseStacked {
+		filtered = collapseStacked(filtered)
+	}
+
+	sortNeighbors(filtered, m.sortColumn, m.sortAsc)
 
 	return filtered
 }
@@ -319,3 +1030,60 @@ func (m *NeighborTableModel) getSelectedNeighbor() *types.Neighbor {
 	}
 	return neighbors[m.selectedIndex]
 }
+
+// stackKey identifies neighbors that are the same device seen on several ports
+// Neighbors without a chassis ID are never grouped and get an empty key
+func stackKey(n *types.Neighbor) string {
+	if n.ID == "" {
+		return ""
+	}
+	return strings.ToLower(n.ID) + "\x00" + n.Hostname + "\x00" + n.Platform
+}
+
+// collapseStacked keeps one neighbor per stack key, preserving input order
+// The most recently seen member represents its group
+func collapseStacked(neighbors []*types.Neighbor) []*types.Neighbor {
+	result := make([]*types.Neighbor, 0, len(neighbors))
+	index := make(map[string]int)
+	for _, n := range neighbors {
+		key := stackKey(n)
+		if key == "" {
+			result = append(result, n)
+			continue
+		}
+		if i, ok := index[key]; ok {
+			if n.LastSeen.After(result[i].LastSeen) {
+				result[i] = n
+			}
+			continue
+		}
+		index[key] = len(result)
+		result = append(result, n)
+	}
+	return result
+}
+
+// stackedPorts returns the sorted ports of each collapsed group with more than one member
+// Returns nil when collapsing is disabled
+func (m *NeighborTableModel) stackedPorts() map[string][]string {
+	if !m.config.CollapseStacked {
+		return nil
+	}
+
+	groups := make(map[string][]string)
+	for _, n := range m.store.GetAll() {
+		key := stackKey(n)
+		if key == "" || !m.matchesCapabilityFilter(n) {
+			continue
+		}
+		groups[key] = append(groups[key], n.PortID)
+	}
+	for key, ports := range groups {
+		if len(ports) < 2 {
+			delete(groups, key)
+			continue
+		}
+		sort.Strings(ports)
+	}
+	return groups
+}
@@ -2,7 +2,10 @@ package tui
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 
@@ -22,6 +25,16 @@ type column struct {
 
 // View renders the neighbor table
 func (m NeighborTableModel) View() string {
+	if m.confirmBroadcast {
+		return m.renderPopupView(m.renderBroadcastConfirmPopup)
+	}
+	if m.showLegend {
+		return m.renderPopupView(m.renderLegendPopup)
+	}
+	if m.showSummary {
+		return m.renderPopupView(m.renderSummaryPopup)
+	}
+
 	// If detail popup is active, show header + popup + footer
 	if m.showDetail {
 		if n := m.getSelectedNeighbor(); n != nil {
@@ -38,18 +51,32 @@ const minDetailPopupHeight = 20
 
 // renderDetailView renders the detail popup with header and footer visible
 func (m NeighborTableModel) renderDetailView(n *types.Neighbor) string {
+	return m.renderPopupView(func(contentHeight int) string {
+		if m.showQR {
+			return m.renderQRPopup(n, contentHeight)
+		}
+		return m.renderDetailPopup(n, contentHeight)
+	})
+}
+
+// renderPopupView renders a popup between the header and footer; popup draws
+// it for the height available
+func (m NeighborTableModel) renderPopupView(popupFn func(contentHeight int) string) string {
 	header := m.renderHeader()
 	footer := m.renderFooter()
 
+	// The footer can wrap onto more than one line on narrow terminals
+	footerLines := strings.Count(footer, "\n") + 1
+
 	// If terminal is too small, show a message instead of the popup
 	if m.height < minDetailPopupHeight {
-		contentHeight := m.height - 2
+		contentHeight := m.height - 1 - footerLines
 		return m.renderTooSmallMessage(header, footer, contentHeight)
 	}
 
 	// Render popup centered in content area
-	contentHeight := m.height - 2
-	popup := m.renderDetailPopup(n, contentHeight)
+	contentHeight := m.height - 1 - footerLines
+	popup := popupFn(contentHeight)
 
 	// Remove any trailing newline from popup to ensure consistent formatting
 	popup = strings.TrimSuffix(popup, "\n")
@@ -68,7 +95,6 @@ func (m NeighborTableModel) renderDetailView(n *types.Neighbor) string {
 	// Calculate padding needed to push footer to bottom
 	// Total lines needed: header (1) + popup + padding + footer (1) = m.height
 	headerLines := strings.Count(header, "\n") + 1
-	footerLines := 1
 	usedLines := headerLines + popupLines + footerLines
 	paddingLines := m.height - usedLines
 	if paddingLines < 0 {
@@ -128,9 +154,11 @@ func (m NeighborTableModel) renderBaseView() string {
 	// Calculate how many blank lines we need to push footer to bottom
 	headerLines := strings.Count(header, "\n") + 1
 	tableLines := strings.Count(table, "\n")
-	footerLines := 1
+	footerLines := strings.Count(footer, "\n") + 1
+	strip := m.renderEventStrip()
+	stripLines := m.eventStripLines()
 
-	usedLines := headerLines + tableLines + footerLines
+	usedLines := headerLines + tableLines + stripLines + footerLines
 	remainingLines := m.height - usedLines
 	if remainingLines < 0 {
 		remainingLines = 0
@@ -142,6 +170,10 @@ func (m NeighborTableModel) renderBaseView() string {
 	b.WriteString("\n")
 	b.WriteString(table)
 	b.WriteString(strings.Repeat("\n", remainingLines))
+	if strip != "" {
+		b.WriteString(strip)
+		b.WriteString("\n")
+	}
 	b.WriteString(footer)
 
 	return b.String()
@@ -183,11 +215,45 @@ func (m NeighborTableModel) renderHeader() string {
 	}
 
 	middlePart := ifaceStyle.Render(m.ifaceInfo.Name)
-	if mac != "" {
-		middlePart += sp + macStyle.Render(mac)
+	if m.multiInterface() {
+		// One MAC and speed can't describe several NICs, so list the names instead
+		middlePart = ifaceStyle.Render(strings.Join(m.captureInterfaceNames(), ", "))
+	} else {
+		if mac != "" {
+			middlePart += sp + macStyle.Render(mac)
+		}
+		if m.ifaceInfo.Speed != "" {
+			middlePart += sp + speedStyle.Render(m.ifaceInfo.Speed)
+		}
 	}
-	if m.ifaceInfo.Speed != "" {
-		middlePart += sp + speedStyle.Render(m.ifaceInfo.Speed)
+	if m.readOnly {
+		// Make it obvious this isn't live data
+		historyStyle := lipgloss.NewStyle().
+			Foreground(theme.Base00).
+			Background(theme.Base0E).
+			Bold(true)
+		middlePart = historyStyle.Render(" HISTORY ")
+		if m.historyPath != "" {
+			middlePart += sp + macStyle.Render(m.historyPath)
+		}
+	} else if !m.config.ListeningEnabled() {
+		notListeningStyle := lipgloss.NewStyle().
+			Foreground(theme.Base00).
+			Background(theme.Base09).
+			Bold(true)
+		middlePart += sp + notListeningStyle.Render(" NOT LISTENING ")
+	} else if m.linkDown() {
+		linkDownStyle := lipgloss.NewStyle().
+			Foreground(theme.Base00).
+			Background(theme.Base08).
+			Bold(true)
+		middlePart += sp + linkDownStyle.Render(" LINK DOWN on "+strings.Join(m.downLinkNames(), ", ")+" ")
+	} else if m.store != nil && slices.ContainsFunc(m.captureInterfaceNames(), m.store.IsInterfaceUnstable) {
+		warnStyle := lipgloss.NewStyle().
+			Foreground(theme.Base08).
+			Background(bg).
+			Bold(true)
+		middlePart += sp + warnStyle.Render("⚠ possible link instability")
 	}
 
 	// Right side: neighbor count
@@ -198,8 +264,16 @@ func (m NeighborTableModel) renderHeader() string {
 	labelStyle := lipgloss.NewStyle().
 		Foreground(theme.Base04).
 		Background(bg)
-	count := m.store.Count()
-	rightPart := countStyle.Render(fmt.Sprintf("%d", count)) + sp + labelStyle.Render("neighbor(s)")
+	// Count what the table shows; when a filter hides some, show the total too
+	count := fmt.Sprintf("%d", len(m.getFilteredNeighbors()))
+	if len(m.config.FilterCapabilities) > 0 || m.search.Value() != "" {
+		count += fmt.Sprintf("/%d", m.store.Count())
+	}
+	countLabel := "neighbor(s)"
+	if m.readOnly {
+		countLabel = "device(s) seen"
+	}
+	rightPart := countStyle.Render(count) + sp + labelStyle.Render(countLabel)
 
 	// Calculate spacing to spread across width
 	leftLen := lipgloss.Width(leftPart)
@@ -232,33 +306,58 @@ func (m NeighborTableModel) renderHeader() string {
 	return headerStyle.Render(headerContent)
 }
 
-// getVisibleColumns returns columns that fit in the current width with dynamic sizing
-func (m NeighborTableModel) getVisibleColumns() []column {
+// getAllColumns returns every table column, sized to fit the current data
+func (m NeighborTableModel) getAllColumns() []column {
 	neighbors := m.getFilteredNeighbors()
+	stacked := m.stackedPorts()
 
 	// Define all columns with priorities and minimum widths
-	// Priority order: hostname, port, last seen, mgmt IP, platform, location, protocol, capabilities
+	// Priority order: hostname, port, interface, last seen, mgmt IP, VLAN, platform, location, protocol, capabilities, discovered
 	allColumns := []column{
-		{name: "Hostname", minWidth: 10, priority: 1, getter: func(n *types.Neighbor) string { return n.Hostname }},
-		{name: "Port", minWidth: 6, priority: 2, getter: func(n *types.Neighbor) string { return abbreviateInterface(n.PortID) }},
-		{name: "Last Seen", minWidth: 10, priority: 3, getter: func(n *types.Neighbor) string { return logger.FormatDuration(n.LastSeen) }},
-		{name: "Mgmt IP", minWidth: 10, priority: 4, getter: func(n *types.Neighbor) string {
+		{name: "Hostname", minWidth: 10, priority: 1, getter: m.hostnameWithNote},
+		{name: "Port", minWidth: 6, priority: 2, getter: func(n *types.Neighbor) string {
+			if ports, ok := stacked[stackKey(n)]; ok {
+				return abbreviateInterfaces(ports)
+			}
+			return abbreviateInterface(n.PortID)
+		}},
+		{name: "Interface", minWidth: 9, priority: 3, getter: neighborInterfaces},
+		{name: "Last Seen", minWidth: 10, priority: 4, getter: func(n *types.Neighbor) string { return logger.FormatDuration(n.LastSeen) }},
+		{name: "Mgmt IP", minWidth: 10, priority: 5, getter: func(n *types.Neighbor) string {
 			if n.ManagementIP != nil {
 				return n.ManagementIP.String()
 			}
 			return ""
 		}},
-		{name: "Platform", minWidth: 10, priority: 5, getter: func(n *types.Neighbor) string { return n.Platform }},
-		{name: "Location", minWidth: 10, priority: 6, getter: func(n *types.Neighbor) string { return n.Location }},
-		{name: "Proto", minWidth: 5, priority: 7, getter: func(n *types.Neighbor) string { return string(n.Protocol) }},
-		{name: "Capabilities", minWidth: 8, priority: 8, getter: func(n *types.Neighbor) string { return logger.FormatCapabilities(n.Capabilities) }},
+		{name: "VLAN", minWidth: 4, priority: 6, getter: func(n *types.Neighbor) string {
+			if vlan := neighborVLAN(n); vlan != 0 {
+				return strconv.Itoa(vlan)
+			}
+			return ""
+		}},
+		{name: "Platform", minWidth: 10, priority: 7, getter: func(n *types.Neighbor) string { return n.Platform }},
+		{name: "Location", minWidth: 10, priority: 8, getter: func(n *types.Neighbor) string { return n.Location }},
+		{name: "Proto", minWidth: 5, priority: 9, getter: func(n *types.Neighbor) string { return string(n.Protocol) }},
+		{name: "Capabilities", minWidth: 8, priority: 10, getter: func(n *types.Neighbor) string { return logger.FormatCapabilities(n.Capabilities) }},
+		{name: "Discovered", minWidth: 10, priority: 11, getter: func(n *types.Neighbor) string { return logger.FormatDuration(n.FirstSeen) }},
+	}
+
+	// Which NIC heard a neighbor only matters when capturing on several
+	if !m.multiInterface() {
+		allColumns = slices.DeleteFunc(allColumns, func(c column) bool { return c.name == "Interface" })
+	}
+	if !m.config.ShowDiscoveredColumn {
+		allColumns = slices.DeleteFunc(allColumns, func(c column) bool { return c.name == "Discovered" })
 	}
 
 	// Calculate dynamic width for each column based on actual data
 	for i := range allColumns {
 		col := &allColumns[i]
-		// Start with header width
+		// Start with header width, including the sort arrow
 		maxWidth := lipgloss.Width(col.name)
+		if col.name == m.sortColumn {
+			maxWidth += lipgloss.Width(m.sortIndicator())
+		}
 
 		// Check all neighbor values
 		for _, n := range neighbors {
@@ -276,8 +375,20 @@ func (m NeighborTableModel) getVisibleColumns() []column {
 		col.width = maxWidth
 	}
 
-	// Calculate which columns fit (already sorted by priority in definition order 1-8)
+	return allColumns
+}
+
+// getVisibleColumns returns columns that fit in the current width with dynamic sizing
+// In locked layout, no columns are dropped; a window starting at colOffset is shown instead
+func (m NeighborTableModel) getVisibleColumns() []column {
+	allColumns := m.getAllColumns()
 	availableWidth := m.width - 2 // Padding
+
+	if m.lockedLayout {
+		return columnWindow(allColumns, m.clampedColOffset(len(allColumns)), availableWidth)
+	}
+
+	// Calculate which columns fit (already sorted by priority in definition order)
 	usedWidth := 0
 	var visibleColumns []column
 
@@ -292,6 +403,86 @@ func (m NeighborTableModel) getVisibleColumns() []column {
 	return visibleColumns
 }
 
+// columnWindow returns consecutive columns starting at offset that fit in availableWidth
+// Always includes at least the first column so the table is never empty
+func columnWindow(columns []column, offset, availableWidth int) []column {
+	var window []column
+	usedWidth := 0
+	for _, col := range columns[offset:] {
+		colWidth := col.width + 2 // Add spacing between columns
+		if usedWidth+colWidth > availableWidth && len(window) > 0 {
+			break
+		}
+		window = append(window, col)
+		usedWidth += colWidth
+	}
+	return window
+}
+
+// clampedColOffset returns colOffset limited to a valid column index
+func (m NeighborTableModel) clampedColOffset(columnCount int) int {
+	if m.colOffset >= columnCount {
+		return columnCount - 1
+	}
+	if m.colOffset < 0 {
+		return 0
+	}
+	return m.colOffset
+}
+
+// listenProtocols returns the names of the protocols currently being listened for
+func (m NeighborTableModel) listenProtocols() []string {
+	var protocols []string
+	if m.config.CDPListen {
+		protocols = append(protocols, "CDP")
+	}
+	if m.config.LLDPListen {
+		protocols = append(protocols, "LLDP")
+	}
+	return protocols
+}
+
+// quietWarningChecks are the usual reasons nothing is heard, shown with the quiet warning
+var quietWarningChecks = []string{
+	"the cable is plugged into a switch port with link",
+	"CDP or LLDP is enabled on the switch and that port (e.g. cdp enable, lldp transmit)",
+	"nbor can capture on this interface (run as root or Administrator)",
+}
+
+// listeningMessage returns the empty-state message for the enabled protocols
+// The ellipsis cycles with the tick when the animation is enabled
+func (m NeighborTableModel) listeningMessage() string {
+	protocols := m.listenProtocols()
+	if len(protocols) == 0 {
+		return "Both CDP and LLDP listening are disabled - nothing will be captured"
+	}
+
+	ellipsis := "..."
+	if m.config.ListeningAnimation {
+		// Pad to a fixed width so the line doesn't jitter
+		dots := m.tickCount%3 + 1
+		ellipsis = strings.Repeat(".", dots) + strings.Repeat(" ", 3-dots)
+	}
+
+	return "Listening for " + strings.Join(protocols, " and ") + " packets" + ellipsis
+}
+
+// listeningHint returns how often neighbors typically announce for the enabled protocols
+// or, with listening disabled, how to turn it back on
+func (m NeighborTableModel) listeningHint() string {
+	if !m.config.ListeningEnabled() {
+		return "Press L to open Listening Options and enable CDP or LLDP."
+	}
+	var hints []string
+	if m.config.CDPListen {
+		hints = append(hints, "CDP advertises ~every 60s")
+	}
+	if m.config.LLDPListen {
+		hints = append(hints, "LLDP advertises ~every 30s")
+	}
+	return strings.Join(hints, ", ")
+}
+
 // renderTable renders the neighbor table
 func (m NeighborTableModel) renderTable() string {
 	var b strings.Builder
@@ -305,21 +496,79 @@ func (m NeighborTableModel) renderTable() string {
 	// Table header (with prefix space for alignment with row cursor)
 	var headerCells []string
 	for _, col := range columns {
-		headerCells = append(headerCells, truncate(col.name, col.width))
+		name := col.name
+		if name == m.sortColumn {
+			name += m.sortIndicator()
+		}
+		headerCells = append(headerCells, truncate(name, col.width))
+	}
+
+	// In locked layout, show arrows when columns are scrolled off either side
+	prefix := "  "
+	suffix := ""
+	if m.lockedLayout {
+		allCount := len(m.getAllColumns())
+		offset := m.clampedColOffset(allCount)
+		if offset > 0 {
+			prefix = "◂ "
+		}
+		if offset+len(columns) < allCount {
+			suffix = "  ▸"
+		}
 	}
 
-	headerRow := "  " + strings.Join(headerCells, "  ")
+	headerRow := prefix + strings.Join(headerCells, "  ") + suffix
 	b.WriteString(m.styles.TableHeader.Render(headerRow))
 	b.WriteString("\n")
 
+	if len(neighbors) == 0 && m.readOnly {
+		b.WriteString("\n")
+		b.WriteString(m.styles.StatusInfo.Render("  " + m.historyMessage()))
+		return b.String()
+	}
+
+	if len(neighbors) == 0 && !m.config.ListeningEnabled() {
+		// Nothing will ever show up, so say so plainly
+		b.WriteString("\n")
+		b.WriteString(m.styles.StatusError.Render("  " + m.listeningMessage()))
+		b.WriteString("\n\n")
+		b.WriteString(m.styles.StatusInfo.Render("  " + m.listeningHint()))
+		return b.String()
+	}
+
+	if len(neighbors) == 0 && len(m.downLinkNames()) == len(m.captureInterfaceNames()) {
+		// Nothing can arrive until the cable is back, so don't look like we're listening
+		// With several interfaces, the others are still listening until all are down
+		b.WriteString("\n")
+		b.WriteString(m.styles.StatusError.Render("  Link is down on " + strings.Join(m.downLinkNames(), ", ") + " - check the cable"))
+		b.WriteString("\n\n")
+		b.WriteString(m.styles.StatusInfo.Render("  Capture resumes automatically when the link comes back."))
+		return b.String()
+	}
+
 	if len(neighbors) == 0 {
 		// Show listening message
 		b.WriteString("\n")
-		listening := m.styles.StatusListening.Render("  Listening for CDP and LLDP packets...")
+		listening := m.styles.StatusListening.Render("  " + m.listeningMessage())
 		b.WriteString(listening)
 		b.WriteString("\n\n")
-		hint := m.styles.StatusInfo.Render("  Neighbors will appear here as they announce themselves.")
-		b.WriteString(hint)
+		if hint := m.listeningHint(); hint != "" {
+			b.WriteString(m.styles.StatusInfo.Render("  " + hint))
+			b.WriteString("\n")
+		}
+		if now := time.Now(); m.showQuietWarning(now) {
+			// New users often don't know the switch has to send CDP/LLDP
+			quiet := m.quietFor(now).Truncate(time.Second)
+			b.WriteString("\n")
+			b.WriteString(m.styles.StatusError.Render(fmt.Sprintf("  No %s packets for %s. Check that:",
+				strings.Join(m.listenProtocols(), " or "), quiet)))
+			for _, check := range quietWarningChecks {
+				b.WriteString("\n")
+				b.WriteString(m.styles.StatusInfo.Render("    - " + check))
+			}
+			return b.String()
+		}
+		b.WriteString(m.styles.StatusInfo.Render("  Neighbors will appear here as they announce themselves."))
 		return b.String()
 	}
 
@@ -349,49 +598,145 @@ func (m NeighborTableModel) renderTable() string {
 
 // renderNeighborRow renders a single neighbor row
 func (m NeighborTableModel) renderNeighborRow(n *types.Neighbor, columns []column, isSelected bool) string {
-	theme := DefaultTheme
-
 	// Determine style based on state:
-	// - Stale (no updates for 3-4 min) = gray
+	// - Stale (no updates for 3-4 min) = gray, faded in over a few seconds
 	// - Active (getting updates) = green
 	// - New/flashing = bold green
+	// - Half duplex = orange, since it's a classic cause of slow links
 	var cellStyle lipgloss.Style
 
 	if n.IsStale {
-		cellStyle = m.styles.TableCellStale
+		cellStyle = m.staleCellStyle(n, time.Now())
 	} else if _, flashing := m.flashRows[n.NeighborKey()]; flashing || n.IsNew {
-		// Brand new or just updated - bold green
-		cellStyle = lipgloss.NewStyle().
-			Foreground(m.styles.TableRowNew.GetForeground()).
-			Bold(true)
+		// Brand new or just updated - bold flash accent (green by default)
+		cellStyle = m.styles.TableRowNew
+	} else if n.Duplex == "half" {
+		cellStyle = m.styles.TableRowWarn
 	} else {
 		// Active neighbor - regular green (not bold)
-		cellStyle = lipgloss.NewStyle().
-			Foreground(m.styles.TableRowNew.GetForeground())
+		cellStyle = m.styles.TableRowActive
 	}
 
 	// Subtle cursor indicator for selection
 	var prefix string
 	if isSelected {
-		cursorStyle := lipgloss.NewStyle().
-			Foreground(theme.Base0D).
-			Bold(true)
-		prefix = cursorStyle.Render("▸ ")
+		prefix = m.styles.TableCursor.Render("▸ ")
 	} else {
 		prefix = "  "
 	}
 
+	// Without color the selection is shown reversed across the whole row
+	gap := "  "
+	if isSelected && monochrome {
+		cellStyle = cellStyle.Inherit(m.styles.TableSelected)
+		gap = cellStyle.Render(gap)
+	}
+
 	var cells []string
 	for _, col := range columns {
 		value := col.getter(n)
-		cells = append(cells, cellStyle.Render(truncate(value, col.width)))
+		style := cellStyle
+		if col.name == "Proto" && !n.IsStale {
+			style = m.protocolCellStyle(cellStyle, n.Protocol)
+		}
+		cells = append(cells, style.Render(truncate(value, col.width)))
 	}
 
-	row := strings.Join(cells, "  ")
+	row := strings.Join(cells, gap)
 
 	return prefix + row
 }
 
+// staleFadeDuration is how long a row takes to fade to gray once its neighbor
+// goes stale; with the one-second tick that's a few intermediate shades
+const staleFadeDuration = 3 * time.Second
+
+// staleCellStyle returns the style for a stale neighbor's row, partway between
+// the row's active color and gray while it's still fading with stale_fade on
+func (m NeighborTableModel) staleCellStyle(n *types.Neighbor, now time.Time) lipgloss.Style {
+	stale := m.styles.TableCellStale
+	progress := m.staleFadeProgress(n, now)
+	if progress >= 1 {
+		return stale
+	}
+	from := m.styles.TableRowActive
+	if n.Duplex == "half" {
+		from = m.styles.TableRowWarn
+	}
+	return stale.Foreground(blendColor(from.GetForeground(), stale.GetForeground(), progress))
+}
+
+// staleFadeProgress returns how far a stale neighbor's row is through its fade,
+// from 0 as it goes stale to 1 once it's fully gray. Age is measured the way the
+// tick marks neighbors stale, so the fade starts when the row turns stale
+func (m NeighborTableModel) staleFadeProgress(n *types.Neighbor, now time.Time) float64 {
+	if m.config == nil || !m.config.StaleFade || m.config.StalenessTimeout <= 0 || monochrome {
+		return 1
+	}
+	threshold := time.Duration(m.config.StalenessTimeout) * time.Second
+	if m.config.StalenessFromTTL && n.TTL > 0 {
+		threshold = n.TTL + ttlStaleGrace
+	}
+	if threshold <= 0 {
+		return 1
+	}
+	lastSeen := n.LastSeen
+	if from := m.agingFromFor(n); from.After(lastSeen) {
+		lastSeen = from
+	}
+	progress := float64(now.Sub(lastSeen)-threshold) / float64(staleFadeDuration)
+	return min(max(progress, 0), 1)
+}
+
+// blendColor mixes two hex colors, t of the way from from to to. Colors that
+// aren't hex, such as ANSI numbers, can't be mixed and give to
+func blendColor(from, to lipgloss.TerminalColor, t float64) lipgloss.TerminalColor {
+	fromRGB, ok1 := hexRGB(from)
+	toRGB, ok2 := hexRGB(to)
+	if !ok1 || !ok2 {
+		return to
+	}
+	var mixed [3]uint8
+	for i := range mixed {
+		mixed[i] = uint8(float64(fromRGB[i]) + (float64(toRGB[i])-float64(fromRGB[i]))*t + 0.5)
+	}
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", mixed[0], mixed[1], mixed[2]))
+}
+
+// hexRGB returns the red, green and blue parts of a "#rrggbb" color
+func hexRGB(c lipgloss.TerminalColor) ([3]uint8, bool) {
+	color, ok := c.(lipgloss.Color)
+	if !ok {
+		return [3]uint8{}, false
+	}
+	hex, err := parseHexColor(string(color))
+	if err != nil {
+		return [3]uint8{}, false
+	}
+	v, _ := strconv.ParseUint(string(hex[1:]), 16, 32)
+	return [3]uint8{uint8(v >> 16), uint8(v >> 8), uint8(v)}, true
+}
+
+// protocolCellStyle tints the Proto cell with the protocol's badge color when
+// protocol_colors is on, keeping the row style's other attributes
+func (m NeighborTableModel) protocolCellStyle(cellStyle lipgloss.Style, protocol types.Protocol) lipgloss.Style {
+	if m.config == nil || !m.config.ProtocolColors {
+		return cellStyle
+	}
+	var tint lipgloss.Style
+	switch protocol {
+	case types.ProtocolCDP:
+		tint = m.styles.ProtoCDP
+	case types.ProtocolLLDP:
+		tint = m.styles.ProtoLLDP
+	case types.ProtocolBoth:
+		tint = m.styles.ProtoBoth
+	default:
+		return cellStyle
+	}
+	return cellStyle.Foreground(tint.GetForeground())
+}
+
 // renderFooter renders the footer with hotkeys spread across width
 func (m NeighborTableModel) renderFooter() string {
 	theme := DefaultTheme
@@ -421,53 +766,198 @@ func (m NeighborTableModel) renderFooter() string {
 
 	// Broadcast status indicator
 	var broadcastStatus string
-	if m.broadcasting {
+	if m.broadcasting && m.config != nil && m.config.BroadcastVariationEnabled() {
+		// Varied identities are a lab-only mode, so make it stand out
+		broadcastStatus = lipgloss.NewStyle().
+			Foreground(theme.Base08).
+			Background(bg).
+			Bold(true).
+			Render("VAR")
+	} else if failed := m.failedBroadcasts(); m.broadcasting && len(failed) > 0 {
+		// Some interfaces' broadcasters stopped on an error; the rest still send
+		broadcastStatus = onStyle.Render("TX") + offStyle.Render(" ("+strings.Join(failed, ",")+" --)")
+	} else if m.broadcasting {
 		broadcastStatus = onStyle.Render("TX")
 	} else {
 		broadcastStatus = offStyle.Render("--")
 	}
 
-	leftPart := keyStyle.Render("r") + textStyle.Render(" refresh") + sep +
-		keyStyle.Render("b") + textStyle.Render(" broadcast:") + broadcastStatus + sep +
-		keyStyle.Render("c") + textStyle.Render(" config") + sep +
-		keyStyle.Render("↑/↓") + textStyle.Render(" select") + sep +
-		keyStyle.Render("enter") + textStyle.Render(" details") + sep +
-		keyStyle.Render("q") + textStyle.Render(" quit")
+	// In locked layout the refresh hint makes room for the column scroll keys,
+	// and the LOCKED marker is kept on narrow terminals like the other status hints
+	firstPart := footerHint{keyStyle.Render("r") + textStyle.Render(" refresh"), 3}
+	if m.lockedLayout {
+		firstPart = footerHint{onStyle.Render("LOCKED") + textStyle.Render(" ") + keyStyle.Render("←/→"), 0}
+	}
+
+	hints := []footerHint{
+		firstPart,
+		{keyStyle.Render("R") + textStyle.Render(" reset"), 1},
+		{keyStyle.Render("b") + textStyle.Render(" broadcast:") + broadcastStatus, 0},
+		{keyStyle.Render("c") + textStyle.Render(" config"), 4},
+		{keyStyle.Render("↑/↓") + textStyle.Render(" select"), 2},
+		{keyStyle.Render("enter") + textStyle.Render(" details"), 1},
+		{keyStyle.Render("q") + textStyle.Render(" quit"), 0},
+	}
+
+	// Capture statistics are opt-in; drops are highlighted since they mean missed frames
+	if m.config != nil && m.config.ShowCaptureStats && m.captureStats != nil {
+		dropStyle := textStyle
+		if m.captureStats.Dropped > 0 {
+			dropStyle = lipgloss.NewStyle().
+				Foreground(theme.Base08).
+				Background(bg).
+				Bold(true)
+		}
+		stats := textStyle.Render(fmt.Sprintf("rx %d ", m.captureStats.Received)) +
+			dropStyle.Render(fmt.Sprintf("drop %d", m.captureStats.Dropped))
+		hints = append(hints, footerHint{stats, 5})
+	}
+
+	// The clear-all prompt replaces the key hints until it's answered
+	if m.confirmClear {
+		warnStyle := lipgloss.NewStyle().
+			Foreground(theme.Base08).
+			Background(bg).
+			Bold(true)
+		hints = []footerHint{
+			{warnStyle.Render("Clear all neighbors?"), 0},
+			{keyStyle.Render("y") + textStyle.Render(" confirm"), 0},
+			{keyStyle.Render("esc") + textStyle.Render(" cancel"), 0},
+		}
+	}
+
+	// A note being typed replaces the key hints until it's saved or cancelled
+	if m.editingNote {
+		hints = []footerHint{
+			{m.noteInput.View(), 0},
+			{keyStyle.Render("enter") + textStyle.Render(" save"), 0},
+			{keyStyle.Render("esc") + textStyle.Render(" cancel"), 0},
+		}
+	}
+
+	// History has no live actions; show search instead
+	if m.readOnly {
+		searchPart := keyStyle.Render("/") + textStyle.Render(" search")
+		if m.searching {
+			searchPart = m.search.View()
+		} else if m.search.Value() != "" {
+			searchPart = keyStyle.Render("/") + textStyle.Render(" ") + onStyle.Render(m.search.Value())
+		}
+		hints = []footerHint{
+			{searchPart, 0},
+			{keyStyle.Render("↑/↓") + textStyle.Render(" select"), 2},
+			{keyStyle.Render("enter") + textStyle.Render(" details"), 1},
+			{keyStyle.Render("esc") + textStyle.Render(" back"), 0},
+		}
+	}
 
-	// Build right side: log file
+	// Build right side: recent notice, otherwise the log file
+	// The log path is the first thing dropped for space; a notice outlasts the key hints
 	var rightPart string
-	if m.logPath != "" {
+	keepRight := false
+	if m.notice != "" {
+		noticeStyle := lipgloss.NewStyle().
+			Foreground(theme.Base0B).
+			Background(bg)
+		rightPart = noticeStyle.Render(m.notice)
+		keepRight = true
+	} else if m.logPath != "" {
 		fileStyle := lipgloss.NewStyle().
 			Foreground(theme.Base0A).
 			Background(bg)
 		rightPart = textStyle.Render("log: ") + fileStyle.Render(m.logPath)
 	}
 
-	// Calculate spacing to spread across width
-	leftLen := lipgloss.Width(leftPart)
-	rightLen := lipgloss.Width(rightPart)
-
 	// Account for padding (1 on each side)
 	availableWidth := m.width - 2
-	totalContentWidth := leftLen + rightLen
-
-	// Calculate gap
-	gap := availableWidth - totalContentWidth
-	if gap < 1 {
-		gap = 1
-	}
+	lines, rightPart := layoutFooter(hints, sep, rightPart, keepRight, availableWidth)
 
-	// Build footer content with background-colored spaces
 	spaceStyle := lipgloss.NewStyle().Background(bg)
-	footerContent := leftPart + spaceStyle.Render(strings.Repeat(" ", gap)) + rightPart
-
-	// Apply background style
 	footerStyle := lipgloss.NewStyle().
 		Background(bg).
 		Padding(0, 1).
 		Width(m.width)
 
-	return footerStyle.Render(footerContent)
+	rendered := make([]string, len(lines))
+	for i, line := range lines {
+		// Spread the first line across the width with the right side at the end
+		right := ""
+		if i == 0 {
+			right = rightPart
+		}
+		gap := availableWidth - lipgloss.Width(line) - lipgloss.Width(right)
+		if gap < 1 {
+			gap = 1
+		}
+		rendered[i] = footerStyle.Render(line + spaceStyle.Render(strings.Repeat(" ", gap)) + right)
+	}
+
+	return strings.Join(rendered, "\n")
+}
+
+// footerHint is one entry of the footer key bar
+// drop orders what goes first when space runs out (1 first); 0 is never dropped
+type footerHint struct {
+	text string
+	drop int
+}
+
+// layoutFooter fits the key hints and right-hand text into width, trimming in
+// priority order: the right text (unless keepRight), then hints by drop order,
+// then the right text regardless. Hints that still don't fit wrap onto more lines
+func layoutFooter(hints []footerHint, sep, right string, keepRight bool, width int) ([]string, string) {
+	kept := append([]footerHint(nil), hints...)
+
+	join := func(hs []footerHint) string {
+		parts := make([]string, len(hs))
+		for i, h := range hs {
+			parts[i] = h.text
+		}
+		return strings.Join(parts, sep)
+	}
+	fits := func() bool {
+		w := lipgloss.Width(join(kept))
+		if right != "" {
+			w += 1 + lipgloss.Width(right)
+		}
+		return w <= width
+	}
+
+	if !fits() && !keepRight {
+		right = ""
+	}
+	for !fits() {
+		// Drop the lowest-priority hint still shown
+		idx := -1
+		for i, h := range kept {
+			if h.drop > 0 && (idx < 0 || h.drop < kept[idx].drop) {
+				idx = i
+			}
+		}
+		if idx < 0 {
+			break
+		}
+		kept = append(kept[:idx], kept[idx+1:]...)
+	}
+	if !fits() {
+		right = ""
+	}
+	if fits() {
+		return []string{join(kept)}, right
+	}
+
+	// Very narrow: wrap the essential hints across lines
+	var lines []string
+	var line []footerHint
+	for _, h := range kept {
+		if len(line) > 0 && lipgloss.Width(join(append(line, h))) > width {
+			lines = append(lines, join(line))
+			line = nil
+		}
+		line = append(line, h)
+	}
+	lines = append(lines, join(line))
+	return lines, right
 }
 
 // truncate truncates a string to the given width and pads with spaces
@@ -537,3 +1027,20 @@ func abbreviateInterface(portID string) string {
 	}
 	return portID
 }
+
+// abbreviateInterfaces abbreviates and joins the ports of a collapsed stack row
+func abbreviateInterfaces(ports []string) string {
+	short := make([]string, len(ports))
+	for i, p := range ports {
+		short[i] = abbreviateInterface(p)
+	}
+	return strings.Join(short, ",")
+}
+
+// neighborInterfaces returns the local interfaces a neighbor was heard on
+func neighborInterfaces(n *types.Neighbor) string {
+	if len(n.Interfaces) > 1 {
+		return strings.Join(n.Interfaces, ",")
+	}
+	return n.Interface
+}
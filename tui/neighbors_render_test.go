@@ -3,14 +3,34 @@ package tui
 import (
 	"fmt"
 	"net"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
 	"nbor/config"
+	"nbor/oui"
 	"nbor/types"
 )
 
+// newTestTable returns an 80x30 table on eth0 with the default config and a
+// store holding neighbors
+func newTestTable(t *testing.T, neighbors ...*types.Neighbor) NeighborTableModel {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	store := types.NewNeighborStore()
+	for _, n := range neighbors {
+		store.Update(n)
+	}
+	m := NewNeighborTable(store, types.InterfaceInfo{Name: "eth0"}, "", &cfg)
+	m.width = 80
+	m.height = 30
+	return m
+}
+
 func TestRenderDetailView(t *testing.T) {
 	// Create a minimal model for testing
 	store := types.NewNeighborStore()
@@ -286,3 +306,1341 @@ func TestLipglossPlaceOutput(t *testing.T) {
 		t.Errorf("Unexpected newline count: got %d, expected around %d", newlineCount, contentHeight-1)
 	}
 }
+
+func TestListeningMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		cdp       bool
+		lldp      bool
+		animate   bool
+		tickCount int
+		want      string
+	}{
+		{"both protocols", true, true, false, 0, "Listening for CDP and LLDP packets..."},
+		{"LLDP only", false, true, false, 0, "Listening for LLDP packets..."},
+		{"CDP only", true, false, false, 0, "Listening for CDP packets..."},
+		{"neither", false, false, false, 0, "Both CDP and LLDP listening are disabled - nothing will be captured"},
+		{"animated first frame", true, true, true, 0, "Listening for CDP and LLDP packets.  "},
+		{"animated second frame", true, true, true, 1, "Listening for CDP and LLDP packets.. "},
+		{"animated wraps", true, true, true, 5, "Listening for CDP and LLDP packets..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestTable(t)
+			m.config.CDPListen = tt.cdp
+			m.config.LLDPListen = tt.lldp
+			m.config.ListeningAnimation = tt.animate
+			m.tickCount = tt.tickCount
+
+			if got := m.listeningMessage(); got != tt.want {
+				t.Errorf("listeningMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCapabilityBits(t *testing.T) {
+	tests := []struct {
+		name   string
+		seen   bool
+		bits   uint32
+		digits int
+		want   string
+	}{
+		{"not seen", false, 0x28, 8, ""},
+		{"CDP bits", true, 0x28, 8, "0x00000028"},
+		{"LLDP bits", true, 0x0014, 4, "0x0014"},
+		{"seen with no bits", true, 0, 4, "0x0000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatCapabilityBits(tt.seen, tt.bits, tt.digits); got != tt.want {
+				t.Errorf("formatCapabilityBits() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockedLayoutKeepsColumns(t *testing.T) {
+	m := newTestTable(t)
+	m.width = 40
+	allCount := len(m.getAllColumns())
+
+	// Auto-fit drops columns that don't fit
+	auto := m.getVisibleColumns()
+	if len(auto) >= allCount {
+		t.Fatalf("auto layout shows %d of %d columns at width %d, want fewer", len(auto), allCount, m.width)
+	}
+
+	// Locked layout scrolls through every column in order
+	m.lockedLayout = true
+	seen := make(map[string]bool)
+	for m.colOffset = 0; m.colOffset < allCount; m.colOffset++ {
+		cols := m.getVisibleColumns()
+		if len(cols) == 0 {
+			t.Fatalf("locked layout at offset %d shows no columns", m.colOffset)
+		}
+		if cols[0].name != m.getAllColumns()[m.colOffset].name {
+			t.Errorf("locked layout at offset %d starts with %q, want %q", m.colOffset, cols[0].name, m.getAllColumns()[m.colOffset].name)
+		}
+		for _, c := range cols {
+			seen[c.name] = true
+		}
+	}
+	if len(seen) != allCount {
+		t.Errorf("locked layout reached %d of %d columns", len(seen), allCount)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+		want  []string
+	}{
+		{"fits", "short text", 20, []string{"short text"}},
+		{"word boundary", "Cisco IOS Software, C3750E", 12, []string{"Cisco IOS", "Software,", "C3750E"}},
+		{"long word", "abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"collapses spaces", "a   b", 10, []string{"a b"}},
+		{"empty", "", 10, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapText(tt.input, tt.width)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+				t.Errorf("wrapText(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetailPopupWrapsLongFields(t *testing.T) {
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	description := "Cisco IOS Software, C3750E Software (C3750E-UNIVERSALK9-M), Version 15.0(2)SE11, RELEASE SOFTWARE (fc3) Technical Support: http://www.cisco.com/techsupport"
+	neighbor := &types.Neighbor{
+		ID:          "switch01",
+		Hostname:    "switch01.local",
+		Description: description,
+		SourceMAC:   mac,
+		Interface:   "eth0",
+		FirstSeen:   time.Now(),
+		LastSeen:    time.Now(),
+	}
+
+	for _, h := range []int{20, 22, 40} {
+		m := newTestTable(t, neighbor)
+		m.config.DetailWrapFields = []string{"description"}
+		m.height = h
+		m.showDetail = true
+		m.selectedIndex = 0
+
+		output := m.View()
+		if got := strings.Count(output, "\n") + 1; got != h {
+			t.Errorf("height %d: line count = %d, want %d", h, got, h)
+		}
+		if !strings.Contains(output, "ESC to close") {
+			t.Errorf("height %d: hint line cut off", h)
+		}
+		if h >= 40 && !strings.Contains(output, "Technical Support:") {
+			t.Errorf("height %d: full description not shown when there is room", h)
+		}
+	}
+
+	// Without wrapping the popup keeps its base height
+	m := newTestTable(t, neighbor)
+	m.config.DetailWrapFields = nil
+	m.height = 40
+	popup := strings.TrimSuffix(m.renderDetailPopup(neighbor, 38), "\n")
+	popupRows := 0
+	for _, line := range strings.Split(popup, "\n") {
+		if strings.TrimSpace(line) != "" {
+			popupRows++
+		}
+	}
+	if popupRows != detailPopupBaseLines {
+		t.Errorf("unwrapped popup has %d lines, want %d", popupRows, detailPopupBaseLines)
+	}
+}
+
+func TestCollapseStackedNeighbors(t *testing.T) {
+	m := newTestTable(t)
+	m.config.CollapseStacked = true
+
+	now := time.Now()
+	for i, port := range []string{"GigabitEthernet2/0/1", "GigabitEthernet1/0/1"} {
+		mac, _ := net.ParseMAC(fmt.Sprintf("00:11:22:33:44:%02x", i))
+		m.store.Update(&types.Neighbor{
+			ID:        "stack01",
+			Hostname:  "stack01.local",
+			PortID:    port,
+			Platform:  "cisco C9300",
+			Protocol:  types.ProtocolCDP,
+			SourceMAC: mac,
+			Interface: "eth0",
+			LastSeen:  now.Add(time.Duration(i) * time.Second),
+		})
+	}
+	other, _ := net.ParseMAC("00:11:22:33:55:00")
+	m.store.Update(&types.Neighbor{
+		ID:        "switch02",
+		Hostname:  "switch02.local",
+		PortID:    "Gi0/2",
+		SourceMAC: other,
+		Interface: "eth0",
+		LastSeen:  now,
+	})
+
+	m.width = 120
+
+	neighbors := m.getFilteredNeighbors()
+	if len(neighbors) != 2 {
+		t.Fatalf("getFilteredNeighbors() returned %d rows, want 2", len(neighbors))
+	}
+	if neighbors[0].PortID != "GigabitEthernet1/0/1" {
+		t.Errorf("stack row represented by %q, want most recently seen GigabitEthernet1/0/1", neighbors[0].PortID)
+	}
+
+	table := m.renderTable()
+	if !strings.Contains(table, "Gi1/0/1,Gi2/0/1") {
+		t.Errorf("table should list both stack ports:\n%s", table)
+	}
+
+	m.config.DetailWrapFields = []string{"ports"}
+	m.showDetail = true
+	detail := m.renderDetailView(neighbors[0])
+	if !strings.Contains(detail, "Ports:") || !strings.Contains(detail, "GigabitEthernet2/0/1") {
+		t.Errorf("detail view should list both stack ports:\n%s", detail)
+	}
+
+	m.config.CollapseStacked = false
+	if got := len(m.getFilteredNeighbors()); got != 3 {
+		t.Errorf("getFilteredNeighbors() without collapsing returned %d rows, want 3", got)
+	}
+}
+
+func TestRefreshKeys(t *testing.T) {
+	var neighbors []*types.Neighbor
+	for i := 0; i < 3; i++ {
+		mac, _ := net.ParseMAC(fmt.Sprintf("00:11:22:33:44:%02x", i))
+		neighbors = append(neighbors, &types.Neighbor{
+			Hostname:  fmt.Sprintf("switch%02d", i),
+			SourceMAC: mac,
+			Interface: "eth0",
+			LastSeen:  time.Now(),
+		})
+	}
+
+	press := func(m NeighborTableModel, k string) NeighborTableModel {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		return m
+	}
+
+	tests := []struct {
+		name       string
+		refreshKey string
+		key        string
+		wantReset  bool
+	}{
+		{"r redraws by default", "redraw", "r", false},
+		{"r resets when configured", "reset", "r", true},
+		{"R always resets", "redraw", "R", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestTable(t, neighbors...)
+			m.config.RefreshKey = tt.refreshKey
+			m.selectedIndex = 2
+			m.scrollOffset = 1
+
+			m = press(m, tt.key)
+			reset := m.selectedIndex == 0 && m.scrollOffset == 0
+			if reset != tt.wantReset {
+				t.Errorf("after %q selectedIndex = %d, scrollOffset = %d; want reset = %v",
+					tt.key, m.selectedIndex, m.scrollOffset, tt.wantReset)
+			}
+		})
+	}
+}
+
+func TestFooterTrimsOnNarrowTerminals(t *testing.T) {
+	m := newTestTable(t)
+	m.logPath = "/var/log/nbor/nbor-eth0-20240101-120000.csv"
+
+	tests := []struct {
+		width     int
+		wantLines int
+		want      []string
+		dropped   []string
+	}{
+		{160, 1, []string{"refresh", "R reset", "details", "quit", "log:"}, nil},
+		{80, 1, []string{"refresh", "details", "quit"}, []string{"log:", "R reset"}},
+		{50, 1, []string{"broadcast", "config", "quit"}, []string{"log:", "details", "select"}},
+		{20, 2, []string{"broadcast", "quit"}, []string{"config", "refresh"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("width %d", tt.width), func(t *testing.T) {
+			m.width = tt.width
+			footer := m.renderFooter()
+			lines := strings.Split(footer, "\n")
+			if len(lines) != tt.wantLines {
+				t.Errorf("footer has %d lines, want %d", len(lines), tt.wantLines)
+			}
+			for _, line := range lines {
+				if w := lipgloss.Width(line); w > tt.width {
+					t.Errorf("footer line is %d wide, want at most %d: %q", w, tt.width, line)
+				}
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(footer, s) {
+					t.Errorf("footer missing %q", s)
+				}
+			}
+			for _, s := range tt.dropped {
+				if strings.Contains(footer, s) {
+					t.Errorf("footer still shows %q", s)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderLocalAddresses(t *testing.T) {
+	cfg := config.DefaultConfig()
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	m := NewConfigMenu(&cfg)
+	m.ifaceInfo = types.InterfaceInfo{
+		Name:      "eth0",
+		MAC:       mac,
+		IPv4Addrs: []net.IP{net.ParseIP("192.168.1.10"), net.ParseIP("10.0.0.5")},
+		IPv6Addrs: []net.IP{net.ParseIP("2001:db8::1")},
+	}
+
+	out := m.renderBroadcast()
+	for _, want := range []string{"Local Addresses", "00:11:22:33:44:55", "CDP + LLDP mgmt", "2001:db8::1", "not advertised"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Broadcast Options missing %q", want)
+		}
+	}
+	if strings.Count(out, "CDP + LLDP mgmt") != 1 {
+		t.Error("more than one address marked as the LLDP management address")
+	}
+
+	cfg.ShowLocalAddresses = false
+	if strings.Contains(m.renderBroadcast(), "Local Addresses") {
+		t.Error("local addresses shown with show_local_addresses off")
+	}
+}
+
+func TestPingCommand(t *testing.T) {
+	ip := net.ParseIP("192.0.2.1")
+
+	unix := pingCommand("linux", ip, 3)
+	if got := strings.Join(unix.Args, " "); !strings.HasSuffix(got, " sh 3 192.0.2.1") {
+		t.Errorf("linux ping args = %q, want the count and IP passed as arguments", got)
+	}
+	if !strings.Contains(unix.Args[2], `ping -c "$1" "$2"`) {
+		t.Errorf("linux ping script = %q, want ping -c", unix.Args[2])
+	}
+
+	windows := pingCommand("windows", ip, 3)
+	if got, want := strings.Join(windows.Args, " "), "cmd /c ping -n 3 192.0.2.1 & pause"; got != want {
+		t.Errorf("windows ping args = %q, want %q", got, want)
+	}
+}
+
+func TestDetailActionsNeedManagementIP(t *testing.T) {
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	m := newTestTable(t, &types.Neighbor{Hostname: "switch01", SourceMAC: mac, Interface: "eth0", LastSeen: time.Now()})
+	m.showDetail = true
+
+	for _, k := range []string{"y", "p", "u"} {
+		got, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		if cmd != nil {
+			t.Errorf("%s without a management IP returned a command", k)
+		}
+		if got.notice != "no management IP to use" {
+			t.Errorf("%s notice = %q, want a missing IP notice", k, got.notice)
+		}
+		if got.showQR {
+			t.Errorf("%s without a management IP showed a QR code", k)
+		}
+	}
+}
+
+func TestDetailHintsFollowState(t *testing.T) {
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	m := newTestTable(t, &types.Neighbor{Hostname: "switch01", SourceMAC: mac, Interface: "eth0",
+		ManagementIP: net.ParseIP("10.1.2.3"), LastSeen: time.Now()})
+	m.height = 40
+	m.showDetail = true
+
+	if view := m.View(); !strings.Contains(view, "a adv") || strings.Contains(view, "hide adv") {
+		t.Error("detail hint should offer to show advanced details")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if !strings.Contains(m.View(), "a hide adv") {
+		t.Error("detail hint should offer to hide advanced details once shown")
+	}
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if cmd == nil {
+		t.Fatal("y didn't send the copy")
+	}
+	if m.notice != "copy sent to terminal: 10.1.2.3" {
+		t.Errorf("notice = %q, want the copy reported as sent", m.notice)
+	}
+}
+
+func TestDetailQRCode(t *testing.T) {
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	m := newTestTable(t, &types.Neighbor{Hostname: "switch01", SourceMAC: mac, Interface: "eth0",
+		ManagementIP: net.ParseIP("10.1.2.3"), LastSeen: time.Now()})
+	m.showDetail = true
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	if !m.showQR {
+		t.Fatal("u with a management IP didn't show the QR code")
+	}
+	view := m.View()
+	if !strings.Contains(view, "http://10.1.2.3") {
+		t.Error("QR view is missing the management URL")
+	}
+	if !strings.Contains(view, "▀") && !strings.Contains(view, "█") {
+		t.Error("QR view has no code")
+	}
+
+	// Escape closes the QR code but leaves the detail popup open
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showQR || !m.showDetail {
+		t.Errorf("after esc showQR = %v, showDetail = %v; want false, true", m.showQR, m.showDetail)
+	}
+
+	if got := managementURL(net.ParseIP("2001:db8::1")); got != "http://[2001:db8::1]" {
+		t.Errorf("managementURL(IPv6) = %q", got)
+	}
+}
+
+func TestEventStrip(t *testing.T) {
+	m := newTestTable(t)
+	m.config.EventStripLines = 1
+	m.width = 60
+	m.height = 20
+
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	old := &types.Neighbor{Hostname: "old-ap", SourceMAC: mac, Interface: "eth0", LastSeen: time.Now().Add(-time.Hour)}
+	m.store.Update(old)
+	m.store.MarkStale(time.Minute)
+	m.store.RemoveStale(time.Minute)
+
+	for i := 0; i < 10; i++ {
+		mac, _ := net.ParseMAC(fmt.Sprintf("00:11:22:33:45:%02x", i))
+		n := &types.Neighbor{Hostname: fmt.Sprintf("core-sw%d", i), PortID: "GigabitEthernet0/1", SourceMAC: mac, Interface: "eth0"}
+		m, _ = m.Update(NewNeighborMsg{Neighbor: n})
+	}
+
+	strip := m.renderEventStrip()
+	if strings.Count(strip, "\n") != 0 {
+		t.Errorf("one-line strip rendered %d lines", strings.Count(strip, "\n")+1)
+	}
+	if w := lipgloss.Width(strip); w > m.width {
+		t.Errorf("strip is %d wide, want at most %d", w, m.width)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(strip), "+ core-sw9 Gi0/1") {
+		t.Errorf("strip doesn't start with the newest event: %q", strip)
+	}
+
+	// With two lines the older stale and removal events also fit
+	m.config.EventStripLines = 2
+	m.width = 200
+	strip = m.renderEventStrip()
+	if !strings.Contains(strip, "~ old-ap") || !strings.Contains(strip, "− old-ap") {
+		t.Errorf("strip missing stale/removed events: %q", strip)
+	}
+	if got := strings.Count(m.View(), "\n") + 1; got != m.height {
+		t.Errorf("view is %d lines with the strip, want %d", got, m.height)
+	}
+}
+
+func TestDetailShowsSupportedCapabilities(t *testing.T) {
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	m := newTestTable(t, &types.Neighbor{
+		Hostname:              "dist01",
+		SourceMAC:             mac,
+		Interface:             "eth0",
+		Protocol:              types.ProtocolLLDP,
+		Capabilities:          []types.Capability{types.CapBridge},
+		SupportedCapabilities: []types.Capability{types.CapRouter, types.CapBridge},
+		LastSeen:              time.Now(),
+	})
+
+	m.height = 24
+	m.showDetail = true
+
+	output := m.View()
+	if !strings.Contains(output, "Enabled:") || !strings.Contains(output, "Supported:") {
+		t.Error("detail popup doesn't split enabled and supported capabilities")
+	}
+	if lines := strings.Count(output, "\n") + 1; lines != m.height {
+		t.Errorf("got %d lines, want %d", lines, m.height)
+	}
+}
+
+func TestFormatMEDPolicy(t *testing.T) {
+	tests := []struct {
+		policy    types.MEDPolicy
+		wantLabel string
+		wantValue string
+	}{
+		{types.MEDPolicy{Application: 1, Tagged: true, VLAN: 200, DSCP: 46}, "Voice VLAN:", "200 (DSCP 46)"},
+		{types.MEDPolicy{Application: 1, VLAN: 0, DSCP: 46}, "Voice VLAN:", "0 untagged (DSCP 46)"},
+		{types.MEDPolicy{Application: 6, Tagged: true, VLAN: 300, DSCP: 34}, "Policy VLAN:", "300 (Video Conferencing, DSCP 34)"},
+		{types.MEDPolicy{Application: 1, Unknown: true}, "Voice VLAN:", "policy unknown"},
+	}
+
+	for _, tt := range tests {
+		label, value := formatMEDPolicy(&tt.policy)
+		if label != tt.wantLabel || value != tt.wantValue {
+			t.Errorf("formatMEDPolicy(%+v) = %q, %q; want %q, %q", tt.policy, label, value, tt.wantLabel, tt.wantValue)
+		}
+	}
+}
+
+func TestNeighborTableSort(t *testing.T) {
+	m := newTestTable(t)
+	now := time.Now()
+	for i, host := range []string{"bravo", "alpha", "charlie"} {
+		m.store.Update(&types.Neighbor{
+			ID:        host,
+			Hostname:  host,
+			SourceMAC: net.HardwareAddr{0, 0, 0, 0, 0, byte(i)},
+			Interface: "eth0",
+			FirstSeen: now,
+			LastSeen:  now.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	m.width = 120
+
+	order := func() string {
+		var names []string
+		for _, n := range m.getFilteredNeighbors() {
+			names = append(names, n.Hostname)
+		}
+		return strings.Join(names, ",")
+	}
+
+	if got := order(); got != "alpha,bravo,charlie" {
+		t.Errorf("default order = %s, want alpha,bravo,charlie", got)
+	}
+	if !strings.Contains(m.renderTable(), "Hostname ▲") {
+		t.Error("header should mark the sorted column")
+	}
+
+	// Select bravo, then sort by Last Seen descending: newest first
+	m.selectedIndex = 1
+	m = m.resort("Last Seen", false)
+	if got := order(); got != "charlie,alpha,bravo" {
+		t.Errorf("Last Seen descending = %s, want charlie,alpha,bravo", got)
+	}
+	if n := m.getSelectedNeighbor(); n == nil || n.Hostname != "bravo" {
+		t.Errorf("selection should follow bravo after resorting, got %v", n)
+	}
+	if !strings.Contains(m.renderTable(), "Last Seen ▼") {
+		t.Error("header should show the descending arrow on Last Seen")
+	}
+
+	// s moves on to the next visible column
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if m.sortColumn != "Mgmt IP" {
+		t.Errorf("sortColumn after s = %q, want Mgmt IP", m.sortColumn)
+	}
+}
+
+func TestStaleRemovalClampsSelection(t *testing.T) {
+	m := newTestTable(t)
+	m.config.StaleRemovalTime = 60
+	old := time.Now().Add(-time.Hour)
+	for i := 0; i < 30; i++ {
+		lastSeen := time.Now()
+		if i >= 5 {
+			lastSeen = old
+		}
+		m.store.Update(&types.Neighbor{
+			ID:        fmt.Sprintf("sw%02d", i),
+			Hostname:  fmt.Sprintf("sw%02d", i),
+			SourceMAC: net.HardwareAddr{0, 0, 0, 0, 0, byte(i)},
+			Interface: "eth0",
+			FirstSeen: old,
+			LastSeen:  lastSeen,
+		})
+	}
+
+	m.width = 100
+	m.height = 20
+	m.selectedIndex = 29
+	m.scrollOffset = 29 - m.visibleRows() + 1
+
+	m, _ = m.Update(TickMsg(time.Now()))
+
+	if got := m.store.Count(); got != 5 {
+		t.Fatalf("store has %d neighbors after removal, want 5", got)
+	}
+	if m.selectedIndex != 4 {
+		t.Errorf("selectedIndex = %d, want 4", m.selectedIndex)
+	}
+	if m.scrollOffset != 0 {
+		t.Errorf("scrollOffset = %d, want 0", m.scrollOffset)
+	}
+	if !strings.Contains(m.renderTable(), "sw04") {
+		t.Error("table should still show the remaining neighbors")
+	}
+}
+
+func TestStalenessTimeoutFromConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		timeout   int
+		wantStale bool
+	}{
+		{"shorter than silence", 240, true},
+		{"longer than silence", 600, false},
+		{"disabled", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestTable(t, &types.Neighbor{
+				ID:        "sw1",
+				Hostname:  "sw1",
+				Interface: "eth0",
+				FirstSeen: time.Now().Add(-time.Hour),
+				LastSeen:  time.Now().Add(-5 * time.Minute),
+			})
+			m.config.StalenessTimeout = tt.timeout
+			m.config.StalenessFromTTL = false
+			// Count silence from before the neighbor was last heard
+			m.agingFrom = map[string]time.Time{"eth0": time.Now().Add(-time.Hour)}
+			m, _ = m.Update(TickMsg(time.Now()))
+
+			if got := m.store.GetAll()[0].IsStale; got != tt.wantStale {
+				t.Errorf("IsStale = %v after 5 minutes with staleness_timeout %d, want %v", got, tt.timeout, tt.wantStale)
+			}
+		})
+	}
+}
+
+func TestStalenessTimeoutZeroNeverStale(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.StalenessTimeout = 0
+	cfg.StalenessFromTTL = true
+	store := types.NewNeighborStore()
+	add := func(id string, ttl, silent time.Duration) {
+		store.Update(&types.Neighbor{ID: id, Hostname: id, Interface: "eth0", TTL: ttl, LastSeen: time.Now().Add(-silent)})
+	}
+	add("ttl", 2*time.Minute, 5*time.Minute)
+	add("no-ttl", 0, 5*time.Minute)
+
+	AgeNeighbors(store, &cfg, nil)
+
+	// staleness_timeout = 0 turns aging off, even for a neighbor past its TTL
+	for _, n := range store.GetAll() {
+		if n.IsStale {
+			t.Errorf("%s went stale with staleness_timeout = 0", n.ID)
+		}
+	}
+}
+
+func TestCapabilityFilterHeaderCount(t *testing.T) {
+	m := newTestTable(t)
+	for i, c := range []types.Capability{types.CapPhone, types.CapSwitch, types.CapPhone} {
+		m.store.Update(&types.Neighbor{
+			ID:           fmt.Sprintf("dev%d", i),
+			Hostname:     fmt.Sprintf("dev%d", i),
+			SourceMAC:    net.HardwareAddr{0, 0, 0, 0, 0, byte(i)},
+			Interface:    "eth0",
+			LastSeen:     time.Now(),
+			Capabilities: []types.Capability{c},
+		})
+	}
+
+	m.width = 120
+
+	if got := len(m.getFilteredNeighbors()); got != 3 {
+		t.Fatalf("unfiltered table has %d rows, want 3", got)
+	}
+
+	// The config menu updates the shared config in place
+	m.config.FilterCapabilities = []string{"phone"}
+	if got := len(m.getFilteredNeighbors()); got != 2 {
+		t.Errorf("filtered table has %d rows, want 2", got)
+	}
+	if header := m.renderBaseView(); !strings.Contains(header, "2/3") {
+		t.Error("header should show the filtered and total counts")
+	}
+}
+
+func TestLinkDownEmptyTable(t *testing.T) {
+	m := newTestTable(t)
+	m.width = 100
+
+	m, _ = m.Update(LinkStateMsg{Interface: "eth0", Up: false})
+	view := m.renderBaseView()
+	if !strings.Contains(view, "LINK DOWN on eth0") {
+		t.Error("header should show the link down banner")
+	}
+	if !strings.Contains(view, "Link is down on eth0") {
+		t.Error("empty table should explain that the link is down")
+	}
+
+	m, _ = m.Update(LinkStateMsg{Interface: "eth0", Up: true})
+	if strings.Contains(m.renderBaseView(), "Link is down") {
+		t.Error("link down message should clear when the link returns")
+	}
+}
+
+func TestMultiInterfaceTable(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ClearOnLinkDown = true
+	store := types.NewNeighborStore()
+	eth0 := types.InterfaceInfo{Name: "eth0"}
+	eth1 := types.InterfaceInfo{Name: "eth1"}
+	store.Update(&types.Neighbor{Hostname: "sw-a", Interface: "eth0", SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, 5}, LastSeen: time.Now()})
+	store.Update(&types.Neighbor{Hostname: "sw-b", Interface: "eth1", SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, 6}, LastSeen: time.Now()})
+
+	single := NewNeighborTable(store, eth0, "", &cfg)
+	single.width = 140
+	single.height = 30
+	for _, col := range single.getAllColumns() {
+		if col.name == "Interface" {
+			t.Error("single-interface table shouldn't have an Interface column")
+		}
+	}
+
+	m := NewNeighborTable(store, eth0, "", &cfg)
+	m.interfaces = []types.InterfaceInfo{eth0, eth1}
+	m.width = 140
+	m.height = 30
+	view := m.renderBaseView()
+	if !strings.Contains(view, "eth0, eth1") {
+		t.Error("header should list every capture interface")
+	}
+	if !strings.Contains(view, "Interface") {
+		t.Error("multi-interface table should have an Interface column")
+	}
+
+	// Losing one link only clears the neighbors it heard
+	m, _ = m.Update(LinkStateMsg{Interface: "eth1", Up: false})
+	if store.Count() != 1 {
+		t.Errorf("store has %d neighbors after eth1 went down, want 1", store.Count())
+	}
+	view = m.renderBaseView()
+	if !strings.Contains(view, "LINK DOWN on eth1") {
+		t.Error("header should name the interface that lost link")
+	}
+	if !strings.Contains(view, "sw-a") {
+		t.Error("neighbors on eth0 should still be shown")
+	}
+}
+
+func TestMultiInterfaceAging(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.StalenessTimeout = 60
+	cfg.StalenessFromTTL = false
+	store := types.NewNeighborStore()
+	eth0 := types.InterfaceInfo{Name: "eth0"}
+	eth1 := types.InterfaceInfo{Name: "eth1"}
+	old := time.Now().Add(-2 * time.Minute)
+	store.Update(&types.Neighbor{Hostname: "sw-a", Interface: "eth0", SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, 5}, LastSeen: old})
+	store.Update(&types.Neighbor{Hostname: "sw-b", Interface: "eth1", SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, 6}, LastSeen: old})
+	stale := func(host string) bool {
+		for _, n := range store.GetAll() {
+			if n.Hostname == host {
+				return n.IsStale
+			}
+		}
+		t.Fatalf("%s not in the store", host)
+		return false
+	}
+
+	m := NewNeighborTable(store, eth0, "", &cfg)
+	m.interfaces = []types.InterfaceInfo{eth0, eth1}
+
+	// eth1 losing link pauses aging there only
+	m, _ = m.Update(LinkStateMsg{Interface: "eth1", Up: false})
+	m, _ = m.Update(TickMsg(time.Now()))
+	if !stale("sw-a") {
+		t.Error("sw-a on eth0 didn't age while eth1 was down")
+	}
+	if stale("sw-b") {
+		t.Error("sw-b aged while its link was down")
+	}
+
+	// When eth1 comes back its neighbors get a full timeout; eth0's don't
+	m, _ = m.Update(LinkStateMsg{Interface: "eth1", Up: true})
+	if m.linkDown() {
+		t.Error("LINK DOWN still shown after eth1 came back")
+	}
+	if !m.agingFrom["eth0"].IsZero() || m.agingFrom["eth1"].IsZero() {
+		t.Errorf("agingFrom = %v, want only eth1 reset", m.agingFrom)
+	}
+	m, _ = m.Update(TickMsg(time.Now()))
+	if stale("sw-b") {
+		t.Error("sw-b went stale right after eth1 came back")
+	}
+}
+
+func TestVLANColumn(t *testing.T) {
+	m := newTestTable(t,
+		&types.Neighbor{Hostname: "sw-cdp", Protocol: types.ProtocolCDP, SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, 5}, NativeVLAN: 20, LastSeen: time.Now()},
+		&types.Neighbor{Hostname: "sw-lldp", Protocol: types.ProtocolLLDP, SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, 6}, PortVLAN: 30, LastSeen: time.Now()},
+	)
+	m.width = 160
+
+	var vlan *column
+	cols := m.getVisibleColumns()
+	for i := range cols {
+		if cols[i].name == "VLAN" {
+			vlan = &cols[i]
+		}
+	}
+	if vlan == nil {
+		t.Fatal("wide table should show the VLAN column")
+	}
+	for _, n := range m.store.GetAll() {
+		want := map[string]string{"sw-cdp": "20", "sw-lldp": "30"}[n.Hostname]
+		if got := vlan.getter(n); got != want {
+			t.Errorf("VLAN for %s = %q, want %q", n.Hostname, got, want)
+		}
+	}
+
+	m.width = 50
+	for _, col := range m.getVisibleColumns() {
+		if col.name == "VLAN" {
+			t.Error("narrow table should drop the VLAN column")
+		}
+	}
+}
+
+func TestProtocolCellStyle(t *testing.T) {
+	m := newTestTable(t)
+	row := m.styles.TableRowActive
+
+	if got := m.protocolCellStyle(row, types.ProtocolCDP); got.GetForeground() != row.GetForeground() {
+		t.Error("Proto cell shouldn't be tinted with protocol_colors off")
+	}
+
+	m.config.ProtocolColors = true
+	tests := []struct {
+		protocol types.Protocol
+		want     lipgloss.Style
+	}{
+		{types.ProtocolCDP, m.styles.ProtoCDP},
+		{types.ProtocolLLDP, m.styles.ProtoLLDP},
+		{types.ProtocolBoth, m.styles.ProtoBoth},
+	}
+	for _, tt := range tests {
+		if got := m.protocolCellStyle(row, tt.protocol); got.GetForeground() != tt.want.GetForeground() {
+			t.Errorf("%s cell foreground = %v, want %v", tt.protocol, got.GetForeground(), tt.want.GetForeground())
+		}
+	}
+}
+
+func TestClearAllNeighbors(t *testing.T) {
+	m := newTestTable(t,
+		&types.Neighbor{Hostname: "sw-a", SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, 5}, LastSeen: time.Now()},
+		&types.Neighbor{Hostname: "sw-b", SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, 6}, LastSeen: time.Now()},
+	)
+	m.width = 120
+	m.selectedIndex = 1
+	x := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}}
+
+	// esc cancels the prompt and keeps the neighbors
+	m, _ = m.Update(x)
+	if !strings.Contains(m.renderFooter(), "Clear all neighbors?") {
+		t.Error("footer should ask to confirm the clear")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.confirmClear || m.store.Count() != 2 {
+		t.Errorf("after esc: confirmClear = %v, %d neighbors, want false and 2", m.confirmClear, m.store.Count())
+	}
+
+	m, _ = m.Update(x)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if m.store.Count() != 0 {
+		t.Errorf("store has %d neighbors after confirming, want 0", m.store.Count())
+	}
+	if m.selectedIndex != 0 || m.scrollOffset != 0 {
+		t.Errorf("selection = %d, scroll = %d, want 0 and 0", m.selectedIndex, m.scrollOffset)
+	}
+}
+
+func TestPageKeys(t *testing.T) {
+	m := newTestTable(t)
+	for i := 0; i < 50; i++ {
+		m.store.Update(&types.Neighbor{
+			Hostname:  fmt.Sprintf("sw-%02d", i),
+			SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, byte(i)},
+			LastSeen:  time.Now(),
+		})
+	}
+
+	m.width = 120
+	m.height = 26 // 20 visible rows
+	rows := m.visibleRows()
+
+	tests := []struct {
+		key        tea.KeyType
+		wantSel    int
+		wantScroll int
+	}{
+		{tea.KeyPgDown, rows, rows},
+		{tea.KeyPgDown, 2 * rows, 50 - rows},
+		{tea.KeyPgDown, 49, 50 - rows},
+		{tea.KeyPgUp, 49 - rows, 50 - 2*rows},
+		{tea.KeyHome, 0, 0},
+		{tea.KeyPgUp, 0, 0},
+		{tea.KeyEnd, 49, 50 - rows},
+	}
+	for i, tt := range tests {
+		m, _ = m.Update(tea.KeyMsg{Type: tt.key})
+		if m.selectedIndex != tt.wantSel || m.scrollOffset != tt.wantScroll {
+			t.Errorf("step %d (%v): selection = %d, scroll = %d, want %d and %d",
+				i, tt.key, m.selectedIndex, m.scrollOffset, tt.wantSel, tt.wantScroll)
+		}
+	}
+}
+
+func TestCaptureStatsFooter(t *testing.T) {
+	m := newTestTable(t)
+	m.width = 160
+	m, _ = m.Update(CaptureStatsMsg{Received: 120, Dropped: 3})
+
+	if strings.Contains(m.renderFooter(), "rx 120") {
+		t.Error("footer shouldn't show capture stats unless show_capture_stats is on")
+	}
+
+	m.config.ShowCaptureStats = true
+	if footer := m.renderFooter(); !strings.Contains(footer, "rx 120") || !strings.Contains(footer, "drop 3") {
+		t.Errorf("footer = %q, want rx 120 and drop 3", footer)
+	}
+}
+
+func TestFormatTTL(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ttl      time.Duration
+		lastSeen time.Time
+		want     string
+	}{
+		{120 * time.Second, now.Add(-35 * time.Second), "120s, expires in 85s"},
+		{180 * time.Second, now, "180s, expires in 180s"},
+		{20 * time.Second, now.Add(-25 * time.Second), "20s, expired 5s ago"},
+	}
+	for _, tt := range tests {
+		if got := formatTTL(tt.ttl, tt.lastSeen, now); got != tt.want {
+			t.Errorf("formatTTL(%v, -%v) = %q, want %q", tt.ttl, now.Sub(tt.lastSeen), got, tt.want)
+		}
+	}
+}
+
+func TestDetailInventory(t *testing.T) {
+	m := newTestTable(t, &types.Neighbor{
+		Hostname:  "phone01",
+		SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, 5},
+		Inventory: &types.Inventory{SerialNumber: "FOC1234X", Model: "IP Phone 8841"},
+		FirstSeen: time.Now(),
+		LastSeen:  time.Now(),
+	})
+
+	m.height = 40
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	view := m.View()
+	if !strings.Contains(view, "Inventory:") || strings.Contains(view, "FOC1234X") {
+		t.Error("inventory should start collapsed to one row")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	view = m.View()
+	if !strings.Contains(view, "FOC1234X") || !strings.Contains(view, "IP Phone 8841") {
+		t.Error("expanded inventory should show the serial number and model")
+	}
+	if strings.Contains(view, "Asset ID:") {
+		t.Error("inventory fields that weren't sent shouldn't be shown")
+	}
+	if got := strings.Count(view, "\n") + 1; got != m.height {
+		t.Errorf("line count = %d, want %d", got, m.height)
+	}
+}
+
+func TestCapabilityLegend(t *testing.T) {
+	m := newTestTable(t)
+	for i, caps := range [][]types.Capability{
+		{types.CapSwitch, types.CapRouter},
+		{types.CapSwitch},
+		{types.CapPhone},
+	} {
+		m.store.Update(&types.Neighbor{
+			Hostname:     fmt.Sprintf("dev%d", i),
+			SourceMAC:    net.HardwareAddr{0, 1, 2, 3, 4, byte(i)},
+			Capabilities: caps,
+			FirstSeen:    time.Now(),
+			LastSeen:     time.Now(),
+		})
+	}
+
+	counts := capabilityCounts(m.store.GetAll())
+	if counts[types.CapSwitch] != 2 || counts[types.CapRouter] != 1 || counts[types.CapPhone] != 1 {
+		t.Errorf("capabilityCounts() = %v, want Switch 2, Router 1, Phone 1", counts)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+
+	view := m.View()
+	for _, entry := range capabilityLegend {
+		if !strings.Contains(view, entry.meaning) {
+			t.Errorf("legend is missing %s: %q", entry.cap, entry.meaning)
+		}
+	}
+	if !strings.Contains(view, "R clears new-neighbor highlights") {
+		t.Error("legend doesn't explain the R key")
+	}
+	if got := strings.Count(view, "\n") + 1; got != m.height {
+		t.Errorf("line count = %d, want %d", got, m.height)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showLegend {
+		t.Error("esc should close the legend")
+	}
+}
+
+func TestDetailMultilineDescription(t *testing.T) {
+	m := newTestTable(t, &types.Neighbor{
+		Hostname:    "switch01",
+		SourceMAC:   net.HardwareAddr{0, 1, 2, 3, 4, 5},
+		Description: "Cisco IOS 15.2(4)E10\nTechnical Support: http://www.cisco.com/techsupport",
+		FirstSeen:   time.Now(),
+		LastSeen:    time.Now(),
+	})
+	m.config.DetailWrapFields = []string{}
+
+	m.width = 100
+	m.height = 40
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	view := m.View()
+	if !strings.Contains(view, "Cisco IOS 15.2(4)E10") {
+		t.Error("detail should show the first line of the description")
+	}
+	if strings.Contains(view, "Technical Support") {
+		t.Error("an unwrapped description should stop at its first line")
+	}
+	if got := strings.Count(view, "\n") + 1; got != m.height {
+		t.Errorf("line count = %d, want %d", got, m.height)
+	}
+}
+
+func TestDiscoveredColumn(t *testing.T) {
+	m := newTestTable(t, &types.Neighbor{
+		Hostname:  "sw-late",
+		SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, 5},
+		LastSeen:  time.Now(),
+	})
+	// The store stamps FirstSeen on insert; backdate it
+	m.store.GetAll()[0].FirstSeen = time.Now().Add(-3 * time.Minute)
+
+	hasDiscovered := func(m NeighborTableModel) bool {
+		for _, col := range m.getAllColumns() {
+			if col.name == "Discovered" {
+				return true
+			}
+		}
+		return false
+	}
+
+	m.width = 200
+	m.height = 40
+	if hasDiscovered(m) {
+		t.Error("Discovered column should be hidden by default")
+	}
+
+	m.config.ShowDiscoveredColumn = true
+	if !hasDiscovered(m) {
+		t.Fatal("show_discovered_column should add the Discovered column")
+	}
+	if !strings.Contains(m.View(), "Discovered") {
+		t.Error("wide table should show the Discovered header")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if view := m.View(); !strings.Contains(view, "Discovered:") || !strings.Contains(view, "3m ago") {
+		t.Error("detail popup should show when the neighbor was discovered")
+	}
+}
+
+func TestQuietWarning(t *testing.T) {
+	m := newTestTable(t)
+	m.width = 120
+
+	if strings.Contains(m.View(), "Check that:") {
+		t.Error("quiet warning shown right after capture started")
+	}
+
+	m.listenStart = time.Now().Add(-2 * time.Minute)
+	if view := m.View(); !strings.Contains(view, "No CDP or LLDP packets for 2m0s") {
+		t.Error("quiet warning missing after 2 minutes without packets")
+	}
+
+	// Any CDP/LLDP packet, even one that doesn't parse, restarts the wait
+	m, _ = m.Update(PacketSeenMsg{})
+	if strings.Contains(m.View(), "Check that:") {
+		t.Error("quiet warning shown after a packet arrived")
+	}
+
+	m.lastPacketAt = time.Time{}
+	m.config.QuietWarningSeconds = 0
+	if strings.Contains(m.View(), "Check that:") {
+		t.Error("quiet_warning_seconds = 0 should turn the warning off")
+	}
+}
+
+func TestStaleFade(t *testing.T) {
+	m := newTestTable(t)
+	m.config.StalenessTimeout = 60
+	m.config.StalenessFromTTL = false
+	m.agingFrom = nil
+
+	now := time.Now()
+	n := &types.Neighbor{IsStale: true}
+	active := m.styles.TableRowActive.GetForeground()
+	stale := m.styles.TableCellStale.GetForeground()
+
+	// Just past the timeout the row is still close to its active color
+	n.LastSeen = now.Add(-61 * time.Second)
+	fg := m.staleCellStyle(n, now).GetForeground()
+	if fg == stale || fg == active {
+		t.Errorf("row 1s into the fade = %v, want a shade between %v and %v", fg, active, stale)
+	}
+
+	n.LastSeen = now.Add(-2 * time.Minute)
+	if fg := m.staleCellStyle(n, now).GetForeground(); fg != stale {
+		t.Errorf("row well past the fade = %v, want %v", fg, stale)
+	}
+
+	m.config.StaleFade = false
+	n.LastSeen = now.Add(-61 * time.Second)
+	if fg := m.staleCellStyle(n, now).GetForeground(); fg != stale {
+		t.Errorf("with stale_fade off the row = %v, want %v at once", fg, stale)
+	}
+}
+
+func TestBlendColor(t *testing.T) {
+	tests := []struct {
+		from, to lipgloss.TerminalColor
+		t        float64
+		want     lipgloss.TerminalColor
+	}{
+		{lipgloss.Color("#000000"), lipgloss.Color("#ffffff"), 0, lipgloss.Color("#000000")},
+		{lipgloss.Color("#000000"), lipgloss.Color("#ffffff"), 0.5, lipgloss.Color("#808080")},
+		{lipgloss.Color("#204060"), lipgloss.Color("#a0c0e0"), 1, lipgloss.Color("#a0c0e0")},
+		{lipgloss.Color("2"), lipgloss.Color("#ffffff"), 0.5, lipgloss.Color("#ffffff")},
+	}
+	for _, tt := range tests {
+		if got := blendColor(tt.from, tt.to, tt.t); got != tt.want {
+			t.Errorf("blendColor(%v, %v, %v) = %v, want %v", tt.from, tt.to, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestDetailNavigation(t *testing.T) {
+	m := newTestTable(t)
+	for i, name := range []string{"sw-a", "sw-b", "sw-c"} {
+		m.store.Update(&types.Neighbor{
+			Hostname:  name,
+			SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, byte(i)},
+			LastSeen:  time.Now(),
+		})
+	}
+	m.width = 120
+	m.height = 40
+	order := m.getFilteredNeighbors()
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	steps := []struct {
+		msg  tea.KeyMsg
+		want int
+	}{
+		{tea.KeyMsg{Type: tea.KeyDown}, 1},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")}, 2},
+		{tea.KeyMsg{Type: tea.KeyDown}, 0}, // wraps past the end
+		{tea.KeyMsg{Type: tea.KeyUp}, 2},   // and back past the start
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")}, 1},
+	}
+	for _, step := range steps {
+		m, _ = m.Update(step.msg)
+		if !m.showDetail {
+			t.Fatalf("%s closed the detail popup", step.msg)
+		}
+		if m.selectedIndex != step.want {
+			t.Fatalf("after %s selectedIndex = %d, want %d", step.msg, m.selectedIndex, step.want)
+		}
+		if view := m.View(); !strings.Contains(view, order[step.want].Hostname) {
+			t.Errorf("after %s popup doesn't show %s", step.msg, order[step.want].Hostname)
+		}
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showDetail || m.selectedIndex != 1 {
+		t.Errorf("closing the popup: showDetail = %v, selectedIndex = %d, want false, 1", m.showDetail, m.selectedIndex)
+	}
+}
+
+func TestCaptureSummary(t *testing.T) {
+	m := newTestTable(t)
+	devices := []struct {
+		protocol types.Protocol
+		caps     []types.Capability
+		ip       string
+	}{
+		{types.ProtocolCDP, []types.Capability{types.CapSwitch}, "10.0.1.2"},
+		{types.ProtocolCDP, []types.Capability{types.CapSwitch, types.CapRouter}, "10.0.1.3"},
+		{types.ProtocolLLDP, []types.Capability{types.CapPhone}, "10.0.2.40"},
+		{types.ProtocolBoth, []types.Capability{types.CapSwitch}, ""},
+	}
+	for i, d := range devices {
+		m.store.Update(&types.Neighbor{
+			Hostname:     fmt.Sprintf("dev%d", i),
+			SourceMAC:    net.HardwareAddr{0, 1, 2, 3, 4, byte(i)},
+			Protocol:     d.protocol,
+			Capabilities: d.caps,
+			ManagementIP: net.ParseIP(d.ip),
+			LastSeen:     time.Now(),
+		})
+	}
+	m.store.GetAll()[0].IsStale = true
+
+	s := summarizeNeighbors(m.store.GetAll())
+	if s.total != 4 || s.active != 3 || s.stale != 1 {
+		t.Errorf("total, active, stale = %d, %d, %d, want 4, 3, 1", s.total, s.active, s.stale)
+	}
+	if s.byProtocol[types.ProtocolCDP] != 2 || s.byProtocol[types.ProtocolLLDP] != 1 || s.byProtocol[types.ProtocolBoth] != 1 {
+		t.Errorf("byProtocol = %v, want CDP 2, LLDP 1, CDP+LLDP 1", s.byProtocol)
+	}
+	if s.capabilities[types.CapSwitch] != 3 || s.capabilities[types.CapPhone] != 1 {
+		t.Errorf("capabilities = %v, want Switch 3, Phone 1", s.capabilities)
+	}
+	if want := []string{"10.0.1.0/24", "10.0.2.0/24"}; !slices.Equal(s.subnets, want) {
+		t.Errorf("subnets = %v, want %v", s.subnets, want)
+	}
+
+	m.height = 40
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	view := m.View()
+	for _, want := range []string{"Capture Summary", "10.0.1.0/24", "Phone"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("summary is missing %q", want)
+		}
+	}
+	if got := strings.Count(view, "\n") + 1; got != m.height {
+		t.Errorf("line count = %d, want %d", got, m.height)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showSummary {
+		t.Error("esc should close the summary")
+	}
+}
+
+func TestConfirmBroadcast(t *testing.T) {
+	m := newTestTable(t)
+	m.ifaceInfo = types.InterfaceInfo{Name: "eth0", IPv4Addrs: []net.IP{net.ParseIP("10.1.2.3").To4()}}
+	m.width = 120
+	m.height = 40
+	b := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}}
+
+	m, cmd := m.Update(b)
+	if !m.confirmBroadcast || m.broadcasting || cmd != nil {
+		t.Fatalf("b: confirmBroadcast = %v, broadcasting = %v, cmd = %v, want a prompt and no broadcast", m.confirmBroadcast, m.broadcasting, cmd)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Start broadcasting?") || !strings.Contains(view, "eth0 10.1.2.3") {
+		t.Errorf("prompt doesn't warn about the routable address:\n%s", view)
+	}
+
+	// esc cancels
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.confirmBroadcast || m.broadcasting {
+		t.Errorf("after esc: confirmBroadcast = %v, broadcasting = %v, want both false", m.confirmBroadcast, m.broadcasting)
+	}
+
+	// b twice starts broadcasting
+	m, _ = m.Update(b)
+	m, cmd = m.Update(b)
+	if m.confirmBroadcast || !m.broadcasting || cmd == nil {
+		t.Fatalf("b b: confirmBroadcast = %v, broadcasting = %v, want a broadcast", m.confirmBroadcast, m.broadcasting)
+	}
+	if msg, ok := cmd().(ToggleBroadcastMsg); !ok || !msg.Enabled {
+		t.Errorf("cmd sent %#v, want ToggleBroadcastMsg{Enabled: true}", msg)
+	}
+
+	// Stopping never asks
+	m, _ = m.Update(b)
+	if m.confirmBroadcast || m.broadcasting {
+		t.Errorf("stop: confirmBroadcast = %v, broadcasting = %v, want both false", m.confirmBroadcast, m.broadcasting)
+	}
+
+	// Link-local only: still asks, without the production warning
+	m.ifaceInfo = types.InterfaceInfo{Name: "eth0", IPv4Addrs: []net.IP{net.ParseIP("169.254.1.1").To4()}}
+	m, _ = m.Update(b)
+	if view := m.View(); strings.Contains(view, "production network") {
+		t.Errorf("prompt warns about a link-local address:\n%s", view)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.broadcasting {
+		t.Error("enter didn't start broadcasting")
+	}
+
+	m.config.ConfirmBroadcast = false
+	m.broadcasting = false
+	m, _ = m.Update(b)
+	if m.confirmBroadcast || !m.broadcasting {
+		t.Errorf("confirm_broadcast = false: confirmBroadcast = %v, broadcasting = %v, want an immediate broadcast", m.confirmBroadcast, m.broadcasting)
+	}
+}
+
+func TestNeighborVendor(t *testing.T) {
+	cisco, _ := net.ParseMAC("00:00:0c:12:34:56")
+	local, _ := net.ParseMAC("02:00:00:00:00:01")
+	unknown, _ := net.ParseMAC("00:00:00:00:00:01")
+
+	tests := []struct {
+		name string
+		n    *types.Neighbor
+		want string
+	}{
+		{"source MAC", &types.Neighbor{ID: "sw1", SourceMAC: cisco}, "Cisco"},
+		{"chassis MAC behind a local source MAC", &types.Neighbor{ID: "00:1c:73:aa:bb:cc", SourceMAC: local}, "Arista"},
+		{"chassis MAC behind an unknown source MAC", &types.Neighbor{ID: "00:1c:73:aa:bb:cc", SourceMAC: unknown}, "Arista"},
+		{"locally administered", &types.Neighbor{ID: "sw1", SourceMAC: local}, oui.LocallyAdministered},
+		{"unknown", &types.Neighbor{ID: "sw1", SourceMAC: unknown}, ""},
+	}
+	for _, tt := range tests {
+		if got := neighborVendor(tt.n); got != tt.want {
+			t.Errorf("%s: neighborVendor = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	m := newTestTable(t, &types.Neighbor{ID: "sw1", Hostname: "sw1", SourceMAC: cisco, LastSeen: time.Now()})
+	m.width = 120
+	m.height = 40
+	m.showDetail = true
+	if view := m.View(); !strings.Contains(view, "Vendor:") || !strings.Contains(view, "Cisco") {
+		t.Errorf("detail popup doesn't show the vendor:\n%s", view)
+	}
+}
+
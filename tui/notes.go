@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"maps"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"nbor/config"
+	"nbor/types"
+)
+
+// NotesLoadedMsg carries the neighbor notes read from the config directory
+type NotesLoadedMsg struct {
+	Notes map[string]string
+	Err   error
+}
+
+// NotesSavedMsg reports the result of writing the neighbor notes
+type NotesSavedMsg struct {
+	Err error
+}
+
+// noteCharLimit keeps a note short enough to sit after a hostname
+const noteCharLimit = 48
+
+// loadNotesCmd reads the neighbor notes in the background
+func loadNotesCmd() tea.Cmd {
+	return func() tea.Msg {
+		notes, err := config.LoadNotes()
+		return NotesLoadedMsg{Notes: notes, Err: err}
+	}
+}
+
+// saveNotesCmd writes a copy of the neighbor notes in the background
+func saveNotesCmd(notes map[string]string) tea.Cmd {
+	notes = maps.Clone(notes)
+	return func() tea.Msg {
+		return NotesSavedMsg{Err: config.SaveNotes(notes)}
+	}
+}
+
+// neighborNote returns the note attached to n, or ""
+func (m NeighborTableModel) neighborNote(n *types.Neighbor) string {
+	return m.notes[n.NeighborKey()]
+}
+
+// hostnameWithNote is the Hostname column value: the hostname followed by the
+// neighbor's note, if it has one, e.g. "sw1 (core switch B)"
+func (m NeighborTableModel) hostnameWithNote(n *types.Neighbor) string {
+	note := m.neighborNote(n)
+	if note == "" {
+		return n.Hostname
+	}
+	if n.Hostname == "" {
+		return "(" + note + ")"
+	}
+	return n.Hostname + " (" + note + ")"
+}
+
+// startNote opens the note editor for the selected neighbor, filled with its
+// current note
+func (m NeighborTableModel) startNote() (NeighborTableModel, tea.Cmd) {
+	n := m.getSelectedNeighbor()
+	if n == nil {
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Prompt = "note: "
+	input.Placeholder = "e.g. core switch B"
+	input.CharLimit = noteCharLimit
+	input.Width = 30
+	input.SetValue(m.neighborNote(n))
+	input.CursorEnd()
+
+	m.noteInput = input
+	m.noteKey = n.NeighborKey()
+	m.editingNote = true
+	return m, m.noteInput.Focus()
+}
+
+// updateNoteInput handles key events while typing a note
+// Enter saves it (an empty note removes it) and esc leaves it unchanged
+func (m NeighborTableModel) updateNoteInput(msg tea.KeyMsg) (NeighborTableModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.editingNote = false
+		m.noteInput.Blur()
+		return m, nil
+	case "enter":
+		m.editingNote = false
+		m.noteInput.Blur()
+		note := strings.TrimSpace(m.noteInput.Value())
+		if note == m.notes[m.noteKey] {
+			return m, nil
+		}
+		if m.notes == nil {
+			m.notes = make(map[string]string)
+		}
+		if note == "" {
+			delete(m.notes, m.noteKey)
+		} else {
+			m.notes[m.noteKey] = note
+		}
+		return m, saveNotesCmd(m.notes)
+	}
+
+	var cmd tea.Cmd
+	m.noteInput, cmd = m.noteInput.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"nbor/types"
+)
+
+func TestNeighborNotes(t *testing.T) {
+	m := newTestTable(t, &types.Neighbor{
+		Hostname:  "sw-a",
+		Interface: "eth0",
+		SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, 5},
+		LastSeen:  time.Now(),
+	})
+	m.width = 120
+	m.height = 40
+	key := m.getSelectedNeighbor().NeighborKey()
+
+	m, _ = m.Update(NotesLoadedMsg{Notes: map[string]string{}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if !m.editingNote {
+		t.Fatal("t didn't open the note editor")
+	}
+	// Keys go to the note, not the table
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("core B q")})
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.editingNote || cmd == nil {
+		t.Fatalf("enter: editingNote = %v, save cmd = %v, want false and a save", m.editingNote, cmd)
+	}
+	if got := m.notes[key]; got != "core B q" {
+		t.Fatalf("note = %q, want %q", got, "core B q")
+	}
+	if view := m.View(); !strings.Contains(view, "sw-a (core B q)") {
+		t.Errorf("table doesn't show the note after the hostname:\n%s", view)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if view := m.View(); !strings.Contains(view, "Note:") || !strings.Contains(view, "core B q") {
+		t.Errorf("detail popup doesn't show the note:\n%s", view)
+	}
+
+	// Esc leaves the note alone; an empty note removes it
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if !m.showDetail || cmd != nil || m.notes[key] != "core B q" {
+		t.Fatalf("esc: showDetail = %v, note = %q, want the popup open and the note unchanged", m.showDetail, m.notes[key])
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m.noteInput.SetValue("")
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if _, ok := m.notes[key]; ok || cmd == nil {
+		t.Errorf("empty note: notes = %v, save cmd = %v, want the note removed and saved", m.notes, cmd)
+	}
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"nbor/types"
+)
+
+// PTRResolvedMsg carries the reverse DNS name of a management IP, or "" when
+// it has none or the lookup failed
+type PTRResolvedMsg struct {
+	IP   string
+	Name string
+}
+
+// ptrTimeout bounds each reverse lookup so a dead resolver can't leave
+// lookups piling up
+const ptrTimeout = 3 * time.Second
+
+// lookupAddr does the reverse lookup; tests replace it
+var lookupAddr = net.DefaultResolver.LookupAddr
+
+// lookupPTRCmd resolves ip in the background
+func lookupPTRCmd(ip string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ptrTimeout)
+		defer cancel()
+		names, err := lookupAddr(ctx, ip)
+		if err != nil || len(names) == 0 {
+			return PTRResolvedMsg{IP: ip}
+		}
+		return PTRResolvedMsg{IP: ip, Name: strings.TrimSuffix(names[0], ".")}
+	}
+}
+
+// resolvePTR starts a lookup of n's management IP when resolve_ptr is on and
+// the IP hasn't been looked up yet. Results, failures included, are cached for
+// the session so each address is queried once
+func (m *NeighborTableModel) resolvePTR(n *types.Neighbor) tea.Cmd {
+	if !m.config.ResolvePTR || n == nil || n.ManagementIP == nil {
+		return nil
+	}
+	ip := n.ManagementIP.String()
+	if _, ok := m.ptrNames[ip]; ok {
+		return nil
+	}
+	if m.ptrNames == nil {
+		m.ptrNames = make(map[string]string)
+	}
+	m.ptrNames[ip] = ptrPending
+	return lookupPTRCmd(ip)
+}
+
+// setPTR caches a finished lookup
+func (m *NeighborTableModel) setPTR(msg PTRResolvedMsg) {
+	if m.ptrNames == nil {
+		m.ptrNames = make(map[string]string)
+	}
+	m.ptrNames[msg.IP] = msg.Name
+}
+
+// ptrPending marks a lookup that hasn't answered yet; no DNS name has spaces
+const ptrPending = "resolving..."
+
+// showsPTR reports whether the detail popup has a PTR row for n
+func (m NeighborTableModel) showsPTR(n *types.Neighbor) bool {
+	return m.config.ResolvePTR && n.ManagementIP != nil
+}
+
+// ptrName returns n's PTR name for the detail popup: "-" when it has none, or
+// ptrPending until the lookup answers
+func (m NeighborTableModel) ptrName(n *types.Neighbor) string {
+	name, ok := m.ptrNames[n.ManagementIP.String()]
+	if !ok {
+		return ptrPending
+	}
+	if name == "" {
+		return "-"
+	}
+	return name
+}
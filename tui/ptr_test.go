@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"nbor/types"
+)
+
+func TestResolvePTR(t *testing.T) {
+	var lookups []string
+	lookupAddr = func(_ context.Context, addr string) ([]string, error) {
+		lookups = append(lookups, addr)
+		if addr == "10.0.0.1" {
+			return []string{"core-sw1.example.net."}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	defer func() { lookupAddr = net.DefaultResolver.LookupAddr }()
+
+	a := &types.Neighbor{Hostname: "a", SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, 5}, ManagementIP: net.ParseIP("10.0.0.1"), LastSeen: time.Now()}
+	b := &types.Neighbor{Hostname: "b", SourceMAC: net.HardwareAddr{0, 1, 2, 3, 4, 6}, ManagementIP: net.ParseIP("10.0.0.2"), LastSeen: time.Now()}
+	m := newTestTable(t, a, b)
+	m.width = 120
+	m.height = 40
+
+	// Off by default: no lookups and no PTR row
+	m, cmd := m.Update(NewNeighborMsg{Neighbor: a})
+	if cmd != nil {
+		t.Fatal("resolve_ptr is off but a lookup was started")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if strings.Contains(m.View(), "PTR:") {
+		t.Error("detail popup shows PTR with resolve_ptr off")
+	}
+	m.showDetail = false
+
+	m.config.ResolvePTR = true
+	m, cmd = m.Update(NewNeighborMsg{Neighbor: a})
+	if cmd == nil {
+		t.Fatal("new neighbor didn't start a lookup")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if n := m.getSelectedNeighbor(); n.Hostname != "a" {
+		t.Fatalf("selected %q, want a", n.Hostname)
+	}
+	if !strings.Contains(m.View(), ptrPending) {
+		t.Errorf("detail popup should show the lookup in progress:\n%s", m.View())
+	}
+	m, _ = m.Update(cmd())
+	if view := m.View(); !strings.Contains(view, "core-sw1.example.net") || strings.Contains(view, "example.net.") {
+		t.Errorf("detail popup doesn't show the PTR name without the trailing dot:\n%s", view)
+	}
+
+	// Moving to b looks it up once; a's cached answer isn't queried again
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if cmd == nil {
+		t.Fatal("moving to b didn't start a lookup")
+	}
+	m, _ = m.Update(cmd())
+	if got := m.ptrNames["10.0.0.2"]; got != "" {
+		t.Errorf("failed lookup cached as %q, want \"\"", got)
+	}
+	if view := m.View(); !strings.Contains(view, "PTR:          - ") {
+		t.Errorf("detail popup should show - for a failed lookup:\n%s", view)
+	}
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if cmd != nil {
+		t.Error("a cached address was looked up again")
+	}
+	if len(lookups) != 2 {
+		t.Errorf("lookups = %v, want one per address", lookups)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !noqr
+
+package tui
+
+import "nbor/qr"
+
+// renderQR encodes text as a QR code drawn with half blocks
+// Build with -tags noqr to leave the encoder out of the binary
+func renderQR(text string) (string, error) {
+	code, err := qr.Encode(text)
+	if err != nil {
+		return "", err
+	}
+	// Scanners need a quiet zone around the symbol; two modules is enough on screen
+	return code.String(2), nil
+}
@@ -0,0 +1,10 @@
+//go:build noqr
+
+package tui
+
+import "errors"
+
+// renderQR is unavailable in builds made with -tags noqr
+func renderQR(text string) (string, error) {
+	return "", errors.New("QR codes not available in this build (built with -tags noqr)")
+}
@@ -26,7 +26,10 @@ type Styles struct {
 	TableHeader    lipgloss.Style
 	TableRow       lipgloss.Style
 	TableRowStale  lipgloss.Style
-	TableRowNew    lipgloss.Style
+	TableRowActive lipgloss.Style
+	TableRowNew    lipgloss.Style // New or just-updated (flashing) rows
+	TableRowWarn   lipgloss.Style // Neighbors advertising something worth a look, like half duplex
+	TableCursor    lipgloss.Style
 	TableCell      lipgloss.Style
 	TableCellStale lipgloss.Style
 	TableSelected  lipgloss.Style
@@ -46,11 +49,19 @@ type Styles struct {
 	// Protocol badges
 	BadgeCDP  lipgloss.Style
 	BadgeLLDP lipgloss.Style
+
+	// Proto column tints for protocol_colors, matching the badges
+	ProtoCDP  lipgloss.Style
+	ProtoLLDP lipgloss.Style
+	ProtoBoth lipgloss.Style
 }
 
 // NewStyles creates styled components based on the theme
 func NewStyles(theme Theme) Styles {
-	return Styles{
+	cursorColor, _ := theme.Slot(cursorAccent)
+	flashColor, _ := theme.Slot(flashAccent)
+
+	styles := Styles{
 		// App container
 		App: lipgloss.NewStyle().
 			Background(theme.Base00),
@@ -93,8 +104,18 @@ func NewStyles(theme Theme) Styles {
 		TableRowStale: lipgloss.NewStyle().
 			Foreground(theme.Base03),
 
+		TableRowActive: lipgloss.NewStyle().
+			Foreground(theme.Base0B),
+
 		TableRowNew: lipgloss.NewStyle().
-			Foreground(theme.Base0B).
+			Foreground(flashColor).
+			Bold(true),
+
+		TableRowWarn: lipgloss.NewStyle().
+			Foreground(theme.Base09),
+
+		TableCursor: lipgloss.NewStyle().
+			Foreground(cursorColor).
 			Bold(true),
 
 		TableCell: lipgloss.NewStyle().
@@ -152,7 +173,30 @@ func NewStyles(theme Theme) Styles {
 			Foreground(theme.Base00).
 			Padding(0, 1).
 			Bold(true),
+
+		ProtoCDP: lipgloss.NewStyle().
+			Foreground(theme.Base0D),
+
+		ProtoLLDP: lipgloss.NewStyle().
+			Foreground(theme.Base0B),
+
+		ProtoBoth: lipgloss.NewStyle().
+			Foreground(theme.Base0E),
 	}
+
+	if monochrome {
+		applyMonochrome(&styles)
+	}
+	return styles
+}
+
+// applyMonochrome adds text attributes to the states that otherwise differ only
+// by color, so they stay distinct when no color is shown
+func applyMonochrome(s *Styles) {
+	s.TableRowStale = s.TableRowStale.Faint(true)
+	s.TableCellStale = s.TableCellStale.Faint(true)
+	s.TableSelected = s.TableSelected.Reverse(true)
+	s.TableRowWarn = s.TableRowWarn.Underline(true)
 }
 
 // DefaultStyles uses the default theme
@@ -1,6 +1,11 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // Theme represents a Base16 color theme
 type Theme struct {
@@ -34,6 +39,64 @@ func SetTheme(theme Theme) {
 	DefaultStyles = NewStyles(theme)
 }
 
+// Accent slots for the selection cursor and flashing/new rows
+// Stored as Base16 slot names so they follow theme changes
+var (
+	cursorAccent = "base0d"
+	flashAccent  = "base0b"
+)
+
+// SetAccents sets the Base16 slots used for the selection cursor and flashing
+// rows and regenerates all styles. Empty or unknown slots keep the current value
+func SetAccents(cursor, flash string) {
+	if _, ok := DefaultTheme.Slot(cursor); ok {
+		cursorAccent = strings.ToLower(cursor)
+	}
+	if _, ok := DefaultTheme.Slot(flash); ok {
+		flashAccent = strings.ToLower(flash)
+	}
+	DefaultStyles = NewStyles(DefaultTheme)
+}
+
+// Slot returns the color for a Base16 slot name such as "base0d" (case-insensitive)
+func (t Theme) Slot(name string) (lipgloss.Color, bool) {
+	switch strings.ToLower(name) {
+	case "base00":
+		return t.Base00, true
+	case "base01":
+		return t.Base01, true
+	case "base02":
+		return t.Base02, true
+	case "base03":
+		return t.Base03, true
+	case "base04":
+		return t.Base04, true
+	case "base05":
+		return t.Base05, true
+	case "base06":
+		return t.Base06, true
+	case "base07":
+		return t.Base07, true
+	case "base08":
+		return t.Base08, true
+	case "base09":
+		return t.Base09, true
+	case "base0a":
+		return t.Base0A, true
+	case "base0b":
+		return t.Base0B, true
+	case "base0c":
+		return t.Base0C, true
+	case "base0d":
+		return t.Base0D, true
+	case "base0e":
+		return t.Base0E, true
+	case "base0f":
+		return t.Base0F, true
+	}
+	return "", false
+}
+
 // GetThemeByName returns a theme by its slug name, or nil if not found
 func GetThemeByName(name string) *Theme {
 	if theme, ok := Themes[name]; ok {
@@ -43,8 +106,9 @@ func GetThemeByName(name string) *Theme {
 }
 
 // ListThemes returns a sorted list of theme slugs and display names
+// User themes from LoadCustomThemes follow the built-in ones
 func ListThemes() [][2]string {
-	return [][2]string{
+	builtin := [][2]string{
 		{"solarized-dark", "Solarized Dark"},
 		{"solarized-light", "Solarized Light"},
 		{"gruvbox-dark", "Gruvbox Dark"},
@@ -65,7 +129,63 @@ func ListThemes() [][2]string {
 		{"zenburn", "Zenburn"},
 		{"palenight", "Palenight"},
 		{"github-dark", "GitHub Dark"},
+		{"high-contrast", "High Contrast"},
+	}
+	return append(builtin, customThemes...)
+}
+
+// ThemeFilter limits the theme list to light or dark themes
+type ThemeFilter string
+
+const (
+	ThemeFilterAll   ThemeFilter = "all"
+	ThemeFilterDark  ThemeFilter = "dark"
+	ThemeFilterLight ThemeFilter = "light"
+)
+
+// Next returns the filter after f, cycling all -> dark -> light -> all
+func (f ThemeFilter) Next() ThemeFilter {
+	switch f {
+	case ThemeFilterDark:
+		return ThemeFilterLight
+	case ThemeFilterLight:
+		return ThemeFilterAll
+	default:
+		return ThemeFilterDark
+	}
+}
+
+// IsLight returns whether the theme has a light background
+// Uses the relative luminance of Base00
+func (t Theme) IsLight() bool {
+	hex := strings.TrimPrefix(string(t.Base00), "#")
+	if len(hex) != 6 {
+		return false
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return false
+	}
+	r := float64(rgb>>16&0xff) / 255
+	g := float64(rgb>>8&0xff) / 255
+	b := float64(rgb&0xff) / 255
+	return 0.2126*r+0.7152*g+0.0722*b > 0.5
+}
+
+// ListThemesFiltered returns the themes from ListThemes matching the filter
+// An empty or unknown filter returns all themes
+func ListThemesFiltered(filter ThemeFilter) [][2]string {
+	if filter != ThemeFilterDark && filter != ThemeFilterLight {
+		return ListThemes()
+	}
+	var result [][2]string
+	for _, t := range ListThemes() {
+		theme := GetThemeByName(t[0])
+		if theme != nil && theme.IsLight() == (filter == ThemeFilterLight) {
+			result = append(result, t)
+		}
 	}
+	return result
 }
 
 // GetThemeCount returns the number of available themes
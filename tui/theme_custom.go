@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// customThemes lists the user themes loaded by LoadCustomThemes as slug and
+// display name, sorted by slug. ListThemes appends them to the built-in themes
+var customThemes [][2]string
+
+// customThemeFile is the layout of a user theme file: an optional display name
+// and the 16 Base16 colors as hex, e.g. base00 = "#1d1f21"
+type customThemeFile struct {
+	Name   string `toml:"name"`
+	Base00 string `toml:"base00"`
+	Base01 string `toml:"base01"`
+	Base02 string `toml:"base02"`
+	Base03 string `toml:"base03"`
+	Base04 string `toml:"base04"`
+	Base05 string `toml:"base05"`
+	Base06 string `toml:"base06"`
+	Base07 string `toml:"base07"`
+	Base08 string `toml:"base08"`
+	Base09 string `toml:"base09"`
+	Base0A string `toml:"base0a"`
+	Base0B string `toml:"base0b"`
+	Base0C string `toml:"base0c"`
+	Base0D string `toml:"base0d"`
+	Base0E string `toml:"base0e"`
+	Base0F string `toml:"base0f"`
+}
+
+// LoadCustomThemes registers every *.toml theme file in dir, using the file name
+// (without .toml) as the slug. Malformed files and files that would replace a
+// built-in theme are skipped with a warning. A missing dir is not an error
+func LoadCustomThemes(dir string) (warnings []string) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+	if err != nil {
+		return []string{fmt.Sprintf("themes: %v", err)}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		slug := strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+		if _, ok := Themes[slug]; ok && !IsCustomTheme(slug) {
+			warnings = append(warnings, fmt.Sprintf("theme %s: %q is a built-in theme, skipped", path, slug))
+			continue
+		}
+		theme, err := loadCustomTheme(path, slug)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("theme %s: %v, skipped", path, err))
+			continue
+		}
+		if !IsCustomTheme(slug) {
+			customThemes = append(customThemes, [2]string{slug, theme.Name})
+		}
+		Themes[slug] = theme
+	}
+
+	sort.Slice(customThemes, func(i, j int) bool { return customThemes[i][0] < customThemes[j][0] })
+	return warnings
+}
+
+// loadCustomTheme reads and validates one user theme file
+func loadCustomTheme(path, slug string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	var file customThemeFile
+	if _, err := toml.Decode(string(data), &file); err != nil {
+		return Theme{}, err
+	}
+
+	theme := Theme{Name: strings.TrimSpace(file.Name)}
+	if theme.Name == "" {
+		theme.Name = slug
+	}
+
+	slots := []struct {
+		name  string
+		value string
+		dst   *lipgloss.Color
+	}{
+		{"base00", file.Base00, &theme.Base00},
+		{"base01", file.Base01, &theme.Base01},
+		{"base02", file.Base02, &theme.Base02},
+		{"base03", file.Base03, &theme.Base03},
+		{"base04", file.Base04, &theme.Base04},
+		{"base05", file.Base05, &theme.Base05},
+		{"base06", file.Base06, &theme.Base06},
+		{"base07", file.Base07, &theme.Base07},
+		{"base08", file.Base08, &theme.Base08},
+		{"base09", file.Base09, &theme.Base09},
+		{"base0a", file.Base0A, &theme.Base0A},
+		{"base0b", file.Base0B, &theme.Base0B},
+		{"base0c", file.Base0C, &theme.Base0C},
+		{"base0d", file.Base0D, &theme.Base0D},
+		{"base0e", file.Base0E, &theme.Base0E},
+		{"base0f", file.Base0F, &theme.Base0F},
+	}
+	for _, slot := range slots {
+		color, err := parseHexColor(slot.value)
+		if err != nil {
+			return Theme{}, fmt.Errorf("%s: %w", slot.name, err)
+		}
+		*slot.dst = color
+	}
+	return theme, nil
+}
+
+// parseHexColor accepts "#rrggbb" or "rrggbb" and returns it as "#rrggbb"
+func parseHexColor(s string) (lipgloss.Color, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if hex == "" {
+		return "", errors.New("missing")
+	}
+	if len(hex) != 6 {
+		return "", fmt.Errorf("%q is not a #rrggbb color", s)
+	}
+	if _, err := strconv.ParseUint(hex, 16, 32); err != nil {
+		return "", fmt.Errorf("%q is not a #rrggbb color", s)
+	}
+	return lipgloss.Color("#" + strings.ToLower(hex)), nil
+}
+
+// IsCustomTheme reports whether slug is a user theme loaded by LoadCustomThemes
+func IsCustomTheme(slug string) bool {
+	for _, t := range customThemes {
+		if t[0] == slug {
+			return true
+		}
+	}
+	return false
+}
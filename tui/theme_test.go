@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestThemeIsLight(t *testing.T) {
+	tests := []struct {
+		slug string
+		want bool
+	}{
+		{"solarized-dark", false},
+		{"solarized-light", true},
+		{"gruvbox-light", true},
+		{"catppuccin-latte", true},
+		{"dracula", false},
+	}
+
+	for _, tt := range tests {
+		theme := GetThemeByName(tt.slug)
+		if theme == nil {
+			t.Fatalf("GetThemeByName(%q) = nil", tt.slug)
+		}
+		if got := theme.IsLight(); got != tt.want {
+			t.Errorf("%s IsLight() = %v, want %v", tt.slug, got, tt.want)
+		}
+	}
+}
+
+func TestListThemesFiltered(t *testing.T) {
+	all := ListThemesFiltered(ThemeFilterAll)
+	dark := ListThemesFiltered(ThemeFilterDark)
+	light := ListThemesFiltered(ThemeFilterLight)
+
+	if len(all) != len(ListThemes()) {
+		t.Errorf("ListThemesFiltered(all) returned %d themes, want %d", len(all), len(ListThemes()))
+	}
+	if len(dark)+len(light) != len(all) {
+		t.Errorf("dark (%d) + light (%d) themes != all (%d)", len(dark), len(light), len(all))
+	}
+	if len(light) == 0 || len(dark) == 0 {
+		t.Errorf("expected both light and dark themes, got %d light and %d dark", len(light), len(dark))
+	}
+	if len(ListThemesFiltered("")) != len(all) {
+		t.Error("ListThemesFiltered(\"\") should return all themes")
+	}
+}
+
+func TestThemeFilterNext(t *testing.T) {
+	tests := []struct {
+		filter ThemeFilter
+		want   ThemeFilter
+	}{
+		{ThemeFilterAll, ThemeFilterDark},
+		{ThemeFilterDark, ThemeFilterLight},
+		{ThemeFilterLight, ThemeFilterAll},
+		{"", ThemeFilterDark},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter.Next(); got != tt.want {
+			t.Errorf("ThemeFilter(%q).Next() = %q, want %q", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestThemeSlot(t *testing.T) {
+	theme := SolarizedDark
+
+	if got, ok := theme.Slot("base0d"); !ok || got != theme.Base0D {
+		t.Errorf("Slot(base0d) = %v, %v, want %v, true", got, ok, theme.Base0D)
+	}
+	if got, ok := theme.Slot("BASE08"); !ok || got != theme.Base08 {
+		t.Errorf("Slot(BASE08) = %v, %v, want %v, true", got, ok, theme.Base08)
+	}
+	if _, ok := theme.Slot("base10"); ok {
+		t.Error("Slot(base10) should not be found")
+	}
+}
+
+func TestSetAccents(t *testing.T) {
+	defer SetAccents("base0d", "base0b")
+
+	SetAccents("base0e", "base09")
+	if got := DefaultStyles.TableCursor.GetForeground(); got != DefaultTheme.Base0E {
+		t.Errorf("cursor color = %v, want %v", got, DefaultTheme.Base0E)
+	}
+	if got := DefaultStyles.TableRowNew.GetForeground(); got != DefaultTheme.Base09 {
+		t.Errorf("flash color = %v, want %v", got, DefaultTheme.Base09)
+	}
+
+	// Unknown slots keep the current accent
+	SetAccents("bogus", "")
+	if got := DefaultStyles.TableCursor.GetForeground(); got != DefaultTheme.Base0E {
+		t.Errorf("cursor color after invalid slot = %v, want %v", got, DefaultTheme.Base0E)
+	}
+}
+
+func TestMonochromeStyles(t *testing.T) {
+	defer SetColorProfile("truecolor")
+
+	if err := SetColorProfile("ascii"); err != nil {
+		t.Fatal(err)
+	}
+	if !monochrome {
+		t.Fatal("ascii profile didn't switch to monochrome")
+	}
+	if !DefaultStyles.TableCellStale.GetFaint() {
+		t.Error("stale cells aren't faint in monochrome")
+	}
+	if !DefaultStyles.TableSelected.GetReverse() {
+		t.Error("selection isn't reversed in monochrome")
+	}
+	if !DefaultStyles.TableRowNew.GetBold() {
+		t.Error("new rows aren't bold in monochrome")
+	}
+
+	if err := SetColorProfile("truecolor"); err != nil {
+		t.Fatal(err)
+	}
+	if monochrome || DefaultStyles.TableSelected.GetReverse() {
+		t.Error("truecolor profile kept monochrome styles")
+	}
+}
+
+func TestLoadCustomThemes(t *testing.T) {
+	// Restore the registry for the other tests
+	savedThemes := make(map[string]Theme, len(Themes))
+	for k, v := range Themes {
+		savedThemes[k] = v
+	}
+	t.Cleanup(func() {
+		Themes = savedThemes
+		customThemes = nil
+	})
+
+	colors := func(base00 string) string {
+		var b strings.Builder
+		fmt.Fprintf(&b, "base00 = %q\n", base00)
+		for _, slot := range []string{"01", "02", "03", "04", "05", "06", "07", "08", "09", "0a", "0b", "0c", "0d", "0e", "0f"} {
+			fmt.Fprintf(&b, "base%s = \"#808080\"\n", slot)
+		}
+		return b.String()
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"my-term.toml":  "name = \"My Terminal\"\n" + colors("#1D1F21"),
+		"plain.toml":    colors("fdf6e3"),
+		"bad-hex.toml":  colors("#12345g"),
+		"missing.toml":  "base00 = \"#000000\"\n",
+		"broken.toml":   "base00 = ",
+		"dracula.toml":  colors("#000000"),
+		"ignored.theme": colors("#000000"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	warnings := LoadCustomThemes(dir)
+	if len(warnings) != 4 {
+		t.Errorf("warnings = %q, want 4 (bad hex, missing colors, bad TOML, built-in name)", warnings)
+	}
+
+	theme := GetThemeByName("my-term")
+	if theme == nil {
+		t.Fatal("GetThemeByName(\"my-term\") = nil")
+	}
+	if theme.Name != "My Terminal" || theme.Base00 != "#1d1f21" {
+		t.Errorf("my-term = %q with base00 %q, want \"My Terminal\" with #1d1f21", theme.Name, theme.Base00)
+	}
+	if plain := GetThemeByName("plain"); plain == nil || !plain.IsLight() {
+		t.Error("plain theme without a name or # prefixes should load as a light theme")
+	}
+	if !IsCustomTheme("my-term") || IsCustomTheme("dracula") || IsCustomTheme("bad-hex") {
+		t.Error("IsCustomTheme should only report the loaded user themes")
+	}
+	if GetThemeByName("dracula").Base00 == "#000000" {
+		t.Error("a user theme must not replace a built-in one")
+	}
+
+	themes := ListThemes()
+	if last := themes[len(themes)-1]; last[0] != "plain" {
+		t.Errorf("last listed theme = %q, want user themes after the built-ins", last[0])
+	}
+	if len(LoadCustomThemes(filepath.Join(dir, "none"))) != 0 {
+		t.Error("a missing themes directory should not warn")
+	}
+}
@@ -422,6 +422,28 @@ var GitHubDark = Theme{
 	Base0F: lipgloss.Color("#ffa198"),
 }
 
+// HighContrast is a black and white theme with saturated accents, for low vision
+// or washed-out displays. Even the dimmed slots keep a strong contrast on black
+var HighContrast = Theme{
+	Name:   "High Contrast",
+	Base00: lipgloss.Color("#000000"),
+	Base01: lipgloss.Color("#262626"),
+	Base02: lipgloss.Color("#5c5c5c"),
+	Base03: lipgloss.Color("#b0b0b0"),
+	Base04: lipgloss.Color("#e0e0e0"),
+	Base05: lipgloss.Color("#ffffff"),
+	Base06: lipgloss.Color("#ffffff"),
+	Base07: lipgloss.Color("#ffffff"),
+	Base08: lipgloss.Color("#ff6e6e"),
+	Base09: lipgloss.Color("#ffa64d"),
+	Base0A: lipgloss.Color("#ffff00"),
+	Base0B: lipgloss.Color("#00ff66"),
+	Base0C: lipgloss.Color("#00ffff"),
+	Base0D: lipgloss.Color("#66b3ff"),
+	Base0E: lipgloss.Color("#ff80ff"),
+	Base0F: lipgloss.Color("#ffcc80"),
+}
+
 // Themes is a registry of all available themes by slug
 var Themes = map[string]Theme{
 	"solarized-dark":   SolarizedDark,
@@ -444,4 +466,5 @@ var Themes = map[string]Theme{
 	"zenburn":          Zenburn,
 	"palenight":        Palenight,
 	"github-dark":      GitHubDark,
+	"high-contrast":    HighContrast,
 }
@@ -2,7 +2,10 @@
 package types
 
 import (
+	"encoding/json"
+	"fmt"
 	"net"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -32,6 +35,49 @@ const (
 	CapOther       Capability = "Other"
 )
 
+// MEDPolicy is an LLDP-MED network policy: the VLAN and QoS markings a switch
+// hands out for an application such as voice
+type MEDPolicy struct {
+	Application uint8 // 1 = voice, 2 = voice signaling, ... (ANSI/TIA-1057)
+	Unknown     bool  // The policy is required but not yet known
+	Tagged      bool  // Whether the application uses a tagged VLAN
+	VLAN        int   // VLAN ID (0 = priority tagged only)
+	Priority    int   // Layer 2 (802.1p) priority
+	DSCP        int
+}
+
+// medApplications names the LLDP-MED network policy application types
+var medApplications = map[uint8]string{
+	1: "Voice",
+	2: "Voice Signaling",
+	3: "Guest Voice",
+	4: "Guest Voice Signaling",
+	5: "Softphone Voice",
+	6: "Video Conferencing",
+	7: "Streaming Video",
+	8: "Video Signaling",
+}
+
+// ApplicationName returns the name of the policy's application type
+func (p MEDPolicy) ApplicationName() string {
+	if name, ok := medApplications[p.Application]; ok {
+		return name
+	}
+	return fmt.Sprintf("Application %d", p.Application)
+}
+
+// Inventory is the LLDP-MED inventory a device advertises (ANSI/TIA-1057)
+// Fields are empty for inventory TLVs the device doesn't send
+type Inventory struct {
+	HardwareRevision string
+	FirmwareRevision string
+	SoftwareRevision string
+	SerialNumber     string
+	Manufacturer     string
+	Model            string
+	AssetID          string
+}
+
 // Neighbor represents a discovered network neighbor
 type Neighbor struct {
 	// Unique identifier (typically chassis ID or device ID)
@@ -49,6 +95,10 @@ type Neighbor struct {
 	// Management IP address
 	ManagementIP net.IP
 
+	// Every management address advertised, IPv4 and IPv6, in the order received
+	// ManagementIP is the first IPv4 address among them
+	AllAddresses []net.IP
+
 	// Platform/model information
 	Platform string
 
@@ -58,9 +108,57 @@ type Neighbor struct {
 	// SNMP Location
 	Location string
 
+	// Native (untagged) VLAN of the neighbor's port, from CDP; 0 = not advertised
+	NativeVLAN int
+
+	// VTP management domain from CDP; "" = not advertised
+	VTPDomain string
+
+	// Duplex of the neighbor's port from CDP or the LLDP MAC/PHY TLV:
+	// "half", "full" or "" when not advertised
+	Duplex string
+
+	// Operational link mode of the neighbor's port from the LLDP MAC/PHY TLV,
+	// e.g. "1000BASE-T full"; empty when not advertised
+	Speed string
+
+	// Port VLAN ID and VLAN name from the LLDP 802.1 TLVs; 0/empty when not advertised
+	PortVLAN int
+	VLANName string
+
+	// PoE power in milliwatts from the CDP power TLV or LLDP-MED power via MDI
+	// 0 when not advertised
+	PowerMW int
+
+	// LLDP-MED network policy, preferring the voice policy when several are sent
+	// nil when not advertised
+	MEDPolicy *MEDPolicy
+
+	// LLDP-MED inventory (serial number, model, revisions); nil when not advertised
+	Inventory *Inventory
+
 	// Device capabilities
 	Capabilities []Capability
 
+	// Capabilities the device supports, whether or not they're enabled
+	// Only LLDP advertises these; nil when not known
+	SupportedCapabilities []Capability
+
+	// Raw capability bits as received, for debugging unusual or reserved bits
+	// 0 means no capabilities TLV was seen for that protocol
+	CDPCapabilityBits  uint32
+	LLDPCapabilityBits uint16 // Enabled capabilities
+
+	// Observed Ethernet frame lengths in bytes, for spotting padding or runt frames
+	// 0 means no frame length has been recorded
+	FrameLenMin  int
+	FrameLenMax  int
+	FrameLenLast int
+
+	// Advertised time to live (CDP hold time or LLDP TTL) from the latest announcement
+	// 0 means none was advertised
+	TTL time.Duration
+
 	// Discovery protocol(s) used - can be CDP, LLDP, or CDP+LLDP
 	Protocol Protocol
 
@@ -85,22 +183,94 @@ type Neighbor struct {
 
 	// The interface this neighbor was seen on
 	Interface string
+
+	// Every local interface the neighbor was heard on; more than one only when
+	// the store merges neighbors across interfaces
+	Interfaces []string
 }
 
 // NeighborKey generates a unique key for this neighbor
 // We key by source MAC since that identifies the physical port sending to us
 // CDP and LLDP from the same physical port will have the same source MAC
 func (n *Neighbor) NeighborKey() string {
+	return n.Interface + ":" + n.DeviceKey()
+}
+
+// DeviceKey identifies the neighbor regardless of the local interface it was heard on
+func (n *Neighbor) DeviceKey() string {
 	// Source MAC is the most reliable key - it's the actual MAC sending the packet
 	// Both CDP and LLDP from the same port should have the same source MAC
 	if n.SourceMAC != nil {
-		return n.Interface + ":" + n.SourceMAC.String()
+		return n.SourceMAC.String()
 	}
 	// Fallback to device ID
 	if n.ID != "" {
-		return n.Interface + ":" + strings.ToLower(n.ID)
+		return strings.ToLower(n.ID)
+	}
+	return "unknown"
+}
+
+// MarshalJSON encodes the neighbor's identifying fields as plain strings, for
+// handing a capture to other tools. Unknown values are empty strings
+func (n *Neighbor) MarshalJSON() ([]byte, error) {
+	capabilities := make([]string, 0, len(n.Capabilities))
+	for _, c := range n.Capabilities {
+		capabilities = append(capabilities, string(c))
 	}
-	return n.Interface + ":unknown"
+	var mgmtIP, sourceMAC string
+	if n.ManagementIP != nil {
+		mgmtIP = n.ManagementIP.String()
+	}
+	if n.SourceMAC != nil {
+		sourceMAC = n.SourceMAC.String()
+	}
+	return json.Marshal(struct {
+		ID           string   `json:"id"`
+		Hostname     string   `json:"hostname"`
+		Interface    string   `json:"interface"`
+		Port         string   `json:"port"`
+		ManagementIP string   `json:"mgmt_ip"`
+		Platform     string   `json:"platform"`
+		Capabilities []string `json:"capabilities"`
+		Protocol     string   `json:"protocol"`
+		FirstSeen    string   `json:"first_seen"`
+		LastSeen     string   `json:"last_seen"`
+		SourceMAC    string   `json:"source_mac"`
+	}{
+		ID:           n.ID,
+		Hostname:     n.Hostname,
+		Interface:    n.Interface,
+		Port:         n.PortID,
+		ManagementIP: mgmtIP,
+		Platform:     n.Platform,
+		Capabilities: capabilities,
+		Protocol:     string(n.Protocol),
+		FirstSeen:    formatJSONTime(n.FirstSeen),
+		LastSeen:     formatJSONTime(n.LastSeen),
+		SourceMAC:    sourceMAC,
+	})
+}
+
+// formatJSONTime formats t as RFC 3339, or "" for the zero time
+func formatJSONTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// RecordFrameLength updates the observed frame length statistics
+func (n *Neighbor) RecordFrameLength(length int) {
+	if length <= 0 {
+		return
+	}
+	if n.FrameLenMin == 0 || length < n.FrameLenMin {
+		n.FrameLenMin = length
+	}
+	if length > n.FrameLenMax {
+		n.FrameLenMax = length
+	}
+	n.FrameLenLast = length
 }
 
 // UpdateProtocol updates the protocol field based on what we've seen
@@ -122,12 +292,72 @@ type NeighborStore struct {
 	OnNewNeighbor func(*Neighbor)
 	// Callback for when a neighbor is updated
 	OnUpdate func(*Neighbor)
+	// Callback for when an interface is flagged for possible link instability
+	OnLinkInstability func(iface string)
+	// Callback for when a neighbor goes stale
+	OnStale func(*Neighbor)
+	// Callback for when a stale neighbor is removed
+	OnRemove func(*Neighbor)
+
+	stability *LinkStabilityTracker
+
+	// Key neighbors by chassis ID instead of NeighborKey
+	mergeInterfaces bool
 }
 
 // NewNeighborStore creates a new neighbor store
 func NewNeighborStore() *NeighborStore {
 	return &NeighborStore{
 		neighbors: make(map[string]*Neighbor),
+		stability: NewLinkStabilityTracker(0, 0),
+	}
+}
+
+// SetFlapDetection configures link instability detection
+// A threshold of 0 disables detection
+func (s *NeighborStore) SetFlapDetection(window time.Duration, threshold int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stability.Window = window
+	s.stability.Threshold = threshold
+}
+
+// SetMergeAcrossInterfaces sets whether a neighbor heard on several interfaces is
+// stored once. Set it before any neighbors are added; existing keys aren't rebuilt
+func (s *NeighborStore) SetMergeAcrossInterfaces(merge bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mergeInterfaces = merge
+}
+
+// key returns the store key for n under the current key strategy
+func (s *NeighborStore) key(n *Neighbor) string {
+	if s.mergeInterfaces {
+		// Each member of a LAG sends from its own port MAC, so the chassis ID
+		// is what ties the links to one device
+		if n.ID != "" {
+			return strings.ToLower(n.ID)
+		}
+		return n.DeviceKey()
+	}
+	return n.NeighborKey()
+}
+
+// IsInterfaceUnstable returns whether neighbors on the interface have been changing repeatedly
+func (s *NeighborStore) IsInterfaceUnstable(iface string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stability.IsUnstable(iface, time.Now())
+}
+
+// notifyInstability calls OnLinkInstability if an event flagged the interface
+// Must be called with the lock held
+func (s *NeighborStore) notifyInstability(flagged bool, iface string) {
+	if flagged && s.OnLinkInstability != nil {
+		s.OnLinkInstability(iface)
 	}
 }
 
@@ -137,7 +367,7 @@ func (s *NeighborStore) Update(n *Neighbor) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	key := n.NeighborKey()
+	key := s.key(n)
 	existing, exists := s.neighbors[key]
 
 	if exists {
@@ -155,6 +385,8 @@ func (s *NeighborStore) Update(n *Neighbor) bool {
 		if n.ManagementIP != nil {
 			existing.ManagementIP = n.ManagementIP
 		}
+		// CDP and LLDP can each carry only some of the addresses; keep them all
+		existing.AllAddresses = mergeAddresses(existing.AllAddresses, n.AllAddresses)
 		if n.Platform != "" {
 			existing.Platform = n.Platform
 		}
@@ -164,9 +396,57 @@ func (s *NeighborStore) Update(n *Neighbor) bool {
 		if n.Location != "" {
 			existing.Location = n.Location
 		}
+		if n.NativeVLAN != 0 {
+			existing.NativeVLAN = n.NativeVLAN
+		}
+		if n.VTPDomain != "" {
+			existing.VTPDomain = n.VTPDomain
+		}
+		if n.Duplex != "" {
+			existing.Duplex = n.Duplex
+		}
+		if n.Speed != "" {
+			existing.Speed = n.Speed
+		}
+		if n.PortVLAN != 0 {
+			existing.PortVLAN = n.PortVLAN
+		}
+		if n.VLANName != "" {
+			existing.VLANName = n.VLANName
+		}
+		if n.MEDPolicy != nil {
+			existing.MEDPolicy = n.MEDPolicy
+		}
+		if n.Inventory != nil {
+			existing.Inventory = n.Inventory
+		}
+		// CDP and LLDP can report different power; keep the larger reading
+		if n.PowerMW > existing.PowerMW {
+			existing.PowerMW = n.PowerMW
+		}
 		if len(n.Capabilities) > 0 {
 			existing.Capabilities = mergeCapabilities(existing.Capabilities, n.Capabilities)
 		}
+		if len(n.SupportedCapabilities) > 0 {
+			existing.SupportedCapabilities = n.SupportedCapabilities
+		}
+		if n.CDPCapabilityBits != 0 {
+			existing.CDPCapabilityBits = n.CDPCapabilityBits
+		}
+		if n.LLDPCapabilityBits != 0 {
+			existing.LLDPCapabilityBits = n.LLDPCapabilityBits
+		}
+		if n.TTL != 0 {
+			existing.TTL = n.TTL
+		}
+		if n.Interface != "" && !slices.Contains(existing.Interfaces, n.Interface) {
+			existing.Interfaces = append(existing.Interfaces, n.Interface)
+		}
+		if n.FrameLenLast != 0 {
+			existing.RecordFrameLength(n.FrameLenMin)
+			existing.RecordFrameLength(n.FrameLenMax)
+			existing.RecordFrameLength(n.FrameLenLast)
+		}
 
 		// Track which protocols we've seen
 		if n.Protocol == ProtocolCDP {
@@ -176,6 +456,11 @@ func (s *NeighborStore) Update(n *Neighbor) bool {
 		}
 		existing.UpdateProtocol()
 
+		// A stale neighbor announcing again counts as reappearing
+		if existing.IsStale {
+			s.notifyInstability(s.stability.RecordAppear(existing.Interface, key, n.LastSeen), existing.Interface)
+		}
+
 		existing.LastSeen = n.LastSeen
 		existing.IsStale = false
 		existing.SourceMAC = n.SourceMAC
@@ -188,8 +473,14 @@ func (s *NeighborStore) Update(n *Neighbor) bool {
 
 	// New neighbor
 	n.FirstSeen = n.LastSeen
+	if len(n.Capabilities) > 0 {
+		n.Capabilities = mergeCapabilities(nil, n.Capabilities)
+	}
 	n.IsNew = true
 	n.IsStale = false
+	if n.Interface != "" {
+		n.Interfaces = []string{n.Interface}
+	}
 
 	// Set initial protocol flags
 	if n.Protocol == ProtocolCDP {
@@ -199,6 +490,7 @@ func (s *NeighborStore) Update(n *Neighbor) bool {
 	}
 
 	s.neighbors[key] = n
+	s.notifyInstability(s.stability.RecordAppear(n.Interface, key, n.LastSeen), n.Interface)
 
 	if s.OnNewNeighbor != nil {
 		s.OnNewNeighbor(n)
@@ -206,23 +498,59 @@ func (s *NeighborStore) Update(n *Neighbor) bool {
 	return true
 }
 
-// mergeCapabilities merges two capability lists, removing duplicates
+// mergeCapabilities merges two capability lists, removing duplicates and
+// sorting the result with sortCapabilities so the order doesn't change between updates
 func mergeCapabilities(existing, new []Capability) []Capability {
 	seen := make(map[Capability]bool)
-	for _, c := range existing {
-		seen[c] = true
-	}
-	for _, c := range new {
-		seen[c] = true
+	result := make([]Capability, 0, len(existing)+len(new))
+	for _, list := range [][]Capability{existing, new} {
+		for _, c := range list {
+			if !seen[c] {
+				seen[c] = true
+				result = append(result, c)
+			}
+		}
 	}
+	sortCapabilities(result)
+	return result
+}
 
-	result := make([]Capability, 0, len(seen))
-	for c := range seen {
-		result = append(result, c)
+// mergeAddresses merges two address lists, removing duplicates and keeping the
+// existing addresses first
+func mergeAddresses(existing, new []net.IP) []net.IP {
+	result := slices.Clone(existing)
+	for _, ip := range new {
+		if !slices.ContainsFunc(result, ip.Equal) {
+			result = append(result, ip)
+		}
 	}
 	return result
 }
 
+// capabilityOrder is the fixed order capabilities are listed in, most
+// significant first
+var capabilityOrder = []Capability{
+	CapRouter, CapSwitch, CapBridge, CapAccessPoint, CapPhone,
+	CapDocsis, CapStation, CapRepeater, CapOther,
+}
+
+// sortCapabilities sorts caps into capabilityOrder; any capability not in it
+// goes last, alphabetically
+func sortCapabilities(caps []Capability) {
+	rank := func(c Capability) int {
+		if i := slices.Index(capabilityOrder, c); i >= 0 {
+			return i
+		}
+		return len(capabilityOrder)
+	}
+	slices.SortStableFunc(caps, func(a, b Capability) int {
+		if ra, rb := rank(a), rank(b); ra != rb {
+			return ra - rb
+		}
+		return strings.Compare(string(a), string(b))
+	})
+}
+
 // GetAll returns all neighbors
 func (s *NeighborStore) GetAll() []*Neighbor {
 	s.mu.RLock()
@@ -242,7 +570,7 @@ func (s *NeighborStore) GetByInterface(iface string) []*Neighbor {
 
 	var result []*Neighbor
 	for _, n := range s.neighbors {
-		if n.Interface == iface {
+		if n.Interface == iface || slices.Contains(n.Interfaces, iface) {
 			result = append(result, n)
 		}
 	}
@@ -251,13 +579,59 @@ func (s *NeighborStore) GetByInterface(iface string) []*Neighbor {
 
 // MarkStale marks neighbors that haven't been seen recently as stale
 func (s *NeighborStore) MarkStale(threshold time.Duration) {
+	s.MarkStaleSince(threshold, nil)
+}
+
+// AgingStart returns when a neighbor's age starts counting if that's later than
+// its LastSeen, e.g. when its interface's link came back. A nil AgingStart, or a
+// zero time, measures age from LastSeen
+type AgingStart func(n *Neighbor) time.Time
+
+// AgeFrom is an AgingStart that measures every neighbor's age from t at the earliest
+func AgeFrom(t time.Time) AgingStart {
+	return func(*Neighbor) time.Time { return t }
+}
+
+// MarkStaleSince marks neighbors as stale, measuring age from the later of
+// LastSeen and since. Used to give neighbors a fresh timeout after a link outage
+func (s *NeighborStore) MarkStaleSince(threshold time.Duration, since AgingStart) {
+	s.markStale(func(*Neighbor) time.Duration { return threshold }, since)
+}
+
+// MarkStaleByTTL marks neighbors as stale once their advertised TTL plus grace has
+// passed, or fallback for neighbors that didn't advertise a TTL (0: they never go
+// stale). Age is measured from the later of LastSeen and since, as in MarkStaleSince
+func (s *NeighborStore) MarkStaleByTTL(fallback, grace time.Duration, since AgingStart) {
+	s.markStale(func(n *Neighbor) time.Duration {
+		if n.TTL > 0 {
+			return n.TTL + grace
+		}
+		return fallback
+	}, since)
+}
+
+// markStale marks neighbors older than their threshold as stale; a threshold of 0
+// or less never expires
+func (s *NeighborStore) markStale(threshold func(*Neighbor) time.Duration, since AgingStart) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	now := time.Now()
-	for _, n := range s.neighbors {
-		if now.Sub(n.LastSeen) > threshold {
-			n.IsStale = true
+	for key, n := range s.neighbors {
+		lastSeen := n.LastSeen
+		if since != nil {
+			if start := since(n); start.After(lastSeen) {
+				lastSeen = start
+			}
+		}
+		if t := threshold(n); t > 0 && now.Sub(lastSeen) > t {
+			if !n.IsStale {
+				s.notifyInstability(s.stability.RecordDisappear(n.Interface, key, now), n.Interface)
+				n.IsStale = true
+				if s.OnStale != nil {
+					s.OnStale(n)
+				}
+			}
 		}
 	}
 }
@@ -274,6 +648,9 @@ func (s *NeighborStore) RemoveStale(threshold time.Duration) int {
 		if n.IsStale && now.Sub(n.LastSeen) > threshold {
 			delete(s.neighbors, key)
 			removed++
+			if s.OnRemove != nil {
+				s.OnRemove(n)
+			}
 		}
 	}
 	return removed
@@ -297,6 +674,19 @@ func (s *NeighborStore) Clear() {
 	s.neighbors = make(map[string]*Neighbor)
 }
 
+// ClearInterface removes the neighbors heard only on iface
+// Neighbors merged across interfaces are kept, since another link still hears them
+func (s *NeighborStore) ClearInterface(iface string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, n := range s.neighbors {
+		if n.Interface == iface && len(n.Interfaces) <= 1 {
+			delete(s.neighbors, key)
+		}
+	}
+}
+
 // Count returns the number of neighbors
 func (s *NeighborStore) Count() int {
 	s.mu.RLock()
@@ -340,6 +730,17 @@ func (i *InterfaceInfo) FormatIPs() string {
 	return strings.Join(ips, ", ")
 }
 
+// HasGlobalIPv6 reports whether the interface has a globally routable IPv6
+// address (unique local addresses included)
+func (i *InterfaceInfo) HasGlobalIPv6() bool {
+	for _, ip := range i.IPv6Addrs {
+		if ip.IsGlobalUnicast() {
+			return true
+		}
+	}
+	return false
+}
+
 // GetInterfaceAddresses returns non-link-local IP addresses for an interface
 func GetInterfaceAddresses(iface *net.Interface) ([]net.IP, []net.IP) {
 	var ipv4Addrs, ipv6Addrs []net.IP
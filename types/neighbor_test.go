@@ -2,6 +2,8 @@ package types
 
 import (
 	"net"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -103,11 +105,15 @@ func TestNeighborStoreUpdate(t *testing.T) {
 
 	// Add new neighbor
 	n1 := &Neighbor{
-		Interface: "eth0",
-		SourceMAC: mac,
-		Hostname:  "switch01",
-		Protocol:  ProtocolCDP,
-		LastSeen:  time.Now(),
+		Interface:  "eth0",
+		SourceMAC:  mac,
+		Hostname:   "switch01",
+		Protocol:   ProtocolCDP,
+		NativeVLAN: 10,
+		VTPDomain:  "campus",
+		Duplex:     "half",
+		PowerMW:    6300,
+		LastSeen:   time.Now(),
 	}
 
 	isNew := store.Update(n1)
@@ -124,6 +130,7 @@ func TestNeighborStoreUpdate(t *testing.T) {
 		SourceMAC: mac,
 		PortID:    "Gi0/1",
 		Protocol:  ProtocolLLDP,
+		PowerMW:   15400,
 		LastSeen:  time.Now(),
 	}
 
@@ -150,6 +157,50 @@ func TestNeighborStoreUpdate(t *testing.T) {
 	if neighbor.Protocol != ProtocolBoth {
 		t.Errorf("Protocol = %q, want %q", neighbor.Protocol, ProtocolBoth)
 	}
+	if neighbor.NativeVLAN != 10 {
+		t.Errorf("NativeVLAN = %d, want CDP's 10 kept after an LLDP update", neighbor.NativeVLAN)
+	}
+	if neighbor.VTPDomain != "campus" {
+		t.Errorf("VTPDomain = %q, want CDP's %q kept after an LLDP update", neighbor.VTPDomain, "campus")
+	}
+	if neighbor.Duplex != "half" {
+		t.Errorf("Duplex = %q, want CDP's %q kept after an LLDP update", neighbor.Duplex, "half")
+	}
+	if neighbor.PowerMW != 15400 {
+		t.Errorf("PowerMW = %d, want the larger reading 15400", neighbor.PowerMW)
+	}
+}
+
+func TestNeighborStoreUpdateMergesAddresses(t *testing.T) {
+	store := NewNeighborStore()
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	v4, v6, oob := net.ParseIP("10.0.0.1"), net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.1")
+
+	// CDP and LLDP alternate, each carrying a different subset of the addresses
+	updates := []struct {
+		protocol Protocol
+		addrs    []net.IP
+	}{
+		{ProtocolCDP, []net.IP{v4, v6}},
+		{ProtocolLLDP, []net.IP{v4, oob}},
+		{ProtocolCDP, []net.IP{v4, v6}},
+		{ProtocolLLDP, nil},
+		{ProtocolLLDP, []net.IP{oob}},
+	}
+	for _, u := range updates {
+		store.Update(&Neighbor{
+			Interface:    "eth0",
+			SourceMAC:    mac,
+			Protocol:     u.protocol,
+			AllAddresses: u.addrs,
+			LastSeen:     time.Now(),
+		})
+	}
+
+	got := store.GetAll()[0].AllAddresses
+	if len(got) != 3 || !got[0].Equal(v4) || !got[1].Equal(v6) || !got[2].Equal(oob) {
+		t.Errorf("AllAddresses = %v, want [%v %v %v]", got, v4, v6, oob)
+	}
 }
 
 func TestNeighborStoreMarkStale(t *testing.T) {
@@ -231,6 +282,23 @@ func TestNeighborStoreClear(t *testing.T) {
 	}
 }
 
+func TestNeighborStoreClearInterface(t *testing.T) {
+	store := NewNeighborStore()
+	mac1, _ := net.ParseMAC("00:11:22:33:44:55")
+	mac2, _ := net.ParseMAC("00:11:22:33:44:66")
+
+	store.Update(&Neighbor{Interface: "eth0", SourceMAC: mac1, LastSeen: time.Now()})
+	store.Update(&Neighbor{Interface: "eth1", SourceMAC: mac2, LastSeen: time.Now()})
+
+	store.ClearInterface("eth0")
+	if store.Count() != 1 {
+		t.Fatalf("Count() after ClearInterface(eth0) = %d, want 1", store.Count())
+	}
+	if got := store.GetByInterface("eth1"); len(got) != 1 {
+		t.Errorf("GetByInterface(eth1) returned %d neighbors, want 1", len(got))
+	}
+}
+
 func TestNeighborStoreGetByInterface(t *testing.T) {
 	store := NewNeighborStore()
 	mac1, _ := net.ParseMAC("00:11:22:33:44:55")
@@ -362,6 +430,31 @@ func TestCapabilityConstants(t *testing.T) {
 	}
 }
 
+func TestMergeCapabilitiesOrder(t *testing.T) {
+	want := []Capability{CapRouter, CapSwitch, CapPhone, "Custom"}
+	inputs := [][2][]Capability{
+		{{CapRouter, CapSwitch}, {CapPhone, "Custom"}},
+		{{"Custom", CapPhone}, {CapSwitch, CapRouter}},
+		{{CapPhone, CapSwitch, CapPhone}, {"Custom", CapRouter, CapSwitch}},
+		{nil, {CapSwitch, "Custom", CapRouter, CapPhone}},
+	}
+	for _, in := range inputs {
+		// Map iteration order varies run to run, so repeat to catch flicker
+		for i := 0; i < 20; i++ {
+			if got := mergeCapabilities(in[0], in[1]); !slices.Equal(got, want) {
+				t.Fatalf("mergeCapabilities(%v, %v) = %v, want %v", in[0], in[1], got, want)
+			}
+		}
+	}
+
+	// A new neighbor's capabilities are put in the same order
+	store := NewNeighborStore()
+	store.Update(&Neighbor{ID: "sw1", Capabilities: []Capability{CapBridge, CapRouter, CapBridge}, LastSeen: time.Now()})
+	if got := store.GetAll()[0].Capabilities; !slices.Equal(got, []Capability{CapRouter, CapBridge}) {
+		t.Errorf("new neighbor Capabilities = %v, want [Router Bridge]", got)
+	}
+}
+
 func TestProtocolConstants(t *testing.T) {
 	// Verify protocol constants
 	if ProtocolCDP != "CDP" {
@@ -374,3 +467,191 @@ func TestProtocolConstants(t *testing.T) {
 		t.Errorf("ProtocolBoth = %q, want %q", ProtocolBoth, "CDP+LLDP")
 	}
 }
+
+func TestNeighborStoreMergeAcrossInterfaces(t *testing.T) {
+	// Two LAG members: each port sends from its own MAC, with one chassis ID
+	mac0, _ := net.ParseMAC("00:11:22:33:44:01")
+	mac1, _ := net.ParseMAC("00:11:22:33:44:02")
+
+	tests := []struct {
+		name           string
+		merge          bool
+		wantCount      int
+		wantInterfaces []string
+	}{
+		{"separate", false, 2, []string{"eth0"}},
+		{"merged", true, 1, []string{"eth0", "eth1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewNeighborStore()
+			store.SetMergeAcrossInterfaces(tt.merge)
+
+			store.Update(&Neighbor{Interface: "eth0", SourceMAC: mac0, ID: "core-sw1", Hostname: "core-sw1", LastSeen: time.Now()})
+			isNew := store.Update(&Neighbor{Interface: "eth1", SourceMAC: mac1, ID: "CORE-SW1", Hostname: "core-sw1", LastSeen: time.Now()})
+			if isNew == tt.merge {
+				t.Errorf("second Update() = %v, want %v", isNew, !tt.merge)
+			}
+
+			if got := store.Count(); got != tt.wantCount {
+				t.Errorf("Count() = %d, want %d", got, tt.wantCount)
+			}
+			n := store.GetByInterface("eth0")
+			if len(n) != 1 {
+				t.Fatalf("GetByInterface(eth0) returned %d neighbors, want 1", len(n))
+			}
+			if strings.Join(n[0].Interfaces, ",") != strings.Join(tt.wantInterfaces, ",") {
+				t.Errorf("Interfaces = %v, want %v", n[0].Interfaces, tt.wantInterfaces)
+			}
+			if got := len(store.GetByInterface("eth1")); got != 1 {
+				t.Errorf("GetByInterface(eth1) returned %d neighbors, want 1", got)
+			}
+		})
+	}
+}
+
+func TestNeighborStoreFlapDetection(t *testing.T) {
+	store := NewNeighborStore()
+	store.SetFlapDetection(10*time.Minute, 2)
+
+	var flagged []string
+	store.OnLinkInstability = func(iface string) {
+		flagged = append(flagged, iface)
+	}
+
+	// Alternate two neighbors on eth0, each going stale before the other appears
+	macs := []string{"00:11:22:33:44:55", "00:11:22:33:44:66"}
+	for i := 0; i < 4; i++ {
+		mac, _ := net.ParseMAC(macs[i%2])
+		store.Update(&Neighbor{
+			Interface: "eth0",
+			SourceMAC: mac,
+			LastSeen:  time.Now().Add(-time.Minute),
+		})
+		store.MarkStale(30 * time.Second)
+	}
+
+	if !store.IsInterfaceUnstable("eth0") {
+		t.Error("IsInterfaceUnstable(eth0) = false, want true")
+	}
+	if len(flagged) != 1 || flagged[0] != "eth0" {
+		t.Errorf("OnLinkInstability called with %v, want [eth0]", flagged)
+	}
+	if store.IsInterfaceUnstable("eth1") {
+		t.Error("IsInterfaceUnstable(eth1) = true, want false")
+	}
+}
+
+func TestNeighborStoreFlapDetectionDisabled(t *testing.T) {
+	store := NewNeighborStore()
+	store.SetFlapDetection(10*time.Minute, 0)
+
+	macs := []string{"00:11:22:33:44:55", "00:11:22:33:44:66"}
+	for i := 0; i < 6; i++ {
+		mac, _ := net.ParseMAC(macs[i%2])
+		store.Update(&Neighbor{
+			Interface: "eth0",
+			SourceMAC: mac,
+			LastSeen:  time.Now().Add(-time.Minute),
+		})
+		store.MarkStale(30 * time.Second)
+	}
+
+	if store.IsInterfaceUnstable("eth0") {
+		t.Error("IsInterfaceUnstable(eth0) = true with threshold 0, want false")
+	}
+}
+
+func TestNeighborStoreMarkStaleSince(t *testing.T) {
+	store := NewNeighborStore()
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+
+	store.Update(&Neighbor{
+		Interface: "eth0",
+		SourceMAC: mac,
+		LastSeen:  time.Now().Add(-5 * time.Minute),
+	})
+
+	// Link came back 30 seconds ago - age is measured from then, so not stale
+	store.MarkStaleSince(time.Minute, AgeFrom(time.Now().Add(-30*time.Second)))
+	if store.GetAll()[0].IsStale {
+		t.Error("Neighbor marked stale within timeout of link returning")
+	}
+
+	// Link came back 2 minutes ago - timeout has passed
+	store.MarkStaleSince(time.Minute, AgeFrom(time.Now().Add(-2*time.Minute)))
+	if !store.GetAll()[0].IsStale {
+		t.Error("Neighbor not marked stale after timeout from link returning")
+	}
+}
+
+func TestNeighborStoreMarkStaleByTTL(t *testing.T) {
+	store := NewNeighborStore()
+	ttlMAC, _ := net.ParseMAC("00:11:22:33:44:55")
+	noTTLMAC, _ := net.ParseMAC("00:11:22:33:44:66")
+
+	// Advertised a 60s TTL and was last heard 70s ago
+	store.Update(&Neighbor{
+		Interface: "eth0",
+		SourceMAC: ttlMAC,
+		TTL:       60 * time.Second,
+		LastSeen:  time.Now().Add(-70 * time.Second),
+	})
+	// No TTL advertised, same age
+	store.Update(&Neighbor{
+		Interface: "eth0",
+		SourceMAC: noTTLMAC,
+		LastSeen:  time.Now().Add(-70 * time.Second),
+	})
+
+	isStale := func(mac net.HardwareAddr) bool {
+		for _, n := range store.GetAll() {
+			if n.SourceMAC.String() == mac.String() {
+				return n.IsStale
+			}
+		}
+		t.Fatalf("neighbor %s not found", mac)
+		return false
+	}
+
+	// Still within TTL plus grace
+	store.MarkStaleByTTL(3*time.Minute, 15*time.Second, nil)
+	if isStale(ttlMAC) {
+		t.Error("Neighbor marked stale within its TTL plus grace")
+	}
+
+	// TTL plus grace has passed, but the fallback hasn't
+	store.MarkStaleByTTL(3*time.Minute, 5*time.Second, nil)
+	if !isStale(ttlMAC) {
+		t.Error("Neighbor not marked stale after its TTL plus grace")
+	}
+	if isStale(noTTLMAC) {
+		t.Error("Neighbor without TTL marked stale before the fallback timeout")
+	}
+
+	// Fallback applies to neighbors without a TTL
+	store.MarkStaleByTTL(time.Minute, 5*time.Second, nil)
+	if !isStale(noTTLMAC) {
+		t.Error("Neighbor without TTL not marked stale after the fallback timeout")
+	}
+}
+
+func TestNeighborStoreFrameLengths(t *testing.T) {
+	store := NewNeighborStore()
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+
+	for _, length := range []int{120, 64, 300, 150} {
+		n := &Neighbor{Interface: "eth0", SourceMAC: mac, Protocol: ProtocolCDP, LastSeen: time.Now()}
+		n.RecordFrameLength(length)
+		store.Update(n)
+	}
+	// Frames without a recorded length leave the statistics alone
+	store.Update(&Neighbor{Interface: "eth0", SourceMAC: mac, Protocol: ProtocolLLDP, LastSeen: time.Now()})
+
+	got := store.GetAll()[0]
+	if got.FrameLenMin != 64 || got.FrameLenMax != 300 || got.FrameLenLast != 150 {
+		t.Errorf("frame lengths = min %d max %d last %d, want min 64 max 300 last 150",
+			got.FrameLenMin, got.FrameLenMax, got.FrameLenLast)
+	}
+}
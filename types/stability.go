@@ -0,0 +1,109 @@
+package types
+
+import "time"
+
+// linkEvent records a neighbor appearing or disappearing on an interface
+type linkEvent struct {
+	key      string
+	appeared bool
+	at       time.Time
+}
+
+// LinkStabilityTracker watches for neighbors being replaced on an interface
+// A "change" is a neighbor appearing while a different one disappeared within
+// the window (or vice versa). Reaching the threshold of changes within the
+// window flags the interface as possibly unstable.
+type LinkStabilityTracker struct {
+	// Window is how far back events are considered
+	Window time.Duration
+
+	// Threshold is the number of changes within Window that flags instability
+	// 0 disables detection
+	Threshold int
+
+	events   map[string][]linkEvent // Per-interface recent appear/disappear events
+	changes  map[string][]time.Time // Per-interface recent change timestamps
+	unstable map[string]bool        // Interfaces currently flagged
+}
+
+// NewLinkStabilityTracker creates a tracker with the given window and threshold
+func NewLinkStabilityTracker(window time.Duration, threshold int) *LinkStabilityTracker {
+	return &LinkStabilityTracker{
+		Window:    window,
+		Threshold: threshold,
+		events:    make(map[string][]linkEvent),
+		changes:   make(map[string][]time.Time),
+		unstable:  make(map[string]bool),
+	}
+}
+
+// RecordAppear records a neighbor appearing (new or returning from stale)
+// Returns true if this event newly flagged the interface as unstable
+func (t *LinkStabilityTracker) RecordAppear(iface, key string, at time.Time) bool {
+	return t.record(iface, linkEvent{key: key, appeared: true, at: at})
+}
+
+// RecordDisappear records a neighbor going stale or being removed
+// Returns true if this event newly flagged the interface as unstable
+func (t *LinkStabilityTracker) RecordDisappear(iface, key string, at time.Time) bool {
+	return t.record(iface, linkEvent{key: key, appeared: false, at: at})
+}
+
+// record stores an event, counts a change if it pairs with an opposite event
+// from a different neighbor, and updates the instability flag
+func (t *LinkStabilityTracker) record(iface string, ev linkEvent) bool {
+	if t.Threshold <= 0 || t.Window <= 0 {
+		return false
+	}
+
+	t.prune(iface, ev.at)
+
+	for _, prev := range t.events[iface] {
+		if prev.appeared != ev.appeared && prev.key != ev.key {
+			t.changes[iface] = append(t.changes[iface], ev.at)
+			break
+		}
+	}
+	t.events[iface] = append(t.events[iface], ev)
+
+	if len(t.changes[iface]) >= t.Threshold && !t.unstable[iface] {
+		t.unstable[iface] = true
+		return true
+	}
+	return false
+}
+
+// prune drops events and changes older than the window and clears the
+// instability flag once the change count falls below the threshold
+func (t *LinkStabilityTracker) prune(iface string, now time.Time) {
+	cutoff := now.Add(-t.Window)
+
+	events := t.events[iface][:0]
+	for _, ev := range t.events[iface] {
+		if ev.at.After(cutoff) {
+			events = append(events, ev)
+		}
+	}
+	t.events[iface] = events
+
+	changes := t.changes[iface][:0]
+	for _, c := range t.changes[iface] {
+		if c.After(cutoff) {
+			changes = append(changes, c)
+		}
+	}
+	t.changes[iface] = changes
+
+	if len(changes) < t.Threshold {
+		delete(t.unstable, iface)
+	}
+}
+
+// IsUnstable returns whether the interface has had too many neighbor changes recently
+func (t *LinkStabilityTracker) IsUnstable(iface string, now time.Time) bool {
+	if t.Threshold <= 0 || t.Window <= 0 {
+		return false
+	}
+	t.prune(iface, now)
+	return t.unstable[iface]
+}